@@ -0,0 +1,290 @@
+// Package anomalies finds the trees of an estate that stand out: trees whose
+// height dropped sharply, or that are far shorter than the trees around them
+// or than the trees of their age.
+//
+// Heights are compared with robust statistics, the median and the median
+// absolute deviation, so that a few outliers do not hide each other. Trees are
+// fed row by row and only the rows around the one being scored are kept, so
+// an estate of any size is scored in a single pass over its trees.
+package anomalies
+
+import (
+	"math"
+	"sawitpro-recruitment/models"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// madScale turns a median absolute deviation into an estimate of the
+// standard deviation of normally distributed heights.
+const madScale = 1.4826
+
+// Config holds the thresholds of the detection.
+type Config struct {
+	Radius         int     // Plots around a tree, in each direction, its neighbours are taken from
+	MinNeighbours  int     // Neighbours a tree needs to be compared with them
+	MinCohortTrees int     // Trees an age cohort needs for its trees to be compared with it
+	MinSpread      float64 // Smallest standard deviation used, in meters, so that even stands are not flagged for small differences
+	NeighbourScore float64 // Score from which a tree stands out from its neighbours
+	CohortScore    float64 // Score from which a tree stands out from its cohort
+	DropMeters     int     // Smallest height drop reported, in meters
+	DropRatio      float64 // Smallest height drop reported, as a share of the previous height
+}
+
+// DefaultConfig returns the thresholds used by the API.
+func DefaultConfig() *Config {
+	return &Config{
+		Radius:         2,
+		MinNeighbours:  4,
+		MinCohortTrees: 10,
+		MinSpread:      1,
+		NeighbourScore: 3,
+		CohortScore:    3.5,
+		DropMeters:     2,
+		DropRatio:      0.2,
+	}
+}
+
+// Spread is the median of a set of heights and their robust standard
+// deviation.
+type Spread struct {
+	Median float64
+	Sigma  float64
+}
+
+// SpreadOfCounts computes the spread of heights given as a number of trees per
+// height, without listing the trees one by one.
+func SpreadOfCounts(counts map[int]int) Spread {
+	heights := make(map[float64]int, len(counts))
+	for height, count := range counts {
+		heights[float64(height)] += count
+	}
+	median := weightedMedian(heights)
+	deviations := make(map[float64]int, len(heights))
+	for height, count := range heights {
+		deviations[math.Abs(height-median)] += count
+	}
+	return Spread{Median: median, Sigma: madScale * weightedMedian(deviations)}
+}
+
+// weightedMedian returns the median of values given as a count per value, 0
+// when there are none.
+func weightedMedian(counts map[float64]int) float64 {
+	values := make([]float64, 0, len(counts))
+	total := 0
+	for value, count := range counts {
+		if count > 0 {
+			values = append(values, value)
+			total += count
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	// The median is the mean of the values at positions lo and hi, counted
+	// from 0 in sorted order; they are the same value for an odd total
+	lo, hi := (total-1)/2, total/2
+	var low, high float64
+	seen := 0
+	for _, value := range values {
+		next := seen + counts[value]
+		if seen <= lo && lo < next {
+			low = value
+		}
+		if hi < next {
+			high = value
+			break
+		}
+		seen = next
+	}
+	return (low + high) / 2
+}
+
+// spreadOf computes the spread of heights. It reorders heights.
+func spreadOf(heights []float64) Spread {
+	median := medianOf(heights)
+	deviations := make([]float64, len(heights))
+	for i, height := range heights {
+		deviations[i] = math.Abs(height - median)
+	}
+	return Spread{Median: median, Sigma: madScale * medianOf(deviations)}
+}
+
+// medianOf returns the median of values, 0 when there are none. It sorts values.
+func medianOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 1 {
+		return values[mid]
+	}
+	return (values[mid-1] + values[mid]) / 2
+}
+
+// row is a buffered row of trees, ordered by column.
+type row struct {
+	y     int
+	trees []*models.Tree
+}
+
+// Detector scores the trees of an estate fed to it row by row.
+type Detector struct {
+	config  *Config
+	cohorts map[string]Spread // Spread of each age cohort with enough trees
+	drops   map[uuid.UUID]int // Previous height of the trees whose latest measurement is lower
+	rows    []row             // Rows still needed, ascending
+	scored  int               // Rows at the start of rows already scored
+	trees   int
+	found   []*models.TreeAnomaly
+}
+
+// NewDetector creates a detector. cohortCounts holds the number of trees per
+// height of each age cohort of the estate, and drops the previous height of
+// the trees whose height went down at their latest measurement.
+func NewDetector(config *Config, cohortCounts map[string]map[int]int, drops map[uuid.UUID]int) *Detector {
+	cohorts := map[string]Spread{}
+	for cohort, counts := range cohortCounts {
+		total := 0
+		for _, count := range counts {
+			total += count
+		}
+		// Trees of unknown age mix every cohort
+		if cohort != models.AgeCohortUnknown && total >= config.MinCohortTrees {
+			cohorts[cohort] = SpreadOfCounts(counts)
+		}
+	}
+	return &Detector{config: config, cohorts: cohorts, drops: drops, found: []*models.TreeAnomaly{}}
+}
+
+// Add feeds the next tree to the detector. Trees must come ordered by row,
+// then by column.
+func (d *Detector) Add(tree *models.Tree) {
+	if n := len(d.rows); n == 0 || d.rows[n-1].y != tree.Y {
+		d.flush(tree.Y)
+		d.rows = append(d.rows, row{y: tree.Y})
+	}
+	last := &d.rows[len(d.rows)-1]
+	last.trees = append(last.trees, tree)
+	d.trees++
+}
+
+// Finish scores the trees not scored yet and returns the anomalies found,
+// ordered by row then column, and the number of trees scored.
+func (d *Detector) Finish() ([]*models.TreeAnomaly, int) {
+	d.flush(math.MaxInt - d.config.Radius)
+	return d.found, d.trees
+}
+
+// flush scores the rows whose neighbours have all been fed once row y starts,
+// then drops the rows too far above every row left to score.
+func (d *Detector) flush(y int) {
+	for d.scored < len(d.rows) && d.rows[d.scored].y+d.config.Radius < y {
+		d.scoreRow(d.scored)
+		d.scored++
+	}
+	next := y
+	if d.scored < len(d.rows) {
+		next = d.rows[d.scored].y
+	}
+	drop := 0
+	for drop < d.scored && d.rows[drop].y < next-d.config.Radius {
+		drop++
+	}
+	d.rows = d.rows[drop:]
+	d.scored -= drop
+}
+
+// scoreRow scores the trees of the buffered row i.
+func (d *Detector) scoreRow(i int) {
+	for _, tree := range d.rows[i].trees {
+		reasons := d.score(tree, d.neighbourHeights(i, tree))
+		if len(reasons) == 0 {
+			continue
+		}
+		sort.SliceStable(reasons, func(a, b int) bool {
+			return reasons[a].Score > reasons[b].Score
+		})
+		d.found = append(d.found, &models.TreeAnomaly{
+			EstateID: tree.EstateID,
+			TreeID:   tree.ID,
+			X:        tree.X,
+			Y:        tree.Y,
+			Height:   tree.Height,
+			Score:    reasons[0].Score,
+			Reasons:  reasons,
+			Status:   models.AnomalyStatusOpen,
+		})
+	}
+}
+
+// neighbourHeights lists the heights of the trees within Radius plots of a
+// tree of the buffered row i, in both directions.
+func (d *Detector) neighbourHeights(i int, tree *models.Tree) []float64 {
+	var heights []float64
+	radius := d.config.Radius
+	for j := i; j >= 0 && d.rows[j].y >= tree.Y-radius; j-- {
+		heights = appendWithin(heights, d.rows[j].trees, tree, radius)
+	}
+	for j := i + 1; j < len(d.rows) && d.rows[j].y <= tree.Y+radius; j++ {
+		heights = appendWithin(heights, d.rows[j].trees, tree, radius)
+	}
+	return heights
+}
+
+// appendWithin appends the heights of the trees of a row, ordered by column,
+// within radius columns of a tree, leaving out the tree itself.
+func appendWithin(heights []float64, trees []*models.Tree, tree *models.Tree, radius int) []float64 {
+	start := sort.Search(len(trees), func(k int) bool { return trees[k].X >= tree.X-radius })
+	for _, other := range trees[start:] {
+		if other.X > tree.X+radius {
+			break
+		}
+		if other != tree {
+			heights = append(heights, float64(other.Height))
+		}
+	}
+	return heights
+}
+
+// score lists the reasons a tree stands out, given its neighbours' heights.
+func (d *Detector) score(tree *models.Tree, neighbours []float64) []models.AnomalyReason {
+	reasons := []models.AnomalyReason{}
+	height := float64(tree.Height)
+	if previous, ok := d.drops[tree.ID]; ok {
+		drop := previous - tree.Height
+		if drop >= d.config.DropMeters && float64(drop) >= d.config.DropRatio*float64(previous) {
+			reasons = append(reasons, reason(models.AnomalyReasonHeightDrop, float64(previous), float64(drop)/d.config.MinSpread))
+		}
+	}
+	if len(neighbours) >= d.config.MinNeighbours {
+		spread := spreadOf(neighbours)
+		if score := d.shortfall(height, spread); score >= d.config.NeighbourScore {
+			reasons = append(reasons, reason(models.AnomalyReasonNeighbours, spread.Median, score))
+		}
+	}
+	if spread, ok := d.cohorts[models.AgeCohortOf(tree.AgeYears)]; ok {
+		if score := d.shortfall(height, spread); score >= d.config.CohortScore {
+			reasons = append(reasons, reason(models.AnomalyReasonCohort, spread.Median, score))
+		}
+	}
+	return reasons
+}
+
+// shortfall scores how far a height is below the median of a spread, in
+// standard deviations of at least MinSpread.
+func (d *Detector) shortfall(height float64, spread Spread) float64 {
+	return (spread.Median - height) / math.Max(spread.Sigma, d.config.MinSpread)
+}
+
+func reason(code string, expected, score float64) models.AnomalyReason {
+	return models.AnomalyReason{Code: code, Expected: round2(expected), Score: round2(score)}
+}
+
+// round2 rounds a figure to two decimals.
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
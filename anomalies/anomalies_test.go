@@ -0,0 +1,163 @@
+package anomalies
+
+import (
+	"math/rand"
+	"testing"
+
+	"sawitpro-recruitment/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// grid plants a square of trees of the same height, row by row.
+func grid(size, height int) []*models.Tree {
+	var trees []*models.Tree
+	for y := 1; y <= size; y++ {
+		for x := 1; x <= size; x++ {
+			trees = append(trees, &models.Tree{ID: uuid.New(), X: x, Y: y, Height: height})
+		}
+	}
+	return trees
+}
+
+func detect(detector *Detector, trees []*models.Tree) ([]*models.TreeAnomaly, int) {
+	for _, tree := range trees {
+		detector.Add(tree)
+	}
+	return detector.Finish()
+}
+
+func TestSpreadOfCounts(t *testing.T) {
+	spread := SpreadOfCounts(map[int]int{10: 2, 12: 1, 20: 1})
+	assert.Equal(t, 11.0, spread.Median)
+	// Deviations 1, 1, 1 and 9
+	assert.InDelta(t, madScale, spread.Sigma, 1e-9)
+
+	assert.Equal(t, Spread{}, SpreadOfCounts(map[int]int{}))
+
+	random := rand.New(rand.NewSource(7))
+	for i := 0; i < 200; i++ {
+		counts := map[int]int{}
+		var heights []float64
+		for j := random.Intn(40); j >= 0; j-- {
+			height := 1 + random.Intn(30)
+			counts[height]++
+			heights = append(heights, float64(height))
+		}
+		assert.Equal(t, spreadOf(heights), SpreadOfCounts(counts), "heights %v", heights)
+	}
+}
+
+func TestDetector_Neighbours(t *testing.T) {
+	trees := grid(7, 15)
+	short := trees[3*7+3] // (4,4)
+	short.Height = 5
+
+	anomalies, scored := detect(NewDetector(DefaultConfig(), nil, nil), trees)
+	assert.Equal(t, 49, scored)
+	if assert.Len(t, anomalies, 1) {
+		anomaly := anomalies[0]
+		assert.Equal(t, short.ID, anomaly.TreeID)
+		assert.Equal(t, models.AnomalyStatusOpen, anomaly.Status)
+		// Every neighbour stands at 15 m, so the shortfall is counted in MinSpread
+		assert.Equal(t, []models.AnomalyReason{{Code: models.AnomalyReasonNeighbours, Expected: 15, Score: 10}}, anomaly.Reasons)
+		assert.Equal(t, 10.0, anomaly.Score)
+	}
+}
+
+func TestDetector_SmallDifferencesIgnored(t *testing.T) {
+	trees := grid(7, 15)
+	trees[3*7+3].Height = 13
+
+	anomalies, _ := detect(NewDetector(DefaultConfig(), nil, nil), trees)
+	assert.Empty(t, anomalies)
+}
+
+func TestDetector_CohortAndDrop(t *testing.T) {
+	age := 10
+	trees := grid(4, 18)
+	for _, tree := range trees {
+		tree.AgeYears = &age
+	}
+	// A lone tree, with no neighbours, much shorter than its cohort and
+	// shorter than at its previous measurement
+	lone := &models.Tree{ID: uuid.New(), X: 20, Y: 20, Height: 8, AgeYears: &age}
+	trees = append(trees, lone)
+	// A tree that dropped too little to be reported
+	trees[0].Height = 17
+
+	cohorts := map[string]map[int]int{
+		"prime":                 {18: 15, 17: 1, 8: 1},
+		models.AgeCohortUnknown: {1: 100},
+	}
+	drops := map[uuid.UUID]int{lone.ID: 12, trees[0].ID: 18}
+	anomalies, _ := detect(NewDetector(DefaultConfig(), cohorts, drops), trees)
+	if assert.Len(t, anomalies, 1) {
+		assert.Equal(t, lone.ID, anomalies[0].TreeID)
+		assert.Equal(t, []models.AnomalyReason{
+			{Code: models.AnomalyReasonCohort, Expected: 18, Score: 10},
+			{Code: models.AnomalyReasonHeightDrop, Expected: 12, Score: 4},
+		}, anomalies[0].Reasons)
+	}
+}
+
+func TestDetector_MatchesWholeEstate(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	config := DefaultConfig()
+	for i := 0; i < 50; i++ {
+		// Sparse rows with gaps between them, some trees much shorter
+		var trees []*models.Tree
+		byPlot := map[[2]int]*models.Tree{}
+		for y := 1; y <= 30; y++ {
+			if random.Intn(4) == 0 {
+				continue
+			}
+			for x := 1; x <= 30; x++ {
+				if random.Intn(3) == 0 {
+					continue
+				}
+				tree := &models.Tree{ID: uuid.New(), X: x, Y: y, Height: 14 + random.Intn(4)}
+				if random.Intn(20) == 0 {
+					tree.Height = 1 + random.Intn(5)
+				}
+				trees = append(trees, tree)
+				byPlot[[2]int{x, y}] = tree
+			}
+		}
+
+		want := map[uuid.UUID]bool{}
+		for _, tree := range trees {
+			var heights []float64
+			for dy := -config.Radius; dy <= config.Radius; dy++ {
+				for dx := -config.Radius; dx <= config.Radius; dx++ {
+					if other, ok := byPlot[[2]int{tree.X + dx, tree.Y + dy}]; ok && other != tree {
+						heights = append(heights, float64(other.Height))
+					}
+				}
+			}
+			if len(heights) >= config.MinNeighbours {
+				spread := spreadOf(heights)
+				if (spread.Median-float64(tree.Height))/max(spread.Sigma, config.MinSpread) >= config.NeighbourScore {
+					want[tree.ID] = true
+				}
+			}
+		}
+
+		detector := NewDetector(config, nil, nil)
+		got := map[uuid.UUID]bool{}
+		for _, tree := range trees {
+			detector.Add(tree)
+			// Only the rows around the one being scored are kept
+			assert.LessOrEqual(t, len(detector.rows), 2*config.Radius+2)
+		}
+		anomalies, scored := detector.Finish()
+		for _, anomaly := range anomalies {
+			got[anomaly.TreeID] = true
+		}
+		assert.Equal(t, len(trees), scored)
+		if !assert.Equal(t, want, got) {
+			return
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Package cache keeps data derived from an estate and its trees, such as drone
+// plans and stats, in memory until the estate or its trees change.
+package cache
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxEntriesPerEstate = 64   // Cached values kept for one estate
+	maxEstates          = 1024 // Estates with cached values; the least recently used is dropped first
+)
+
+// EstateCache stores derived values per estate. A nil *EstateCache is valid
+// and caches nothing.
+//
+// Each estate has a generation, changed whenever it is invalidated. A value
+// computed before an invalidation is not stored, so a value never outlives a
+// change to the estate made while it was computed: take the generation with
+// Generation before reading the data a value is derived from, and pass it to
+// Set.
+type EstateCache struct {
+	mu      sync.Mutex
+	estates map[uuid.UUID]*list.Element // Elements of order, holding *estateEntry
+	order   *list.List                  // Most recently used first
+	clock   uint64                      // Counts invalidations
+	// Generation of estates without an entry: the latest generation of an
+	// evicted estate, which may be one of them
+	evicted uint64
+}
+
+// estateEntry holds the cached values of an estate and its generation.
+type estateEntry struct {
+	id         uuid.UUID
+	generation uint64
+	values     map[string]interface{}
+}
+
+// NewEstateCache creates an empty EstateCache.
+func NewEstateCache() *EstateCache {
+	return &EstateCache{
+		estates: make(map[uuid.UUID]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns the value cached for the estate under key.
+func (c *EstateCache) Get(estateID uuid.UUID, key string) (interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.estates[estateID]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	value, ok := element.Value.(*estateEntry).values[key]
+	return value, ok
+}
+
+// Generation returns the generation of the estate, to be passed to Set for a
+// value computed from now on.
+func (c *EstateCache) Generation(estateID uuid.UUID) uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation(estateID)
+}
+
+// Set caches a value for the estate under key, unless the estate has been
+// invalidated since the given generation.
+func (c *EstateCache) Set(estateID uuid.UUID, key string, generation uint64, value interface{}) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation(estateID) != generation {
+		return
+	}
+	entry := c.entry(estateID, generation)
+	if len(entry.values) >= maxEntriesPerEstate {
+		entry.values = make(map[string]interface{})
+	}
+	entry.values[key] = value
+}
+
+// Invalidate drops every value cached for the estate.
+func (c *EstateCache) Invalidate(estateID uuid.UUID) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock++
+	entry := c.entry(estateID, c.clock)
+	entry.generation = c.clock
+	entry.values = make(map[string]interface{})
+}
+
+// generation returns the generation of the estate; c.mu must be held.
+func (c *EstateCache) generation(estateID uuid.UUID) uint64 {
+	if element, ok := c.estates[estateID]; ok {
+		return element.Value.(*estateEntry).generation
+	}
+	return c.evicted
+}
+
+// entry returns the entry of the estate, marked most recently used. A missing
+// entry is created at the given generation, evicting the least recently used
+// estate when the cache is full; c.mu must be held.
+func (c *EstateCache) entry(estateID uuid.UUID, generation uint64) *estateEntry {
+	if element, ok := c.estates[estateID]; ok {
+		c.order.MoveToFront(element)
+		return element.Value.(*estateEntry)
+	}
+	if c.order.Len() >= maxEstates {
+		oldest := c.order.Remove(c.order.Back()).(*estateEntry)
+		delete(c.estates, oldest.id)
+		c.evicted = max(c.evicted, oldest.generation)
+	}
+	entry := &estateEntry{id: estateID, generation: generation, values: make(map[string]interface{})}
+	c.estates[estateID] = c.order.PushFront(entry)
+	return entry
+}
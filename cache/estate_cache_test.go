@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstateCache_SetGetInvalidate(t *testing.T) {
+	c := NewEstateCache()
+	estateID, otherID := uuid.New(), uuid.New()
+
+	c.Set(estateID, "stats", c.Generation(estateID), 42)
+	c.Set(otherID, "stats", c.Generation(otherID), 7)
+
+	value, ok := c.Get(estateID, "stats")
+	assert.True(t, ok)
+	assert.Equal(t, 42, value)
+
+	c.Invalidate(estateID)
+	_, ok = c.Get(estateID, "stats")
+	assert.False(t, ok)
+
+	value, ok = c.Get(otherID, "stats")
+	assert.True(t, ok)
+	assert.Equal(t, 7, value)
+}
+
+func TestEstateCache_BoundedEntries(t *testing.T) {
+	c := NewEstateCache()
+	estateID := uuid.New()
+
+	for i := 0; i <= maxEntriesPerEstate; i++ {
+		c.Set(estateID, uuid.NewString(), c.Generation(estateID), i)
+	}
+	assert.LessOrEqual(t, len(c.estates[estateID].Value.(*estateEntry).values), maxEntriesPerEstate)
+}
+
+func TestEstateCache_StaleSet(t *testing.T) {
+	c := NewEstateCache()
+	estateID := uuid.New()
+
+	// Computed from trees changed before it is stored
+	generation := c.Generation(estateID)
+	c.Invalidate(estateID)
+	c.Set(estateID, "stats", generation, 42)
+	_, ok := c.Get(estateID, "stats")
+	assert.False(t, ok)
+
+	c.Set(estateID, "stats", c.Generation(estateID), 7)
+	value, ok := c.Get(estateID, "stats")
+	assert.True(t, ok)
+	assert.Equal(t, 7, value)
+}
+
+func TestEstateCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewEstateCache()
+	first, second := uuid.New(), uuid.New()
+	c.Set(first, "stats", c.Generation(first), 1)
+	c.Set(second, "stats", c.Generation(second), 2)
+	// Invalidated while its stats are computed, then evicted
+	generation := c.Generation(second)
+	c.Invalidate(second)
+
+	c.Get(first, "stats")
+	for i := 2; i < maxEstates+1; i++ {
+		estateID := uuid.New()
+		c.Set(estateID, "stats", c.Generation(estateID), i)
+	}
+	assert.Equal(t, maxEstates, c.order.Len())
+	assert.Len(t, c.estates, maxEstates)
+
+	_, ok := c.Get(first, "stats")
+	assert.True(t, ok)
+	_, ok = c.estates[second]
+	assert.False(t, ok)
+	c.Set(second, "stats", generation, 2)
+	_, ok = c.Get(second, "stats")
+	assert.False(t, ok)
+}
+
+func TestEstateCache_Nil(t *testing.T) {
+	var c *EstateCache
+	estateID := uuid.New()
+
+	c.Set(estateID, "stats", c.Generation(estateID), 42)
+	_, ok := c.Get(estateID, "stats")
+	assert.False(t, ok)
+	c.Invalidate(estateID)
+}
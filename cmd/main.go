@@ -3,9 +3,12 @@ package main
 import (
     "github.com/labstack/echo/v4"
     "github.com/labstack/echo/v4/middleware"
+    "os"
+    "path/filepath"
     "sawitpro-recruitment/database"
     "sawitpro-recruitment/generated"
     "sawitpro-recruitment/repositories"
+    "sawitpro-recruitment/tiles"
 )
 
 // @title SawitPro Recruitment API
@@ -27,9 +30,18 @@ func main() {
     // Initialize repositories
     estateRepo := repositories.NewEstateRepository(database.DB)
     treeRepo := repositories.NewTreeRepository(database.DB)
+    speciesRepo := repositories.NewSpeciesRepository(database.DB)
+    harvestRepo := repositories.NewHarvestRepository(database.DB)
+    anomalyRepo := repositories.NewAnomalyRepository(database.DB)
+
+    // Map tiles are cached on local disk, in TILE_CACHE_DIR when set
+    tileDir := os.Getenv("TILE_CACHE_DIR")
+    if tileDir == "" {
+        tileDir = filepath.Join(os.TempDir(), "estate-tiles")
+    }
 
     // Initialize server
-    server := NewServer(estateRepo, treeRepo)
+    server := NewServer(estateRepo, treeRepo, speciesRepo, harvestRepo, anomalyRepo, tiles.NewDiskCache(tileDir))
 
     // Register handlers
     generated.RegisterHandlers(e, server)
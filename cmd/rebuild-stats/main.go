@@ -0,0 +1,60 @@
+// Command rebuild-stats recomputes the height summaries of estates from their
+// trees, creating the missing ones and repairing any that drifted.
+//
+//    go run ./cmd/rebuild-stats              # every estate
+//    go run ./cmd/rebuild-stats -estate <id> # one estate
+package main
+
+import (
+    "flag"
+    "os"
+    "sawitpro-recruitment/database"
+    "sawitpro-recruitment/repositories"
+
+    "github.com/google/uuid"
+    "github.com/sirupsen/logrus"
+)
+
+func main() {
+    estate := flag.String("estate", "", "ID of the only estate to rebuild")
+    flag.Parse()
+
+    ids := []uuid.UUID{}
+    if *estate != "" {
+        id, err := uuid.Parse(*estate)
+        if err != nil {
+            logrus.Fatalf("Invalid estate ID: %s", *estate)
+        }
+        ids = append(ids, id)
+    }
+
+    database.InitDB()
+    estateRepo := repositories.NewEstateRepository(database.DB)
+
+    if len(ids) == 0 {
+        var err error
+        ids, err = estateRepo.ListEstateIDs()
+        if err != nil {
+            logrus.Fatalf("Failed to list estates: %v", err)
+        }
+    }
+
+    // Each estate is rebuilt in its own transaction, so one failure leaves the
+    // others rebuilt
+    drifted, failed := 0, 0
+    for _, id := range ids {
+        changed, err := estateRepo.RebuildHeightSummary(id)
+        if err != nil {
+            failed++
+            continue
+        }
+        if changed {
+            drifted++
+        }
+    }
+
+    logrus.Infof("Rebuilt %d height summaries: %d had drifted, %d failed", len(ids)-failed, drifted, failed)
+    if failed > 0 {
+        os.Exit(1)
+    }
+}
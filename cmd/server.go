@@ -1,9 +1,11 @@
 package main
 
 import (
+	"sawitpro-recruitment/cache"
 	"sawitpro-recruitment/generated"
 	"sawitpro-recruitment/handlers"
 	"sawitpro-recruitment/repositories"
+	"sawitpro-recruitment/tiles"
 	"strconv"
 
 	"github.com/google/uuid"
@@ -11,9 +13,10 @@ import (
 )
 
 type Server struct {
-	estateHandler *handlers.EstateHandler
-	droneHandler  *handlers.DroneHandler
-	treeHandler   *handlers.TreeHandler
+	estateHandler  *handlers.EstateHandler
+	droneHandler   *handlers.DroneHandler
+	treeHandler    *handlers.TreeHandler
+	speciesHandler *handlers.SpeciesHandler
 }
 
 // GetHello implements generated.ServerInterface.
@@ -21,18 +24,71 @@ func (s *Server) GetHello(ctx echo.Context, params generated.GetHelloParams) err
 	return handlers.HelloHandler(ctx)
 }
 
-func NewServer(estateRepo repositories.EstateRepository, treeRepo repositories.TreeRepository) *Server {
+func NewServer(estateRepo repositories.EstateRepository, treeRepo repositories.TreeRepository, speciesRepo repositories.SpeciesRepository,
+	harvestRepo repositories.HarvestRepository, anomalyRepo repositories.AnomalyRepository, tileCache *tiles.DiskCache) *Server {
+	// Derived data is shared so that changes made through one handler
+	// invalidate what the others have cached
+	estateCache := cache.NewEstateCache()
+
+	estateHandler := handlers.NewEstateHandler(estateRepo)
+	estateHandler.Cache = estateCache
+	estateHandler.HarvestRepo = harvestRepo
+	estateHandler.Tiles = tileCache
+	droneHandler := handlers.NewDroneHandler(treeRepo, estateRepo)
+	droneHandler.Cache = estateCache
+	treeHandler := handlers.NewTreeHandler(treeRepo, estateRepo)
+	treeHandler.Cache = estateCache
+	treeHandler.SpeciesRepo = speciesRepo
+	treeHandler.HarvestRepo = harvestRepo
+	treeHandler.AnomalyRepo = anomalyRepo
+	treeHandler.Tiles = tileCache
+
 	return &Server{
-		estateHandler: handlers.NewEstateHandler(estateRepo),
-		droneHandler:  handlers.NewDroneHandler(treeRepo, estateRepo),
-		treeHandler:   handlers.NewTreeHandler(treeRepo, estateRepo),
+		estateHandler:  estateHandler,
+		droneHandler:   droneHandler,
+		treeHandler:    treeHandler,
+		speciesHandler: handlers.NewSpeciesHandler(speciesRepo),
 	}
 }
 
+func (s *Server) GetEstate(ctx echo.Context, params generated.GetEstateParams) error {
+	return s.estateHandler.ListEstates(ctx)
+}
+
 func (s *Server) PostEstate(ctx echo.Context) error {
 	return s.estateHandler.CreateEstate(ctx)
 }
 
+func (s *Server) GetEstateId(ctx echo.Context, id uuid.UUID) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.estateHandler.GetEstate(ctx)
+}
+
+func (s *Server) PatchEstateId(ctx echo.Context, id uuid.UUID) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.estateHandler.UpdateEstate(ctx)
+}
+
+func (s *Server) DeleteEstateId(ctx echo.Context, id uuid.UUID) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.estateHandler.DeleteEstate(ctx)
+}
+
+func (s *Server) PostEstateIdArchive(ctx echo.Context, id uuid.UUID) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.estateHandler.ArchiveEstate(ctx)
+}
+
+func (s *Server) PostEstateIdRestore(ctx echo.Context, id uuid.UUID) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.estateHandler.RestoreEstate(ctx)
+}
+
 func (s *Server) GetEstateIdDronePlan(ctx echo.Context, id uuid.UUID, params generated.GetEstateIdDronePlanParams) error {
 	ctx.SetParamNames("id")
 	ctx.SetParamValues(id.String())
@@ -42,18 +98,198 @@ func (s *Server) GetEstateIdDronePlan(ctx echo.Context, id uuid.UUID, params gen
 	return s.droneHandler.CalculateDronePlanWithLimit(ctx)
 }
 
-func (s *Server) GetEstateIdStats(ctx echo.Context, id uuid.UUID) error {
+func (s *Server) GetEstateIdStats(ctx echo.Context, id uuid.UUID, params generated.GetEstateIdStatsParams) error {
 	ctx.SetParamNames("id")
 	ctx.SetParamValues(id.String())
 	return s.estateHandler.GetEstateStats(ctx)
 }
 
+func (s *Server) GetEstateIdStatsBreakdown(ctx echo.Context, id uuid.UUID) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.estateHandler.GetEstateStatsBreakdown(ctx)
+}
+
+func (s *Server) GetEstateIdStatsTimeseries(ctx echo.Context, id uuid.UUID, params generated.GetEstateIdStatsTimeseriesParams) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.estateHandler.GetEstateTimeseries(ctx)
+}
+
+func (s *Server) PostEstateIdStatsRegions(ctx echo.Context, id uuid.UUID, params generated.PostEstateIdStatsRegionsParams) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.estateHandler.GetEstateRegionStats(ctx)
+}
+
+func (s *Server) GetPortfolioStats(ctx echo.Context, params generated.GetPortfolioStatsParams) error {
+	return s.estateHandler.GetPortfolioStats(ctx)
+}
+
 func (s *Server) PostEstateIdTree(ctx echo.Context, id uuid.UUID) error {
 	ctx.SetParamNames("id")
 	ctx.SetParamValues(id.String())
 	return s.treeHandler.AddTreeToEstate(ctx)
 }
 
+func (s *Server) PostEstateIdTreeImport(ctx echo.Context, id uuid.UUID, params generated.PostEstateIdTreeImportParams) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.treeHandler.ImportTrees(ctx)
+}
+
+func (s *Server) GetEstateIdTreeTreeId(ctx echo.Context, id uuid.UUID, treeId uuid.UUID) error {
+	ctx.SetParamNames("id", "treeId")
+	ctx.SetParamValues(id.String(), treeId.String())
+	return s.treeHandler.GetTree(ctx)
+}
+
+func (s *Server) PatchEstateIdTreeTreeId(ctx echo.Context, id uuid.UUID, treeId uuid.UUID) error {
+	ctx.SetParamNames("id", "treeId")
+	ctx.SetParamValues(id.String(), treeId.String())
+	return s.treeHandler.UpdateTree(ctx)
+}
+
+func (s *Server) DeleteEstateIdTreeTreeId(ctx echo.Context, id uuid.UUID, treeId uuid.UUID) error {
+	ctx.SetParamNames("id", "treeId")
+	ctx.SetParamValues(id.String(), treeId.String())
+	return s.treeHandler.DeleteTree(ctx)
+}
+
+func (s *Server) GetEstateIdTreeTreeIdMeasurements(ctx echo.Context, id uuid.UUID, treeId uuid.UUID) error {
+	ctx.SetParamNames("id", "treeId")
+	ctx.SetParamValues(id.String(), treeId.String())
+	return s.treeHandler.GetTreeMeasurements(ctx)
+}
+
+func (s *Server) PostEstateIdTreeTreeIdMeasurements(ctx echo.Context, id uuid.UUID, treeId uuid.UUID) error {
+	ctx.SetParamNames("id", "treeId")
+	ctx.SetParamValues(id.String(), treeId.String())
+	return s.treeHandler.AddTreeMeasurement(ctx)
+}
+
+func (s *Server) GetEstateIdTreeTreeIdObservations(ctx echo.Context, id uuid.UUID, treeId uuid.UUID) error {
+	ctx.SetParamNames("id", "treeId")
+	ctx.SetParamValues(id.String(), treeId.String())
+	return s.treeHandler.GetTreeObservations(ctx)
+}
+
+func (s *Server) PostEstateIdTreeTreeIdObservations(ctx echo.Context, id uuid.UUID, treeId uuid.UUID) error {
+	ctx.SetParamNames("id", "treeId")
+	ctx.SetParamValues(id.String(), treeId.String())
+	return s.treeHandler.AddTreeObservation(ctx)
+}
+
+func (s *Server) GetEstateIdTreeTreeIdHarvests(ctx echo.Context, id uuid.UUID, treeId uuid.UUID) error {
+	ctx.SetParamNames("id", "treeId")
+	ctx.SetParamValues(id.String(), treeId.String())
+	return s.treeHandler.GetTreeHarvests(ctx)
+}
+
+func (s *Server) PostEstateIdHarvests(ctx echo.Context, id uuid.UUID) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.treeHandler.AddHarvests(ctx)
+}
+
+func (s *Server) GetEstateIdAnomalies(ctx echo.Context, id uuid.UUID, params generated.GetEstateIdAnomaliesParams) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.treeHandler.ListAnomalies(ctx)
+}
+
+func (s *Server) PostEstateIdAnomaliesDetect(ctx echo.Context, id uuid.UUID) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.treeHandler.DetectAnomalies(ctx)
+}
+
+func (s *Server) PostEstateIdAnomaliesAnomalyIdAcknowledge(ctx echo.Context, id uuid.UUID, anomalyId uuid.UUID) error {
+	ctx.SetParamNames("id", "anomalyId")
+	ctx.SetParamValues(id.String(), anomalyId.String())
+	return s.treeHandler.AcknowledgeAnomaly(ctx)
+}
+
+func (s *Server) PostEstateIdAnomaliesAnomalyIdDismiss(ctx echo.Context, id uuid.UUID, anomalyId uuid.UUID) error {
+	ctx.SetParamNames("id", "anomalyId")
+	ctx.SetParamValues(id.String(), anomalyId.String())
+	return s.treeHandler.DismissAnomaly(ctx)
+}
+
+func (s *Server) GetEstateIdYield(ctx echo.Context, id uuid.UUID, params generated.GetEstateIdYieldParams) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.estateHandler.GetEstateYield(ctx)
+}
+
+func (s *Server) GetEstateIdGaps(ctx echo.Context, id uuid.UUID, params generated.GetEstateIdGapsParams) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.treeHandler.GetEstateGaps(ctx)
+}
+
+func (s *Server) GetEstateIdForecast(ctx echo.Context, id uuid.UUID, params generated.GetEstateIdForecastParams) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.treeHandler.GetEstateForecast(ctx)
+}
+
+func (s *Server) GetEstateIdTrees(ctx echo.Context, id uuid.UUID, params generated.GetEstateIdTreesParams) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.treeHandler.ListTrees(ctx)
+}
+
+func (s *Server) GetEstateIdTreesNearby(ctx echo.Context, id uuid.UUID, params generated.GetEstateIdTreesNearbyParams) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.treeHandler.GetNearbyTrees(ctx)
+}
+
+func (s *Server) GetEstateIdTreesExport(ctx echo.Context, id uuid.UUID, params generated.GetEstateIdTreesExportParams) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.treeHandler.ExportTrees(ctx)
+}
+
+func (s *Server) GetEstateIdHeatmapPng(ctx echo.Context, id uuid.UUID, params generated.GetEstateIdHeatmapPngParams) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.treeHandler.HeatmapPNG(ctx)
+}
+
+func (s *Server) GetEstateIdHeatmapSvg(ctx echo.Context, id uuid.UUID, params generated.GetEstateIdHeatmapSvgParams) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.treeHandler.HeatmapSVG(ctx)
+}
+
+func (s *Server) GetEstateIdTilesZXY(ctx echo.Context, id uuid.UUID, z int, x int, y string, params generated.GetEstateIdTilesZXYParams) error {
+	ctx.SetParamNames("id", "z", "x", "y")
+	ctx.SetParamValues(id.String(), strconv.Itoa(z), strconv.Itoa(x), y)
+	return s.treeHandler.GetTile(ctx)
+}
+
+func (s *Server) GetEstateIdReport(ctx echo.Context, id uuid.UUID, params generated.GetEstateIdReportParams) error {
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(id.String())
+	return s.treeHandler.GetEstateReport(ctx)
+}
+
+func (s *Server) GetEstateIdPlotXY(ctx echo.Context, id uuid.UUID, x int, y int) error {
+	ctx.SetParamNames("id", "x", "y")
+	ctx.SetParamValues(id.String(), strconv.Itoa(x), strconv.Itoa(y))
+	return s.treeHandler.GetTreeAtPlot(ctx)
+}
+
+func (s *Server) GetSpecies(ctx echo.Context) error {
+	return s.speciesHandler.ListSpecies(ctx)
+}
+
+func (s *Server) PostSpecies(ctx echo.Context) error {
+	return s.speciesHandler.CreateSpecies(ctx)
+}
+
 func (s *Server) HelloHandler(ctx echo.Context) error {
 	return handlers.HelloHandler(ctx)
 }
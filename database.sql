@@ -1,15 +1,196 @@
 -- database.sql
+--
+-- The schema is created on a fresh database volume. Every statement is
+-- idempotent, so running the file again upgrades an existing database to the
+-- current schema: columns added after a table was first created are added by
+-- the ALTER TABLE statements following it. Run `make migrate` after pulling.
 
 CREATE TABLE IF NOT EXISTS estates (
     id UUID PRIMARY KEY,
     width INT NOT NULL,
-    length INT NOT NULL
+    length INT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    code TEXT UNIQUE,
+    company TEXT NOT NULL DEFAULT '',
+    region TEXT NOT NULL DEFAULT '',
+    notes TEXT NOT NULL DEFAULT '',
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    archived_at TIMESTAMPTZ,
+    -- Map location of the outer corner of plot (1,1), all NULL when unknown
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    rotation DOUBLE PRECISION,
+    CONSTRAINT estates_location_check
+        CHECK ((latitude IS NULL) = (longitude IS NULL) AND (latitude IS NULL) = (rotation IS NULL))
 );
 
+ALTER TABLE estates
+    ADD COLUMN IF NOT EXISTS name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS code TEXT UNIQUE,
+    ADD COLUMN IF NOT EXISTS company TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS region TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS notes TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS rotation DOUBLE PRECISION;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'estates'::regclass AND conname = 'estates_location_check') THEN
+        ALTER TABLE estates ADD CONSTRAINT estates_location_check
+            CHECK ((latitude IS NULL) = (longitude IS NULL) AND (latitude IS NULL) = (rotation IS NULL));
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_estates_created_at ON estates (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_estates_area ON estates ((width::BIGINT * length), id);
+CREATE INDEX IF NOT EXISTS idx_estates_company ON estates (company);
+CREATE INDEX IF NOT EXISTS idx_estates_tags ON estates USING gin (tags);
+
+CREATE TABLE IF NOT EXISTS species (
+    code TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    min_height INT NOT NULL CHECK (min_height >= 1),
+    max_height INT NOT NULL CHECK (max_height >= min_height),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO species (code, name, min_height, max_height) VALUES
+    ('dura', 'Dura', 1, 30),
+    ('tenera', 'Tenera', 1, 25),
+    ('pisifera', 'Pisifera', 1, 20)
+ON CONFLICT (code) DO NOTHING;
+
+-- Lets GiST indexes combine the estate ID with the location of trees
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
 CREATE TABLE IF NOT EXISTS trees (
     id UUID PRIMARY KEY,
-    estate_id UUID REFERENCES estates(id),
+    estate_id UUID REFERENCES estates(id) ON DELETE CASCADE,
+    x INT NOT NULL,
+    y INT NOT NULL,
+    height INT NOT NULL,
+    species TEXT REFERENCES species(code),
+    planted_on DATE,
+    health_status TEXT NOT NULL DEFAULT 'healthy'
+        CHECK (health_status IN ('healthy', 'ganoderma', 'oryctes', 'nutrient_deficiency', 'other', 'dead')),
+    location POINT GENERATED ALWAYS AS (point(x, y)) STORED
+);
+
+ALTER TABLE trees
+    ADD COLUMN IF NOT EXISTS species TEXT REFERENCES species(code),
+    ADD COLUMN IF NOT EXISTS planted_on DATE,
+    ADD COLUMN IF NOT EXISTS health_status TEXT NOT NULL DEFAULT 'healthy'
+        CHECK (health_status IN ('healthy', 'ganoderma', 'oryctes', 'nutrient_deficiency', 'other', 'dead')),
+    ADD COLUMN IF NOT EXISTS location POINT GENERATED ALWAYS AS (point(x, y)) STORED;
+
+-- Trees are deleted with their estate; the first schema kept them
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'trees'::regclass AND contype = 'f'
+                   AND confrelid = 'estates'::regclass AND confdeltype = 'c') THEN
+        ALTER TABLE trees DROP CONSTRAINT IF EXISTS trees_estate_id_fkey;
+        ALTER TABLE trees ADD CONSTRAINT trees_estate_id_fkey
+            FOREIGN KEY (estate_id) REFERENCES estates(id) ON DELETE CASCADE;
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_trees_estate_id ON trees (estate_id);
+-- Creating it on an existing database fails while two trees share a plot;
+-- those must be moved or deleted first
+CREATE UNIQUE INDEX IF NOT EXISTS idx_trees_estate_plot ON trees (estate_id, x, y);
+CREATE INDEX IF NOT EXISTS idx_trees_estate_row ON trees (estate_id, y, x, id);
+CREATE INDEX IF NOT EXISTS idx_trees_estate_height ON trees (estate_id, height, id);
+CREATE INDEX IF NOT EXISTS idx_trees_estate_species ON trees (estate_id, species);
+CREATE INDEX IF NOT EXISTS idx_trees_estate_health ON trees (estate_id, health_status);
+-- Neighbour queries: points within a circle and nearest first, per estate
+CREATE INDEX IF NOT EXISTS idx_trees_estate_location ON trees USING gist (estate_id, location);
+
+-- Height summary of each estate, updated in the transaction of every change to
+-- its trees so that estate stats need not read them. height_counts[h] counts
+-- the trees h meters tall, up to 30; taller trees are only counted in
+-- other_heights, and the stats of their estate are computed from its trees.
+-- Estates without a row are also computed from their trees until
+-- `go run ./cmd/rebuild-stats` creates it; the command also repairs drift.
+CREATE TABLE IF NOT EXISTS estate_height_summaries (
+    estate_id UUID PRIMARY KEY REFERENCES estates(id) ON DELETE CASCADE,
+    tree_count INT NOT NULL DEFAULT 0,
+    height_sum BIGINT NOT NULL DEFAULT 0,
+    min_height INT,
+    max_height INT,
+    other_heights INT NOT NULL DEFAULT 0,
+    height_counts INT[] NOT NULL DEFAULT array_fill(0, ARRAY[30]),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS tree_measurements (
+    id UUID PRIMARY KEY,
+    tree_id UUID NOT NULL REFERENCES trees(id) ON DELETE CASCADE,
+    height INT NOT NULL,
+    measured_at TIMESTAMPTZ NOT NULL,
+    source TEXT NOT NULL CHECK (source IN ('manual', 'drone', 'lidar')),
+    surveyor TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tree_measurements_tree ON tree_measurements (tree_id, measured_at, created_at);
+
+-- Trees planted before measurements were recorded start with their stored height
+INSERT INTO tree_measurements (id, tree_id, height, measured_at, source)
+SELECT md5(t.id::TEXT || ':initial')::UUID, t.id, t.height, NOW(), 'manual'
+FROM trees t
+WHERE NOT EXISTS (SELECT 1 FROM tree_measurements m WHERE m.tree_id = t.id);
+
+CREATE TABLE IF NOT EXISTS tree_observations (
+    id UUID PRIMARY KEY,
+    tree_id UUID NOT NULL REFERENCES trees(id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('healthy', 'ganoderma', 'oryctes', 'nutrient_deficiency', 'other', 'dead')),
+    severity INT NOT NULL CHECK (severity BETWEEN 0 AND 5),
+    notes TEXT NOT NULL DEFAULT '',
+    observer TEXT NOT NULL DEFAULT '',
+    observed_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tree_observations_tree ON tree_observations (tree_id, observed_at, created_at);
+
+CREATE TABLE IF NOT EXISTS harvests (
+    id UUID PRIMARY KEY,
+    estate_id UUID NOT NULL REFERENCES estates(id) ON DELETE CASCADE,
+    tree_id UUID REFERENCES trees(id) ON DELETE SET NULL,
     x INT NOT NULL,
     y INT NOT NULL,
-    height INT NOT NULL
-);
\ No newline at end of file
+    harvested_on DATE NOT NULL,
+    bunches INT NOT NULL CHECK (bunches >= 1),
+    weight_kg NUMERIC(10, 2) NOT NULL CHECK (weight_kg > 0),
+    harvester TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_harvests_estate_date ON harvests (estate_id, harvested_on);
+CREATE INDEX IF NOT EXISTS idx_harvests_tree ON harvests (tree_id, harvested_on, created_at);
+
+-- One row per tree found standing out by the anomaly detection; each run
+-- updates the rows of the trees it finds and keeps their review status
+CREATE TABLE IF NOT EXISTS tree_anomalies (
+    id UUID PRIMARY KEY,
+    estate_id UUID NOT NULL REFERENCES estates(id) ON DELETE CASCADE,
+    tree_id UUID NOT NULL UNIQUE REFERENCES trees(id) ON DELETE CASCADE,
+    x INT NOT NULL,
+    y INT NOT NULL,
+    height INT NOT NULL,
+    score FLOAT8 NOT NULL,
+    reasons JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'acknowledged', 'dismissed')),
+    note TEXT NOT NULL DEFAULT '',
+    detected_at TIMESTAMPTZ NOT NULL,
+    reviewed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_tree_anomalies_estate ON tree_anomalies (estate_id, status, score DESC, id);
@@ -0,0 +1,296 @@
+// Package forecast predicts the fresh fruit bunch (FFB) yield of an estate
+// from the age, height and harvest history of its trees.
+//
+// The model is deliberately simple so that every forecast can be explained:
+// each palm is expected to yield along a fixed age curve, scaled by how the
+// palm performed against that curve over the last quarters. The interval comes
+// from how much the estate's yield strayed from the curve quarter to quarter.
+package forecast
+
+import (
+	"math"
+	"sawitpro-recruitment/models"
+)
+
+// DefaultAgeCurve is the expected yield of a palm by age: nothing before the
+// first bunches at about 3 years, a peak of 220 kg a year between 10 and 18
+// years, then a slow decline.
+var DefaultAgeCurve = []models.AgeCurvePoint{
+	{AgeYears: 0, KgPerYear: 0},
+	{AgeYears: 2.5, KgPerYear: 0},
+	{AgeYears: 3, KgPerYear: 30},
+	{AgeYears: 4, KgPerYear: 90},
+	{AgeYears: 5, KgPerYear: 140},
+	{AgeYears: 6, KgPerYear: 170},
+	{AgeYears: 8, KgPerYear: 200},
+	{AgeYears: 10, KgPerYear: 220},
+	{AgeYears: 18, KgPerYear: 220},
+	{AgeYears: 22, KgPerYear: 190},
+	{AgeYears: 25, KgPerYear: 160},
+	{AgeYears: 30, KgPerYear: 120},
+}
+
+const (
+	// QuarterDays is the length of a history quarter.
+	QuarterDays = 91
+	// HistoryQuarters is the number of quarters of harvests the trend is fitted on.
+	HistoryQuarters = 4
+
+	daysPerYear = 365.0
+)
+
+// Model holds the parameters of the forecast.
+type Model struct {
+	AgeCurve            []models.AgeCurvePoint
+	HeightGrowth        float64   // Meters a palm grows in a year, to age palms without a planting date
+	QuarterWeights      []float64 // Weight of each history quarter in the trend, oldest first
+	CredibilityHarvests int       // Harvests at which a palm's own history weighs as much as the estate's
+	DefaultSigma        float64   // Spread used when the history is too short to measure it
+	MinSigma            float64   // Smallest spread used, so intervals never collapse
+	Confidence          float64   // Coverage of the interval
+}
+
+// DefaultModel returns the model used by the API.
+func DefaultModel() *Model {
+	return &Model{
+		AgeCurve:            DefaultAgeCurve,
+		HeightGrowth:        0.6,
+		QuarterWeights:      []float64{1, 2, 3, 4},
+		CredibilityHarvests: 6,
+		DefaultSigma:        0.25,
+		MinSigma:            0.05,
+		Confidence:          0.9,
+	}
+}
+
+// Tree is a palm as seen by the model.
+type Tree struct {
+	AgeYears *float64 // Age at the forecast date, nil when the planting date is unknown
+	Height   int
+	Dead     bool
+	History  []float64 // Weight harvested in each history quarter, oldest first
+	Harvests int       // Number of harvests in the history
+}
+
+// Result is a forecast with the figures it was derived from.
+type Result struct {
+	PointKg         float64
+	LowerKg         float64
+	UpperKg         float64
+	Sigma           float64
+	MeanPerformance float64
+	QuarterRatios   []float64 // Actual over expected estate yield, for quarters with harvests recorded
+	AgedByHeight    int
+	Dead            int
+	WithHistory     int
+	Harvests        int
+	HistoryWeightKg float64
+}
+
+// KgPerYear returns the yield a palm of the given age is expected to produce
+// in a year, interpolating the age curve.
+func (m *Model) KgPerYear(age float64) float64 {
+	curve := m.AgeCurve
+	if len(curve) == 0 {
+		return 0
+	}
+	if age <= curve[0].AgeYears {
+		return curve[0].KgPerYear
+	}
+	for i := 1; i < len(curve); i++ {
+		if age <= curve[i].AgeYears {
+			prev, next := curve[i-1], curve[i]
+			share := (age - prev.AgeYears) / (next.AgeYears - prev.AgeYears)
+			return prev.KgPerYear + share*(next.KgPerYear-prev.KgPerYear)
+		}
+	}
+	return curve[len(curve)-1].KgPerYear
+}
+
+// expectedKg is the yield expected from a palm over a number of days centred
+// on the given age.
+func (m *Model) expectedKg(midAge float64, days float64) float64 {
+	return m.KgPerYear(midAge) * days / daysPerYear
+}
+
+// age returns the age of a palm, estimated from its height when the planting
+// date is unknown.
+func (m *Model) age(tree *Tree) float64 {
+	if tree.AgeYears != nil {
+		return *tree.AgeYears
+	}
+	return float64(tree.Height) / m.HeightGrowth
+}
+
+// historyAge returns the age of a palm in the middle of history quarter q,
+// counted from the oldest, when the history has the given number of quarters.
+func historyAge(age float64, q, quarters int) float64 {
+	return age - (float64(quarters-q)-0.5)*QuarterDays/daysPerYear
+}
+
+// Forecast predicts the yield of the trees over the horizonDays following the
+// end of their history.
+//
+// Quarters before the first harvest recorded on the estate are left out, since
+// missing records there say nothing about the palms. Each palm's performance
+// factor is its weighted actual over expected yield in the remaining quarters,
+// shrunk towards the estate's own factor when it has few harvests. Dead palms
+// yield nothing.
+func (m *Model) Forecast(trees []*Tree, horizonDays int) *Result {
+	result := &Result{}
+	quarters := 0
+	for _, tree := range trees {
+		quarters = max(quarters, len(tree.History))
+	}
+
+	// Estate totals per quarter, and the first quarter with harvests
+	actual := make([]float64, quarters)
+	expected := make([]float64, quarters)
+	for _, tree := range trees {
+		if tree.AgeYears == nil {
+			result.AgedByHeight++
+		}
+		if tree.Harvests > 0 {
+			result.WithHistory++
+		}
+		result.Harvests += tree.Harvests
+		age := m.age(tree)
+		for q, kg := range tree.History {
+			result.HistoryWeightKg += kg
+			// Dead palms are expected to yield nothing, so their past harvests
+			// would only inflate the estate's ratios
+			if !tree.Dead {
+				actual[q] += kg
+				expected[q] += m.expectedKg(historyAge(age, q, quarters), QuarterDays)
+			}
+		}
+	}
+	first := 0
+	for first < quarters && actual[first] == 0 {
+		first++
+	}
+
+	estate := 1.0
+	var weightedActual, weightedExpected float64
+	for q := first; q < quarters; q++ {
+		weightedActual += m.quarterWeight(q, quarters) * actual[q]
+		weightedExpected += m.quarterWeight(q, quarters) * expected[q]
+	}
+	if weightedExpected > 0 && weightedActual > 0 {
+		estate = weightedActual / weightedExpected
+	}
+
+	living := 0
+	performance := 0.0
+	horizonAge := float64(horizonDays) / 2 / daysPerYear
+	for _, tree := range trees {
+		if tree.Dead {
+			result.Dead++
+			continue
+		}
+		factor := m.performance(tree, estate, first, quarters)
+		living++
+		performance += factor
+		result.PointKg += factor * m.expectedKg(m.age(tree)+horizonAge, float64(horizonDays))
+	}
+	if living > 0 {
+		result.MeanPerformance = performance / float64(living)
+	}
+
+	// Spread of the estate's yield around the curve, quarter to quarter
+	logRatios := []float64{}
+	result.QuarterRatios = []float64{}
+	for q := first; q < quarters; q++ {
+		if expected[q] > 0 && actual[q] > 0 {
+			ratio := actual[q] / expected[q]
+			result.QuarterRatios = append(result.QuarterRatios, ratio)
+			logRatios = append(logRatios, math.Log(ratio))
+		}
+	}
+	// Spread measured on few quarters is uncertain itself, hence the Student
+	// quantile; it is widened for predicting a new quarter from their mean
+	result.Sigma = m.DefaultSigma
+	z := normalQuantile(0.5 + m.Confidence/2)
+	if n := len(logRatios); n >= 2 {
+		result.Sigma = math.Max(stddev(logRatios)*math.Sqrt(1+1/float64(n)), m.MinSigma)
+		z = studentQuantile(0.5+m.Confidence/2, n-1)
+	}
+
+	result.LowerKg = result.PointKg * math.Exp(-z*result.Sigma)
+	result.UpperKg = result.PointKg * math.Exp(z*result.Sigma)
+	return result
+}
+
+// performance returns the factor applied to the age curve of a palm, from its
+// history quarters starting at first, shrunk towards the estate factor.
+func (m *Model) performance(tree *Tree, estate float64, first, quarters int) float64 {
+	age := m.age(tree)
+	var weightedActual, weightedExpected float64
+	for q := first; q < len(tree.History); q++ {
+		weight := m.quarterWeight(q, quarters)
+		weightedActual += weight * tree.History[q]
+		weightedExpected += weight * m.expectedKg(historyAge(age, q, quarters), QuarterDays)
+	}
+	if weightedExpected == 0 {
+		return estate // Too young to have been expected to yield
+	}
+	n := float64(tree.Harvests)
+	k := float64(m.CredibilityHarvests)
+	return (n*weightedActual/weightedExpected + k*estate) / (n + k)
+}
+
+// quarterWeight returns the weight of history quarter q, counted from the
+// oldest, when the history has the given number of quarters.
+func (m *Model) quarterWeight(q, quarters int) float64 {
+	if i := q + len(m.QuarterWeights) - quarters; i >= 0 && i < len(m.QuarterWeights) {
+		return m.QuarterWeights[i]
+	}
+	return 1
+}
+
+// Describe returns the parameters of the model with the spread of a forecast.
+func (m *Model) Describe(result *Result) models.ForecastModel {
+	return models.ForecastModel{
+		Name:                "age-curve-trend",
+		AgeCurve:            m.AgeCurve,
+		HeightGrowth:        m.HeightGrowth,
+		HistoryQuarters:     HistoryQuarters,
+		QuarterWeights:      m.QuarterWeights,
+		CredibilityHarvests: m.CredibilityHarvests,
+		ResidualSigma:       result.Sigma,
+		Confidence:          m.Confidence,
+	}
+}
+
+// stddev returns the sample standard deviation of values.
+func stddev(values []float64) float64 {
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sum / float64(len(values)-1))
+}
+
+// normalQuantile returns the quantile of the standard normal distribution at p.
+func normalQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+// studentQuantile approximates the quantile of Student's t distribution with
+// dof degrees of freedom at p, with the Cornish-Fisher expansion around the
+// normal quantile. It is within 1% from 2 degrees of freedom.
+func studentQuantile(p float64, dof int) float64 {
+	z := normalQuantile(p)
+	v := float64(dof)
+	z3, z5, z7, z9 := math.Pow(z, 3), math.Pow(z, 5), math.Pow(z, 7), math.Pow(z, 9)
+	return z +
+		(z3+z)/(4*v) +
+		(5*z5+16*z3+3*z)/(96*v*v) +
+		(3*z7+19*z5+17*z3-15*z)/(384*v*v*v) +
+		(79*z9+776*z7+1482*z5-1920*z3-945*z)/(92160*v*v*v*v)
+}
@@ -0,0 +1,129 @@
+package forecast
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_KgPerYear(t *testing.T) {
+	m := DefaultModel()
+
+	assert.Equal(t, 0.0, m.KgPerYear(1))
+	assert.Equal(t, 60.0, m.KgPerYear(3.5))
+	assert.Equal(t, 220.0, m.KgPerYear(12))
+	assert.Equal(t, 205.0, m.KgPerYear(20))
+	// Flat after the last point of the curve
+	assert.Equal(t, 120.0, m.KgPerYear(40))
+}
+
+func TestStudentQuantile(t *testing.T) {
+	// Two-sided 90% critical values from the t table
+	assert.InDelta(t, 2.920, studentQuantile(0.95, 2), 0.03)
+	assert.InDelta(t, 2.353, studentQuantile(0.95, 3), 0.02)
+	assert.InDelta(t, 1.812, studentQuantile(0.95, 10), 0.02)
+	assert.InDelta(t, 1.645, normalQuantile(0.95), 0.001)
+}
+
+func TestModel_Forecast_WithoutHistory(t *testing.T) {
+	m := DefaultModel()
+	age := 12.0
+
+	result := m.Forecast([]*Tree{
+		{AgeYears: &age, Height: 10},
+		// Aged by height: 6 meters at 0.6 meters a year is 10 years
+		{Height: 6},
+		{AgeYears: &age, Height: 10, Dead: true},
+	}, 365)
+
+	// Both living palms are at the peak of the curve
+	assert.InDelta(t, 440, result.PointKg, 1e-9)
+	assert.Equal(t, 1, result.AgedByHeight)
+	assert.Equal(t, 1, result.Dead)
+	assert.Equal(t, 1.0, result.MeanPerformance)
+	assert.Equal(t, m.DefaultSigma, result.Sigma)
+	assert.Less(t, result.LowerKg, result.PointKg)
+	assert.Greater(t, result.UpperKg, result.PointKg)
+	assert.Empty(t, result.QuarterRatios)
+}
+
+func TestModel_Forecast_SkipsQuartersBeforeFirstHarvest(t *testing.T) {
+	m := DefaultModel()
+	age := 12.0
+	quarterKg := 220.0 * QuarterDays / daysPerYear
+
+	// Harvests were only recorded for the last two quarters, at half the curve
+	result := m.Forecast([]*Tree{
+		{AgeYears: &age, Height: 10, History: []float64{0, 0, quarterKg / 2, quarterKg / 2}, Harvests: 100},
+	}, QuarterDays)
+
+	assert.InDeltaSlice(t, []float64{0.5, 0.5}, result.QuarterRatios, 1e-9)
+	assert.InDelta(t, 0.5, result.MeanPerformance, 1e-9)
+	assert.InDelta(t, quarterKg/2, result.PointKg, 1e-9)
+	assert.Equal(t, m.MinSigma, result.Sigma)
+}
+
+// syntheticEstate simulates the harvests of an estate over HistoryQuarters
+// quarters and the quarter that follows. Each palm yields along the default age
+// curve, scaled by its own factor, an estate-wide shock per quarter and noise.
+func syntheticEstate(rng *rand.Rand, m *Model, trees int) (history []*Tree, actualKg, curveKg float64) {
+	lognormal := func(mean, sigma float64) float64 {
+		return mean * math.Exp(sigma*rng.NormFloat64()-sigma*sigma/2)
+	}
+	shocks := make([]float64, HistoryQuarters+1)
+	for q := range shocks {
+		shocks[q] = lognormal(1, 0.1)
+	}
+
+	for i := 0; i < trees; i++ {
+		age := 3 + rng.Float64()*22
+		tree := &Tree{AgeYears: &age, Height: int(age * m.HeightGrowth), History: make([]float64, HistoryQuarters)}
+		if rng.Float64() < 0.02 {
+			tree.Dead = true
+		}
+		factor := lognormal(0.8, 0.25)
+		for q := 0; q <= HistoryQuarters; q++ {
+			kg := factor * shocks[q] * lognormal(1, 0.3) * m.expectedKg(historyAge(age, q, HistoryQuarters), QuarterDays)
+			if q < HistoryQuarters {
+				tree.History[q] = kg
+				tree.Harvests += 3
+			} else if !tree.Dead {
+				actualKg += kg
+				curveKg += m.expectedKg(age+float64(QuarterDays)/2/daysPerYear, QuarterDays)
+			}
+		}
+		history = append(history, tree)
+	}
+	return history, actualKg, curveKg
+}
+
+// TestModel_Forecast_Backtest fits the model on simulated estates and checks
+// its forecast of the next quarter against what the estates then yielded.
+func TestModel_Forecast_Backtest(t *testing.T) {
+	m := DefaultModel()
+	rng := rand.New(rand.NewSource(42))
+
+	const runs = 200
+	var modelError, curveError float64
+	covered := 0
+	for run := 0; run < runs; run++ {
+		trees, actualKg, curveKg := syntheticEstate(rng, m, 200)
+		result := m.Forecast(trees, QuarterDays)
+
+		modelError += math.Abs(result.PointKg-actualKg) / actualKg
+		curveError += math.Abs(curveKg-actualKg) / actualKg
+		if result.LowerKg <= actualKg && actualKg <= result.UpperKg {
+			covered++
+		}
+	}
+	modelError /= runs
+	curveError /= runs
+
+	t.Logf("MAPE %.3f (age curve alone %.3f), %d%% of actual yields within the interval", modelError, curveError, covered*100/runs)
+	assert.Less(t, modelError, 0.15)
+	assert.Less(t, modelError, curveError/2)
+	// The interval is meant to cover 90%
+	assert.GreaterOrEqual(t, covered, runs*4/5)
+}
@@ -0,0 +1,232 @@
+// Package gaps finds the empty plots of an estate and proposes where to
+// replant.
+//
+// Estates may hold billions of plots, so plots are never enumerated one by one.
+// Each row is cut into runs of empty plots between its trees, and runs that
+// touch across rows are joined into regions with a union-find. Work and memory
+// grow with the number of trees and rows, not with the area.
+package gaps
+
+import (
+	"math"
+	"sawitpro-recruitment/models"
+	"sort"
+)
+
+// run is a stretch of empty plots in a row, from x1 to x2 included.
+type run struct {
+	x1, x2 int
+	node   int // Union-find node of the run
+}
+
+// regions joins runs into regions. Each root node holds the totals of its region.
+type regions struct {
+	parent []int
+	region []models.GapRegion
+}
+
+// add creates a node for a run in row y and returns it.
+func (r *regions) add(y int, x1, x2 int) int {
+	node := len(r.parent)
+	r.parent = append(r.parent, node)
+	r.region = append(r.region, models.GapRegion{X1: x1, Y1: y, X2: x2, Y2: y, Plots: x2 - x1 + 1})
+	return node
+}
+
+// find returns the root of a node, compressing the path to it.
+func (r *regions) find(node int) int {
+	for r.parent[node] != node {
+		r.parent[node] = r.parent[r.parent[node]]
+		node = r.parent[node]
+	}
+	return node
+}
+
+// union joins the regions of two nodes.
+func (r *regions) union(a, b int) {
+	a, b = r.find(a), r.find(b)
+	if a == b {
+		return
+	}
+	if r.region[a].Plots < r.region[b].Plots {
+		a, b = b, a
+	}
+	r.parent[b] = a
+	ra, rb := &r.region[a], &r.region[b]
+	ra.X1, ra.Y1 = min(ra.X1, rb.X1), min(ra.Y1, rb.Y1)
+	ra.X2, ra.Y2 = max(ra.X2, rb.X2), max(ra.Y2, rb.Y2)
+	ra.Plots += rb.Plots
+}
+
+// emptyRuns cuts a row of the given width into runs between the trees at xs,
+// which are sorted.
+func emptyRuns(width int, xs []int) []run {
+	runs := []run{}
+	start := 1
+	for _, x := range xs {
+		if x > start {
+			runs = append(runs, run{x1: start, x2: x - 1})
+		}
+		start = x + 1
+	}
+	if start <= width {
+		runs = append(runs, run{x1: start, x2: width})
+	}
+	return runs
+}
+
+// Analyze reports the gaps of an estate from the columns of its trees per row,
+// each sorted, and proposes up to limit plots to replant with the given
+// spacing. Regions are listed largest first, also up to limit.
+//
+// With triangular spacing, palms are planted on alternate plots of each row,
+// staggered by one plot from a row to the next, so that every palm stands at
+// the corner of triangles rather than squares. The stagger follows the
+// existing trees: most of them set which plots belong to the pattern. A plot
+// next to an existing tree in its row or column breaks the pattern and is not
+// proposed.
+func Analyze(estate *models.Estate, rows map[int][]int, spacing string, limit int) *models.GapAnalysis {
+	analysis := &models.GapAnalysis{Plots: estate.Width * estate.Length}
+	parity := patternParity(rows)
+
+	plan := &analysis.Replanting
+	plan.Spacing = spacing
+	plan.Plots = []models.Plot{}
+
+	joined := &regions{}
+	var previous []run
+	for y := 1; y <= estate.Length; y++ {
+		xs := rows[y]
+		analysis.Trees += len(xs)
+		runs := emptyRuns(estate.Width, xs)
+
+		// Join the runs sharing a column with a run of the previous row
+		p := 0
+		for i := range runs {
+			runs[i].node = joined.add(y, runs[i].x1, runs[i].x2)
+			for p < len(previous) && previous[p].x2 < runs[i].x1 {
+				p++
+			}
+			for q := p; q < len(previous) && previous[q].x1 <= runs[i].x2; q++ {
+				joined.union(runs[i].node, previous[q].node)
+			}
+			analysis.EmptyPlots += runs[i].x2 - runs[i].x1 + 1
+		}
+		previous = runs
+
+		for _, r := range runs {
+			if spacing == models.SpacingTriangular {
+				proposeTriangular(plan, estate.Width, y, r, parity, rows[y-1], rows[y+1], limit)
+			} else {
+				proposeGrid(plan, y, r, limit)
+			}
+		}
+	}
+
+	for node, parent := range joined.parent {
+		if node == parent {
+			analysis.Regions = append(analysis.Regions, joined.region[node])
+		}
+	}
+	analysis.RegionCount = len(analysis.Regions)
+	sort.Slice(analysis.Regions, func(i, j int) bool {
+		a, b := analysis.Regions[i], analysis.Regions[j]
+		if a.Plots != b.Plots {
+			return a.Plots > b.Plots
+		}
+		if a.Y1 != b.Y1 {
+			return a.Y1 < b.Y1
+		}
+		return a.X1 < b.X1
+	})
+	if len(analysis.Regions) > limit {
+		analysis.Regions = analysis.Regions[:limit]
+	}
+	if analysis.Regions == nil {
+		analysis.Regions = []models.GapRegion{}
+	}
+
+	hectares := estate.AreaHectares()
+	analysis.CoveragePercent = round2(float64(analysis.Trees) / float64(analysis.Plots) * 100)
+	analysis.DensityPerHectare = round2(float64(analysis.Trees) / hectares)
+	plan.DensityPerHectare = round2(float64(analysis.Trees+plan.Total) / hectares)
+	return analysis
+}
+
+// proposeGrid proposes every plot of an empty run.
+func proposeGrid(plan *models.ReplantingPlan, y int, r run, limit int) {
+	plan.Total += r.x2 - r.x1 + 1
+	for x := r.x1; x <= r.x2 && len(plan.Plots) < limit; x++ {
+		plan.Plots = append(plan.Plots, models.Plot{X: x, Y: y})
+	}
+	plan.Truncated = plan.Total > len(plan.Plots)
+}
+
+// proposeTriangular proposes the plots of an empty run that belong to the
+// staggered pattern and have no tree beside them. above and below are the
+// sorted tree columns of the neighbouring rows.
+func proposeTriangular(plan *models.ReplantingPlan, width, y int, r run, parity int, above, below []int, limit int) {
+	first := r.x1
+	if (first+y)%2 != parity {
+		first++
+	}
+	if first > r.x2 {
+		return
+	}
+	candidates := (r.x2-first)/2 + 1
+
+	// Pattern plots beside a tree: the ends of the run and the plots under or
+	// over a tree of the neighbouring rows
+	blocked := []int{}
+	if r.x1 > 1 {
+		blocked = append(blocked, r.x1)
+	}
+	blocked = append(blocked, within(above, r.x1, r.x2)...)
+	blocked = append(blocked, within(below, r.x1, r.x2)...)
+	if r.x2 < width {
+		blocked = append(blocked, r.x2)
+	}
+	skip := map[int]bool{}
+	for _, x := range blocked {
+		if (x+y)%2 == parity && !skip[x] {
+			skip[x] = true
+			candidates--
+		}
+	}
+
+	plan.Total += candidates
+	for x := first; x <= r.x2 && len(plan.Plots) < limit; x += 2 {
+		if !skip[x] {
+			plan.Plots = append(plan.Plots, models.Plot{X: x, Y: y})
+		}
+	}
+	plan.Truncated = plan.Total > len(plan.Plots)
+}
+
+// within returns the values of the sorted xs from x1 to x2 included.
+func within(xs []int, x1, x2 int) []int {
+	from := sort.SearchInts(xs, x1)
+	to := sort.SearchInts(xs, x2+1)
+	return xs[from:to]
+}
+
+// patternParity returns the parity of x + y shared by most trees, which sets
+// the plots of the triangular pattern. It is 0 for an estate without trees, so
+// that the pattern starts at the first plot.
+func patternParity(rows map[int][]int) int {
+	counts := [2]int{}
+	for y, xs := range rows {
+		for _, x := range xs {
+			counts[(x+y)%2]++
+		}
+	}
+	if counts[1] > counts[0] {
+		return 1
+	}
+	return 0
+}
+
+// round2 rounds to two decimals.
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
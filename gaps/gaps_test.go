@@ -0,0 +1,186 @@
+package gaps
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"sawitpro-recruitment/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rowsOf groups plots by row, each row sorted by column, as the handler does.
+func rowsOf(plots ...models.Plot) map[int][]int {
+	rows := map[int][]int{}
+	for _, p := range plots {
+		rows[p.Y] = append(rows[p.Y], p.X)
+	}
+	for _, xs := range rows {
+		sort.Ints(xs)
+	}
+	return rows
+}
+
+func TestAnalyze_Grid(t *testing.T) {
+	// A 5 by 3 estate with a wall of trees in column 3, but for a gap at the top
+	//   y=3  . . . . .
+	//   y=2  . . T . .
+	//   y=1  . T T . .
+	estate := &models.Estate{Width: 5, Length: 3}
+	rows := rowsOf(models.Plot{X: 2, Y: 1}, models.Plot{X: 3, Y: 1}, models.Plot{X: 3, Y: 2})
+
+	analysis := Analyze(estate, rows, models.SpacingGrid, 100)
+
+	assert.Equal(t, 15, analysis.Plots)
+	assert.Equal(t, 3, analysis.Trees)
+	assert.Equal(t, 12, analysis.EmptyPlots)
+	assert.Equal(t, 20.0, analysis.CoveragePercent)
+	assert.Equal(t, 20.0, analysis.DensityPerHectare)
+	// The gap at the top joins both sides of the wall
+	assert.Equal(t, 1, analysis.RegionCount)
+	assert.Equal(t, []models.GapRegion{{X1: 1, Y1: 1, X2: 5, Y2: 3, Plots: 12}}, analysis.Regions)
+
+	plan := analysis.Replanting
+	assert.Equal(t, 12, plan.Total)
+	assert.False(t, plan.Truncated)
+	assert.Equal(t, models.Plot{X: 1, Y: 1}, plan.Plots[0])
+	assert.Equal(t, models.Plot{X: 4, Y: 1}, plan.Plots[1])
+	assert.Equal(t, 100.0, plan.DensityPerHectare)
+}
+
+func TestAnalyze_SeparateRegions(t *testing.T) {
+	// A full wall of trees in column 3 splits the estate in two
+	estate := &models.Estate{Width: 5, Length: 2}
+	rows := rowsOf(models.Plot{X: 3, Y: 1}, models.Plot{X: 3, Y: 2}, models.Plot{X: 4, Y: 1})
+
+	analysis := Analyze(estate, rows, models.SpacingGrid, 1)
+
+	assert.Equal(t, 2, analysis.RegionCount)
+	// Largest first, cut to the limit
+	assert.Equal(t, []models.GapRegion{{X1: 1, Y1: 1, X2: 2, Y2: 2, Plots: 4}}, analysis.Regions)
+	assert.Equal(t, 7, analysis.Replanting.Total)
+	assert.Equal(t, []models.Plot{{X: 1, Y: 1}}, analysis.Replanting.Plots)
+	assert.True(t, analysis.Replanting.Truncated)
+}
+
+func TestAnalyze_Triangular(t *testing.T) {
+	// Most trees stand on odd x + y, which sets the stagger
+	//   y=3  . . T . .
+	//   y=2  T . . . .
+	//   y=1  . T . . .
+	estate := &models.Estate{Width: 5, Length: 3}
+	rows := rowsOf(models.Plot{X: 2, Y: 1}, models.Plot{X: 1, Y: 2}, models.Plot{X: 3, Y: 3})
+
+	analysis := Analyze(estate, rows, models.SpacingTriangular, 100)
+
+	// The tree at (3,3) is off the pattern and blocks (3,2), (2,3) and (4,3)
+	assert.Equal(t, []models.Plot{{X: 4, Y: 1}, {X: 5, Y: 2}}, analysis.Replanting.Plots)
+	assert.Equal(t, 2, analysis.Replanting.Total)
+	assert.Equal(t, models.SpacingTriangular, analysis.Replanting.Spacing)
+}
+
+func TestAnalyze_EmptyEstate(t *testing.T) {
+	estate := &models.Estate{Width: 4, Length: 4}
+
+	analysis := Analyze(estate, map[int][]int{}, models.SpacingTriangular, 100)
+
+	assert.Equal(t, 16, analysis.EmptyPlots)
+	assert.Equal(t, 0.0, analysis.CoveragePercent)
+	assert.Equal(t, []models.GapRegion{{X1: 1, Y1: 1, X2: 4, Y2: 4, Plots: 16}}, analysis.Regions)
+	// The pattern starts at the first plot
+	assert.Equal(t, 8, analysis.Replanting.Total)
+	assert.Equal(t, models.Plot{X: 1, Y: 1}, analysis.Replanting.Plots[0])
+	assert.Equal(t, models.Plot{X: 2, Y: 2}, analysis.Replanting.Plots[2])
+}
+
+func TestAnalyze_LargestEstate(t *testing.T) {
+	// 2.5 billion plots are analysed row by row, without visiting each plot
+	estate := &models.Estate{Width: 50000, Length: 50000}
+	rows := rowsOf(models.Plot{X: 25000, Y: 25000})
+
+	analysis := Analyze(estate, rows, models.SpacingTriangular, 10)
+
+	assert.Equal(t, 50000*50000-1, analysis.EmptyPlots)
+	assert.Equal(t, 1, analysis.RegionCount)
+	assert.Len(t, analysis.Replanting.Plots, 10)
+	// Half the plots, less the tree's own; the plots beside it are off the pattern
+	assert.Equal(t, 50000*50000/2-1, analysis.Replanting.Total)
+}
+
+// bruteForce analyses an estate plot by plot, for comparison.
+func bruteForce(width, length int, rows map[int][]int, spacing string) (empty int, sizes []int, proposed []models.Plot) {
+	tree := func(x, y int) bool {
+		return x >= 1 && y >= 1 && x <= width && y <= length && sort.SearchInts(rows[y], x) < len(rows[y]) && rows[y][sort.SearchInts(rows[y], x)] == x
+	}
+	parity := patternParity(rows)
+	seen := map[models.Plot]bool{}
+	for y := 1; y <= length; y++ {
+		for x := 1; x <= width; x++ {
+			if tree(x, y) {
+				continue
+			}
+			empty++
+			beside := tree(x-1, y) || tree(x+1, y) || tree(x, y-1) || tree(x, y+1)
+			if spacing == models.SpacingGrid || ((x+y)%2 == parity && !beside) {
+				proposed = append(proposed, models.Plot{X: x, Y: y})
+			}
+			if seen[models.Plot{X: x, Y: y}] {
+				continue
+			}
+			size := 0
+			stack := []models.Plot{{X: x, Y: y}}
+			seen[stack[0]] = true
+			for len(stack) > 0 {
+				p := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				size++
+				for _, n := range []models.Plot{{X: p.X - 1, Y: p.Y}, {X: p.X + 1, Y: p.Y}, {X: p.X, Y: p.Y - 1}, {X: p.X, Y: p.Y + 1}} {
+					if n.X >= 1 && n.Y >= 1 && n.X <= width && n.Y <= length && !tree(n.X, n.Y) && !seen[n] {
+						seen[n] = true
+						stack = append(stack, n)
+					}
+				}
+			}
+			sizes = append(sizes, size)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+	return empty, sizes, proposed
+}
+
+func TestAnalyze_MatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for i := 0; i < 200; i++ {
+		width, length := 1+rng.Intn(12), 1+rng.Intn(12)
+		density := rng.Float64()
+		plots := []models.Plot{}
+		for y := 1; y <= length; y++ {
+			for x := 1; x <= width; x++ {
+				if rng.Float64() < density {
+					plots = append(plots, models.Plot{X: x, Y: y})
+				}
+			}
+		}
+		rows := rowsOf(plots...)
+
+		for _, spacing := range []string{models.SpacingGrid, models.SpacingTriangular} {
+			analysis := Analyze(&models.Estate{Width: width, Length: length}, rows, spacing, width*length)
+			empty, sizes, proposed := bruteForce(width, length, rows, spacing)
+
+			assert.Equal(t, empty, analysis.EmptyPlots)
+			regionSizes := []int{}
+			for _, region := range analysis.Regions {
+				regionSizes = append(regionSizes, region.Plots)
+			}
+			assert.Equal(t, len(sizes), analysis.RegionCount)
+			if len(sizes) > 0 {
+				assert.Equal(t, sizes, regionSizes)
+			}
+			assert.Equal(t, len(proposed), analysis.Replanting.Total)
+			if len(proposed) > 0 {
+				assert.Equal(t, proposed, analysis.Replanting.Plots)
+			}
+		}
+	}
+}
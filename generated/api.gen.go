@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
@@ -19,101 +20,2559 @@ import (
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
+// Defines values for AnomalyReasonCode.
+const (
+	Cohort     AnomalyReasonCode = "cohort"
+	HeightDrop AnomalyReasonCode = "height_drop"
+	Neighbours AnomalyReasonCode = "neighbours"
+)
+
+// Defines values for ReplantingPlanSpacing.
+const (
+	ReplantingPlanSpacingGrid       ReplantingPlanSpacing = "grid"
+	ReplantingPlanSpacingTriangular ReplantingPlanSpacing = "triangular"
+)
+
+// Defines values for TreeHealthStatus.
+const (
+	TreeHealthStatusDead               TreeHealthStatus = "dead"
+	TreeHealthStatusGanoderma          TreeHealthStatus = "ganoderma"
+	TreeHealthStatusHealthy            TreeHealthStatus = "healthy"
+	TreeHealthStatusNutrientDeficiency TreeHealthStatus = "nutrient_deficiency"
+	TreeHealthStatusOryctes            TreeHealthStatus = "oryctes"
+	TreeHealthStatusOther              TreeHealthStatus = "other"
+)
+
+// Defines values for TreeAnomalyStatus.
+const (
+	TreeAnomalyStatusAcknowledged TreeAnomalyStatus = "acknowledged"
+	TreeAnomalyStatusDismissed    TreeAnomalyStatus = "dismissed"
+	TreeAnomalyStatusOpen         TreeAnomalyStatus = "open"
+)
+
+// Defines values for TreeMeasurementSource.
+const (
+	Drone  TreeMeasurementSource = "drone"
+	Lidar  TreeMeasurementSource = "lidar"
+	Manual TreeMeasurementSource = "manual"
+)
+
+// Defines values for TreeNeighbourHealthStatus.
+const (
+	TreeNeighbourHealthStatusDead               TreeNeighbourHealthStatus = "dead"
+	TreeNeighbourHealthStatusGanoderma          TreeNeighbourHealthStatus = "ganoderma"
+	TreeNeighbourHealthStatusHealthy            TreeNeighbourHealthStatus = "healthy"
+	TreeNeighbourHealthStatusNutrientDeficiency TreeNeighbourHealthStatus = "nutrient_deficiency"
+	TreeNeighbourHealthStatusOryctes            TreeNeighbourHealthStatus = "oryctes"
+	TreeNeighbourHealthStatusOther              TreeNeighbourHealthStatus = "other"
+)
+
+// Defines values for TreeObservationStatus.
+const (
+	TreeObservationStatusDead               TreeObservationStatus = "dead"
+	TreeObservationStatusGanoderma          TreeObservationStatus = "ganoderma"
+	TreeObservationStatusHealthy            TreeObservationStatus = "healthy"
+	TreeObservationStatusNutrientDeficiency TreeObservationStatus = "nutrient_deficiency"
+	TreeObservationStatusOryctes            TreeObservationStatus = "oryctes"
+	TreeObservationStatusOther              TreeObservationStatus = "other"
+)
+
+// Defines values for GetEstateParamsSort.
+const (
+	GetEstateParamsSortArea      GetEstateParamsSort = "area"
+	GetEstateParamsSortCreatedAt GetEstateParamsSort = "created_at"
+)
+
+// Defines values for GetEstateParamsOrder.
+const (
+	GetEstateParamsOrderAsc  GetEstateParamsOrder = "asc"
+	GetEstateParamsOrderDesc GetEstateParamsOrder = "desc"
+)
+
+// Defines values for GetEstateIdAnomaliesParamsStatus.
+const (
+	GetEstateIdAnomaliesParamsStatusAcknowledged GetEstateIdAnomaliesParamsStatus = "acknowledged"
+	GetEstateIdAnomaliesParamsStatusDismissed    GetEstateIdAnomaliesParamsStatus = "dismissed"
+	GetEstateIdAnomaliesParamsStatusOpen         GetEstateIdAnomaliesParamsStatus = "open"
+)
+
+// Defines values for GetEstateIdDronePlanParamsRoute.
+const (
+	GetEstateIdDronePlanParamsRouteInspection GetEstateIdDronePlanParamsRoute = "inspection"
+	GetEstateIdDronePlanParamsRouteSurvey     GetEstateIdDronePlanParamsRoute = "survey"
+)
+
+// Defines values for GetEstateIdDronePlanParamsFormat.
+const (
+	GetEstateIdDronePlanParamsFormatJson GetEstateIdDronePlanParamsFormat = "json"
+	GetEstateIdDronePlanParamsFormatXlsx GetEstateIdDronePlanParamsFormat = "xlsx"
+)
+
+// Defines values for GetEstateIdGapsParamsSpacing.
+const (
+	GetEstateIdGapsParamsSpacingGrid       GetEstateIdGapsParamsSpacing = "grid"
+	GetEstateIdGapsParamsSpacingTriangular GetEstateIdGapsParamsSpacing = "triangular"
+)
+
+// Defines values for GetEstateIdHeatmapPngParamsRoute.
+const (
+	GetEstateIdHeatmapPngParamsRouteInspection GetEstateIdHeatmapPngParamsRoute = "inspection"
+	GetEstateIdHeatmapPngParamsRouteSurvey     GetEstateIdHeatmapPngParamsRoute = "survey"
+)
+
+// Defines values for GetEstateIdHeatmapSvgParamsRoute.
+const (
+	Inspection GetEstateIdHeatmapSvgParamsRoute = "inspection"
+	Survey     GetEstateIdHeatmapSvgParamsRoute = "survey"
+)
+
+// Defines values for GetEstateIdReportParamsFormat.
+const (
+	Html GetEstateIdReportParamsFormat = "html"
+	Pdf  GetEstateIdReportParamsFormat = "pdf"
+)
+
+// Defines values for GetEstateIdStatsParamsFormat.
+const (
+	GetEstateIdStatsParamsFormatJson GetEstateIdStatsParamsFormat = "json"
+	GetEstateIdStatsParamsFormatXlsx GetEstateIdStatsParamsFormat = "xlsx"
+)
+
+// Defines values for GetEstateIdStatsTimeseriesParamsMetric.
+const (
+	Count        GetEstateIdStatsTimeseriesParamsMetric = "count"
+	MaxHeight    GetEstateIdStatsTimeseriesParamsMetric = "max_height"
+	MeanHeight   GetEstateIdStatsTimeseriesParamsMetric = "mean_height"
+	MedianHeight GetEstateIdStatsTimeseriesParamsMetric = "median_height"
+	MinHeight    GetEstateIdStatsTimeseriesParamsMetric = "min_height"
+)
+
+// Defines values for GetEstateIdStatsTimeseriesParamsInterval.
+const (
+	Month   GetEstateIdStatsTimeseriesParamsInterval = "month"
+	Quarter GetEstateIdStatsTimeseriesParamsInterval = "quarter"
+	Week    GetEstateIdStatsTimeseriesParamsInterval = "week"
+	Year    GetEstateIdStatsTimeseriesParamsInterval = "year"
+)
+
+// Defines values for GetEstateIdTilesZXYParamsColor.
+const (
+	GetEstateIdTilesZXYParamsColorHealth GetEstateIdTilesZXYParamsColor = "health"
+	GetEstateIdTilesZXYParamsColorHeight GetEstateIdTilesZXYParamsColor = "height"
+)
+
+// Defines values for GetEstateIdTreesParamsSort.
+const (
+	GetEstateIdTreesParamsSortCoordinate GetEstateIdTreesParamsSort = "coordinate"
+	GetEstateIdTreesParamsSortHeight     GetEstateIdTreesParamsSort = "height"
+)
+
+// Defines values for GetEstateIdTreesParamsOrder.
+const (
+	GetEstateIdTreesParamsOrderAsc  GetEstateIdTreesParamsOrder = "asc"
+	GetEstateIdTreesParamsOrderDesc GetEstateIdTreesParamsOrder = "desc"
+)
+
+// Defines values for GetEstateIdTreesParamsHealth.
+const (
+	GetEstateIdTreesParamsHealthDead               GetEstateIdTreesParamsHealth = "dead"
+	GetEstateIdTreesParamsHealthGanoderma          GetEstateIdTreesParamsHealth = "ganoderma"
+	GetEstateIdTreesParamsHealthHealthy            GetEstateIdTreesParamsHealth = "healthy"
+	GetEstateIdTreesParamsHealthNutrientDeficiency GetEstateIdTreesParamsHealth = "nutrient_deficiency"
+	GetEstateIdTreesParamsHealthOryctes            GetEstateIdTreesParamsHealth = "oryctes"
+	GetEstateIdTreesParamsHealthOther              GetEstateIdTreesParamsHealth = "other"
+)
+
+// Defines values for GetEstateIdTreesExportParamsFormat.
+const (
+	GetEstateIdTreesExportParamsFormatCsv     GetEstateIdTreesExportParamsFormat = "csv"
+	GetEstateIdTreesExportParamsFormatGeojson GetEstateIdTreesExportParamsFormat = "geojson"
+	GetEstateIdTreesExportParamsFormatParquet GetEstateIdTreesExportParamsFormat = "parquet"
+	GetEstateIdTreesExportParamsFormatXlsx    GetEstateIdTreesExportParamsFormat = "xlsx"
+)
+
+// Defines values for GetEstateIdYieldParamsFormat.
+const (
+	GetEstateIdYieldParamsFormatJson GetEstateIdYieldParamsFormat = "json"
+	GetEstateIdYieldParamsFormatXlsx GetEstateIdYieldParamsFormat = "xlsx"
+)
+
+// Defines values for GetPortfolioStatsParamsRankBy.
+const (
+	GetPortfolioStatsParamsRankByArea         GetPortfolioStatsParamsRankBy = "area"
+	GetPortfolioStatsParamsRankByCoverage     GetPortfolioStatsParamsRankBy = "coverage"
+	GetPortfolioStatsParamsRankByMeanHeight   GetPortfolioStatsParamsRankBy = "mean_height"
+	GetPortfolioStatsParamsRankByMedianHeight GetPortfolioStatsParamsRankBy = "median_height"
+	GetPortfolioStatsParamsRankByTrees        GetPortfolioStatsParamsRankBy = "trees"
+)
+
+// AgeCurvePoint defines model for AgeCurvePoint.
+type AgeCurvePoint struct {
+	AgeYears *float32 `json:"age_years,omitempty"`
+
+	// KgPerYear Expected FFB weight of one palm over a year at this age
+	KgPerYear *float32 `json:"kg_per_year,omitempty"`
+}
+
+// AnomalyDetection defines model for AnomalyDetection.
+type AnomalyDetection struct {
+	// Anomalies Trees found standing out
+	Anomalies  *int       `json:"anomalies,omitempty"`
+	DetectedAt *time.Time `json:"detected_at,omitempty"`
+
+	// Reasons Anomalies per reason
+	Reasons *map[string]int `json:"reasons,omitempty"`
+
+	// Resolved Open anomalies no longer found, removed
+	Resolved *int `json:"resolved,omitempty"`
+
+	// Trees Trees scored
+	Trees *int `json:"trees,omitempty"`
+}
+
+// AnomalyList defines model for AnomalyList.
+type AnomalyList struct {
+	Anomalies *[]TreeAnomaly `json:"anomalies,omitempty"`
+
+	// Counts Anomalies of the estate per status
+	Counts *map[string]int `json:"counts,omitempty"`
+}
+
+// AnomalyReason defines model for AnomalyReason.
+type AnomalyReason struct {
+	Code *AnomalyReasonCode `json:"code,omitempty"`
+
+	// Expected Previous height, or median height of the neighbours or cohort, in meters
+	Expected *float32 `json:"expected,omitempty"`
+
+	// Score Shortfall from the expected height in robust standard deviations
+	Score *float32 `json:"score,omitempty"`
+}
+
+// AnomalyReasonCode defines model for AnomalyReason.Code.
+type AnomalyReasonCode string
+
+// AnomalyReview defines model for AnomalyReview.
+type AnomalyReview struct {
+	Note *string `json:"note,omitempty"`
+}
+
+// BlockYield defines model for BlockYield.
+type BlockYield struct {
+	AreaHectares *float32 `json:"area_hectares,omitempty"`
+	BlockX       *int     `json:"block_x,omitempty"`
+	BlockY       *int     `json:"block_y,omitempty"`
+	Bunches      *int     `json:"bunches,omitempty"`
+
+	// Harvests Number of harvest records
+	Harvests         *int     `json:"harvests,omitempty"`
+	TonnesPerHectare *float32 `json:"tonnes_per_hectare,omitempty"`
+	WeightKg         *float32 `json:"weight_kg,omitempty"`
+	X1               *int     `json:"x1,omitempty"`
+	X2               *int     `json:"x2,omitempty"`
+	Y1               *int     `json:"y1,omitempty"`
+	Y2               *int     `json:"y2,omitempty"`
+}
+
+// BoundingBox defines model for BoundingBox.
+type BoundingBox struct {
+	X1 *int `json:"x1,omitempty"`
+	X2 *int `json:"x2,omitempty"`
+	Y1 *int `json:"y1,omitempty"`
+	Y2 *int `json:"y2,omitempty"`
+}
+
+// CohortGrowth defines model for CohortGrowth.
+type CohortGrowth struct {
+	// BelowExpectation Growth rate below 80% of the expected one
+	BelowExpectation *bool `json:"below_expectation,omitempty"`
+
+	// Cohort Age cohort name, by age at the end of the series
+	Cohort *string `json:"cohort,omitempty"`
+
+	// ExpectedGrowthRate Null for trees without a planting date
+	ExpectedGrowthRate *float32 `json:"expected_growth_rate"`
+
+	// GrowthRate Mean meters grown per year
+	GrowthRate *float32 `json:"growth_rate,omitempty"`
+
+	// Trees Trees measured at least twice, 30 days or more apart
+	Trees *int `json:"trees,omitempty"`
+}
+
 // DronePlan defines model for DronePlan.
 type DronePlan struct {
 	LandedAt *struct {
 		X *int `json:"x,omitempty"`
 		Y *int `json:"y,omitempty"`
 	} `json:"landed_at,omitempty"`
-	TotalDistance *int `json:"total_distance,omitempty"`
+
+	// Route Set for inspection routes
+	Route         *string `json:"route,omitempty"`
+	TotalDistance *int    `json:"total_distance,omitempty"`
+
+	// Trees Number of trees visited on an inspection route
+	Trees *int `json:"trees,omitempty"`
+}
+
+// ErrorResponse defines model for ErrorResponse.
+type ErrorResponse struct {
+	Message string `json:"message"`
+}
+
+// Estate defines model for Estate.
+type Estate struct {
+	// ArchivedAt Time the estate was archived, absent while active
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+
+	// Code Unique short code of the estate, stored upper-case
+	Code *string `json:"code,omitempty"`
+
+	// Company Company owning the estate
+	Company *string `json:"company,omitempty"`
+
+	// CreatedAt Time the estate was created
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+
+	// Id Unique identifier for the estate
+	Id *openapi_types.UUID `json:"id,omitempty"`
+
+	// Length Length of the estate in 10m plots
+	Length *int `json:"length,omitempty"`
+
+	// Location Position of the estate on the map, for map tiles
+	Location *Location `json:"location,omitempty"`
+
+	// Name Human readable name of the estate
+	Name *string `json:"name,omitempty"`
+
+	// Notes Free-text notes
+	Notes *string `json:"notes,omitempty"`
+
+	// Region Region the estate is located in
+	Region *string `json:"region,omitempty"`
+
+	// Tags Tags grouping the estate into portfolios, stored lower-case
+	Tags *[]string `json:"tags,omitempty"`
+
+	// UpdatedAt Time the estate was last updated
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+
+	// Width Width of the estate in 10m plots
+	Width *int `json:"width,omitempty"`
+}
+
+// EstateForecast defines model for EstateForecast.
+type EstateForecast struct {
+	// From First day forecast
+	From        *openapi_types.Date `json:"from,omitempty"`
+	HorizonDays *int                `json:"horizon_days,omitempty"`
+	Inputs      *ForecastInputs     `json:"inputs,omitempty"`
+	LowerKg     *float32            `json:"lower_kg,omitempty"`
+	Model       *ForecastModel      `json:"model,omitempty"`
+	PointKg     *float32            `json:"point_kg,omitempty"`
+
+	// To Last day forecast
+	To *openapi_types.Date `json:"to,omitempty"`
+
+	// TonnesPerHectare Point estimate over the estate area
+	TonnesPerHectare *float32 `json:"tonnes_per_hectare,omitempty"`
+	UpperKg          *float32 `json:"upper_kg,omitempty"`
+}
+
+// EstatePage defines model for EstatePage.
+type EstatePage struct {
+	Estates []Estate `json:"estates"`
+
+	// NextCursor Cursor for the next page, absent on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+// EstateResizeConflict defines model for EstateResizeConflict.
+type EstateResizeConflict struct {
+	Message string `json:"message"`
+
+	// Trees Trees outside the requested dimensions, at most 100
+	Trees *[]Tree `json:"trees,omitempty"`
+
+	// Truncated Whether more trees are out of bounds than listed
+	Truncated *bool `json:"truncated,omitempty"`
+}
+
+// EstateStats defines model for EstateStats.
+type EstateStats struct {
+	// AreaHectares Area of the estate, or of the region
+	AreaHectares *float32 `json:"area_hectares,omitempty"`
+	BucketWidth  *int     `json:"bucket_width,omitempty"`
+	Count        *int     `json:"count,omitempty"`
+
+	// Health Number of trees per current health status
+	Health *map[string]int `json:"health,omitempty"`
+
+	// Histogram Every bucket from the shortest to the tallest tree, empty ones included
+	Histogram   *[]HistogramBucket `json:"histogram,omitempty"`
+	Max         *int               `json:"max,omitempty"`
+	Mean        *float32           `json:"mean,omitempty"`
+	Median      *float32           `json:"median,omitempty"`
+	Min         *int               `json:"min,omitempty"`
+	Percentiles *[]Percentile      `json:"percentiles,omitempty"`
+
+	// Region Part of an estate. A plot belongs to the region when it passes every filter set; at least one is required.
+	Region *Region `json:"region,omitempty"`
+
+	// Stddev Population standard deviation of the heights
+	Stddev *float32 `json:"stddev,omitempty"`
+
+	// TonnesPerHectare FFB yield of the 12 months up to as_of or today
+	TonnesPerHectare *float32 `json:"tonnes_per_hectare,omitempty"`
+}
+
+// EstateStatsBreakdown defines model for EstateStatsBreakdown.
+type EstateStatsBreakdown struct {
+	// AgeCohorts Cohorts with trees, youngest first (immature, young, prime, old, senile), then unknown
+	AgeCohorts *[]TreeGroupStats `json:"age_cohorts,omitempty"`
+	Species    *[]TreeGroupStats `json:"species,omitempty"`
+}
+
+// EstateTimeseries defines model for EstateTimeseries.
+type EstateTimeseries struct {
+	BelowExpectation *bool `json:"below_expectation,omitempty"`
+
+	// Cohorts Youngest first, trees without a planting date last
+	Cohorts            *[]CohortGrowth     `json:"cohorts,omitempty"`
+	ExpectedGrowthRate *float32            `json:"expected_growth_rate"`
+	From               *openapi_types.Date `json:"from,omitempty"`
+
+	// GrowthRate Mean growth of the trees with a planting date
+	GrowthRate *float32            `json:"growth_rate"`
+	Interval   *string             `json:"interval,omitempty"`
+	Metric     *string             `json:"metric,omitempty"`
+	Points     *[]TimeseriesPoint  `json:"points,omitempty"`
+	To         *openapi_types.Date `json:"to,omitempty"`
+}
+
+// EstateUpdate Fields to change; omitted fields are left unchanged
+type EstateUpdate struct {
+	Code    *string `json:"code,omitempty"`
+	Company *string `json:"company,omitempty"`
+	Length  *int    `json:"length,omitempty"`
+
+	// Location Position of the estate on the map, for map tiles
+	Location *Location `json:"location,omitempty"`
+	Name     *string   `json:"name,omitempty"`
+	Notes    *string   `json:"notes,omitempty"`
+	Region   *string   `json:"region,omitempty"`
+	Tags     *[]string `json:"tags,omitempty"`
+	Width    *int      `json:"width,omitempty"`
+}
+
+// EstateYield defines model for EstateYield.
+type EstateYield struct {
+	AreaHectares *float32 `json:"area_hectares,omitempty"`
+	BlockSize    *int     `json:"block_size,omitempty"`
+
+	// Blocks Blocks with harvests, row by row
+	Blocks  *[]BlockYield       `json:"blocks,omitempty"`
+	Bunches *int                `json:"bunches,omitempty"`
+	From    *openapi_types.Date `json:"from,omitempty"`
+
+	// Harvests Number of harvest records
+	Harvests         *int                `json:"harvests,omitempty"`
+	To               *openapi_types.Date `json:"to,omitempty"`
+	TonnesPerHectare *float32            `json:"tonnes_per_hectare,omitempty"`
+	WeightKg         *float32            `json:"weight_kg,omitempty"`
+}
+
+// ForecastInputs defines model for ForecastInputs.
+type ForecastInputs struct {
+	Harvests        *int                `json:"harvests,omitempty"`
+	HistoryFrom     *openapi_types.Date `json:"history_from,omitempty"`
+	HistoryTo       *openapi_types.Date `json:"history_to,omitempty"`
+	HistoryWeightKg *float32            `json:"history_weight_kg,omitempty"`
+
+	// MeanPerformance Mean factor applied to the age curve over living trees
+	MeanPerformance *float32 `json:"mean_performance,omitempty"`
+
+	// QuarterRatios Actual over expected estate yield per history quarter with harvests, oldest first
+	QuarterRatios         *[]float32 `json:"quarter_ratios,omitempty"`
+	Trees                 *int       `json:"trees,omitempty"`
+	TreesAgedByHeight     *int       `json:"trees_aged_by_height,omitempty"`
+	TreesDead             *int       `json:"trees_dead,omitempty"`
+	TreesWithHistory      *int       `json:"trees_with_history,omitempty"`
+	TreesWithPlantingDate *int       `json:"trees_with_planting_date,omitempty"`
+}
+
+// ForecastModel defines model for ForecastModel.
+type ForecastModel struct {
+	// AgeCurve Linear between points and flat after the last one
+	AgeCurve *[]AgeCurvePoint `json:"age_curve,omitempty"`
+
+	// Confidence Coverage of the interval
+	Confidence *float32 `json:"confidence,omitempty"`
+
+	// CredibilityHarvests Harvests at which a tree's own history weighs as much as the estate's
+	CredibilityHarvests *int `json:"credibility_harvests,omitempty"`
+
+	// HeightGrowthMPerYear Used to estimate the age of palms without a planting date
+	HeightGrowthMPerYear *float32 `json:"height_growth_m_per_year,omitempty"`
+	HistoryQuarters      *int     `json:"history_quarters,omitempty"`
+	Name                 *string  `json:"name,omitempty"`
+
+	// QuarterWeights Weight of each history quarter, oldest first
+	QuarterWeights *[]float32 `json:"quarter_weights,omitempty"`
+
+	// ResidualSigma Spread of the log ratio of actual to expected estate yield per quarter
+	ResidualSigma *float32 `json:"residual_sigma,omitempty"`
+}
+
+// GapAnalysis defines model for GapAnalysis.
+type GapAnalysis struct {
+	CoveragePercent   *float32 `json:"coverage_percent,omitempty"`
+	DensityPerHectare *float32 `json:"density_per_hectare,omitempty"`
+	EmptyPlots        *int     `json:"empty_plots,omitempty"`
+	Plots             *int     `json:"plots,omitempty"`
+	RegionCount       *int     `json:"region_count,omitempty"`
+
+	// Regions Largest regions first, up to the limit
+	Regions    *[]GapRegion    `json:"regions,omitempty"`
+	Replanting *ReplantingPlan `json:"replanting,omitempty"`
+	Trees      *int            `json:"trees,omitempty"`
+}
+
+// GapRegion A contiguous region of empty plots, joined by their sides
+type GapRegion struct {
+	// Plots Empty plots in the region
+	Plots *int `json:"plots,omitempty"`
+	X1    *int `json:"x1,omitempty"`
+	X2    *int `json:"x2,omitempty"`
+	Y1    *int `json:"y1,omitempty"`
+	Y2    *int `json:"y2,omitempty"`
+}
+
+// Harvest defines model for Harvest.
+type Harvest struct {
+	// Bunches Fresh fruit bunches cut
+	Bunches     int                 `json:"bunches"`
+	CreatedAt   *time.Time          `json:"created_at,omitempty"`
+	EstateId    *openapi_types.UUID `json:"estate_id,omitempty"`
+	HarvestedOn openapi_types.Date  `json:"harvested_on"`
+	Harvester   *string             `json:"harvester,omitempty"`
+	Id          *openapi_types.UUID `json:"id,omitempty"`
+
+	// TreeId Harvested tree; x and y may then be left out. Harvests of a planted plot are linked to its tree.
+	TreeId *openapi_types.UUID `json:"tree_id,omitempty"`
+
+	// WeightKg Total weight of the bunches, at most 60 kg per bunch
+	WeightKg float32 `json:"weight_kg"`
+
+	// X X coordinate of the harvested plot
+	X *int `json:"x,omitempty"`
+
+	// Y Y coordinate of the harvested plot
+	Y *int `json:"y,omitempty"`
+}
+
+// HarvestBatchReport defines model for HarvestBatchReport.
+type HarvestBatchReport struct {
+	// Errors Rejected harvests, at most 1000 of them
+	Errors *[]struct {
+		Message *string `json:"message,omitempty"`
+
+		// Row 1-based position of the harvest in the batch
+		Row *int `json:"row,omitempty"`
+	} `json:"errors,omitempty"`
+
+	// Failed Number of harvests rejected
+	Failed *int `json:"failed,omitempty"`
+
+	// Recorded Number of harvests stored, 0 when any is rejected
+	Recorded *int `json:"recorded,omitempty"`
+}
+
+// HarvestList defines model for HarvestList.
+type HarvestList struct {
+	Harvests *[]Harvest `json:"harvests,omitempty"`
+}
+
+// HelloResponse defines model for HelloResponse.
+type HelloResponse struct {
+	Message string `json:"message"`
+}
+
+// HistogramBucket defines model for HistogramBucket.
+type HistogramBucket struct {
+	Count *int `json:"count,omitempty"`
+	From  *int `json:"from,omitempty"`
+
+	// To Tallest height of the bucket, included
+	To *int `json:"to,omitempty"`
+}
+
+// Location Position of the estate on the map, for map tiles
+type Location struct {
+	// Latitude Latitude of the outer corner of plot (1,1), in degrees
+	Latitude float32 `json:"latitude"`
+
+	// Longitude Longitude of the outer corner of plot (1,1), in degrees
+	Longitude float32 `json:"longitude"`
+
+	// Rotation Clockwise rotation of the estate in degrees; at 0 rows run east and columns south
+	Rotation *float32 `json:"rotation,omitempty"`
+}
+
+// Percentile defines model for Percentile.
+type Percentile struct {
+	// Height Height below which p percent of the trees stand
+	Height *float32 `json:"height,omitempty"`
+	P      *float32 `json:"p,omitempty"`
+}
+
+// Plot defines model for Plot.
+type Plot struct {
+	X *int `json:"x,omitempty"`
+	Y *int `json:"y,omitempty"`
+}
+
+// PortfolioEstateStats defines model for PortfolioEstateStats.
+type PortfolioEstateStats struct {
+	AreaHectares *float32 `json:"area_hectares,omitempty"`
+	Code         *string  `json:"code,omitempty"`
+	Company      *string  `json:"company,omitempty"`
+
+	// CoveragePercent Share of the plots holding a tree
+	CoveragePercent *float32            `json:"coverage_percent,omitempty"`
+	Id              *openapi_types.UUID `json:"id,omitempty"`
+	Max             *int                `json:"max,omitempty"`
+	Mean            *float32            `json:"mean,omitempty"`
+	Median          *float32            `json:"median,omitempty"`
+	Min             *int                `json:"min,omitempty"`
+	Name            *string             `json:"name,omitempty"`
+	Percentiles     *[]Percentile       `json:"percentiles,omitempty"`
+
+	// Rank 1 for the first estate; estates tied share a rank
+	Rank   *int     `json:"rank,omitempty"`
+	Stddev *float32 `json:"stddev,omitempty"`
+	Trees  *int     `json:"trees,omitempty"`
+}
+
+// PortfolioStats defines model for PortfolioStats.
+type PortfolioStats struct {
+	AreaHectares *float32 `json:"area_hectares,omitempty"`
+	BucketWidth  *int     `json:"bucket_width,omitempty"`
+	Count        *int     `json:"count,omitempty"`
+
+	// CoveragePercent Share of the plots of all estates holding a tree
+	CoveragePercent *float32 `json:"coverage_percent,omitempty"`
+	EstateCount     *int     `json:"estate_count,omitempty"`
+
+	// Estates Ranked, first rank first
+	Estates     *[]PortfolioEstateStats `json:"estates,omitempty"`
+	Histogram   *[]HistogramBucket      `json:"histogram,omitempty"`
+	Max         *int                    `json:"max,omitempty"`
+	Mean        *float32                `json:"mean,omitempty"`
+	Median      *float32                `json:"median,omitempty"`
+	Min         *int                    `json:"min,omitempty"`
+	Percentiles *[]Percentile           `json:"percentiles,omitempty"`
+	RankBy      *string                 `json:"rank_by,omitempty"`
+	Stddev      *float32                `json:"stddev,omitempty"`
+}
+
+// Range defines model for Range.
+type Range struct {
+	From *int `json:"from,omitempty"`
+
+	// To Last row or column, included
+	To *int `json:"to,omitempty"`
+}
+
+// Region Part of an estate. A plot belongs to the region when it passes every filter set; at least one is required.
+type Region struct {
+	Bbox    *BoundingBox `json:"bbox,omitempty"`
+	Columns *Range       `json:"columns,omitempty"`
+	Name    *string      `json:"name,omitempty"`
+
+	// Polygon Vertices in order, from 3 to 100; plots on an edge are inside
+	Polygon *[]Plot `json:"polygon,omitempty"`
+	Rows    *Range  `json:"rows,omitempty"`
+}
+
+// RegionStatsList defines model for RegionStatsList.
+type RegionStatsList struct {
+	Regions *[]EstateStats `json:"regions,omitempty"`
+}
+
+// RegionStatsRequest defines model for RegionStatsRequest.
+type RegionStatsRequest struct {
+	Regions []Region `json:"regions"`
+}
+
+// ReplantingPlan defines model for ReplantingPlan.
+type ReplantingPlan struct {
+	// DensityPerHectare Trees per hectare once replanted
+	DensityPerHectare *float32 `json:"density_per_hectare,omitempty"`
+
+	// Plots Plots proposed, row by row, up to the limit
+	Plots   *[]Plot                `json:"plots,omitempty"`
+	Spacing *ReplantingPlanSpacing `json:"spacing,omitempty"`
+
+	// Total Plots proposed in all
+	Total     *int  `json:"total,omitempty"`
+	Truncated *bool `json:"truncated,omitempty"`
+}
+
+// ReplantingPlanSpacing defines model for ReplantingPlan.Spacing.
+type ReplantingPlanSpacing string
+
+// Species defines model for Species.
+type Species struct {
+	// Code Unique short code, e.g. tenera
+	Code      string     `json:"code"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+
+	// MaxHeight Largest accepted tree height in meters
+	MaxHeight int `json:"max_height"`
+
+	// MinHeight Smallest accepted tree height in meters
+	MinHeight int    `json:"min_height"`
+	Name      string `json:"name"`
+}
+
+// SpeciesList defines model for SpeciesList.
+type SpeciesList struct {
+	Species *[]Species `json:"species,omitempty"`
+}
+
+// TimeseriesPoint defines model for TimeseriesPoint.
+type TimeseriesPoint struct {
+	// Period First day of the period
+	Period *openapi_types.Date `json:"period,omitempty"`
+
+	// Trees Trees measured by the end of the period
+	Trees *int `json:"trees,omitempty"`
+
+	// Value Value of the metric at the end of the period, null before any tree was measured
+	Value *float32 `json:"value"`
+}
+
+// Tree defines model for Tree.
+type Tree struct {
+	// AgeYears Whole years since planting
+	AgeYears *int `json:"age_years,omitempty"`
+
+	// EstateId ID of the estate this tree belongs to
+	EstateId *openapi_types.UUID `json:"estate_id,omitempty"`
+
+	// HealthStatus Status of the latest health observation, healthy when there is none
+	HealthStatus *TreeHealthStatus `json:"health_status,omitempty"`
+
+	// Height Height of the tree in meters, within the range of its species or 1 to 30 without one
+	Height *int `json:"height,omitempty"`
+
+	// Id Unique identifier for the tree
+	Id *openapi_types.UUID `json:"id,omitempty"`
+
+	// PlantedOn Date the tree was planted
+	PlantedOn *openapi_types.Date `json:"planted_on,omitempty"`
+
+	// Species Code of the tree's species
+	Species *string `json:"species,omitempty"`
+
+	// X X coordinate of the tree in its plot
+	X *int `json:"x,omitempty"`
+
+	// Y Y coordinate of the tree in its plot
+	Y *int `json:"y,omitempty"`
+}
+
+// TreeHealthStatus Status of the latest health observation, healthy when there is none
+type TreeHealthStatus string
+
+// TreeAnomaly defines model for TreeAnomaly.
+type TreeAnomaly struct {
+	DetectedAt *time.Time          `json:"detected_at,omitempty"`
+	EstateId   *openapi_types.UUID `json:"estate_id,omitempty"`
+
+	// Height Height of the tree when detected
+	Height *int                `json:"height,omitempty"`
+	Id     *openapi_types.UUID `json:"id,omitempty"`
+	Note   *string             `json:"note,omitempty"`
+
+	// Reasons Highest score first
+	Reasons    *[]AnomalyReason `json:"reasons,omitempty"`
+	ReviewedAt *time.Time       `json:"reviewed_at,omitempty"`
+
+	// Score Highest score of its reasons
+	Score  *float32            `json:"score,omitempty"`
+	Status *TreeAnomalyStatus  `json:"status,omitempty"`
+	TreeId *openapi_types.UUID `json:"tree_id,omitempty"`
+	X      *int                `json:"x,omitempty"`
+	Y      *int                `json:"y,omitempty"`
+}
+
+// TreeAnomalyStatus defines model for TreeAnomaly.Status.
+type TreeAnomalyStatus string
+
+// TreeGroupStats defines model for TreeGroupStats.
+type TreeGroupStats struct {
+	Count *int `json:"count,omitempty"`
+
+	// Group Species code or age cohort name; empty for trees without a species
+	Group  *string  `json:"group,omitempty"`
+	Max    *int     `json:"max,omitempty"`
+	Median *float32 `json:"median,omitempty"`
+	Min    *int     `json:"min,omitempty"`
+}
+
+// TreeImportReport defines model for TreeImportReport.
+type TreeImportReport struct {
+	// Errors Rejected rows, at most 1000 of them
+	Errors *[]struct {
+		Message *string `json:"message,omitempty"`
+
+		// Row 1-based position of the row in the file, not counting the CSV header
+		Row *int `json:"row,omitempty"`
+	} `json:"errors,omitempty"`
+
+	// Failed Number of rows rejected
+	Failed *int `json:"failed,omitempty"`
+
+	// Imported Number of trees stored
+	Imported *int `json:"imported,omitempty"`
+
+	// Truncated Whether rejected rows were left out of errors
+	Truncated *bool `json:"truncated,omitempty"`
+}
+
+// TreeMeasurement defines model for TreeMeasurement.
+type TreeMeasurement struct {
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+
+	// Height Measured height in meters
+	Height int                 `json:"height"`
+	Id     *openapi_types.UUID `json:"id,omitempty"`
+
+	// MeasuredAt Time the measurement was taken, defaults to now
+	MeasuredAt *time.Time             `json:"measured_at,omitempty"`
+	Source     *TreeMeasurementSource `json:"source,omitempty"`
+	Surveyor   *string                `json:"surveyor,omitempty"`
+	TreeId     *openapi_types.UUID    `json:"tree_id,omitempty"`
+}
+
+// TreeMeasurementSource defines model for TreeMeasurement.Source.
+type TreeMeasurementSource string
+
+// TreeMeasurementList defines model for TreeMeasurementList.
+type TreeMeasurementList struct {
+	Measurements *[]TreeMeasurement `json:"measurements,omitempty"`
+}
+
+// TreeNeighbour defines model for TreeNeighbour.
+type TreeNeighbour struct {
+	// AgeYears Whole years since planting
+	AgeYears *int `json:"age_years,omitempty"`
+
+	// DistanceMeters Straight-line distance in meters, at 10 meters per plot
+	DistanceMeters *float32 `json:"distance_meters,omitempty"`
+
+	// DistancePlots Straight-line distance in plots
+	DistancePlots *float32 `json:"distance_plots,omitempty"`
+
+	// EstateId ID of the estate this tree belongs to
+	EstateId *openapi_types.UUID `json:"estate_id,omitempty"`
+
+	// HealthStatus Status of the latest health observation, healthy when there is none
+	HealthStatus *TreeNeighbourHealthStatus `json:"health_status,omitempty"`
+
+	// Height Height of the tree in meters, within the range of its species or 1 to 30 without one
+	Height *int `json:"height,omitempty"`
+
+	// Id Unique identifier for the tree
+	Id *openapi_types.UUID `json:"id,omitempty"`
+
+	// PlantedOn Date the tree was planted
+	PlantedOn *openapi_types.Date `json:"planted_on,omitempty"`
+
+	// Species Code of the tree's species
+	Species *string `json:"species,omitempty"`
+
+	// X X coordinate of the tree in its plot
+	X *int `json:"x,omitempty"`
+
+	// Y Y coordinate of the tree in its plot
+	Y *int `json:"y,omitempty"`
+}
+
+// TreeNeighbourHealthStatus Status of the latest health observation, healthy when there is none
+type TreeNeighbourHealthStatus string
+
+// TreeNeighbourList defines model for TreeNeighbourList.
+type TreeNeighbourList struct {
+	// Trees Nearest first
+	Trees *[]TreeNeighbour `json:"trees,omitempty"`
+}
+
+// TreeObservation defines model for TreeObservation.
+type TreeObservation struct {
+	CreatedAt *time.Time          `json:"created_at,omitempty"`
+	Id        *openapi_types.UUID `json:"id,omitempty"`
+	Notes     *string             `json:"notes,omitempty"`
+
+	// ObservedAt Time the tree was observed, defaults to now
+	ObservedAt *time.Time `json:"observed_at,omitempty"`
+	Observer   *string    `json:"observer,omitempty"`
+
+	// Severity 0 for healthy observations, otherwise 1 (mild) to 5 (severe)
+	Severity *int                  `json:"severity,omitempty"`
+	Status   TreeObservationStatus `json:"status"`
+	TreeId   *openapi_types.UUID   `json:"tree_id,omitempty"`
+}
+
+// TreeObservationStatus defines model for TreeObservation.Status.
+type TreeObservationStatus string
+
+// TreeObservationList defines model for TreeObservationList.
+type TreeObservationList struct {
+	Observations *[]TreeObservation `json:"observations,omitempty"`
+}
+
+// TreePage defines model for TreePage.
+type TreePage struct {
+	// NextCursor Cursor for the next page, absent on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
+	Trees      *[]Tree `json:"trees,omitempty"`
+}
+
+// TreeUpdate Fields to change; omitted fields are left unchanged
+type TreeUpdate struct {
+	Height    *int                `json:"height,omitempty"`
+	PlantedOn *openapi_types.Date `json:"planted_on,omitempty"`
+
+	// Species Species code; an empty code clears the species
+	Species *string `json:"species,omitempty"`
+	X       *int    `json:"x,omitempty"`
+	Y       *int    `json:"y,omitempty"`
+}
+
+// GetEstateParams defines parameters for GetEstate.
+type GetEstateParams struct {
+	Sort   *GetEstateParamsSort  `form:"sort,omitempty" json:"sort,omitempty"`
+	Order  *GetEstateParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+	Cursor *string               `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Q Search term matched against estate name and code
+	Q       *string `form:"q,omitempty" json:"q,omitempty"`
+	Company *string `form:"company,omitempty" json:"company,omitempty"`
+
+	// Tag Only list estates carrying this tag
+	Tag    *string `form:"tag,omitempty" json:"tag,omitempty"`
+	Region *string `form:"region,omitempty" json:"region,omitempty"`
+
+	// Archived List archived estates instead of active ones
+	Archived  *bool `form:"archived,omitempty" json:"archived,omitempty"`
+	Limit     *int  `form:"limit,omitempty" json:"limit,omitempty"`
+	MinWidth  *int  `form:"min_width,omitempty" json:"min_width,omitempty"`
+	MaxWidth  *int  `form:"max_width,omitempty" json:"max_width,omitempty"`
+	MinLength *int  `form:"min_length,omitempty" json:"min_length,omitempty"`
+	MaxLength *int  `form:"max_length,omitempty" json:"max_length,omitempty"`
+	MinTrees  *int  `form:"min_trees,omitempty" json:"min_trees,omitempty"`
+	MaxTrees  *int  `form:"max_trees,omitempty" json:"max_trees,omitempty"`
+}
+
+// GetEstateParamsSort defines parameters for GetEstate.
+type GetEstateParamsSort string
+
+// GetEstateParamsOrder defines parameters for GetEstate.
+type GetEstateParamsOrder string
+
+// GetEstateIdAnomaliesParams defines parameters for GetEstateIdAnomalies.
+type GetEstateIdAnomaliesParams struct {
+	Status *GetEstateIdAnomaliesParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// Limit Anomalies listed
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetEstateIdAnomaliesParamsStatus defines parameters for GetEstateIdAnomalies.
+type GetEstateIdAnomaliesParamsStatus string
+
+// GetEstateIdDronePlanParams defines parameters for GetEstateIdDronePlan.
+type GetEstateIdDronePlanParams struct {
+	MaxDistance *int `form:"max_distance,omitempty" json:"max_distance,omitempty"`
+
+	// AsOf Use the heights measured up to this date; not supported for inspection routes
+	AsOf *openapi_types.Date `form:"as_of,omitempty" json:"as_of,omitempty"`
+
+	// Route survey flies over every plot in rows; inspection flies straight to each unhealthy, living tree in row order
+	Route *GetEstateIdDronePlanParamsRoute `form:"route,omitempty" json:"route,omitempty"`
+
+	// Format Response format; xlsx is also chosen by an Accept header naming XLSX
+	Format *GetEstateIdDronePlanParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetEstateIdDronePlanParamsRoute defines parameters for GetEstateIdDronePlan.
+type GetEstateIdDronePlanParamsRoute string
+
+// GetEstateIdDronePlanParamsFormat defines parameters for GetEstateIdDronePlan.
+type GetEstateIdDronePlanParamsFormat string
+
+// GetEstateIdForecastParams defines parameters for GetEstateIdForecast.
+type GetEstateIdForecastParams struct {
+	// Horizon Days forecast
+	Horizon *int `form:"horizon,omitempty" json:"horizon,omitempty"`
+}
+
+// GetEstateIdGapsParams defines parameters for GetEstateIdGaps.
+type GetEstateIdGapsParams struct {
+	// Spacing Replanting spacing
+	Spacing *GetEstateIdGapsParamsSpacing `form:"spacing,omitempty" json:"spacing,omitempty"`
+
+	// Limit Regions and replanting plots listed
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetEstateIdGapsParamsSpacing defines parameters for GetEstateIdGaps.
+type GetEstateIdGapsParamsSpacing string
+
+// PostEstateIdHarvestsJSONBody defines parameters for PostEstateIdHarvests.
+type PostEstateIdHarvestsJSONBody = []Harvest
+
+// GetEstateIdHeatmapPngParams defines parameters for GetEstateIdHeatmapPng.
+type GetEstateIdHeatmapPngParams struct {
+	// Downsample Plots per cell side; by default the smallest keeping the map within 2048 cells a side
+	Downsample *int `form:"downsample,omitempty" json:"downsample,omitempty"`
+
+	// Cell Pixels per cell side; by default the largest keeping the map within 1024 pixels
+	Cell *int `form:"cell,omitempty" json:"cell,omitempty"`
+
+	// Route Drone route drawn over the map, with the point the drone rests at when it stops short
+	Route *GetEstateIdHeatmapPngParamsRoute `form:"route,omitempty" json:"route,omitempty"`
+
+	// MaxDistance Maximum distance the drone can travel on the route
+	MaxDistance *int `form:"max_distance,omitempty" json:"max_distance,omitempty"`
+}
+
+// GetEstateIdHeatmapPngParamsRoute defines parameters for GetEstateIdHeatmapPng.
+type GetEstateIdHeatmapPngParamsRoute string
+
+// GetEstateIdHeatmapSvgParams defines parameters for GetEstateIdHeatmapSvg.
+type GetEstateIdHeatmapSvgParams struct {
+	// Downsample Plots per cell side; by default the smallest keeping the map within 512 cells a side
+	Downsample *int `form:"downsample,omitempty" json:"downsample,omitempty"`
+
+	// Cell Pixels per cell side; by default the largest keeping the map within 1024 pixels
+	Cell *int `form:"cell,omitempty" json:"cell,omitempty"`
+
+	// Route Drone route drawn over the map, with the point the drone rests at when it stops short
+	Route *GetEstateIdHeatmapSvgParamsRoute `form:"route,omitempty" json:"route,omitempty"`
+
+	// MaxDistance Maximum distance the drone can travel on the route
+	MaxDistance *int `form:"max_distance,omitempty" json:"max_distance,omitempty"`
+}
+
+// GetEstateIdHeatmapSvgParamsRoute defines parameters for GetEstateIdHeatmapSvg.
+type GetEstateIdHeatmapSvgParamsRoute string
+
+// GetEstateIdReportParams defines parameters for GetEstateIdReport.
+type GetEstateIdReportParams struct {
+	// Format Report format, html by default
+	Format *GetEstateIdReportParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Month Month of the report (YYYY-MM), the current month by default
+	Month *string `form:"month,omitempty" json:"month,omitempty"`
+}
+
+// GetEstateIdReportParamsFormat defines parameters for GetEstateIdReport.
+type GetEstateIdReportParamsFormat string
+
+// GetEstateIdStatsParams defines parameters for GetEstateIdStats.
+type GetEstateIdStatsParams struct {
+	// AsOf Use the heights measured up to this date
+	AsOf *openapi_types.Date `form:"as_of,omitempty" json:"as_of,omitempty"`
+
+	// Percentiles Comma-separated percentiles to compute, each above 0 and below 100, at most 10; 10,25,75,90 by default
+	Percentiles *string `form:"percentiles,omitempty" json:"percentiles,omitempty"`
+
+	// BucketWidth Height histogram bucket width in meters, 5 by default
+	BucketWidth *int `form:"bucket_width,omitempty" json:"bucket_width,omitempty"`
+
+	// Bbox Narrow the stats to a rectangle of plots, as x1,y1,x2,y2
+	Bbox *string `form:"bbox,omitempty" json:"bbox,omitempty"`
+
+	// Rows Narrow the stats to a range of rows, as from-to
+	Rows *string `form:"rows,omitempty" json:"rows,omitempty"`
+
+	// Columns Narrow the stats to a range of columns, as from-to
+	Columns *string `form:"columns,omitempty" json:"columns,omitempty"`
+
+	// Polygon Narrow the stats to a polygon, as the list of its vertices x1,y1,x2,y2,...; plots on its edges are inside
+	Polygon *string `form:"polygon,omitempty" json:"polygon,omitempty"`
+
+	// Format Response format; xlsx is also chosen by an Accept header naming XLSX
+	Format *GetEstateIdStatsParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetEstateIdStatsParamsFormat defines parameters for GetEstateIdStats.
+type GetEstateIdStatsParamsFormat string
+
+// PostEstateIdStatsRegionsParams defines parameters for PostEstateIdStatsRegions.
+type PostEstateIdStatsRegionsParams struct {
+	// AsOf Use the heights measured up to this date
+	AsOf *openapi_types.Date `form:"as_of,omitempty" json:"as_of,omitempty"`
+
+	// Percentiles Comma-separated percentiles to compute, each above 0 and below 100, at most 10; 10,25,75,90 by default
+	Percentiles *string `form:"percentiles,omitempty" json:"percentiles,omitempty"`
+
+	// BucketWidth Height histogram bucket width in meters, 5 by default
+	BucketWidth *int `form:"bucket_width,omitempty" json:"bucket_width,omitempty"`
+}
+
+// GetEstateIdStatsTimeseriesParams defines parameters for GetEstateIdStatsTimeseries.
+type GetEstateIdStatsTimeseriesParams struct {
+	Metric   *GetEstateIdStatsTimeseriesParamsMetric   `form:"metric,omitempty" json:"metric,omitempty"`
+	Interval *GetEstateIdStatsTimeseriesParamsInterval `form:"interval,omitempty" json:"interval,omitempty"`
+
+	// From First day of the series, moved back to the start of its period; 12 periods before to by default
+	From *openapi_types.Date `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Last day of the series, today by default
+	To *openapi_types.Date `form:"to,omitempty" json:"to,omitempty"`
+}
+
+// GetEstateIdStatsTimeseriesParamsMetric defines parameters for GetEstateIdStatsTimeseries.
+type GetEstateIdStatsTimeseriesParamsMetric string
+
+// GetEstateIdStatsTimeseriesParamsInterval defines parameters for GetEstateIdStatsTimeseries.
+type GetEstateIdStatsTimeseriesParamsInterval string
+
+// GetEstateIdTilesZXYParams defines parameters for GetEstateIdTilesZXY.
+type GetEstateIdTilesZXYParams struct {
+	// Color Colour of the dots of PNG tiles, height by default
+	Color *GetEstateIdTilesZXYParamsColor `form:"color,omitempty" json:"color,omitempty"`
+}
+
+// GetEstateIdTilesZXYParamsColor defines parameters for GetEstateIdTilesZXY.
+type GetEstateIdTilesZXYParamsColor string
+
+// PostEstateIdTreeImportJSONBody defines parameters for PostEstateIdTreeImport.
+type PostEstateIdTreeImportJSONBody = []Tree
+
+// PostEstateIdTreeImportParams defines parameters for PostEstateIdTreeImport.
+type PostEstateIdTreeImportParams struct {
+	// Atomic Store no tree unless every row is valid
+	Atomic *bool `form:"atomic,omitempty" json:"atomic,omitempty"`
+}
+
+// GetEstateIdTreesParams defines parameters for GetEstateIdTrees.
+type GetEstateIdTreesParams struct {
+	Sort   *GetEstateIdTreesParamsSort  `form:"sort,omitempty" json:"sort,omitempty"`
+	Order  *GetEstateIdTreesParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+	Cursor *string                      `form:"cursor,omitempty" json:"cursor,omitempty"`
+	Limit  *int                         `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// X1 Smallest x of the bounding box, inclusive
+	X1 *int `form:"x1,omitempty" json:"x1,omitempty"`
+
+	// Y1 Smallest y of the bounding box, inclusive
+	Y1 *int `form:"y1,omitempty" json:"y1,omitempty"`
+
+	// X2 Largest x of the bounding box, inclusive
+	X2 *int `form:"x2,omitempty" json:"x2,omitempty"`
+
+	// Y2 Largest y of the bounding box, inclusive
+	Y2 *int `form:"y2,omitempty" json:"y2,omitempty"`
+
+	// MinHeight Minimum height
+	MinHeight *int `form:"min_height,omitempty" json:"min_height,omitempty"`
+
+	// MaxHeight Maximum height
+	MaxHeight *int `form:"max_height,omitempty" json:"max_height,omitempty"`
+
+	// Species Species code
+	Species *string `form:"species,omitempty" json:"species,omitempty"`
+
+	// Health Current health status
+	Health *GetEstateIdTreesParamsHealth `form:"health,omitempty" json:"health,omitempty"`
+}
+
+// GetEstateIdTreesParamsSort defines parameters for GetEstateIdTrees.
+type GetEstateIdTreesParamsSort string
+
+// GetEstateIdTreesParamsOrder defines parameters for GetEstateIdTrees.
+type GetEstateIdTreesParamsOrder string
+
+// GetEstateIdTreesParamsHealth defines parameters for GetEstateIdTrees.
+type GetEstateIdTreesParamsHealth string
+
+// GetEstateIdTreesExportParams defines parameters for GetEstateIdTreesExport.
+type GetEstateIdTreesExportParams struct {
+	// Format Export format; without it, xlsx is chosen by an Accept header naming XLSX
+	Format *GetEstateIdTreesExportParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetEstateIdTreesExportParamsFormat defines parameters for GetEstateIdTreesExport.
+type GetEstateIdTreesExportParamsFormat string
+
+// GetEstateIdTreesNearbyParams defines parameters for GetEstateIdTreesNearby.
+type GetEstateIdTreesNearbyParams struct {
+	X int `form:"x" json:"x"`
+	Y int `form:"y" json:"y"`
+
+	// Radius Radius in plots; exclusive with k
+	Radius *int `form:"radius,omitempty" json:"radius,omitempty"`
+
+	// K Number of nearest trees; exclusive with radius
+	K *int `form:"k,omitempty" json:"k,omitempty"`
+}
+
+// GetEstateIdYieldParams defines parameters for GetEstateIdYield.
+type GetEstateIdYieldParams struct {
+	// From First harvest day included, defaults to the start of the 12 months up to to
+	From *openapi_types.Date `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Last harvest day included, defaults to today
+	To *openapi_types.Date `form:"to,omitempty" json:"to,omitempty"`
+
+	// BlockSize Side of a block in plots
+	BlockSize *int `form:"block_size,omitempty" json:"block_size,omitempty"`
+
+	// Format Response format; xlsx is also chosen by an Accept header naming XLSX
+	Format *GetEstateIdYieldParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetEstateIdYieldParamsFormat defines parameters for GetEstateIdYield.
+type GetEstateIdYieldParamsFormat string
+
+// GetHelloParams defines parameters for GetHello.
+type GetHelloParams struct {
+	Id *string `form:"id,omitempty" json:"id,omitempty"`
+}
+
+// GetPortfolioStatsParams defines parameters for GetPortfolioStats.
+type GetPortfolioStatsParams struct {
+	// Ids Comma-separated estate IDs, at most 100
+	Ids     *string                        `form:"ids,omitempty" json:"ids,omitempty"`
+	Tag     *string                        `form:"tag,omitempty" json:"tag,omitempty"`
+	Company *string                        `form:"company,omitempty" json:"company,omitempty"`
+	RankBy  *GetPortfolioStatsParamsRankBy `form:"rank_by,omitempty" json:"rank_by,omitempty"`
+
+	// Percentiles Comma-separated percents to compute the height percentiles of, 10,25,75,90 by default
+	Percentiles *string `form:"percentiles,omitempty" json:"percentiles,omitempty"`
+
+	// BucketWidth Height histogram bucket width in meters, 5 by default
+	BucketWidth *int `form:"bucket_width,omitempty" json:"bucket_width,omitempty"`
+}
+
+// GetPortfolioStatsParamsRankBy defines parameters for GetPortfolioStats.
+type GetPortfolioStatsParamsRankBy string
+
+// PostEstateJSONRequestBody defines body for PostEstate for application/json ContentType.
+type PostEstateJSONRequestBody = Estate
+
+// PatchEstateIdJSONRequestBody defines body for PatchEstateId for application/json ContentType.
+type PatchEstateIdJSONRequestBody = EstateUpdate
+
+// PostEstateIdAnomaliesAnomalyIdAcknowledgeJSONRequestBody defines body for PostEstateIdAnomaliesAnomalyIdAcknowledge for application/json ContentType.
+type PostEstateIdAnomaliesAnomalyIdAcknowledgeJSONRequestBody = AnomalyReview
+
+// PostEstateIdAnomaliesAnomalyIdDismissJSONRequestBody defines body for PostEstateIdAnomaliesAnomalyIdDismiss for application/json ContentType.
+type PostEstateIdAnomaliesAnomalyIdDismissJSONRequestBody = AnomalyReview
+
+// PostEstateIdHarvestsJSONRequestBody defines body for PostEstateIdHarvests for application/json ContentType.
+type PostEstateIdHarvestsJSONRequestBody = PostEstateIdHarvestsJSONBody
+
+// PostEstateIdStatsRegionsJSONRequestBody defines body for PostEstateIdStatsRegions for application/json ContentType.
+type PostEstateIdStatsRegionsJSONRequestBody = RegionStatsRequest
+
+// PostEstateIdTreeJSONRequestBody defines body for PostEstateIdTree for application/json ContentType.
+type PostEstateIdTreeJSONRequestBody = Tree
+
+// PostEstateIdTreeImportJSONRequestBody defines body for PostEstateIdTreeImport for application/json ContentType.
+type PostEstateIdTreeImportJSONRequestBody = PostEstateIdTreeImportJSONBody
+
+// PatchEstateIdTreeTreeIdJSONRequestBody defines body for PatchEstateIdTreeTreeId for application/json ContentType.
+type PatchEstateIdTreeTreeIdJSONRequestBody = TreeUpdate
+
+// PostEstateIdTreeTreeIdMeasurementsJSONRequestBody defines body for PostEstateIdTreeTreeIdMeasurements for application/json ContentType.
+type PostEstateIdTreeTreeIdMeasurementsJSONRequestBody = TreeMeasurement
+
+// PostEstateIdTreeTreeIdObservationsJSONRequestBody defines body for PostEstateIdTreeTreeIdObservations for application/json ContentType.
+type PostEstateIdTreeTreeIdObservationsJSONRequestBody = TreeObservation
+
+// PostSpeciesJSONRequestBody defines body for PostSpecies for application/json ContentType.
+type PostSpeciesJSONRequestBody = Species
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// List estates
+	// (GET /estate)
+	GetEstate(ctx echo.Context, params GetEstateParams) error
+	// Create a new estate
+	// (POST /estate)
+	PostEstate(ctx echo.Context) error
+	// Delete an estate
+	// (DELETE /estate/{id})
+	DeleteEstateId(ctx echo.Context, id openapi_types.UUID) error
+	// Get an estate
+	// (GET /estate/{id})
+	GetEstateId(ctx echo.Context, id openapi_types.UUID) error
+	// Update an estate
+	// (PATCH /estate/{id})
+	PatchEstateId(ctx echo.Context, id openapi_types.UUID) error
+	// List height anomalies
+	// (GET /estate/{id}/anomalies)
+	GetEstateIdAnomalies(ctx echo.Context, id openapi_types.UUID, params GetEstateIdAnomaliesParams) error
+	// Detect height anomalies
+	// (POST /estate/{id}/anomalies/detect)
+	PostEstateIdAnomaliesDetect(ctx echo.Context, id openapi_types.UUID) error
+	// Acknowledge an anomaly
+	// (POST /estate/{id}/anomalies/{anomalyId}/acknowledge)
+	PostEstateIdAnomaliesAnomalyIdAcknowledge(ctx echo.Context, id openapi_types.UUID, anomalyId openapi_types.UUID) error
+	// Dismiss an anomaly
+	// (POST /estate/{id}/anomalies/{anomalyId}/dismiss)
+	PostEstateIdAnomaliesAnomalyIdDismiss(ctx echo.Context, id openapi_types.UUID, anomalyId openapi_types.UUID) error
+	// Archive an estate
+	// (POST /estate/{id}/archive)
+	PostEstateIdArchive(ctx echo.Context, id openapi_types.UUID) error
+	// Calculate the drone's total travel distance with an optional max_distance parameter
+	// (GET /estate/{id}/drone-plan)
+	GetEstateIdDronePlan(ctx echo.Context, id openapi_types.UUID, params GetEstateIdDronePlanParams) error
+	// Forecast the yield of an estate
+	// (GET /estate/{id}/forecast)
+	GetEstateIdForecast(ctx echo.Context, id openapi_types.UUID, params GetEstateIdForecastParams) error
+	// Analyse the gaps of an estate
+	// (GET /estate/{id}/gaps)
+	GetEstateIdGaps(ctx echo.Context, id openapi_types.UUID, params GetEstateIdGapsParams) error
+	// Record harvests
+	// (POST /estate/{id}/harvests)
+	PostEstateIdHarvests(ctx echo.Context, id openapi_types.UUID) error
+	// Get the height heatmap of an estate as PNG
+	// (GET /estate/{id}/heatmap.png)
+	GetEstateIdHeatmapPng(ctx echo.Context, id openapi_types.UUID, params GetEstateIdHeatmapPngParams) error
+	// Get the height heatmap of an estate as SVG
+	// (GET /estate/{id}/heatmap.svg)
+	GetEstateIdHeatmapSvg(ctx echo.Context, id openapi_types.UUID, params GetEstateIdHeatmapSvgParams) error
+	// Get the tree on a plot
+	// (GET /estate/{id}/plot/{x}/{y})
+	GetEstateIdPlotXY(ctx echo.Context, id openapi_types.UUID, x int, y int) error
+	// Get the monthly report of an estate
+	// (GET /estate/{id}/report)
+	GetEstateIdReport(ctx echo.Context, id openapi_types.UUID, params GetEstateIdReportParams) error
+	// Restore an archived estate
+	// (POST /estate/{id}/restore)
+	PostEstateIdRestore(ctx echo.Context, id openapi_types.UUID) error
+	// Get stats of trees in an estate
+	// (GET /estate/{id}/stats)
+	GetEstateIdStats(ctx echo.Context, id openapi_types.UUID, params GetEstateIdStatsParams) error
+	// Get stats of trees per species and age cohort
+	// (GET /estate/{id}/stats/breakdown)
+	GetEstateIdStatsBreakdown(ctx echo.Context, id openapi_types.UUID) error
+	// Get stats of regions of an estate
+	// (POST /estate/{id}/stats/regions)
+	PostEstateIdStatsRegions(ctx echo.Context, id openapi_types.UUID, params PostEstateIdStatsRegionsParams) error
+	// Get a stat of an estate over time
+	// (GET /estate/{id}/stats/timeseries)
+	GetEstateIdStatsTimeseries(ctx echo.Context, id openapi_types.UUID, params GetEstateIdStatsTimeseriesParams) error
+	// Get a map tile of an estate
+	// (GET /estate/{id}/tiles/{z}/{x}/{y})
+	GetEstateIdTilesZXY(ctx echo.Context, id openapi_types.UUID, z int, x int, y string, params GetEstateIdTilesZXYParams) error
+	// Add a tree to an estate
+	// (POST /estate/{id}/tree)
+	PostEstateIdTree(ctx echo.Context, id openapi_types.UUID) error
+	// Import trees into an estate
+	// (POST /estate/{id}/tree/import)
+	PostEstateIdTreeImport(ctx echo.Context, id openapi_types.UUID, params PostEstateIdTreeImportParams) error
+	// Delete a tree
+	// (DELETE /estate/{id}/tree/{treeId})
+	DeleteEstateIdTreeTreeId(ctx echo.Context, id openapi_types.UUID, treeId openapi_types.UUID) error
+	// Get a tree
+	// (GET /estate/{id}/tree/{treeId})
+	GetEstateIdTreeTreeId(ctx echo.Context, id openapi_types.UUID, treeId openapi_types.UUID) error
+	// Update a tree
+	// (PATCH /estate/{id}/tree/{treeId})
+	PatchEstateIdTreeTreeId(ctx echo.Context, id openapi_types.UUID, treeId openapi_types.UUID) error
+	// Get the harvests of a tree
+	// (GET /estate/{id}/tree/{treeId}/harvests)
+	GetEstateIdTreeTreeIdHarvests(ctx echo.Context, id openapi_types.UUID, treeId openapi_types.UUID) error
+	// Get the measurements of a tree
+	// (GET /estate/{id}/tree/{treeId}/measurements)
+	GetEstateIdTreeTreeIdMeasurements(ctx echo.Context, id openapi_types.UUID, treeId openapi_types.UUID) error
+	// Record a tree measurement
+	// (POST /estate/{id}/tree/{treeId}/measurements)
+	PostEstateIdTreeTreeIdMeasurements(ctx echo.Context, id openapi_types.UUID, treeId openapi_types.UUID) error
+	// Get the health observations of a tree
+	// (GET /estate/{id}/tree/{treeId}/observations)
+	GetEstateIdTreeTreeIdObservations(ctx echo.Context, id openapi_types.UUID, treeId openapi_types.UUID) error
+	// Record a tree health observation
+	// (POST /estate/{id}/tree/{treeId}/observations)
+	PostEstateIdTreeTreeIdObservations(ctx echo.Context, id openapi_types.UUID, treeId openapi_types.UUID) error
+	// List the trees of an estate
+	// (GET /estate/{id}/trees)
+	GetEstateIdTrees(ctx echo.Context, id openapi_types.UUID, params GetEstateIdTreesParams) error
+	// Export the trees of an estate
+	// (GET /estate/{id}/trees/export)
+	GetEstateIdTreesExport(ctx echo.Context, id openapi_types.UUID, params GetEstateIdTreesExportParams) error
+	// List the trees near a point
+	// (GET /estate/{id}/trees/nearby)
+	GetEstateIdTreesNearby(ctx echo.Context, id openapi_types.UUID, params GetEstateIdTreesNearbyParams) error
+	// Get the yield of an estate
+	// (GET /estate/{id}/yield)
+	GetEstateIdYield(ctx echo.Context, id openapi_types.UUID, params GetEstateIdYieldParams) error
+	// Greet the user
+	// (GET /hello)
+	GetHello(ctx echo.Context, params GetHelloParams) error
+	// Get stats of a portfolio of estates
+	// (GET /portfolio/stats)
+	GetPortfolioStats(ctx echo.Context, params GetPortfolioStatsParams) error
+	// List species
+	// (GET /species)
+	GetSpecies(ctx echo.Context) error
+	// Create a species
+	// (POST /species)
+	PostSpecies(ctx echo.Context) error
+}
+
+// ServerInterfaceWrapper converts echo contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler ServerInterface
+}
+
+// GetEstate converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstate(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEstateParams
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "order" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "order", ctx.QueryParams(), &params.Order)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter order: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// ------------- Optional query parameter "q" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "q", ctx.QueryParams(), &params.Q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter q: %s", err))
+	}
+
+	// ------------- Optional query parameter "company" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "company", ctx.QueryParams(), &params.Company)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter company: %s", err))
+	}
+
+	// ------------- Optional query parameter "tag" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag", ctx.QueryParams(), &params.Tag)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tag: %s", err))
+	}
+
+	// ------------- Optional query parameter "region" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "region", ctx.QueryParams(), &params.Region)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter region: %s", err))
+	}
+
+	// ------------- Optional query parameter "archived" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "archived", ctx.QueryParams(), &params.Archived)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter archived: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "min_width" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "min_width", ctx.QueryParams(), &params.MinWidth)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter min_width: %s", err))
+	}
+
+	// ------------- Optional query parameter "max_width" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "max_width", ctx.QueryParams(), &params.MaxWidth)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter max_width: %s", err))
+	}
+
+	// ------------- Optional query parameter "min_length" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "min_length", ctx.QueryParams(), &params.MinLength)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter min_length: %s", err))
+	}
+
+	// ------------- Optional query parameter "max_length" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "max_length", ctx.QueryParams(), &params.MaxLength)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter max_length: %s", err))
+	}
+
+	// ------------- Optional query parameter "min_trees" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "min_trees", ctx.QueryParams(), &params.MinTrees)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter min_trees: %s", err))
+	}
+
+	// ------------- Optional query parameter "max_trees" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "max_trees", ctx.QueryParams(), &params.MaxTrees)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter max_trees: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstate(ctx, params)
+	return err
+}
+
+// PostEstate converts echo context to params.
+func (w *ServerInterfaceWrapper) PostEstate(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostEstate(ctx)
+	return err
+}
+
+// DeleteEstateId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteEstateId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteEstateId(ctx, id)
+	return err
+}
+
+// GetEstateId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstateId(ctx, id)
+	return err
+}
+
+// PatchEstateId converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchEstateId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PatchEstateId(ctx, id)
+	return err
+}
+
+// GetEstateIdAnomalies converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdAnomalies(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEstateIdAnomaliesParams
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstateIdAnomalies(ctx, id, params)
+	return err
+}
+
+// PostEstateIdAnomaliesDetect converts echo context to params.
+func (w *ServerInterfaceWrapper) PostEstateIdAnomaliesDetect(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostEstateIdAnomaliesDetect(ctx, id)
+	return err
+}
+
+// PostEstateIdAnomaliesAnomalyIdAcknowledge converts echo context to params.
+func (w *ServerInterfaceWrapper) PostEstateIdAnomaliesAnomalyIdAcknowledge(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "anomalyId" -------------
+	var anomalyId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "anomalyId", ctx.Param("anomalyId"), &anomalyId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter anomalyId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostEstateIdAnomaliesAnomalyIdAcknowledge(ctx, id, anomalyId)
+	return err
+}
+
+// PostEstateIdAnomaliesAnomalyIdDismiss converts echo context to params.
+func (w *ServerInterfaceWrapper) PostEstateIdAnomaliesAnomalyIdDismiss(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "anomalyId" -------------
+	var anomalyId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "anomalyId", ctx.Param("anomalyId"), &anomalyId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter anomalyId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostEstateIdAnomaliesAnomalyIdDismiss(ctx, id, anomalyId)
+	return err
+}
+
+// PostEstateIdArchive converts echo context to params.
+func (w *ServerInterfaceWrapper) PostEstateIdArchive(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostEstateIdArchive(ctx, id)
+	return err
+}
+
+// GetEstateIdDronePlan converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdDronePlan(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEstateIdDronePlanParams
+	// ------------- Optional query parameter "max_distance" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "max_distance", ctx.QueryParams(), &params.MaxDistance)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter max_distance: %s", err))
+	}
+
+	// ------------- Optional query parameter "as_of" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "as_of", ctx.QueryParams(), &params.AsOf)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter as_of: %s", err))
+	}
+
+	// ------------- Optional query parameter "route" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "route", ctx.QueryParams(), &params.Route)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter route: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstateIdDronePlan(ctx, id, params)
+	return err
+}
+
+// GetEstateIdForecast converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdForecast(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEstateIdForecastParams
+	// ------------- Optional query parameter "horizon" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "horizon", ctx.QueryParams(), &params.Horizon)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter horizon: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstateIdForecast(ctx, id, params)
+	return err
+}
+
+// GetEstateIdGaps converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdGaps(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEstateIdGapsParams
+	// ------------- Optional query parameter "spacing" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "spacing", ctx.QueryParams(), &params.Spacing)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter spacing: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstateIdGaps(ctx, id, params)
+	return err
+}
+
+// PostEstateIdHarvests converts echo context to params.
+func (w *ServerInterfaceWrapper) PostEstateIdHarvests(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostEstateIdHarvests(ctx, id)
+	return err
+}
+
+// GetEstateIdHeatmapPng converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdHeatmapPng(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEstateIdHeatmapPngParams
+	// ------------- Optional query parameter "downsample" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "downsample", ctx.QueryParams(), &params.Downsample)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter downsample: %s", err))
+	}
+
+	// ------------- Optional query parameter "cell" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cell", ctx.QueryParams(), &params.Cell)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cell: %s", err))
+	}
+
+	// ------------- Optional query parameter "route" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "route", ctx.QueryParams(), &params.Route)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter route: %s", err))
+	}
+
+	// ------------- Optional query parameter "max_distance" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "max_distance", ctx.QueryParams(), &params.MaxDistance)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter max_distance: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstateIdHeatmapPng(ctx, id, params)
+	return err
+}
+
+// GetEstateIdHeatmapSvg converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdHeatmapSvg(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEstateIdHeatmapSvgParams
+	// ------------- Optional query parameter "downsample" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "downsample", ctx.QueryParams(), &params.Downsample)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter downsample: %s", err))
+	}
+
+	// ------------- Optional query parameter "cell" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cell", ctx.QueryParams(), &params.Cell)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cell: %s", err))
+	}
+
+	// ------------- Optional query parameter "route" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "route", ctx.QueryParams(), &params.Route)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter route: %s", err))
+	}
+
+	// ------------- Optional query parameter "max_distance" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "max_distance", ctx.QueryParams(), &params.MaxDistance)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter max_distance: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstateIdHeatmapSvg(ctx, id, params)
+	return err
+}
+
+// GetEstateIdPlotXY converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdPlotXY(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "x" -------------
+	var x int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "x", ctx.Param("x"), &x, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter x: %s", err))
+	}
+
+	// ------------- Path parameter "y" -------------
+	var y int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "y", ctx.Param("y"), &y, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter y: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstateIdPlotXY(ctx, id, x, y)
+	return err
+}
+
+// GetEstateIdReport converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdReport(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEstateIdReportParams
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// ------------- Optional query parameter "month" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "month", ctx.QueryParams(), &params.Month)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter month: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstateIdReport(ctx, id, params)
+	return err
+}
+
+// PostEstateIdRestore converts echo context to params.
+func (w *ServerInterfaceWrapper) PostEstateIdRestore(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostEstateIdRestore(ctx, id)
+	return err
+}
+
+// GetEstateIdStats converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdStats(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEstateIdStatsParams
+	// ------------- Optional query parameter "as_of" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "as_of", ctx.QueryParams(), &params.AsOf)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter as_of: %s", err))
+	}
+
+	// ------------- Optional query parameter "percentiles" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "percentiles", ctx.QueryParams(), &params.Percentiles)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter percentiles: %s", err))
+	}
+
+	// ------------- Optional query parameter "bucket_width" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "bucket_width", ctx.QueryParams(), &params.BucketWidth)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter bucket_width: %s", err))
+	}
+
+	// ------------- Optional query parameter "bbox" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "bbox", ctx.QueryParams(), &params.Bbox)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter bbox: %s", err))
+	}
+
+	// ------------- Optional query parameter "rows" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "rows", ctx.QueryParams(), &params.Rows)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter rows: %s", err))
+	}
+
+	// ------------- Optional query parameter "columns" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "columns", ctx.QueryParams(), &params.Columns)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter columns: %s", err))
+	}
+
+	// ------------- Optional query parameter "polygon" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "polygon", ctx.QueryParams(), &params.Polygon)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter polygon: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstateIdStats(ctx, id, params)
+	return err
+}
+
+// GetEstateIdStatsBreakdown converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdStatsBreakdown(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstateIdStatsBreakdown(ctx, id)
+	return err
+}
+
+// PostEstateIdStatsRegions converts echo context to params.
+func (w *ServerInterfaceWrapper) PostEstateIdStatsRegions(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostEstateIdStatsRegionsParams
+	// ------------- Optional query parameter "as_of" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "as_of", ctx.QueryParams(), &params.AsOf)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter as_of: %s", err))
+	}
+
+	// ------------- Optional query parameter "percentiles" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "percentiles", ctx.QueryParams(), &params.Percentiles)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter percentiles: %s", err))
+	}
+
+	// ------------- Optional query parameter "bucket_width" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "bucket_width", ctx.QueryParams(), &params.BucketWidth)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter bucket_width: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostEstateIdStatsRegions(ctx, id, params)
+	return err
+}
+
+// GetEstateIdStatsTimeseries converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdStatsTimeseries(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEstateIdStatsTimeseriesParams
+	// ------------- Optional query parameter "metric" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "metric", ctx.QueryParams(), &params.Metric)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter metric: %s", err))
+	}
+
+	// ------------- Optional query parameter "interval" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "interval", ctx.QueryParams(), &params.Interval)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter interval: %s", err))
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", ctx.QueryParams(), &params.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter from: %s", err))
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstateIdStatsTimeseries(ctx, id, params)
+	return err
+}
+
+// GetEstateIdTilesZXY converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdTilesZXY(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "z" -------------
+	var z int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "z", ctx.Param("z"), &z, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter z: %s", err))
+	}
+
+	// ------------- Path parameter "x" -------------
+	var x int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "x", ctx.Param("x"), &x, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter x: %s", err))
+	}
+
+	// ------------- Path parameter "y" -------------
+	var y string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "y", ctx.Param("y"), &y, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter y: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEstateIdTilesZXYParams
+	// ------------- Optional query parameter "color" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "color", ctx.QueryParams(), &params.Color)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter color: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstateIdTilesZXY(ctx, id, z, x, y, params)
+	return err
+}
+
+// PostEstateIdTree converts echo context to params.
+func (w *ServerInterfaceWrapper) PostEstateIdTree(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostEstateIdTree(ctx, id)
+	return err
+}
+
+// PostEstateIdTreeImport converts echo context to params.
+func (w *ServerInterfaceWrapper) PostEstateIdTreeImport(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostEstateIdTreeImportParams
+	// ------------- Optional query parameter "atomic" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "atomic", ctx.QueryParams(), &params.Atomic)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter atomic: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostEstateIdTreeImport(ctx, id, params)
+	return err
+}
+
+// DeleteEstateIdTreeTreeId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteEstateIdTreeTreeId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "treeId" -------------
+	var treeId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "treeId", ctx.Param("treeId"), &treeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter treeId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteEstateIdTreeTreeId(ctx, id, treeId)
+	return err
+}
+
+// GetEstateIdTreeTreeId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdTreeTreeId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "treeId" -------------
+	var treeId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "treeId", ctx.Param("treeId"), &treeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter treeId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstateIdTreeTreeId(ctx, id, treeId)
+	return err
+}
+
+// PatchEstateIdTreeTreeId converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchEstateIdTreeTreeId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "treeId" -------------
+	var treeId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "treeId", ctx.Param("treeId"), &treeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter treeId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PatchEstateIdTreeTreeId(ctx, id, treeId)
+	return err
+}
+
+// GetEstateIdTreeTreeIdHarvests converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdTreeTreeIdHarvests(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "treeId" -------------
+	var treeId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "treeId", ctx.Param("treeId"), &treeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter treeId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstateIdTreeTreeIdHarvests(ctx, id, treeId)
+	return err
 }
 
-// Error defines model for Error.
-type Error struct {
-	Message *string `json:"message,omitempty"`
-}
+// GetEstateIdTreeTreeIdMeasurements converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdTreeTreeIdMeasurements(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
 
-// Estate defines model for Estate.
-type Estate struct {
-	// Id Unique identifier for the estate
-	Id *openapi_types.UUID `json:"id,omitempty"`
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
 
-	// Length Length of the estate in 10m plots
-	Length *int `json:"length,omitempty"`
+	// ------------- Path parameter "treeId" -------------
+	var treeId openapi_types.UUID
 
-	// Width Width of the estate in 10m plots
-	Width *int `json:"width,omitempty"`
-}
+	err = runtime.BindStyledParameterWithOptions("simple", "treeId", ctx.Param("treeId"), &treeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter treeId: %s", err))
+	}
 
-// EstateStats defines model for EstateStats.
-type EstateStats struct {
-	Count  *int `json:"count,omitempty"`
-	Max    *int `json:"max,omitempty"`
-	Median *int `json:"median,omitempty"`
-	Min    *int `json:"min,omitempty"`
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstateIdTreeTreeIdMeasurements(ctx, id, treeId)
+	return err
 }
 
-// Tree defines model for Tree.
-type Tree struct {
-	// EstateId ID of the estate this tree belongs to
-	EstateId *openapi_types.UUID `json:"estate_id,omitempty"`
+// PostEstateIdTreeTreeIdMeasurements converts echo context to params.
+func (w *ServerInterfaceWrapper) PostEstateIdTreeTreeIdMeasurements(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
 
-	// Height Height of the tree in meters (1 to 30)
-	Height *int `json:"height,omitempty"`
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
 
-	// Id Unique identifier for the tree
-	Id *openapi_types.UUID `json:"id,omitempty"`
+	// ------------- Path parameter "treeId" -------------
+	var treeId openapi_types.UUID
 
-	// X X coordinate of the tree in its plot
-	X *int `json:"x,omitempty"`
+	err = runtime.BindStyledParameterWithOptions("simple", "treeId", ctx.Param("treeId"), &treeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter treeId: %s", err))
+	}
 
-	// Y Y coordinate of the tree in its plot
-	Y *int `json:"y,omitempty"`
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostEstateIdTreeTreeIdMeasurements(ctx, id, treeId)
+	return err
 }
 
-// GetEstateIdDronePlanParams defines parameters for GetEstateIdDronePlan.
-type GetEstateIdDronePlanParams struct {
-	MaxDistance *int `form:"max_distance,omitempty" json:"max_distance,omitempty"`
-}
+// GetEstateIdTreeTreeIdObservations converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdTreeTreeIdObservations(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
 
-// PostEstateJSONRequestBody defines body for PostEstate for application/json ContentType.
-type PostEstateJSONRequestBody = Estate
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
 
-// PostEstateIdTreeJSONRequestBody defines body for PostEstateIdTree for application/json ContentType.
-type PostEstateIdTreeJSONRequestBody = Tree
+	// ------------- Path parameter "treeId" -------------
+	var treeId openapi_types.UUID
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
-	// Create a new estate
-	// (POST /estate)
-	PostEstate(ctx echo.Context) error
-	// Calculate the drone's total travel distance with an optional max_distance parameter
-	// (GET /estate/{id}/drone-plan)
-	GetEstateIdDronePlan(ctx echo.Context, id openapi_types.UUID, params GetEstateIdDronePlanParams) error
-	// Get stats of trees in an estate
-	// (GET /estate/{id}/stats)
-	GetEstateIdStats(ctx echo.Context, id openapi_types.UUID) error
-	// Add a tree to an estate
-	// (POST /estate/{id}/tree)
-	PostEstateIdTree(ctx echo.Context, id openapi_types.UUID) error
+	err = runtime.BindStyledParameterWithOptions("simple", "treeId", ctx.Param("treeId"), &treeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter treeId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstateIdTreeTreeIdObservations(ctx, id, treeId)
+	return err
 }
 
-// ServerInterfaceWrapper converts echo contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler ServerInterface
+// PostEstateIdTreeTreeIdObservations converts echo context to params.
+func (w *ServerInterfaceWrapper) PostEstateIdTreeTreeIdObservations(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "treeId" -------------
+	var treeId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "treeId", ctx.Param("treeId"), &treeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter treeId: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostEstateIdTreeTreeIdObservations(ctx, id, treeId)
+	return err
 }
 
-// PostEstate converts echo context to params.
-func (w *ServerInterfaceWrapper) PostEstate(ctx echo.Context) error {
+// GetEstateIdTrees converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdTrees(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEstateIdTreesParams
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "order" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "order", ctx.QueryParams(), &params.Order)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter order: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "x1" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "x1", ctx.QueryParams(), &params.X1)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter x1: %s", err))
+	}
+
+	// ------------- Optional query parameter "y1" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "y1", ctx.QueryParams(), &params.Y1)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter y1: %s", err))
+	}
+
+	// ------------- Optional query parameter "x2" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "x2", ctx.QueryParams(), &params.X2)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter x2: %s", err))
+	}
+
+	// ------------- Optional query parameter "y2" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "y2", ctx.QueryParams(), &params.Y2)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter y2: %s", err))
+	}
+
+	// ------------- Optional query parameter "min_height" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "min_height", ctx.QueryParams(), &params.MinHeight)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter min_height: %s", err))
+	}
+
+	// ------------- Optional query parameter "max_height" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "max_height", ctx.QueryParams(), &params.MaxHeight)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter max_height: %s", err))
+	}
+
+	// ------------- Optional query parameter "species" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "species", ctx.QueryParams(), &params.Species)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter species: %s", err))
+	}
+
+	// ------------- Optional query parameter "health" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "health", ctx.QueryParams(), &params.Health)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter health: %s", err))
+	}
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PostEstate(ctx)
+	err = w.Handler.GetEstateIdTrees(ctx, id, params)
 	return err
 }
 
-// GetEstateIdDronePlan converts echo context to params.
-func (w *ServerInterfaceWrapper) GetEstateIdDronePlan(ctx echo.Context) error {
+// GetEstateIdTreesExport converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdTreesExport(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "id" -------------
 	var id openapi_types.UUID
@@ -124,21 +2583,21 @@ func (w *ServerInterfaceWrapper) GetEstateIdDronePlan(ctx echo.Context) error {
 	}
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params GetEstateIdDronePlanParams
-	// ------------- Optional query parameter "max_distance" -------------
+	var params GetEstateIdTreesExportParams
+	// ------------- Optional query parameter "format" -------------
 
-	err = runtime.BindQueryParameter("form", true, false, "max_distance", ctx.QueryParams(), &params.MaxDistance)
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter max_distance: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetEstateIdDronePlan(ctx, id, params)
+	err = w.Handler.GetEstateIdTreesExport(ctx, id, params)
 	return err
 }
 
-// GetEstateIdStats converts echo context to params.
-func (w *ServerInterfaceWrapper) GetEstateIdStats(ctx echo.Context) error {
+// GetEstateIdTreesNearby converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdTreesNearby(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "id" -------------
 	var id openapi_types.UUID
@@ -148,13 +2607,43 @@ func (w *ServerInterfaceWrapper) GetEstateIdStats(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEstateIdTreesNearbyParams
+	// ------------- Required query parameter "x" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "x", ctx.QueryParams(), &params.X)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter x: %s", err))
+	}
+
+	// ------------- Required query parameter "y" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "y", ctx.QueryParams(), &params.Y)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter y: %s", err))
+	}
+
+	// ------------- Optional query parameter "radius" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "radius", ctx.QueryParams(), &params.Radius)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter radius: %s", err))
+	}
+
+	// ------------- Optional query parameter "k" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "k", ctx.QueryParams(), &params.K)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter k: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetEstateIdStats(ctx, id)
+	err = w.Handler.GetEstateIdTreesNearby(ctx, id, params)
 	return err
 }
 
-// PostEstateIdTree converts echo context to params.
-func (w *ServerInterfaceWrapper) PostEstateIdTree(ctx echo.Context) error {
+// GetEstateIdYield converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEstateIdYield(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "id" -------------
 	var id openapi_types.UUID
@@ -164,8 +2653,127 @@ func (w *ServerInterfaceWrapper) PostEstateIdTree(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEstateIdYieldParams
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", ctx.QueryParams(), &params.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter from: %s", err))
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// ------------- Optional query parameter "block_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "block_size", ctx.QueryParams(), &params.BlockSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter block_size: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEstateIdYield(ctx, id, params)
+	return err
+}
+
+// GetHello converts echo context to params.
+func (w *ServerInterfaceWrapper) GetHello(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetHelloParams
+	// ------------- Optional query parameter "id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "id", ctx.QueryParams(), &params.Id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PostEstateIdTree(ctx, id)
+	err = w.Handler.GetHello(ctx, params)
+	return err
+}
+
+// GetPortfolioStats converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPortfolioStats(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPortfolioStatsParams
+	// ------------- Optional query parameter "ids" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "ids", ctx.QueryParams(), &params.Ids)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter ids: %s", err))
+	}
+
+	// ------------- Optional query parameter "tag" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag", ctx.QueryParams(), &params.Tag)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tag: %s", err))
+	}
+
+	// ------------- Optional query parameter "company" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "company", ctx.QueryParams(), &params.Company)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter company: %s", err))
+	}
+
+	// ------------- Optional query parameter "rank_by" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "rank_by", ctx.QueryParams(), &params.RankBy)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter rank_by: %s", err))
+	}
+
+	// ------------- Optional query parameter "percentiles" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "percentiles", ctx.QueryParams(), &params.Percentiles)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter percentiles: %s", err))
+	}
+
+	// ------------- Optional query parameter "bucket_width" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "bucket_width", ctx.QueryParams(), &params.BucketWidth)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter bucket_width: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetPortfolioStats(ctx, params)
+	return err
+}
+
+// GetSpecies converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSpecies(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetSpecies(ctx)
+	return err
+}
+
+// PostSpecies converts echo context to params.
+func (w *ServerInterfaceWrapper) PostSpecies(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostSpecies(ctx)
 	return err
 }
 
@@ -197,32 +2805,230 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.GET(baseURL+"/estate", wrapper.GetEstate)
 	router.POST(baseURL+"/estate", wrapper.PostEstate)
+	router.DELETE(baseURL+"/estate/:id", wrapper.DeleteEstateId)
+	router.GET(baseURL+"/estate/:id", wrapper.GetEstateId)
+	router.PATCH(baseURL+"/estate/:id", wrapper.PatchEstateId)
+	router.GET(baseURL+"/estate/:id/anomalies", wrapper.GetEstateIdAnomalies)
+	router.POST(baseURL+"/estate/:id/anomalies/detect", wrapper.PostEstateIdAnomaliesDetect)
+	router.POST(baseURL+"/estate/:id/anomalies/:anomalyId/acknowledge", wrapper.PostEstateIdAnomaliesAnomalyIdAcknowledge)
+	router.POST(baseURL+"/estate/:id/anomalies/:anomalyId/dismiss", wrapper.PostEstateIdAnomaliesAnomalyIdDismiss)
+	router.POST(baseURL+"/estate/:id/archive", wrapper.PostEstateIdArchive)
 	router.GET(baseURL+"/estate/:id/drone-plan", wrapper.GetEstateIdDronePlan)
+	router.GET(baseURL+"/estate/:id/forecast", wrapper.GetEstateIdForecast)
+	router.GET(baseURL+"/estate/:id/gaps", wrapper.GetEstateIdGaps)
+	router.POST(baseURL+"/estate/:id/harvests", wrapper.PostEstateIdHarvests)
+	router.GET(baseURL+"/estate/:id/heatmap.png", wrapper.GetEstateIdHeatmapPng)
+	router.GET(baseURL+"/estate/:id/heatmap.svg", wrapper.GetEstateIdHeatmapSvg)
+	router.GET(baseURL+"/estate/:id/plot/:x/:y", wrapper.GetEstateIdPlotXY)
+	router.GET(baseURL+"/estate/:id/report", wrapper.GetEstateIdReport)
+	router.POST(baseURL+"/estate/:id/restore", wrapper.PostEstateIdRestore)
 	router.GET(baseURL+"/estate/:id/stats", wrapper.GetEstateIdStats)
+	router.GET(baseURL+"/estate/:id/stats/breakdown", wrapper.GetEstateIdStatsBreakdown)
+	router.POST(baseURL+"/estate/:id/stats/regions", wrapper.PostEstateIdStatsRegions)
+	router.GET(baseURL+"/estate/:id/stats/timeseries", wrapper.GetEstateIdStatsTimeseries)
+	router.GET(baseURL+"/estate/:id/tiles/:z/:x/:y", wrapper.GetEstateIdTilesZXY)
 	router.POST(baseURL+"/estate/:id/tree", wrapper.PostEstateIdTree)
+	router.POST(baseURL+"/estate/:id/tree/import", wrapper.PostEstateIdTreeImport)
+	router.DELETE(baseURL+"/estate/:id/tree/:treeId", wrapper.DeleteEstateIdTreeTreeId)
+	router.GET(baseURL+"/estate/:id/tree/:treeId", wrapper.GetEstateIdTreeTreeId)
+	router.PATCH(baseURL+"/estate/:id/tree/:treeId", wrapper.PatchEstateIdTreeTreeId)
+	router.GET(baseURL+"/estate/:id/tree/:treeId/harvests", wrapper.GetEstateIdTreeTreeIdHarvests)
+	router.GET(baseURL+"/estate/:id/tree/:treeId/measurements", wrapper.GetEstateIdTreeTreeIdMeasurements)
+	router.POST(baseURL+"/estate/:id/tree/:treeId/measurements", wrapper.PostEstateIdTreeTreeIdMeasurements)
+	router.GET(baseURL+"/estate/:id/tree/:treeId/observations", wrapper.GetEstateIdTreeTreeIdObservations)
+	router.POST(baseURL+"/estate/:id/tree/:treeId/observations", wrapper.PostEstateIdTreeTreeIdObservations)
+	router.GET(baseURL+"/estate/:id/trees", wrapper.GetEstateIdTrees)
+	router.GET(baseURL+"/estate/:id/trees/export", wrapper.GetEstateIdTreesExport)
+	router.GET(baseURL+"/estate/:id/trees/nearby", wrapper.GetEstateIdTreesNearby)
+	router.GET(baseURL+"/estate/:id/yield", wrapper.GetEstateIdYield)
+	router.GET(baseURL+"/hello", wrapper.GetHello)
+	router.GET(baseURL+"/portfolio/stats", wrapper.GetPortfolioStats)
+	router.GET(baseURL+"/species", wrapper.GetSpecies)
+	router.POST(baseURL+"/species", wrapper.PostSpecies)
 
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xWUW/bRgz+KwQ3YBugxfKaAYXe0rXojA2Y0WTYhiEIrjraukK6U3hUEiPwfx/uZE1e",
-	"dF7SIcn60BdDvhM/UuT3kbzF0jWts2TFY3GLvqyoUfHxNTtLy1rZ8Kdl1xKLoXhVK6tJXyiZXt2EH9m0",
-	"hAUaK7Qmxm2Gm9TxNhuO3PsPVEp4UZyo+kIbL8qW9FCrN8yOp8E05L1a76N4YWPXB0C8KKEpitHhV5Mv",
-	"2bRinMUCf7XmsiMwmqyYlSGGlWOQioB6lAxXjpuQIew6ozG7G0GGNdm1VFPsn+M5uNUeHhgL87yBtnbi",
-	"R7C9DF8bnQL7LRx/HNbh3JyK6mnyzwSVrrOSrnujDhCiIW16aiXujH1o4c+YEhXrv/MiVbjF6zvJkMp4",
-	"ECaC91Q7u/Yg7iHVq8isK5k6+DGeD04isLHQkBB7+HoO4uBF/k2yhB9HtAD9kEBvpqC/Q+kca2NDAu5E",
-	"asRHZiQj3Eyx/vhvWNNihiNjV27q4iyUKFSpIjhZLkC7smvIigr3MR+n6trIkh28o5I7I+EWWnYB+Sh4",
-	"N1IHX8n3TpYLzPCK2Pfu5kd5+FbXklWtwQJfHOVHOWbYKqkiv2Y0tgrnEyT4gSmkQ4Gl67EjBIrGmBca",
-	"C1w6L2+GK6bLjry8cnrTK8oK9ZpSbVubMprNPnhnxy4dnr5kWmGBX8zGNj7b9fDZDjwmNuAbJo2FcEfx",
-	"wLfO+l4v3+XzJ/GaSooOuT3O88dzGHt/wt8rpeFdn9fg8/vn8LmwQmxVDafEV8QwvJih75pG8eYgOUSt",
-	"PRZ/7nqXx/NgtCPa7Nbo7UyHgfxtu5vIa0rxTtVlV/dtjSAafBUamqgahNUV1TCMVrg2UoGy4KKxqqFR",
-	"N38PXmgVq9i0Jrx9SzvaLvS4IgRx7AzCV9xiaOFRMJihVU3Uvsa7RMz28n1PI9tmO9DLjngzou5Hjft4",
-	"jbGm6Ros5onucz5RwOORY8xKgiC//PR/8f84P356nz0zwDqBleus/rSE9yTqGIQb8VK69cPWlJTsWxKI",
-	"b8ThyUQ+TE5lD42NPfn169gzSO8p1bK/WX7Wyyell/upef/QkmFBT+5JJ1qD6tdFcf9C+nFXWuizfu99",
-	"HtI//lYWw3/mnWz0+XkjSxH9MA0Hgkf2B3oHuwjU067jGgusRNpiNqtdqerKeSle5i9z3J5v/woAAP//",
-	"T9jWFV8RAAA=",
+	"H4sIAAAAAAAC/+x9a5fbNrLgX8FqZ0+SM3Rb3bHnJvbZD46dON4bJ33cmcx4Znx1ILIkYZoEGADsbsXr",
+	"/35PFQA+JJCi+mUnV1/slgQCRaBQ78f7SaqKUkmQ1kyevJ+YdAUFpz+fLeF5pS/gVAlp8YtSqxK0FUA/",
+	"8yXM1sA1fbDrEiZPJrIq5qAnH5LJ+XJWgqYB+HsGJtWitELJyZPJt1clpBYy9t1337BLEMuVZWrBlARW",
+	"8rxg6gI04wwfZtwyuxKG8SVMks11PtTfqPm/IbW48jOpCp6vX4CF1K23BTmN8B+6kP2sAQxbqEpmzFgu",
+	"MyGXTFW2WVpIC0v3jhktAdmM0/YslC7wr0nGLTywomgBbKwWcokPaeBGSQdHlglcl+enHfhiK7WBfBbg",
+	"ZyVo5iacRHZCg1H5BWTbr/lTCZLV28CkYrmSS9DuzROmoVD4YOytrYb+nTOp0tHnBk7qB2HsjkMSFgr6",
+	"408aFpMnk//9sMHahx5lHyIEfspJsxzXmtPnVFXS3tK+qwWzK2BgLLdAp4B/VWb7FAbe+407uK03T1UG",
+	"+D/Iqpg8+edkRRdklmlVTpKJxE9zVWlcLFUrpe3kXQTPwN+x7ZM61XAhVGWYmzhhSrMCMsGl/ya8XrMU",
+	"DnFrJUxIVoAFbbavYzKh899e8gwfXfA8ZwutCrd3gQb4NYVkWs0rY9294zpjGVwIjjOYvW7+G7gQcLm9",
+	"r1JZAq3gVz+AXNrV5MnJdDrd2rvY1N/kKj1/KyDPIpiqgc9WkFquIU4M5/j07CqOY+7Hdc+PlUxXfdi5",
+	"4voCjI1cxh9paTxHP4ZpSJXOTPxGKynBEL32rxF9C0eoZ+fL6K9Xx3Egr07i3697xq+j46NngrRKyOU3",
+	"6mr7UBw4hZCiwFt0nOwN2vCz48F8TvfmpVaXdrUN5xxydTlzl4EHdtU9TPco00hqaDj7avp/ahIUrpGS",
+	"LXYzVyoHLh3Zw+W3Z322BH+lmeQFJGy+Rh7r+C0wkFlYwoBGYAdozGxJIM4QxBgy4r1XmhHnYJfCrlRl",
+	"GWdlzqVFBosMc5JMZJXnfJ7D5InVFUSoy+Ayr4EHysRwoCS6TAJIZKpBLlYAN5WGDDcjB24ss5cihYR9",
+	"OWUZXxM5LJQGxkuu7Uhu90IrCac5j1D8nMusFiM28LgHRcdin1ZVbLPOwNKRCGlKJyYxGhk9Zqssz2eZ",
+	"QMKcQhygnv1sCJE7/AthhMNWxuXW6iN38lutlX4DplTSwPaWFWAMX7bhbFF2Db9WQkOGjDUMfBdbg1h7",
+	"jNinK3FRH9YG9ogC2oLBJTcsPJAwPjcgLbtciRwYT624wBeOSo0aePaTzNcbN6E5kiAjdNf/qxS/VsAM",
+	"3Woc0hVUEmYsimesKkvQD1JucK2SWwsaH/+vfz578A/+4Lfpg6/fNX8+ePd+mnx5/OFPkygcRcnlehuU",
+	"5+4Hpi4l3vAGiEnSZsDHEf6bTFIN3O6xx378tXdTZL17KTKQViwEyca6+x71YlUlstju5P4tN+d2b78h",
+	"RQrJjqcFK3Nl42w6V2nNIYbk4B/CuA/JBEn79vrfVwWXDLcFyS3R/y4wYw5JKhu78d9pgAcWrixzA5Kd",
+	"8hYe0DLK+d7Q951NMoy2ATIm5BgoLV/GyDxfEpOoyi5yMiGtYiUKqyoXytQ3JleXzY2ptZH21Qn3xv0/",
+	"G7w1Bb965eY4mW4rKlWZ7YX9OTIo/9C1r8ClyGKY+jf8ej9E/dBLTb9TGlIeU/ZQK4igktDGIsNli/Dk",
+	"xuvFNneltPhNyRky6jivErKs7E51MkD7yo2mC3gJuk/+LVQG+dg5X9PgD8mkVEL2itRWRUgHv8aWxAX8",
+	"Da0QIcEjFgUeMplgWofONfCYHEWsJP4C/Yhw6tlzFwncSuP1fTdXTNWXcGVnaaWNihifntP3NTXHsazk",
+	"S6gZtKc4dK1Kvozs6IYcEQDvlyPegBG/wXMlF7lI7T4iyw5JVVXWiMyRBAQJDJLGTBQgDSrOCYqwhTKW",
+	"HU+nk2TcxuLUsW21upJEeyN0YgV2BV4qdqIe14DgIfGYo6ZmmF1xyXJhLGQRVWVv4ezMcmu293JLHd/Q",
+	"ezTwTbFI6fCN50QRRJ9X6TnYWU0nt8kKGZjiP62A53Z1E9vTpiBdgmZppTUirJu91wSVTFbCWLXUPEJj",
+	"v70AvWbu5RrjDEmQYCyzij5bnuf0UQMkDIrSrpmSYJiQaV5lkI1Fre8DJN/QijEsK3iPzlMAl61fWqSX",
+	"jFfxn4SMT1aCTkFake9BcE7rZ2JwN0LM0BxOpMHxxmYZXMQIcVnlJMBFLGEBT53RLGqCG0Ps0e6+RmtW",
+	"mO/4hBVK2pVhVYmHzs0MDfKaWZXx9TjzW+tWfqOBn2fqUsbdBs7uYGJaA/1ANgKH6Albq0ouEfkWJA58",
+	"LoqC20qD/yVhpRYFXuI8S5gBKXL4IsGXkqyS5xKB2IPuvUShkN4hdsamhHRfk/TQjP3biIKeN7yMsxn1",
+	"WX4iu/y2s6PJsF2G+ODYHezYuiL712cw2mn5CQLiTlFnt4nIjQiI37z7dQxSQlrQFzyPMu4CrBZp9CcS",
+	"+/ZAoxoZnC8uxpvViO3pR7e/kvoQE8EhR86tWLricglPmSqERRlj4X5BLp/DwrJKuhHICuL+jCHjwYDq",
+	"fLuKcL8aO6CV9mqV9eltj9g4n165of9MbuhuQIlzwOMQoQvk5PCXIfgWEqbVJdqGtbocSwVazpLITgy6",
+	"NEbf89vzfYxa7hZcJLGD3tAxt866/ZYRqRKFKb2ejd80/4BVew0fdvwUwCVuDE3n7cMRsrvgqVWa8bLM",
+	"BWRBsESPQ4pBBk7hzMUFGWQ0QFS2+bXi2oJG8i5UTLhPbcVzN1ftGfEarJN3StDMvxfzs20ivMqzmjm2",
+	"UX4Lmm39CPrwmn6a8SVks/l65qS3oZEZ8GzodwR55t9j57jA1maBxo+hQV1TRVyMw4OLmCiEBK7ZHOwl",
+	"gGSO1zEuM7bIuWV8Yb1pgTRs57caRVe6ASlRF79ciAyiSPgckQLRzTP+mnVH0CzVkIm5yIVdz/oJzff+",
+	"F1SxL1ciRQnCaoDPDFq9ayyj22MYN6yocIxpqZ6fxYmS9/h7WaYYiKT5q3F3qbbbhEulFhRKM+Ru23rt",
+	"cN/9vehB5V5OGi7npddNto0EdXQB8HS1eQ1vcPE0GJFVPJ8ZsSx4xNtVauC1rpOrJSMCgl9wRzGsGqAX",
+	"HsBxWtBLXj6TPF8bYWKhHQ4JZ179jL5dBtIg5u1iNqSGz5wZNq7j9v/kZJvZgMnCjTAxC6ReOq5KA4IS",
+	"4fRG2l9RiNEaw0teNirx9rkGnN2tVoeR5GEdIsY9h/amxwfxjKVKWrGsMGLGvTNhMNlAaIcT9m8lJGQo",
+	"JdkVCM2MyIh/dQ+/Po4NC0wzE5rWY1aodszCvYdZeDIXUUMbQW7LBWRWbKErYZkfxFKKoRuOqei6/a7n",
+	"x3CXdyayzhTeQbfzaU/sIZsp2ZlghxgKeiOwKO6MuiZUVkN4oygHQhagAZ6yK+Kya1bwtTOAzL1ypip7",
+	"xGp+pRaBG0BGiOe0OCHPHTMR1tCER2N8nB3hcMNEjXEDrfhORG2PD41d+i9Tdr4kMks/xfjS1fbUf2ep",
+	"UjoTknwVbur68Oidorcn4qh+e52ZNmzVHbRpNJz25rzrv1nfcJuu3kCpdOSSgdZKm5hr9N8+fK6WW1uW",
+	"/ql/k6LjrRztckBdb2vF4wdzjtJGqYzomCK9luVp1xzfZqRbcJPeL7jIIRuh0hmm/ftHj9kpfCNnch7e",
+	"hE3Z5YqiYtdMDM4/QCTjoaxtEXKcldw9MM5e+D3kuRoVDQNXvChzfJqeSdjflM6z/7XTvzXkitk06EdE",
+	"nl4RI+itfVr5ps/e+SBWG/QEl03ajogx5/VDy4S0aX7voreXBr1TsOBlQq7DgpfMeQ+SrWAuK2wVC8/5",
+	"wf8SplaVBc1SpaVDSSLFnx8nx1/g+7AMll4PLviVY5tfPW7x0AdfPa7frCGWGMjdt3746foAHH81bUNA",
+	"H7dA0KovlPE5WoYuhQEWxmzHFvhVnyI9m6LZyTBdSUZheMjeUpVXhTTMqMqu2rB9+ZcObF/+ZRu2DcSu",
+	"j6q9bTEsb3l+tm93rcxvMGf63kdsOv2wZF7u71qfyc0TY3zlSDPSKXKpWw8dPA1hMPu5W7e16evYf2Oa",
+	"0mZMOXmY3UY68XmlckrYcHp4bEfjElgsQOcefJC9mvQdOCe5PI+w9DoKwvnW3CV86v83zKKlztA+c0ZT",
+	"xLht48jsj7LdC9+ujWk38tFfC+NQlM7zesN2I6DXTwbgaIXBbIh8HAX0xJ8VHse2qWQQQ2L3OYIrnWiB",
+	"//FefS7PZ/M4jepF/Bh2v+EyFvQUZKBhzbg3EgydM0p7nri3FNRn8zjlmlgUlx6zj9gzJxwgN5NLE0w9",
+	"3h5CgrOwrOTGgGFAMSULkVvQzIB92sTRKwlOunZM+GhLeprP1dWuE2snftDFxZffec7uAFpkd6e6Xqp8",
+	"vYztzy8Ib0rhLwwVDZ240JkvcV+Op9OngTpQlDtkS4qfY0IakY22dhNTj2Gkuhz7rv1nTpc/rq20DH97",
+	"hOHtEeDQguCNC1m7ORCNDbEbW1sI6T8dR0Bry4JhxXdRiDvWxS1oeyy2sYA98j+5IUzJFJi3cUJcAIyb",
+	"DBE1DEMYlEGO0Lhor22H7cM2U/LU219DPuJSO6FJCy6XVc715F1f0sgu0PEC8TzvyTNtRRtGggW3Tums",
+	"Cc6JByDsSJZIGBwtj5gFCZpPdqYlXM8+WfCrWZ/GECzrPE2hDFa9Vn5knXbZqGOPp7GtK4TsXeSs8Hr0",
+	"7lUGWVKP8Lpxq2jr/eAOXJ2deNd/mnEatW8clp9sHH3ajLbZWr0ELVQ2FK8eZEQ3cEyE9qh0tPl6Mzev",
+	"XmH7gC54XkXQ/hf8OjzuQpQiOX9u3oRhBBSbw4KS3eTa4colb2DaHSUV3WINsKOiwGZ8scqBkvkMMwLp",
+	"Zu0Z6r10WzJ11Ib+6sWGCYKqDdB7NsLOGEO4i8Kd+Sjc7XtH39c+SG6dLQufYWpu0BeNIxP/3doJVXYF",
+	"miQm6fzkTU44DZokkyWXKgNd8EkyUXqdupwbWVktQNpZBguRCpApjlU43SSZUHTBuzH+kGHbRsuK0VCO",
+	"hFzOwZOFggiOE9Ywf2tRYD1GJvXltPZOd7JXWwe3X2aWV7p2HpXnuLOYbPcieNFrVG/4885r3KJLm9EH",
+	"jdXNxwiEsZFpRjo8wr7j3t7U5TFirr6LHGouRMSia9THGHTg3QRB6T4FkAaQbeeaoY7AUGmPDWjEcoXX",
+	"naoj7Ke0d2tFRH3kWO1gz03uKdPQhdPf2vBWsWoPNa0LdEmVICfJhKcYeJ2j5oNbmAlTCGMgm7wbdm/u",
+	"3Pub2jQ3ArL38VZQumAstsQRNZduq11EW5NX/9RHCsTy3wcowIC5ZF+rSN82vCpKpe21/Y6ohn40nyMq",
+	"PJ7DLEQOCZMKZfjKxTfh18/PfkFOmrXDdm7ZF+lcE0N+SEFbPDxLcAH01M0ZlXWl24fCLkE3bn9cwp9l",
+	"MkqLQtR47YS7AmLS721oQX1k+3WQdIeUni+nu3STa8ZZBJl2OPO2aDaHxAPLz0EmLIMFr3JLpjFJsdIj",
+	"SbGqdIhXpBkmTyYFlxVFJgayWn+RaScp5SLr0bsNRkiu1biAlAHSu2O3NiMg+hW5DYSKK3StTd0vu6Y1",
+	"80jtTgP8GAob4Qo8z39aTJ78c1RO5JaI44tizDyaRsR+zXFrHuRCAgvD29IyR+rpP5F5qCt+teICw1o9",
+	"VqH+pTZStfs1s3ebGxQ/rb5iH8B1NIBz18Y25zH6CH9qVKa7IVHXpCF1OsnuqgdO69tFbmo1JAy/Pqnx",
+	"M4wjDQat+MJG1IcpCTNBTW3priZhpGGSg/+YfV6IPPsCgXzMPqfp4Is2HW/HMkzjjsVN8fJ21N67I4Me",
+	"4ne7kTZ+sdqbuRcZbM08/g7F0/DvOnm+Zey6QTZ63zvdZSJbI7UMix9d88L1jQZt1eIpeZJIlcCPLM3J",
+	"FoZ7vcuQMAzseteQ7Z3+sJWd/jPazIQD59npK5aptCpA+hAfxJYzfinsqVbsDaS6EhZ/RTcAzoheQCEX",
+	"avIETYkJKZG8FJMnE1eeaEV78xDqkkxLiFnPRR3B4HPYHBIjGgrpjWtGaVIR5utQNIghtSTFTQNPXJoK",
+	"eS79sKaWAv1G5NhpiQQoJRLIVxnWaQP7baieU3LNg0jwz/cTgQD+WoFeB2P4kwmCMkl8wdU2kWtxr4SC",
+	"HiI060MSn5S8kdFZuUkn7tj2mM6TgfZ8kSc3sBa4TlfMgi5YgcGgkDG+5ELWx+MKDrlYLucPjSz9665V",
+	"o/D6iKK9AEbyTmUpauxJudZrp0kiUvNlD4zul72hrMP79wCSsDvUEqsBxU31mS2uoBhVZeiBNjwdW7il",
+	"EsZhDs7E5snGCTXdoZD1zYnuIBerE4Fo99P86iZPCznzOcbXXfwmjws5C0mO11t8xNNk4HdhwURAT6bT",
+	"CZm4pPWKPSVjukDYh//2tWibCXc7/UmAiLCDn/4TOcuj21yvU/IvsuQ3PGMhmuBDMnl8n2u/kha05Dk7",
+	"I/Ga0QPEOE1VFFyvN7jTJCSQt0oHUbiJibC158QNGGcSLpvqbF3Oc6pMw3p8GaBvVLa+5eN2797Iu1ZX",
+	"8GELyY7vZNXYpmQfG8seTb++v7Xdbjjpj+eol6wZXAljzSeJ8HG83cb7D0mQ7B6+F9kHdwFyiMnwp6AL",
+	"jqDma+bGNBFqzKqlM4a6Wh55HvwXgVJ2r8wLet7t6ausR2JD2bMhu14fbGN/m/7u8F1EyPGjiPeRwPoE",
+	"EPvRvSO2VNaVn/8k0fnFBr710PCoZvISbPOgw09EzA3NogDLM255v17xsfB0eg8U/af/POD8p4bzHbzt",
+	"E1oo3W87RoPMMM3Tn/WiO4YWm5UW8pzZFVZSUFWeYbTwBTj2VhcE2agmSGHEi8pA5hTzVbvwoKsyeLQt",
+	"KSG4936b7kogc7s8Tiw7XOJ7ucS3KhLGaofGrG8uroAuznyj6CYVt2xFt/WIj4+Op/e+bytu2BxA1maN",
+	"T5IIblKyMULsw07zmn5rJZ4LbzeVqVdJ2Go7ZijxdRFXwGQdP9B5nuqqOCfE0ZAcUXeyuQ8S2GPFqMuV",
+	"btsq948i2jaY1a/YVJwdZ9eq3fDOrtWycu0yc92l5NTulXSgvFHKeyBhQ7YnH1HDWzc/0LHmuwFK9tCF",
+	"TiLAcUPVGREql/tFXpI2Pas9AG0xDU+OCZu0vvQ6eyuIjtMYw8rQtaoVKnLEfm4SqH2nNhL/fNV+bpr3",
+	"feqTMmvmp8EFZ7FzgNLQGi6WMhBQNtwljRYKLdYITN817WjAPtciva5B3e9do9vqtncgTgfitJ9hBTHn",
+	"5uTpvftz/Qq/bQSHfnr1mutzJFCqueVrohjNw5mXuGiUq9qOx0rpsLU0Uj+bcikpRbYzxUhq8CxA/6x5",
+	"9j6Fs+6k9V5+irpvt83fB6/93hF96zSVPJA2XwxH10h/d8rvLlj8qTRkrtEPDgR3gOC2CAwSNn+QN6K3",
+	"fudH0lrEnhaBbNPe+gh7Ce+LDbIrvMHvHErbTvGhnnKVNE7w3JcI+1UOBPhAgH9fBPhA9frETHejr0Hx",
+	"3Fv107ZnbkDXdkfaqPAddXLnxTBH7FXwRzc0y+m3KDyi5KjBqa47CJaH6eANPHgD709u2MTzUZZwSlh6",
+	"UPqyKVFT+HOep9jxyOU70AOfGUb1Q5jV/ALyJpllSyrAWLj61/o2DNm+mz68H8/23Ya6G1S5I4xyq/p7",
+	"uxtUUygiFIERhoq9PyXUMlXpLV59nX9joFInqEn0nfuazGyC6VLS2MJ5KRC/nJWQKkpR3/dL87QNkBtp",
+	"fD4Tvgo5Nirp8z+SdqMIPwMLoc+xlwjdhSNWfg9dK9mu/qKBaJTHIVww5jboKbvKzRW6qXluMO1AGZDU",
+	"ZVuyZ1R7xSeoMskLfJm//3D29x74/ZbHX4BoQQO+/4iLT97dL0FvrhbSgfY8FzI7UiXIqyJ372IeqMVC",
+	"pBAyBY4M1eg3KwBb5Ef0f3fhGu3mQnK9jiDegY0cDI97BCjeCdsJHJHmizHERasJbpQdhsYrBBY26rsM",
+	"ptEmgizPQ/u+0KZ1KS5AttzC1Kbe9XNUhdJUGiy8RWh9UsfNUO9a+uRa46JAiimHGWg8PprniH2LNNhR",
+	"XNN0y7DKg8LROYLT1z19EmZSnru6RSvM2LfM9wlqpYTYlR/dvAsljy1UVXfeMO2C3YOO7e+arrj3wds3",
+	"S8fgnjcQxCi5704cJ+VfH3fKGT/+iP7mjY7NB+J6IK77ENcOEas7jcZUh3CtI6Ryycv+AJrnqpJu+lYr",
+	"lo0YmoXwBG6rhQulKlJVliLxnuBWfyRXztFnqLmqwC4z0Nct9ItZFao3sk6X+s8MwxqJjKRsxpmxfLkE",
+	"lMubmonM944fpGYvcQM+BiVrCl6yUAAyTs6aX2OSqa8UuU/hyBgsyzpotGkI5I/gDxHb024ZdSC0B0K7",
+	"l1WGEMfJsEgv+8isy4LZprHt3iRx++YbaqZCCyzijZ2avuExIuxsEXjFmp4r3DJOiddeqvQ/UGKwCYE6",
+	"aOSmGY/Yz6GxDIqePrhHYdoueXycNSHMIQy74LnInraKUViqVb3RMccH8DibyLDBNbRO+sQjxvdtLtPU",
+	"Sq4pYG+15HsNMY90RjoQxk+aMD46Obnn0z9TBXTvspD+4ktlVygjXPJALT5J2u0p66ohLiOE4hVwW/Dy",
+	"qJTLXtn4DcgMNBXbTyHP66pS2CdA1ZWEm7LPSUeGtppLU3INsh1x7kY6jd43tfENkY4YFa6uixLgUWDv",
+	"f0PdpshCgFAYJ0uTTIkUm+ehfWIHqhVVWJPdNk/CF08clJa/dztzKpf3QaaTnsrmoN2eG4FFW7CAiJM5",
+	"6c1MKL6NMaehVGHBy1Ay92T66Cu/WZyFZgERybbZ3ht4D07FFeS7IM59TfIegI+nJ49YSRP1gIpTx+tG",
+	"fHmS7Akx2Zedu4Jlml/KxmZEjblqZKWew41dj+kgdYROFcaq0rja76NdBjf1D7x2L95YFRv40PvrbY5e",
+	"kwzL3573aLcGIwq+hIeesNzI8O6vIqMZD0z6oL0MZJi2mIvnbRuZA4ad/vhyjCrjOaO56OeMZ7xw9Mu3",
+	"4T798WVYNWGULsDOfnl5xJ4TDVYLx0OJP7liZ8EXSbqFM++g85HrQJG4YY7ipJbLZQ5jWNbZxe+XZT0+",
+	"PjlwrAPH+pgcy1ws/3xV5AeudeBanxLXOvtlDNdCHejh+6sPD9+vP/SyrbAiTlJ37ua25fn16l1o67Bl",
+	"B+xlQcgD/v7240U6Xw3OOaqsXHfC9fUnfHfHccwHI1YrgJlw+UBx9qI4zi4uvV18BHXRdXuHIUORM4/j",
+	"yC4RW5AJvlAS40aM82TKDAM9gH3/8+sfqMKvC0Znzx6x0xffJbHyRkmnxRhO7bx5daPR0PQo2SCpLjO4",
+	"lj4QNi9++JDCjWDCpI5oUZ3U3SP2vY+QpFa2dRemVsMrekkqlqEWWFS7LnGcVlqDtG7A042sE7tSxi8W",
+	"2jWFjRSGLUGC5lHnQosAewvnR3LzIqjuuYStbJG3pOnxsYh1eW5boORcZotx4iUhllq0t+3zt2/fvn3w",
+	"+vUXyfbu74aNhnVA8272yZPJf/1z+uDrd+8ffXjg/jj58Kcbx7vjm+4pdSYTC1f2IW1V59Gd4qk7raCm",
+	"cmt5uqIyyvjyGeMLC51SL3gX3IYcRNkDYxlmLIQn+TrGBUaF+fu8lSEvMg1gvNmQgKdGoX4rQjAH4W1l",
+	"sKOgCxIc9Mz6eQ+pMIdUmPt03PUh86jLYkLvsV5dj0Y03aGE7LTiNpQqkDDOLpU+nyt17kN7mQeRUfg8",
+	"XSTeSFnu20FZxHVF+xiiyNhUlrvLUnmuioI/MIDvjmSo1UseIUBcqiwkzvzL5+oC2JS22DlDMaKs1Q7t",
+	"KTueJiePk/94nHw93S24tBbbry68b3TYHPKcWvAzqozebjP0eDcQ7tFIUfX9Sr1vNAXiGHxOZ+uw2irG",
+	"G/M4Irl3A3PDro6T9XFydZKsT/pgxP7we+1QDwChManvY+eC5R9Y1WtcvTS3uq7vWj9iaT/yNlb3He2T",
+	"4P6grge+5tJFaGrfOoTk6Oio1cweh0G2BLPRzj6G0G6l/YA+ZFB1mukfcqgO6sOnrz4MyCrjpaGHcw38",
+	"HJ2EO23g1HwnwQyshBVC+mLCmdiMGWoqytXwkOcwdKHG5/BzU2tup2T0TQ3kH0Ld2Hinw+U/XP4bXv7N",
+	"+9W5W2NJgc/Q6bclBEpgeBGkG27Yy29/ZtuzkR3XRWwsvC5xMq2TgLqR6r6jMCWSO7caFnojzxrK+HJZ",
+	"C6/4dO2ZL7kxYLwZ2vUOa4yaOHI4tpxuoU9yOaheB9Xr2qrXHVV7cqjpsdTT5fvNBmhBcKh/fGBUN2NU",
+	"MdK/B3OyogADeqi0Oy7H8fJrkQ7Ioty2fZBE2i4BzpPgivS57+iSXAPXSZNllSOEttOIPMyA67iO5D6Y",
+	"XrZizJZaXdoV07h6eKDh0E1kmnu/I/acvje1U9UDjtNQFH+uLukJLptyAFwDW+SY75rttjT+3GzmR6zF",
+	"QwfVYxlwisXMscaWiSB0AN38vYDOJ37V+iDqX8Y34AylGvrA8/7OABYi0CSpv/YoNEkmiEGjfLLfCW0s",
+	"Vo8ImOvOB7ESrdxznp47oYAkL11bj0rQQmVP2fGJ/xMJw0JpwNG7fcpaFTcTIX7gcbCtwi93AmDVXsvf",
+	"vW7WuhoHdndgd3u3bSKG12U4jr6LYhy7I4H44fvfPuwMTmwFETVsji1BaViABpk2rl5FxqG/v/0HRWTj",
+	"Ei6cgeLwvQ8rU1QmpskBxt9f83KurtgvkFql2c8iDzV5fOB0/YAI/cfXoNm/XFzUvyZH9IgzW6ecWhIr",
+	"Khh8zioU/VvA+yhtmqbwzcIHORnN/I97C6Hs7v0/FBHOyEq/DS5UqxUnnZD2L8d4dGj3nVOiJZRcgrFx",
+	"SEZGeE5Hr03FjOqFpdIoLlHjbh/IBEfLI3Z8gjmSiEHHJ0fFRQ9ww9Gim9FDf/7Xv44+L+Xy/xcX9os/",
+	"jVNtKXfEc6XMp8cjutMFqyPednIoTEKJd/auJQwXRHdzlwU6Ggq6cA8u6MI9QFj3D3S6vXyy155aYBMP",
+	"BxKzgQaoygbTs3F5rAfeOP36o/BG6pDiVjmw6N0sOvDAfWt24PcD9YizLFTQsKozb78NkoLTf7/dEJvY",
+	"+vtrTt0Xz//JtKY+yOefasme3gs65uI/FEUI6Y/f/1f0u2eJJCVx9vzsF7YIDJNxdpWskybUPgOd1KUt",
+	"87Ub1HGTuoyjmZJN2IrSYfL/d/bTj4zKxNSOoCP2LXlDMAQl1OMhy34uzoESRSn2B8c+db/iUNPu3SWk",
+	"q/jjQair99Tj6so97G/0UlYVIv2/eO3r+h9NsaBK5mCCj6YN1dFOuuj286NI92cIPHJVQpbed+hzzNCO",
+	"xC1HC54bqCGYK5UDlzcj0KOKDzmquVVcyAfFp+ZiZ0z8/bkdmsPvL0LjL5v2Aw40/xOpRnT8+P4A+ats",
+	"Kqv7FRkh7i2XRRqDj8/ozjMR0NITzTmkvDLAjCqgoaC+XtInySQ7XEzI6zDK9/jvq+yDI3w5WIjZrtCu",
+	"7dlx4oIFrqhMgc+n8ZWQF5DnkG3xihc0bZtb0CFlHy+d1ob1bzPw6NH2xrk3zw65qwdxd2eDR8QUf8Ui",
+	"dzcZ8qK2BMWuMXu+JrfTqxeDptk/6oU8pKUfrvYnY8bqvdcl6m+xyt1aQ9opoUEeo+DwIY4sbFCO68qE",
+	"tWuXF9Br1MIl/4jX/26MZn8tszrt8H4VqwPNOdCca9Ech7L9ZGdQFeiUux7MMggDmaYirb5pVtATVJ6B",
+	"sWwhtLHjBJD7rCD9uxRE/AYdAjwPtOHmhbnC5a3linG1lbu0ohVfOYJeOEGm9YwLDNfrBojr0I3XbSAO",
+	"tKNfoGht1IGGHGjIzSuitC5elI609Jyhvhk8Rhrq+Vxri0hA9xxSVYDpFEHaTm/c7b36I1KRu9GFWnt0",
+	"FwpRqfGgrE8cENmobQzfqDka8g807UDTbtJowptYWmRmb/1JzQ3mI9iQoDkoEy1c37fWI7elTf3UBuMg",
+	"FfUTtdZGHaSiAwW5uWZFIcbdO30j4WiLRjTTfWb8ch0hqT00LiQRhLi5lbmOrPTHoi13Iyu19uggKx0o",
+	"3R9XVtqmdyNFpn7pCPlwX04wRV6mlTZKY21lIWnJhBmlqenpfN2q8Y4nG/p3UfYLlZ7ww+Z40vS3uqJf",
+	"6yyRQfHqYybiGhdmuZ3g0rwyJbnsmUJL5Tyi83KTThyK7DGdO51dlSLGtaO9SV29s9CX5SowufaRJ0zI",
+	"NK+MuOgr23F1HHuHMQuur7fget8Ff/CNXK77gifXXO+677fveq/deTc3MzZrK2t8z9n51YjZ+dX1Zj/z",
+	"0eGp6q0A6API96up8jwmxfUs4Mb0pMThTzh2yaXKQBd8kkyUXqeWIJKV1QKknWWwEKkAmeJY6ulEFIFn",
+	"9134D4nvKbWdOcgth2ySPeSVAXFirKjyEK4Gu0ScWQ288IkH26F4lKD9/OyXhL0EhckgCTvl+tcKLEoo",
+	"WJOTck+Ows8uZduwyjfSb7etcaoePVPXNl7RAt3SxnVNEw8D/muSZh8SZspcWMZTrYxxD7nNCWWikumj",
+	"r5LH//E4jG8qpYbSKPNcpef46ZiqSB1PfSHUUA7F96BwDSnKnA/38ifp6turj5ZL4pauK6uGzFlhk7rK",
+	"6t0VWMXEjlbxFPq0BOULrZYOW26t5OoS1J+3b+KmerhdXJWXPF3BUQBn73zney/WukfazEYaPV5i8Bgh",
+	"8kOzswN/6eMvnm7ckMNI4Hq+vrZO7Pps8kw4k56v+kFJiPjUuX8OVwFDkaNI18JHciwcsRe+j1BIM9Sc",
+	"3Km5kEj/hWyR97p4HxaAJI6Hj5Iu4LsdEgBMWAP5AmknaQeZ68pWNyya7uYIP7qN+Xha93B1jt6ea5vK",
+	"z96zbNiD3dmGQ3jK4MprW47Vn/dwHYcTt1esvirmrnpnjUp4SFvg1MvGYDq/UQHHO9QvfkRNb64qfXAF",
+	"HRjBTRUNvCCBEI9gA2v08+z0GM8rWWd8X/r8dBeuR0mM9hJ3yV4qqkdrEjZXdlWTXqLhVlme17W9DVbA",
+	"g0aS950mau2BoHKPSenrF68gtVz7yBzXrsELsiZUvjs+cUUaTSiRi0Xm9m7M4v5WCweeGeQVb2n7Pobe",
+	"4AoC+lOgCnvNfm/uS10RML5Jd1v8bwSIeEy3UwBw2yAmMt+g0CFb4GQ9y9GgmRG/QVxjOp4mHc6xNyc7",
+	"tNJwV8ddnEMrjQN7/Z1EWDiW1KNkbcSuryDP1UBdRltpabAWowYg/2EBxvBltB/09zRXnMNsFqXNBo36",
+	"d5olglAObfXHvmPdM9XgT7UyoNvnSJvtDrFU2i5ULtSIvnB1D2DfBGWrpW7oDZ6qC9DcdZrCh3hqUXlx",
+	"CGWYgdwXhlizVy9Mwixf0vPqUiKe4DZwuU58KWmrlmBXoN0QSdnXSrqOCfgx45bPuQGm8Z4zq0V5xL71",
+	"a1GtHi7P3WoIjOc+57BO2EosV7WO/rQG0ArImFnhs9Qz6/yIPbMsB2T0uKJ7AaWRowVhh6ALExSVcZ0Z",
+	"GM9zvwtFVMQ6Dfvf0/xuuFWBW89tYtOCYNrDOkVmruM5tnx5ncf8KV7nUdzy2Xzdw+StjxQIXD58Dli3",
+	"szA218BHlaPu6QzRbgvRTr9pN45Qi+R/ciOIOyLAG7flk5RyPu0GBKgz+z3Ej55g9dZkDm70naZTP9Cr",
+	"5ynH8MiaH6AamrjONo4Oe8f9FjU8q732d4ZCfolhI9Snaf5oYhrCYYVv+kNdXbk/Py5hpkp9M/2S5wW7",
+	"4FqAXTOlWUp99dEc3bJQeMLm7BCcNDUfPS+sP+toXGv7HG8/BjTMfuexn4SmMRr8+4j2vMcyvO2IHMZz",
+	"1F7XDK6EseaTvE+uTirjg3eKHqE5nCxW6XzyZLKytnzy8GGuUp6vlLFPvpp+NZ0g27PC5oCbwS+FPdWK",
+	"vYFUV8JSSt2z01eTZIJzOSiPj6aTD/89AK0UVAcALQEA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
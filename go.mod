@@ -5,37 +5,47 @@ go 1.23
 require (
     github.com/DATA-DOG/go-sqlmock v1.5.2
     github.com/getkin/kin-openapi v0.127.0
+    github.com/go-pdf/fpdf v0.9.0
     github.com/golang/mock v1.6.0
     github.com/google/uuid v1.6.0
     github.com/labstack/echo/v4 v4.12.0
+    github.com/ledongthuc/pdf v0.0.0-20240201131950-da5b75280b06
     github.com/lib/pq v1.10.9
     github.com/oapi-codegen/runtime v1.1.1
     github.com/sirupsen/logrus v1.9.3
     github.com/stretchr/testify v1.9.0
+    github.com/xitongsys/parquet-go v1.6.2
     github.com/oapi-codegen/oapi-codegen/v2 v2.4.1 
 )
 
 require (
+    github.com/apache/arrow/go/arrow v0.0.0-20200730104253-651201b0f516 // indirect
+    github.com/apache/thrift v0.14.2 // indirect
     github.com/apapsch/go-jsonmerge/v2 v2.0.0 // indirect
     github.com/davecgh/go-spew v1.1.1 // indirect
     github.com/go-openapi/jsonpointer v0.21.0 // indirect
     github.com/go-openapi/swag v0.23.0 // indirect
     github.com/golang-jwt/jwt v3.2.2+incompatible // indirect
+    github.com/golang/snappy v0.0.4 // indirect
     github.com/invopop/yaml v0.3.1 // indirect
     github.com/josharian/intern v1.0.0 // indirect
+    github.com/klauspost/compress v1.16.7 // indirect
     github.com/labstack/gommon v0.4.2 // indirect
     github.com/mailru/easyjson v0.7.7 // indirect
     github.com/mattn/go-colorable v0.1.13 // indirect
     github.com/mattn/go-isatty v0.0.20 // indirect
     github.com/mohae/deepcopy v0.0.0-20170929034955-c48cc78d4826 // indirect
     github.com/perimeterx/marshmallow v1.1.5 // indirect
+    github.com/pierrec/lz4/v4 v4.1.8 // indirect
     github.com/pmezard/go-difflib v1.0.0 // indirect
     github.com/valyala/bytebufferpool v1.0.0 // indirect
     github.com/valyala/fasttemplate v1.2.2 // indirect
+    github.com/xitongsys/parquet-go-source v0.0.0-20200817004010-026bad9b25d0 // indirect
     golang.org/x/crypto v0.27.0 // indirect
     golang.org/x/net v0.29.0 // indirect
     golang.org/x/sys v0.25.0 // indirect
     golang.org/x/text v0.18.0 // indirect
     golang.org/x/time v0.5.0 // indirect
+    golang.org/x/xerrors v0.0.0-20220411194840-2f41105eb62f // indirect
     gopkg.in/yaml.v3 v3.0.1 // indirect
 )
\ No newline at end of file
@@ -2,19 +2,30 @@ package handlers
 
 import (
     "fmt"
+    "math"
     "net/http"
     "strconv"
+    "sawitpro-recruitment/cache"
+    "sawitpro-recruitment/models"
     "sawitpro-recruitment/repositories"
+    "sawitpro-recruitment/spreadsheet"
 
-    "github.com/google/uuid"
     "github.com/labstack/echo/v4"
     "github.com/sirupsen/logrus"
 )
 
+// Drone routes: a survey flies over every plot, an inspection only visits
+// trees that need a scout's attention.
+const (
+    droneRouteSurvey     = "survey"
+    droneRouteInspection = "inspection"
+)
+
 // DroneHandler manages drone-related requests.
 type DroneHandler struct {
     TreeRepo repositories.TreeRepository
     EstateRepo repositories.EstateRepository
+    Cache *cache.EstateCache // Drone plans are cached until the estate or its trees change; nil disables caching
 }
 
 // NewDroneHandler creates a new DroneHandler.
@@ -30,11 +41,16 @@ func NewDroneHandler(treeRepo repositories.TreeRepository, estateRepo repositori
 // @Description Calculate the drone's total travel distance with an optional max_distance parameter
 // @Tags drones
 // @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param id path string true "Estate ID"
 // @Param max_distance query int false "Maximum distance the drone can travel"
+// @Param as_of query string false "Plan over the heights measured up to this date (YYYY-MM-DD)"
+// @Param route query string false "survey (default) flies over every plot, inspection only visits unhealthy trees"
+// @Param format query string false "json (default) or xlsx, a workbook with a sheet summing up the plan; XLSX is also chosen by an Accept header naming it"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /estate/{id}/drone-plan [get]
 func (h *DroneHandler) CalculateDronePlanWithLimit(c echo.Context) error {
@@ -47,7 +63,6 @@ func (h *DroneHandler) CalculateDronePlanWithLimit(c echo.Context) error {
     }).Info("Received request to calculate drone plan")
 
     var maxDistance int
-    var limitReached bool
     if maxDistanceStr != "" {
         var err error
         maxDistance, err = strconv.Atoi(maxDistanceStr)
@@ -61,38 +76,95 @@ func (h *DroneHandler) CalculateDronePlanWithLimit(c echo.Context) error {
         }
     }
 
-    // Convert to UUID
-    estateUUID, err := uuid.Parse(estateID)
+    before, asOf, err := asOfParam(c)
     if err != nil {
         logrus.WithFields(logrus.Fields{
-            "estateID": estateID,
-        }).Warn("Invalid estate ID format")
+            "as_of": c.QueryParam("as_of"),
+        }).Warn("Invalid as_of value")
+        return c.JSON(http.StatusBadRequest, map[string]string{
+            "message": err.Error(),
+        })
+    }
+
+    route := c.QueryParam("route")
+    if route == "" {
+        route = droneRouteSurvey
+    }
+    if route != droneRouteSurvey && route != droneRouteInspection {
+        logrus.WithFields(logrus.Fields{
+            "route": route,
+        }).Warn("Invalid route value")
+        return c.JSON(http.StatusBadRequest, map[string]string{
+            "message": "route must be survey or inspection",
+        })
+    }
+    // Health is only known as it is now, so past inspections cannot be planned
+    if route == droneRouteInspection && before != nil {
+        logrus.Warn("as_of requested for an inspection route")
         return c.JSON(http.StatusBadRequest, map[string]string{
-            "message": "Invalid estate ID format",
+            "message": "as_of is not supported for inspection routes",
         })
     }
 
-    // Check if the estate exists
-    estate, err := h.EstateRepo.GetEstateByID(estateUUID)
+    xlsx, err := xlsxParam(c)
     if err != nil {
         logrus.WithFields(logrus.Fields{
-            "estateID": estateID,
-        }).Error("Database error while retrieving estate")
-        return c.JSON(http.StatusInternalServerError, map[string]string{
-            "message": "Database error while retrieving estate",
+            "format": c.QueryParam("format"),
+        }).Warn("Invalid format value")
+        return c.JSON(http.StatusBadRequest, map[string]string{
+            "message": err.Error(),
         })
     }
+
+    // Check if the estate exists and is not archived, reading the cache
+    // generation first so that a plan computed from changed trees is not cached
+    generation := cacheGeneration(c, h.Cache)
+    estate, err := findActiveEstate(c, h.EstateRepo)
     if estate == nil {
+        return err
+    }
+    estateUUID := estate.ID
+
+    respond := func(plan map[string]interface{}) error {
+        if xlsx {
+            return sendXLSX(c, fmt.Sprintf("estate-%s-drone-plan", estateUUID), func(wb *spreadsheet.Workbook) error {
+                return writeDronePlan(wb, plan)
+            })
+        }
+        return c.JSON(http.StatusOK, plan)
+    }
+
+    // Serve the plan from the cache when neither the estate nor its trees changed
+    cacheKey := fmt.Sprintf("drone-plan:%s:%d:%s", route, maxDistance, asOf)
+    if cached, ok := h.Cache.Get(estateUUID, cacheKey); ok {
         logrus.WithFields(logrus.Fields{
             "estateID": estateID,
-        }).Warn("Estate not found")
-        return c.JSON(http.StatusNotFound, map[string]string{
-            "message": "Estate not found",
-        })
+        }).Info("Drone plan served from cache")
+        return respond(cached.(map[string]interface{}))
     }
 
-    // Get tree heights from the repository
-    treeHeights, err := h.TreeRepo.GetTreesByEstateID(estateUUID)
+    if route == droneRouteInspection {
+        trees, err := h.TreeRepo.GetTreesToInspect(estateUUID)
+        if err != nil {
+            logrus.WithFields(logrus.Fields{
+                "estateID": estateID,
+            }).Error("Database error while fetching trees to inspect")
+            return c.JSON(http.StatusInternalServerError, map[string]string{
+                "message": "Database error while fetching trees to inspect",
+            })
+        }
+        plan := inspectionPlan(trees, maxDistance)
+        h.Cache.Set(estateUUID, cacheKey, generation, plan)
+        return respond(plan)
+    }
+
+    // Get tree heights from the repository, as measured by the as_of date if given
+    var treeHeights map[string]int
+    if before != nil {
+        treeHeights, err = h.TreeRepo.GetTreeHeightsAsOf(estateUUID, *before)
+    } else {
+        treeHeights, err = h.TreeRepo.GetTreesByEstateID(estateUUID)
+    }
     if err != nil {
         logrus.WithFields(logrus.Fields{
             "estateID": estateID,
@@ -106,9 +178,19 @@ func (h *DroneHandler) CalculateDronePlanWithLimit(c echo.Context) error {
         "estateID": estateID,
     }).Info("Fetched tree heights")
 
+    plan := surveyPlan(estate, treeHeights, maxDistance)
+    h.Cache.Set(estateUUID, cacheKey, generation, plan)
+    return respond(plan)
+}
+
+// surveyPlan flies the drone over every plot of the estate, row by row, 10
+// meters per plot plus the climbs and descents between tree heights. With a
+// max distance the drone rests at the first plot it cannot reach.
+func surveyPlan(estate *models.Estate, treeHeights map[string]int, maxDistance int) map[string]interface{} {
     totalDistance := 0
     prevHeight := 0 // Start at ground level
     var landingPlotX, landingPlotY int
+    var limitReached bool
 
     // Simulate drone movement (zigzag)
     for y := 1; y <= estate.Length; y++ {
@@ -143,21 +225,65 @@ func (h *DroneHandler) CalculateDronePlanWithLimit(c echo.Context) error {
             "landingPlotY": landingPlotY,
             "totalDistance": totalDistance,
         }).Info("Drone landed")
-        return c.JSON(http.StatusOK, map[string]interface{}{
+        return map[string]interface{}{
             "distance": totalDistance,
             "rest": map[string]int{
                 "x": landingPlotX,
                 "y": landingPlotY,
             },
-        })
+        }
     }
 
     logrus.WithFields(logrus.Fields{
         "totalDistance": totalDistance,
     }).Info("Drone completed the plan")
-    return c.JSON(http.StatusOK, map[string]interface{}{
+    return map[string]interface{}{
+        "distance": totalDistance,
+    }
+}
+
+// inspectionPlan flies the drone from the ground at plot (1,1) straight to each
+// tree in turn, hovering at its height. Legs between trees are measured as the
+// crow flies, 10 meters per plot. With a max distance the drone rests at the
+// first tree it cannot reach.
+func inspectionPlan(trees []*models.Tree, maxDistance int) map[string]interface{} {
+    totalDistance := 0
+    prevX, prevY, prevHeight := 1, 1, 0
+    visited := 0
+
+    for _, tree := range trees {
+        horizontalDistance := int(math.Round(10 * math.Hypot(float64(tree.X-prevX), float64(tree.Y-prevY))))
+        verticalDistance := abs(tree.Height - prevHeight)
+        if maxDistance > 0 && totalDistance+horizontalDistance+verticalDistance > maxDistance {
+            logrus.WithFields(logrus.Fields{
+                "landingPlotX": tree.X,
+                "landingPlotY": tree.Y,
+                "totalDistance": totalDistance,
+            }).Info("Max distance reached during inspection")
+            return map[string]interface{}{
+                "route":    droneRouteInspection,
+                "distance": totalDistance,
+                "trees":    visited,
+                "rest": map[string]int{
+                    "x": tree.X,
+                    "y": tree.Y,
+                },
+            }
+        }
+        totalDistance += horizontalDistance + verticalDistance
+        prevX, prevY, prevHeight = tree.X, tree.Y, tree.Height
+        visited++
+    }
+
+    logrus.WithFields(logrus.Fields{
+        "totalDistance": totalDistance,
+        "trees":         visited,
+    }).Info("Drone completed the inspection")
+    return map[string]interface{}{
+        "route":    droneRouteInspection,
         "distance": totalDistance,
-    })
+        "trees":    visited,
+    }
 }
 
 // Helper function for absolute value
@@ -6,7 +6,9 @@ import (
     "net/http/httptest"
     "encoding/json"
     "testing"
+    "time"
 
+    "sawitpro-recruitment/cache"
     "sawitpro-recruitment/models"
     "sawitpro-recruitment/mocks"
     "github.com/golang/mock/gomock"
@@ -209,4 +211,158 @@ func TestCalculateDronePlanWithLimit_MaxDistanceReached(t *testing.T) {
             assert.Equal(t, 1, int(landedAt["y"].(float64)))
         }
     }
-}
\ No newline at end of file
+}
+
+func TestCalculateDronePlanWithLimit_ServedFromCache(t *testing.T) {
+    ctrl := gomock.NewController(t)
+    defer ctrl.Finish()
+
+    mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+    mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+    handler := NewDroneHandler(mockTreeRepo, mockEstateRepo)
+    handler.Cache = cache.NewEstateCache()
+
+    e := echo.New()
+    estateID := uuid.New()
+    estate := &models.Estate{ID: estateID, Width: 2, Length: 1}
+
+    // The trees are only read once; the second plan comes from the cache
+    mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(estate, nil).Times(3)
+    mockTreeRepo.EXPECT().GetTreesByEstateID(estateID).Return(map[string]int{"1,1": 5}, nil).Times(2)
+
+    plan := func() map[string]interface{} {
+        req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/drone-plan", nil)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(estateID.String())
+        assert.NoError(t, handler.CalculateDronePlanWithLimit(c))
+        assert.Equal(t, http.StatusOK, rec.Code)
+        var response map[string]interface{}
+        assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+        return response
+    }
+
+    first := plan()
+    assert.Equal(t, first, plan())
+
+    // Invalidating the estate forces the plan to be recomputed
+    handler.Cache.Invalidate(estateID)
+    assert.Equal(t, first, plan())
+}
+
+func TestCalculateDronePlanWithLimit_AsOf(t *testing.T) {
+    ctrl := gomock.NewController(t)
+    defer ctrl.Finish()
+
+    mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+    mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+    handler := NewDroneHandler(mockTreeRepo, mockEstateRepo)
+
+    e := echo.New()
+    estateID := uuid.New()
+    req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/drone-plan?as_of=2024-03-01", nil)
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+    c.SetParamNames("id")
+    c.SetParamValues(estateID.String())
+
+    before := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+    mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 2, Length: 1}, nil)
+    mockTreeRepo.EXPECT().GetTreeHeightsAsOf(estateID, before).Return(map[string]int{"1,1": 5}, nil)
+
+    if assert.NoError(t, handler.CalculateDronePlanWithLimit(c)) {
+        assert.Equal(t, http.StatusOK, rec.Code)
+        var response map[string]interface{}
+        assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+        // Up 5 and 10 across to the tree, down 5 and 10 across to the empty plot
+        assert.Equal(t, 30, int(response["distance"].(float64)))
+    }
+}
+
+func TestCalculateDronePlanWithLimit_InvalidAsOf(t *testing.T) {
+    ctrl := gomock.NewController(t)
+    defer ctrl.Finish()
+
+    mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+    mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+    handler := NewDroneHandler(mockTreeRepo, mockEstateRepo)
+
+    e := echo.New()
+    estateID := uuid.New().String()
+    req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID+"/drone-plan?as_of=yesterday", nil)
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+    c.SetParamNames("id")
+    c.SetParamValues(estateID)
+
+    if assert.NoError(t, handler.CalculateDronePlanWithLimit(c)) {
+        assert.Equal(t, http.StatusBadRequest, rec.Code)
+    }
+}
+
+func TestCalculateDronePlanWithLimit_InspectionRoute(t *testing.T) {
+    ctrl := gomock.NewController(t)
+    defer ctrl.Finish()
+
+    mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+    mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+    handler := NewDroneHandler(mockTreeRepo, mockEstateRepo)
+
+    e := echo.New()
+    estateID := uuid.New()
+    trees := []*models.Tree{
+        {ID: uuid.New(), EstateID: estateID, X: 4, Y: 1, Height: 10, HealthStatus: models.HealthStatusGanoderma},
+        {ID: uuid.New(), EstateID: estateID, X: 1, Y: 5, Height: 12, HealthStatus: models.HealthStatusOryctes},
+    }
+    mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil).Times(2)
+    mockTreeRepo.EXPECT().GetTreesToInspect(estateID).Return(trees, nil).Times(2)
+
+    plan := func(query string) map[string]interface{} {
+        req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/drone-plan?"+query, nil)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(estateID.String())
+        assert.NoError(t, handler.CalculateDronePlanWithLimit(c))
+        assert.Equal(t, http.StatusOK, rec.Code)
+        var response map[string]interface{}
+        assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+        return response
+    }
+
+    // 30 across and 10 up to the first tree, 50 diagonally and 2 up to the second
+    response := plan("route=inspection")
+    assert.Equal(t, "inspection", response["route"])
+    assert.Equal(t, 92, int(response["distance"].(float64)))
+    assert.Equal(t, 2, int(response["trees"].(float64)))
+    assert.Nil(t, response["rest"])
+
+    response = plan("route=inspection&max_distance=60")
+    assert.Equal(t, 40, int(response["distance"].(float64)))
+    assert.Equal(t, 1, int(response["trees"].(float64)))
+    assert.Equal(t, map[string]interface{}{"x": 1.0, "y": 5.0}, response["rest"])
+}
+
+func TestCalculateDronePlanWithLimit_InvalidRoute(t *testing.T) {
+    ctrl := gomock.NewController(t)
+    defer ctrl.Finish()
+
+    mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+    mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+    handler := NewDroneHandler(mockTreeRepo, mockEstateRepo)
+
+    e := echo.New()
+    estateID := uuid.New().String()
+    for _, query := range []string{"route=spiral", "route=inspection&as_of=2024-03-01"} {
+        req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID+"/drone-plan?"+query, nil)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(estateID)
+
+        if assert.NoError(t, handler.CalculateDronePlanWithLimit(c)) {
+            assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+        }
+    }
+}
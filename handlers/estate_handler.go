@@ -1,18 +1,54 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"regexp"
+	"sawitpro-recruitment/cache"
 	"sawitpro-recruitment/models"
+	"sawitpro-recruitment/regions"
 	"sawitpro-recruitment/repositories"
+	"sawitpro-recruitment/spreadsheet"
+	"sawitpro-recruitment/tiles"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
 )
 
+// estateCodePattern matches estate codes such as "RIAU-01".
+var estateCodePattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9-]{0,31}$`)
+
+// estateTagPattern matches estate tags such as "organic" or "north-2".
+var estateTagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// Maximum lengths of the estate text fields.
+const (
+	maxEstateNameLength  = 100
+	maxEstateOwnerLength = 100
+	maxEstateNotesLength = 2000
+	maxEstateTags        = 20
+)
+
+// maxReportedOutOfBoundsTrees caps the trees listed when a resize is refused.
+const maxReportedOutOfBoundsTrees = 100
+
+// Cache keys of the estate stats responses.
+const (
+	statsCacheKey          = "stats"
+	statsBreakdownCacheKey = "stats-breakdown"
+)
+
 // EstateHandler manages estate-related requests.
 type EstateHandler struct {
-	EstateRepo repositories.EstateRepository
+	EstateRepo  repositories.EstateRepository
+	HarvestRepo repositories.HarvestRepository // Yield of the estate's harvests
+	Cache       *cache.EstateCache             // Derived data invalidated when an estate changes; nil disables caching
+	Tiles       *tiles.DiskCache               // Map tiles invalidated when an estate moves, is resized or is deleted; nil disables caching
 }
 
 // NewEstateHandler creates a new EstateHandler.
@@ -45,17 +81,36 @@ func (h *EstateHandler) CreateEstate(c echo.Context) error {
 	}
 
 	// Validate estate dimensions
-	if estate.Width < 1 || estate.Length < 1 || estate.Width > 50000 || estate.Length > 50000 {
+	if !validEstateDimensions(estate) {
 		logrus.Warnf("Invalid estate dimensions: width=%d, length=%d", estate.Width, estate.Length)
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"message": "Estate dimensions must be between 1 and 50000",
 		})
 	}
 
+	// Normalize and validate estate metadata
+	normalizeEstateMetadata(estate)
+	if err := validateEstateMetadata(estate); err != nil {
+		logrus.Warnf("Invalid estate metadata: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+
 	estate.ID = uuid.New()
+	estate.CreatedAt = time.Now().UTC()
+	estate.UpdatedAt = estate.CreatedAt
+	estate.ArchivedAt = nil
 
 	// Call the repository to create estate
-	if err := h.EstateRepo.CreateEstate(estate); err != nil {
+	err := h.EstateRepo.CreateEstate(estate)
+	if errors.Is(err, repositories.ErrDuplicateEstateCode) {
+		logrus.Warnf("Estate code already exists: %s", estate.Code)
+		return c.JSON(http.StatusConflict, map[string]string{
+			"message": "Estate code already exists",
+		})
+	}
+	if err != nil {
 		logrus.Errorf("Failed to store estate in database: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"message": "Failed to store estate in database",
@@ -68,60 +123,617 @@ func (h *EstateHandler) CreateEstate(c echo.Context) error {
 	})
 }
 
+// GetEstate retrieves a single estate with its metadata
+// @Summary Get an estate
+// @Description Get an estate with its dimensions and metadata
+// @Tags estates
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Success 200 {object} models.Estate
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id} [get]
+func (h *EstateHandler) GetEstate(c echo.Context) error {
+	estate, err := findEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	logrus.Infof("Estate retrieved successfully: %s", estate.ID)
+	return c.JSON(http.StatusOK, estate)
+}
+
 // GetEstateStats retrieves stats of trees in an estate
 // @Summary Get stats of trees in an estate
-// @Description Get stats of trees in an estate
+// @Description Get the count, min, max, median, mean, standard deviation, percentiles and histogram of the tree heights of an estate, with the health of its trees and its yield. The region parameters narrow the stats to part of the estate; a plot must pass all of them.
 // @Tags estates
 // @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param id path string true "Estate ID"
-// @Success 200 {object} map[string]int
+// @Param as_of query string false "Compute the stats from the heights measured up to this date (YYYY-MM-DD)"
+// @Param percentiles query string false "Comma-separated percents to compute the height percentiles of, 10,25,75,90 by default"
+// @Param bucket_width query int false "Height histogram bucket width in meters, 5 by default"
+// @Param bbox query string false "Rectangle of plots as x1,y1,x2,y2"
+// @Param rows query string false "Range of rows as from-to"
+// @Param columns query string false "Range of columns as from-to"
+// @Param polygon query string false "Polygon vertices as x1,y1,x2,y2,..."
+// @Param format query string false "json (default) or xlsx, a workbook with a summary and a histogram sheet; XLSX is also chosen by an Accept header naming it"
+// @Success 200 {object} models.EstateStats
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /estate/{id}/stats [get]
 func (h *EstateHandler) GetEstateStats(c echo.Context) error {
-	id := c.Param("id")
+	xlsx, err := xlsxParam(c)
+	if err != nil {
+		logrus.Warnf("Invalid stats format: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+	before, asOf, err := asOfParam(c)
+	if err != nil {
+		logrus.Warnf("Invalid as_of parameter: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+	options, err := statsOptionsParam(c)
+	if err != nil {
+		logrus.Warnf("Invalid stats options: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+	region, err := regionParam(c)
+	if err == nil && region != nil {
+		err = validateRegion(region)
+	}
+	if err != nil {
+		logrus.Warnf("Invalid stats region: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
 
-	// Convert to UUID
-	estateID, err := uuid.Parse(id)
+	generation := cacheGeneration(c, h.Cache)
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	stats, err := h.estateStats(estate, generation, region, options, before, asOf)
 	if err != nil {
-		logrus.Warnf("Invalid estate ID format: %s", id)
+		logrus.Errorf("Failed to get estate stats for ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while fetching estate stats",
+		})
+	}
+
+	logrus.Infof("Estate stats retrieved successfully for ID %s", estate.ID)
+	if xlsx {
+		return sendXLSX(c, fmt.Sprintf("estate-%s-stats", estate.ID), func(wb *spreadsheet.Workbook) error {
+			if err := writeStatsSummary(wb, stats); err != nil {
+				return err
+			}
+			return writeHistogram(wb, stats)
+		})
+	}
+	return c.JSON(http.StatusOK, stats)
+}
+
+// estateStats computes the stats of an estate, or of a region of it, up to
+// the before time when it is not nil. Stats are served from the cache when
+// the estate has not changed and the yield covers the same days, and cached
+// unless the estate changed since the cache generation was read.
+func (h *EstateHandler) estateStats(estate *models.Estate, generation uint64, region *models.Region, options models.StatsOptions, before *time.Time, asOf string) (*models.EstateStats, error) {
+	// Yield of the 12 months up to the as_of date or today
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if before != nil {
+		to = before.AddDate(0, 0, -1)
+	}
+
+	cacheKey := statsCacheKey + ":" + regionCacheKey(region) + ":" + statsOptionsCacheKey(options)
+	if before != nil {
+		cacheKey += ":" + asOf
+	}
+	// Stats cached yesterday would otherwise keep yesterday's trailing year
+	cacheKey += ":" + to.Format(models.DateLayout)
+	if cached, ok := h.Cache.Get(estate.ID, cacheKey); ok {
+		logrus.Infof("Estate stats served from cache for ID %s", estate.ID)
+		return withRegion(cached.(*models.EstateStats), region), nil
+	}
+
+	// Call the repository to get stats and health counts
+	var heights *models.HeightStats
+	var health map[string]int
+	var err error
+	if before != nil {
+		heights, err = h.EstateRepo.GetEstateStatsAsOf(estate.ID, *before, region, options)
+		if err == nil {
+			health, err = h.EstateRepo.GetHealthCountsAsOf(estate.ID, *before, region)
+		}
+	} else {
+		heights, err = h.EstateRepo.GetEstateStats(estate.ID, region, options)
+		if err == nil {
+			health, err = h.EstateRepo.GetHealthCounts(estate.ID, region)
+		}
+	}
+	var yield *models.YieldTotals
+	if err == nil {
+		yield, err = h.HarvestRepo.GetYieldTotals(estate.ID, region, trailingYearStart(to), to)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Every status is listed, with 0 when no tree has it
+	healthCounts := make(map[string]int, len(models.HealthStatuses))
+	for _, status := range models.HealthStatuses {
+		healthCounts[status] = health[status]
+	}
+
+	area := float64(regions.Plots(region, estate.Width, estate.Length)) * models.PlotAreaHectares
+	stats := &models.EstateStats{
+		HeightStats:      *heights,
+		Health:           healthCounts,
+		AreaHectares:     math.Round(area*100) / 100,
+		TonnesPerHectare: models.TonnesPerHectare(yield.WeightKg, area),
+	}
+	h.Cache.Set(estate.ID, cacheKey, generation, stats)
+	return withRegion(stats, region), nil
+}
+
+// withRegion returns a copy of stats naming the region they were computed
+// for, leaving the cached stats untouched.
+func withRegion(stats *models.EstateStats, region *models.Region) *models.EstateStats {
+	if region == nil {
+		return stats
+	}
+	named := *stats
+	named.Region = region
+	return &named
+}
+
+// GetEstateStatsBreakdown retrieves stats of trees in an estate per species and age cohort
+// @Summary Get stats of trees per species and age cohort
+// @Description Get the count, max, min and median height of the trees of an estate per species and per age cohort (immature 0-3 years, young 4-8, prime 9-18, old 19-25, senile 26+, unknown without a planting date)
+// @Tags estates
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Success 200 {object} models.EstateStatsBreakdown
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/stats/breakdown [get]
+func (h *EstateHandler) GetEstateStatsBreakdown(c echo.Context) error {
+	generation := cacheGeneration(c, h.Cache)
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	if cached, ok := h.Cache.Get(estate.ID, statsBreakdownCacheKey); ok {
+		logrus.Infof("Estate stats breakdown served from cache for ID %s", estate.ID)
+		return c.JSON(http.StatusOK, cached)
+	}
+
+	breakdown, err := h.EstateRepo.GetEstateStatsBreakdown(estate.ID)
+	if err != nil {
+		logrus.Errorf("Failed to get estate stats breakdown for ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while fetching estate stats",
+		})
+	}
+	h.Cache.Set(estate.ID, statsBreakdownCacheKey, generation, breakdown)
+
+	logrus.Infof("Estate stats breakdown retrieved successfully for ID %s", estate.ID)
+	return c.JSON(http.StatusOK, breakdown)
+}
+
+// UpdateEstate partially updates an estate's dimensions and metadata
+// @Summary Update an estate
+// @Description Update an estate's dimensions and metadata. A shrink that would leave trees out of bounds is refused.
+// @Tags estates
+// @Accept json
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param estate body models.EstateUpdate true "Fields to update"
+// @Success 200 {object} models.Estate
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]interface{}
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id} [patch]
+func (h *EstateHandler) UpdateEstate(c echo.Context) error {
+	update := new(models.EstateUpdate)
+
+	// Bind the request body to the estate update
+	if err := c.Bind(update); err != nil {
+		logrus.Warnf("Failed to bind estate update: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Invalid input format",
+		})
+	}
+
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+	estateID := estate.ID
+
+	// Apply and validate the changes
+	update.Apply(estate)
+	if !validEstateDimensions(estate) {
+		logrus.Warnf("Invalid estate dimensions: width=%d, length=%d", estate.Width, estate.Length)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Estate dimensions must be between 1 and 50000",
+		})
+	}
+	normalizeEstateMetadata(estate)
+	if err := validateEstateMetadata(estate); err != nil {
+		logrus.Warnf("Invalid estate metadata: %v", err)
 		return c.JSON(http.StatusBadRequest, map[string]string{
-			"message": "Invalid estate ID format",
+			"message": err.Error(),
+		})
+	}
+	estate.UpdatedAt = time.Now().UTC()
+
+	// Store the changes, refusing shrinks that would strand trees
+	err = h.EstateRepo.UpdateEstate(estate)
+	if errors.Is(err, repositories.ErrTreesOutOfBounds) {
+		return h.respondTreesOutOfBounds(c, estate)
+	}
+	if errors.Is(err, repositories.ErrDuplicateEstateCode) {
+		logrus.Warnf("Estate code already exists: %s", estate.Code)
+		return c.JSON(http.StatusConflict, map[string]string{
+			"message": "Estate code already exists",
+		})
+	}
+	if errors.Is(err, repositories.ErrEstateNotFound) {
+		// Deleted since it was looked up
+		logrus.Warnf("Estate not found: %s", estateID)
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"message": "Estate not found",
+		})
+	}
+	if err != nil {
+		logrus.Errorf("Failed to update estate ID %s: %v", estateID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Failed to update estate in database",
 		})
 	}
 
-	// Check if the estate exists
-	estate, err := h.EstateRepo.GetEstateByID(estateID)
+	// Drone plans and stats depend on the estate dimensions
+	h.invalidateEstate(estateID)
+
+	logrus.Infof("Estate updated successfully: %s", estateID)
+	return c.JSON(http.StatusOK, estate)
+}
+
+// respondTreesOutOfBounds reports the trees preventing an estate from being resized.
+func (h *EstateHandler) respondTreesOutOfBounds(c echo.Context, estate *models.Estate) error {
+	trees, err := h.EstateRepo.GetTreesOutOfBounds(estate.ID, estate.Width, estate.Length, maxReportedOutOfBoundsTrees+1)
 	if err != nil {
-		logrus.Errorf("Database error while retrieving estate ID %s: %v", estateID, err)
+		logrus.Errorf("Failed to retrieve out of bounds trees for estate ID %s: %v", estate.ID, err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"message": "Database error while retrieving estate",
+			"message": "Database error while checking tree bounds",
+		})
+	}
+	if len(trees) == 0 {
+		// The update matched no row and no tree is in the way: the estate is gone
+		logrus.Warnf("Estate not found: %s", estate.ID)
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"message": "Estate not found",
 		})
 	}
+
+	truncated := len(trees) > maxReportedOutOfBoundsTrees
+	if truncated {
+		trees = trees[:maxReportedOutOfBoundsTrees]
+	}
+	logrus.Warnf("Refusing to resize estate ID %s to %dx%d: trees out of bounds", estate.ID, estate.Width, estate.Length)
+	return c.JSON(http.StatusConflict, map[string]interface{}{
+		"message":   "Estate dimensions would leave trees out of bounds",
+		"trees":     trees,
+		"truncated": truncated,
+	})
+}
+
+// ArchiveEstate soft deletes an estate
+// @Summary Archive an estate
+// @Description Archive an estate, hiding it from listings. Its trees are kept and it can be restored.
+// @Tags estates
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Success 200 {object} models.Estate
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/archive [post]
+func (h *EstateHandler) ArchiveEstate(c echo.Context) error {
+	estate, err := findEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	// Archiving twice keeps the original archive time
+	if !estate.IsArchived() {
+		archivedAt := time.Now().UTC()
+		if err := h.EstateRepo.ArchiveEstate(estate.ID, archivedAt); err != nil {
+			return respondEstateWriteError(c, estate.ID, err, "Failed to archive estate")
+		}
+		estate.ArchivedAt = &archivedAt
+		h.Cache.Invalidate(estate.ID)
+	}
+
+	logrus.Infof("Estate archived successfully: %s", estate.ID)
+	return c.JSON(http.StatusOK, estate)
+}
+
+// RestoreEstate restores an archived estate
+// @Summary Restore an archived estate
+// @Description Restore an archived estate so it is listed and usable again
+// @Tags estates
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Success 200 {object} models.Estate
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/restore [post]
+func (h *EstateHandler) RestoreEstate(c echo.Context) error {
+	estate, err := findEstate(c, h.EstateRepo)
 	if estate == nil {
+		return err
+	}
+
+	if estate.IsArchived() {
+		if err := h.EstateRepo.RestoreEstate(estate.ID); err != nil {
+			return respondEstateWriteError(c, estate.ID, err, "Failed to restore estate")
+		}
+		estate.ArchivedAt = nil
+	}
+
+	logrus.Infof("Estate restored successfully: %s", estate.ID)
+	return c.JSON(http.StatusOK, estate)
+}
+
+// DeleteEstate permanently deletes an estate and its trees
+// @Summary Delete an estate
+// @Description Permanently delete an estate together with all of its trees
+// @Tags estates
+// @Param id path string true "Estate ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id} [delete]
+func (h *EstateHandler) DeleteEstate(c echo.Context) error {
+	estate, err := findEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	if err := h.EstateRepo.DeleteEstate(estate.ID); err != nil {
+		return respondEstateWriteError(c, estate.ID, err, "Failed to delete estate")
+	}
+	h.invalidateEstate(estate.ID)
+
+	logrus.Infof("Estate deleted successfully: %s", estate.ID)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// respondEstateWriteError answers a failed estate modification.
+func respondEstateWriteError(c echo.Context, estateID uuid.UUID, err error, message string) error {
+	if errors.Is(err, repositories.ErrEstateNotFound) {
 		logrus.Warnf("Estate not found: %s", estateID)
 		return c.JSON(http.StatusNotFound, map[string]string{
 			"message": "Estate not found",
 		})
 	}
+	logrus.Errorf("%s ID %s: %v", message, estateID, err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"message": message,
+	})
+}
+
+// ListEstates lists estates with cursor pagination, sorting and filters
+// @Summary List estates
+// @Description List estates with cursor pagination, sorting by created time or area, and filtering by dimensions and tree count
+// @Tags estates
+// @Produce json
+// @Param sort query string false "Sort key: created_at or area"
+// @Param order query string false "Sort order: asc or desc"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param q query string false "Search term matched against name and code"
+// @Param company query string false "Owning company"
+// @Param region query string false "Region"
+// @Param tag query string false "Tag carried by the estate"
+// @Param archived query bool false "List archived estates instead of active ones"
+// @Param limit query int false "Page size (1 to 100)"
+// @Param min_width query int false "Minimum width"
+// @Param max_width query int false "Maximum width"
+// @Param min_length query int false "Minimum length"
+// @Param max_length query int false "Maximum length"
+// @Param min_trees query int false "Minimum number of trees"
+// @Param max_trees query int false "Maximum number of trees"
+// @Success 200 {object} models.EstatePage
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate [get]
+func (h *EstateHandler) ListEstates(c echo.Context) error {
+	query := models.EstateQuery{
+		SortBy:  c.QueryParam("sort"),
+		Cursor:  c.QueryParam("cursor"),
+		Search:  strings.TrimSpace(c.QueryParam("q")),
+		Company: strings.TrimSpace(c.QueryParam("company")),
+		Region:  strings.TrimSpace(c.QueryParam("region")),
+		Tag:     strings.ToLower(strings.TrimSpace(c.QueryParam("tag"))),
+	}
+
+	// Archived estates are only listed on request
+	switch archived := c.QueryParam("archived"); archived {
+	case "", "false":
+	case "true":
+		query.Archived = true
+	default:
+		logrus.Warnf("Invalid archived value: %s", archived)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "archived must be true or false",
+		})
+	}
+
+	// Validate sort key and order
+	switch query.SortBy {
+	case "":
+		query.SortBy = models.EstateSortCreatedAt
+	case models.EstateSortCreatedAt, models.EstateSortArea:
+	default:
+		logrus.Warnf("Invalid estate sort key: %s", query.SortBy)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "sort must be one of created_at, area",
+		})
+	}
+	switch order := c.QueryParam("order"); order {
+	case "", "asc":
+	case "desc":
+		query.Desc = true
+	default:
+		logrus.Warnf("Invalid estate sort order: %s", order)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "order must be one of asc, desc",
+		})
+	}
 
-	// Call the repository to get stats
-	count, max, min, median, err := h.EstateRepo.GetEstateStats(estateID)
+	limit, err := pageSizeParam(c)
 	if err != nil {
-		logrus.Errorf("Failed to get estate stats for ID %s: %v", estateID, err)
+		logrus.Warnf("Invalid estate page size: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+	query.Limit = limit
+
+	// Parse the optional range filters
+	filters := []struct {
+		name   string
+		target **int
+	}{
+		{"min_width", &query.MinWidth},
+		{"max_width", &query.MaxWidth},
+		{"min_length", &query.MinLength},
+		{"max_length", &query.MaxLength},
+		{"min_trees", &query.MinTrees},
+		{"max_trees", &query.MaxTrees},
+	}
+	for _, f := range filters {
+		value, err := optionalIntParam(c, f.name)
+		if err != nil {
+			logrus.Warnf("Invalid estate filter: %v", err)
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"message": err.Error(),
+			})
+		}
+		*f.target = value
+	}
+
+	page, err := h.EstateRepo.ListEstates(query)
+	if errors.Is(err, repositories.ErrInvalidCursor) {
+		logrus.Warnf("Invalid estate cursor: %s", query.Cursor)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Invalid cursor",
+		})
+	}
+	if err != nil {
+		logrus.Errorf("Failed to list estates: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"message": "Database error while fetching estate stats",
+			"message": "Database error while listing estates",
 		})
 	}
 
-	stats := map[string]int{
-		"count":  count,
-		"max":    max,
-		"min":    min,
-		"median": median,
+	logrus.Infof("Listed %d estates", len(page.Estates))
+	return c.JSON(http.StatusOK, page)
+}
+
+// validEstateDimensions reports whether the estate width and length are within 1 and 50000 plots.
+func validEstateDimensions(estate *models.Estate) bool {
+	return estate.Width >= 1 && estate.Length >= 1 && estate.Width <= 50000 && estate.Length <= 50000
+}
+
+// normalizeEstateMetadata trims the estate text fields and upper-cases the code.
+func normalizeEstateMetadata(estate *models.Estate) {
+	estate.Name = strings.TrimSpace(estate.Name)
+	estate.Code = strings.ToUpper(strings.TrimSpace(estate.Code))
+	estate.Company = strings.TrimSpace(estate.Company)
+	estate.Region = strings.TrimSpace(estate.Region)
+	estate.Notes = strings.TrimSpace(estate.Notes)
+
+	// Tags are lower-cased and listed once, in the order given
+	tags := make([]string, 0, len(estate.Tags))
+	seen := make(map[string]bool, len(estate.Tags))
+	for _, tag := range estate.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
 	}
+	estate.Tags = tags
+}
 
-	logrus.Infof("Estate stats retrieved successfully for ID %s", estateID)
-	return c.JSON(http.StatusOK, stats)
+// validateEstateMetadata checks the estate text fields, returning an error
+// message suitable for the client.
+func validateEstateMetadata(estate *models.Estate) error {
+	if estate.Code != "" && !estateCodePattern.MatchString(estate.Code) {
+		return errors.New("Estate code must be 1 to 32 letters, digits or dashes")
+	}
+	if len(estate.Tags) > maxEstateTags {
+		return fmt.Errorf("Estate must have at most %d tags", maxEstateTags)
+	}
+	for _, tag := range estate.Tags {
+		if !estateTagPattern.MatchString(tag) {
+			return errors.New("Estate tags must be 1 to 32 lowercase letters, digits, dashes or underscores")
+		}
+	}
+	// Map tiles cannot show latitudes beyond 85 degrees
+	if location := estate.Location; location != nil {
+		if math.IsNaN(location.Latitude) || location.Latitude < -85 || location.Latitude > 85 {
+			return errors.New("Estate latitude must be between -85 and 85")
+		}
+		if math.IsNaN(location.Longitude) || location.Longitude < -180 || location.Longitude > 180 {
+			return errors.New("Estate longitude must be between -180 and 180")
+		}
+		if math.IsNaN(location.Rotation) || location.Rotation < -360 || location.Rotation > 360 {
+			return errors.New("Estate rotation must be between -360 and 360")
+		}
+	}
+	fields := []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{"name", estate.Name, maxEstateNameLength},
+		{"company", estate.Company, maxEstateOwnerLength},
+		{"region", estate.Region, maxEstateOwnerLength},
+		{"notes", estate.Notes, maxEstateNotesLength},
+	}
+	for _, f := range fields {
+		if len([]rune(f.value)) > f.max {
+			return fmt.Errorf("Estate %s must be at most %d characters", f.name, f.max)
+		}
+	}
+	return nil
+}
+
+// invalidateEstate drops the data cached for an estate, including its map tiles.
+func (h *EstateHandler) invalidateEstate(estateID uuid.UUID) {
+	h.Cache.Invalidate(estateID)
+	if err := h.Tiles.InvalidateEstate(estateID); err != nil {
+		logrus.Warnf("Failed to invalidate map tiles of estate ID %s: %v", estateID, err)
+	}
 }
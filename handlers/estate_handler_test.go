@@ -7,9 +7,12 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"sawitpro-recruitment/cache"
 	"sawitpro-recruitment/mocks"
 	"sawitpro-recruitment/models"
+	"sawitpro-recruitment/repositories"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
@@ -17,6 +20,28 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+// newRequest builds the context of a request. A body is sent as JSON.
+func newRequest(method, path, query, body string) (echo.Context, *httptest.ResponseRecorder) {
+	if query != "" {
+		path += "?" + query
+	}
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	rec := httptest.NewRecorder()
+	return echo.New().NewContext(req, rec), rec
+}
+
+// newEstateRequest builds the context of a request to path under an estate,
+// such as "/yield", with the estate ID as its id parameter.
+func newEstateRequest(method, path string, estateID uuid.UUID, query, body string) (echo.Context, *httptest.ResponseRecorder) {
+	c, rec := newRequest(method, "/estate/"+estateID.String()+path, query, body)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+	return c, rec
+}
+
 func TestEstateHandler_CreateEstate(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -102,7 +127,9 @@ func TestEstateHandler_GetEstateStats(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockHarvestRepo := mocks.NewMockHarvestRepository(ctrl)
 	handler := NewEstateHandler(mockEstateRepo)
+	handler.HarvestRepo = mockHarvestRepo
 
 	e := echo.New()
 	estateID := uuid.New().String()
@@ -112,17 +139,107 @@ func TestEstateHandler_GetEstateStats(t *testing.T) {
 	c.SetParamNames("id")
 	c.SetParamValues(estateID)
 
-	mockEstateRepo.EXPECT().GetEstateByID(gomock.Any()).Return(&models.Estate{ID: uuid.MustParse(estateID)}, nil)
-	mockEstateRepo.EXPECT().GetEstateStats(gomock.Any()).Return(10, 20, 5, 15, nil)
+	mockEstateRepo.EXPECT().GetEstateByID(gomock.Any()).Return(&models.Estate{ID: uuid.MustParse(estateID), Width: 100, Length: 50}, nil)
+	heights := &models.HeightStats{
+		Count: 10, Max: 20, Min: 5, Median: 15.5, Mean: 13.2, StdDev: 4.1,
+		Percentiles: []models.Percentile{{P: 10, Height: 6}, {P: 25, Height: 9.25}, {P: 75, Height: 17}, {P: 90, Height: 19.1}},
+		BucketWidth: 5,
+		Histogram:   []models.HistogramBucket{{From: 5, To: 9, Count: 3}, {From: 10, To: 14, Count: 2}, {From: 15, To: 19, Count: 4}, {From: 20, To: 24, Count: 1}},
+	}
+	defaultOptions := models.StatsOptions{Percentiles: []float64{10, 25, 75, 90}, BucketWidth: 5}
+	mockEstateRepo.EXPECT().GetEstateStats(gomock.Any(), gomock.Nil(), defaultOptions).Return(heights, nil)
+	mockEstateRepo.EXPECT().GetHealthCounts(gomock.Any(), gomock.Nil()).Return(map[string]int{"healthy": 9, "ganoderma": 1}, nil)
+	mockHarvestRepo.EXPECT().GetYieldTotals(gomock.Any(), gomock.Nil(), gomock.Any(), gomock.Any()).Return(&models.YieldTotals{Harvests: 40, Bunches: 900, WeightKg: 12500}, nil)
 
 	if assert.NoError(t, handler.GetEstateStats(c)) {
 		assert.Equal(t, http.StatusOK, rec.Code)
-		var response map[string]int
+		var response models.EstateStats
 		if assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response)) {
-			assert.Equal(t, 10, response["count"])
-			assert.Equal(t, 20, response["max"])
-			assert.Equal(t, 5, response["min"])
-			assert.Equal(t, 15, response["median"])
+			assert.Equal(t, *heights, response.HeightStats)
+			assert.Equal(t, 15.5, response.Median)
+			// 12.5 tonnes over 100 by 50 plots of 10 meters, 50 hectares
+			assert.Equal(t, 0.25, response.TonnesPerHectare)
+			assert.Equal(t, map[string]int{
+				"healthy": 9, "ganoderma": 1, "oryctes": 0, "nutrient_deficiency": 0, "other": 0, "dead": 0,
+			}, response.Health)
+		}
+	}
+}
+
+func TestEstateHandler_GetEstateStats_CachedYesterday(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockHarvestRepo := mocks.NewMockHarvestRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+	handler.HarvestRepo = mockHarvestRepo
+	handler.Cache = cache.NewEstateCache()
+
+	e := echo.New()
+	estateID := uuid.New()
+	estate := &models.Estate{ID: estateID, Width: 100, Length: 50}
+	options := models.StatsOptions{Percentiles: models.DefaultStatsPercentiles, BucketWidth: models.DefaultHistogramBucketWidth}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	// Stats cached yesterday cover another trailing year of harvests
+	yesterdayKey := statsCacheKey + ":" + regionCacheKey(nil) + ":" + statsOptionsCacheKey(options) + ":" + today.AddDate(0, 0, -1).Format(models.DateLayout)
+	handler.Cache.Set(estateID, yesterdayKey, handler.Cache.Generation(estateID), &models.EstateStats{TonnesPerHectare: 9})
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(estate, nil).Times(2)
+	mockEstateRepo.EXPECT().GetEstateStats(estateID, gomock.Nil(), options).Return(&models.HeightStats{Count: 1}, nil)
+	mockEstateRepo.EXPECT().GetHealthCounts(estateID, gomock.Nil()).Return(map[string]int{}, nil)
+	mockHarvestRepo.EXPECT().GetYieldTotals(estateID, gomock.Nil(), trailingYearStart(today), today).Return(&models.YieldTotals{WeightKg: 5000}, nil)
+
+	// The second request of the day is served from the cache
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/stats", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(estateID.String())
+
+		if assert.NoError(t, handler.GetEstateStats(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			var response models.EstateStats
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, 0.1, response.TonnesPerHectare)
+		}
+	}
+}
+
+func TestEstateHandler_GetEstateStats_ChangedWhileComputed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockHarvestRepo := mocks.NewMockHarvestRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+	handler.HarvestRepo = mockHarvestRepo
+	handler.Cache = cache.NewEstateCache()
+
+	estateID := uuid.New()
+	options := models.StatsOptions{Percentiles: models.DefaultStatsPercentiles, BucketWidth: models.DefaultHistogramBucketWidth}
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 100, Length: 50}, nil).Times(2)
+	// A tree is planted while the first request reads the stats, so they are not cached
+	gomock.InOrder(
+		mockEstateRepo.EXPECT().GetEstateStats(estateID, gomock.Nil(), options).DoAndReturn(
+			func(uuid.UUID, *models.Region, models.StatsOptions) (*models.HeightStats, error) {
+				handler.Cache.Invalidate(estateID)
+				return &models.HeightStats{Count: 1}, nil
+			}),
+		mockEstateRepo.EXPECT().GetEstateStats(estateID, gomock.Nil(), options).Return(&models.HeightStats{Count: 2}, nil),
+	)
+	mockEstateRepo.EXPECT().GetHealthCounts(estateID, gomock.Nil()).Return(map[string]int{}, nil).Times(2)
+	mockHarvestRepo.EXPECT().GetYieldTotals(estateID, gomock.Nil(), gomock.Any(), gomock.Any()).Return(&models.YieldTotals{}, nil).Times(2)
+
+	for _, count := range []int{1, 2} {
+		c, rec := newEstateRequest(http.MethodGet, "/stats", estateID, "", "")
+
+		if assert.NoError(t, handler.GetEstateStats(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			var response models.EstateStats
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, count, response.Count)
 		}
 	}
 }
@@ -184,9 +301,687 @@ func TestEstateHandler_GetEstateStats_ErrorFetchingStats(t *testing.T) {
 	c.SetParamValues(estateID)
 
 	mockEstateRepo.EXPECT().GetEstateByID(gomock.Any()).Return(&models.Estate{ID: uuid.MustParse(estateID)}, nil)
-	mockEstateRepo.EXPECT().GetEstateStats(gomock.Any()).Return(0, 0, 0, 0, errors.New("database error"))
+	mockEstateRepo.EXPECT().GetEstateStats(gomock.Any(), gomock.Nil(), gomock.Any()).Return(nil, errors.New("database error"))
 
 	if assert.NoError(t, handler.GetEstateStats(c)) {
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 	}
 }
+
+func TestEstateHandler_ListEstates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate?sort=area&order=desc&limit=5&min_width=10&max_trees=100", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	minWidth, maxTrees := 10, 100
+	expectedQuery := models.EstateQuery{
+		SortBy:   models.EstateSortArea,
+		Desc:     true,
+		Limit:    5,
+		MinWidth: &minWidth,
+		MaxTrees: &maxTrees,
+	}
+	estate := &models.Estate{ID: uuid.New(), Width: 10, Length: 20}
+	mockEstateRepo.EXPECT().ListEstates(expectedQuery).Return(&models.EstatePage{
+		Estates:    []*models.Estate{estate},
+		NextCursor: "next",
+	}, nil)
+
+	if assert.NoError(t, handler.ListEstates(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response models.EstatePage
+		if assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response)) {
+			assert.Len(t, response.Estates, 1)
+			assert.Equal(t, estate.ID, response.Estates[0].ID)
+			assert.Equal(t, "next", response.NextCursor)
+		}
+	}
+}
+
+func TestEstateHandler_ListEstates_InvalidParams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	for _, query := range []string{"sort=name", "order=up", "limit=0", "limit=101", "min_width=abc"} {
+		req := httptest.NewRequest(http.MethodGet, "/estate?"+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if assert.NoError(t, handler.ListEstates(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	}
+}
+
+func TestEstateHandler_ListEstates_InvalidCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate?cursor=bogus", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	mockEstateRepo.EXPECT().ListEstates(gomock.Any()).Return(nil, repositories.ErrInvalidCursor)
+
+	if assert.NoError(t, handler.ListEstates(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestEstateHandler_ListEstates_DatabaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	mockEstateRepo.EXPECT().ListEstates(gomock.Any()).Return(nil, errors.New("database error"))
+
+	if assert.NoError(t, handler.ListEstates(c)) {
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	}
+}
+
+func TestEstateHandler_CreateEstate_WithMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	body := `{"width": 100, "length": 200, "name": " Riau Block A ", "code": "riau-01", "company": "PT Sawit Makmur", "region": "Riau", "tags": ["North", " organic ", "north"]}`
+	req := httptest.NewRequest(http.MethodPost, "/estate", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	mockEstateRepo.EXPECT().CreateEstate(gomock.Any()).DoAndReturn(func(estate *models.Estate) error {
+		assert.Equal(t, "Riau Block A", estate.Name)
+		assert.Equal(t, "RIAU-01", estate.Code)
+		assert.Equal(t, "PT Sawit Makmur", estate.Company)
+		assert.Equal(t, []string{"north", "organic"}, estate.Tags)
+		assert.False(t, estate.CreatedAt.IsZero())
+		assert.Equal(t, estate.CreatedAt, estate.UpdatedAt)
+		return nil
+	})
+
+	if assert.NoError(t, handler.CreateEstate(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+}
+
+func TestEstateHandler_CreateEstate_InvalidMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	bodies := []string{
+		`{"width": 100, "length": 200, "code": "not a code!"}`,
+		`{"width": 100, "length": 200, "name": "` + strings.Repeat("a", 101) + `"}`,
+		`{"width": 100, "length": 200, "tags": ["two words"]}`,
+		`{"width": 100, "length": 200, "tags": ["` + strings.Repeat("a", 33) + `"]}`,
+		`{"width": 100, "length": 200, "location": {"latitude": 86, "longitude": 101.5}}`,
+		`{"width": 100, "length": 200, "location": {"latitude": 0.5, "longitude": 181}}`,
+		`{"width": 100, "length": 200, "location": {"latitude": 0.5, "longitude": 101.5, "rotation": 400}}`,
+	}
+	for _, body := range bodies {
+		req := httptest.NewRequest(http.MethodPost, "/estate", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if assert.NoError(t, handler.CreateEstate(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	}
+}
+
+func TestEstateHandler_CreateEstate_DuplicateCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate", strings.NewReader(`{"width": 100, "length": 200, "code": "RIAU-01"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	mockEstateRepo.EXPECT().CreateEstate(gomock.Any()).Return(repositories.ErrDuplicateEstateCode)
+
+	if assert.NoError(t, handler.CreateEstate(c)) {
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	}
+}
+
+func TestEstateHandler_GetEstate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String(), nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 20, Name: "Riau Block A", Code: "RIAU-01"}, nil)
+
+	if assert.NoError(t, handler.GetEstate(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response models.Estate
+		if assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response)) {
+			assert.Equal(t, estateID, response.ID)
+			assert.Equal(t, "Riau Block A", response.Name)
+			assert.Equal(t, "RIAU-01", response.Code)
+		}
+	}
+}
+
+func TestEstateHandler_GetEstate_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String(), nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(nil, nil)
+
+	if assert.NoError(t, handler.GetEstate(c)) {
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestEstateHandler_UpdateEstate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+	handler.Cache = cache.NewEstateCache()
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodPatch, "/estate/"+estateID.String(), strings.NewReader(`{"width": 30, "name": "Extended"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	handler.Cache.Set(estateID, statsCacheKey, handler.Cache.Generation(estateID), map[string]int{"count": 1})
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 20}, nil)
+	mockEstateRepo.EXPECT().UpdateEstate(gomock.Any()).DoAndReturn(func(estate *models.Estate) error {
+		assert.Equal(t, 30, estate.Width)
+		assert.Equal(t, 20, estate.Length)
+		assert.Equal(t, "Extended", estate.Name)
+		assert.False(t, estate.UpdatedAt.IsZero())
+		return nil
+	})
+
+	if assert.NoError(t, handler.UpdateEstate(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response models.Estate
+		if assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response)) {
+			assert.Equal(t, 30, response.Width)
+		}
+		_, cached := handler.Cache.Get(estateID, statsCacheKey)
+		assert.False(t, cached)
+	}
+}
+
+func TestEstateHandler_UpdateEstate_TreesOutOfBounds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodPatch, "/estate/"+estateID.String(), strings.NewReader(`{"width": 5}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	strayTree := &models.Tree{ID: uuid.New(), EstateID: estateID, X: 8, Y: 3, Height: 10}
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 20}, nil)
+	mockEstateRepo.EXPECT().UpdateEstate(gomock.Any()).Return(repositories.ErrTreesOutOfBounds)
+	mockEstateRepo.EXPECT().GetTreesOutOfBounds(estateID, 5, 20, maxReportedOutOfBoundsTrees+1).Return([]*models.Tree{strayTree}, nil)
+
+	if assert.NoError(t, handler.UpdateEstate(c)) {
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		var response struct {
+			Message   string         `json:"message"`
+			Trees     []*models.Tree `json:"trees"`
+			Truncated bool           `json:"truncated"`
+		}
+		if assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response)) {
+			assert.Len(t, response.Trees, 1)
+			assert.Equal(t, strayTree.ID, response.Trees[0].ID)
+			assert.False(t, response.Truncated)
+		}
+	}
+}
+
+func TestEstateHandler_UpdateEstate_InvalidDimensions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodPatch, "/estate/"+estateID.String(), strings.NewReader(`{"length": 0}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 20}, nil)
+
+	if assert.NoError(t, handler.UpdateEstate(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestEstateHandler_UpdateEstate_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodPatch, "/estate/"+estateID.String(), strings.NewReader(`{"width": 30}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(nil, nil)
+
+	if assert.NoError(t, handler.UpdateEstate(c)) {
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestEstateHandler_UpdateEstate_DeletedConcurrently(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodPatch, "/estate/"+estateID.String(), strings.NewReader(`{"width": 30}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 20, Length: 20}, nil)
+	mockEstateRepo.EXPECT().UpdateEstate(gomock.Any()).Return(repositories.ErrEstateNotFound)
+
+	if assert.NoError(t, handler.UpdateEstate(c)) {
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestEstateHandler_ArchiveEstate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+	handler.Cache = cache.NewEstateCache()
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate/"+estateID.String()+"/archive", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	handler.Cache.Set(estateID, statsCacheKey, handler.Cache.Generation(estateID), "cached")
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 10}, nil)
+	mockEstateRepo.EXPECT().ArchiveEstate(estateID, gomock.Any()).Return(nil)
+
+	if assert.NoError(t, handler.ArchiveEstate(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response models.Estate
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.True(t, response.IsArchived())
+		_, ok := handler.Cache.Get(estateID, statsCacheKey)
+		assert.False(t, ok)
+	}
+}
+
+func TestEstateHandler_ArchiveEstate_AlreadyArchived(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate/"+estateID.String()+"/archive", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	archivedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, ArchivedAt: &archivedAt}, nil)
+
+	if assert.NoError(t, handler.ArchiveEstate(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response models.Estate
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, archivedAt, *response.ArchivedAt)
+	}
+}
+
+func TestEstateHandler_RestoreEstate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate/"+estateID.String()+"/restore", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	archivedAt := time.Now().UTC()
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, ArchivedAt: &archivedAt}, nil)
+	mockEstateRepo.EXPECT().RestoreEstate(estateID).Return(nil)
+
+	if assert.NoError(t, handler.RestoreEstate(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response models.Estate
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.False(t, response.IsArchived())
+	}
+}
+
+func TestEstateHandler_DeleteEstate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodDelete, "/estate/"+estateID.String(), nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID}, nil)
+	mockEstateRepo.EXPECT().DeleteEstate(estateID).Return(nil)
+
+	if assert.NoError(t, handler.DeleteEstate(c)) {
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Empty(t, rec.Body.Bytes())
+	}
+}
+
+func TestEstateHandler_DeleteEstate_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodDelete, "/estate/"+estateID.String(), nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	// The estate disappeared between the lookup and the delete
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID}, nil)
+	mockEstateRepo.EXPECT().DeleteEstate(estateID).Return(repositories.ErrEstateNotFound)
+
+	if assert.NoError(t, handler.DeleteEstate(c)) {
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestEstateHandler_GetEstateStats_Archived(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/stats", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	archivedAt := time.Now().UTC()
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, ArchivedAt: &archivedAt}, nil)
+
+	if assert.NoError(t, handler.GetEstateStats(c)) {
+		assert.Equal(t, http.StatusGone, rec.Code)
+		var response map[string]string
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "Estate has been archived", response["message"])
+	}
+}
+
+func TestEstateHandler_GetEstateStats_AsOf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockHarvestRepo := mocks.NewMockHarvestRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+	handler.HarvestRepo = mockHarvestRepo
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/stats?as_of=2024-03-01", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	// Measurements taken during the as_of date are included
+	before := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 10}, nil)
+	mockEstateRepo.EXPECT().GetEstateStatsAsOf(estateID, before, gomock.Nil(), gomock.Any()).Return(&models.HeightStats{
+		Count: 2, Max: 11, Min: 8, Median: 9.5, Mean: 9.5, StdDev: 1.5,
+		Percentiles: []models.Percentile{}, BucketWidth: 5, Histogram: []models.HistogramBucket{},
+	}, nil)
+	mockEstateRepo.EXPECT().GetHealthCountsAsOf(estateID, before, gomock.Nil()).Return(map[string]int{"healthy": 1, "dead": 1}, nil)
+	mockHarvestRepo.EXPECT().GetYieldTotals(estateID, gomock.Nil(), time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)).
+		Return(&models.YieldTotals{}, nil)
+
+	if assert.NoError(t, handler.GetEstateStats(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response map[string]interface{}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, map[string]interface{}{
+			"count": 2.0, "max": 11.0, "min": 8.0, "median": 9.5, "mean": 9.5, "stddev": 1.5,
+			"percentiles": []interface{}{}, "bucket_width": 5.0, "histogram": []interface{}{},
+			"area_hectares": 1.0, "tonnes_per_hectare": 0.0,
+			"health": map[string]interface{}{
+				"healthy": 1.0, "ganoderma": 0.0, "oryctes": 0.0, "nutrient_deficiency": 0.0, "other": 0.0, "dead": 1.0,
+			},
+		}, response)
+	}
+}
+
+func TestEstateHandler_GetEstateStats_InvalidAsOf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/stats?as_of=01-03-2024", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	if assert.NoError(t, handler.GetEstateStats(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestEstateHandler_GetEstateStats_Options(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockHarvestRepo := mocks.NewMockHarvestRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+	handler.HarvestRepo = mockHarvestRepo
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/stats?percentiles=5,%2050,99.5&bucket_width=2", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	options := models.StatsOptions{Percentiles: []float64{5, 50, 99.5}, BucketWidth: 2}
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 10}, nil)
+	mockEstateRepo.EXPECT().GetEstateStats(estateID, gomock.Nil(), options).Return(&models.HeightStats{BucketWidth: 2}, nil)
+	mockEstateRepo.EXPECT().GetHealthCounts(estateID, gomock.Nil()).Return(map[string]int{}, nil)
+	mockHarvestRepo.EXPECT().GetYieldTotals(estateID, gomock.Nil(), gomock.Any(), gomock.Any()).Return(&models.YieldTotals{}, nil)
+
+	if assert.NoError(t, handler.GetEstateStats(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+}
+
+func TestEstateHandler_GetEstateStats_InvalidOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	for _, query := range []string{
+		"percentiles=0",
+		"percentiles=100",
+		"percentiles=10,,90",
+		"percentiles=median",
+		"percentiles=1,2,3,4,5,6,7,8,9,10,11",
+		"bucket_width=0",
+		"bucket_width=101",
+		"bucket_width=wide",
+	} {
+		e := echo.New()
+		estateID := uuid.New()
+		req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/stats?"+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(estateID.String())
+
+		if assert.NoError(t, handler.GetEstateStats(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	}
+}
+
+func TestEstateHandler_GetEstateStatsBreakdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+	handler.Cache = cache.NewEstateCache()
+
+	estateID := uuid.New()
+	breakdown := &models.EstateStatsBreakdown{
+		Species:    []models.TreeGroupStats{{Group: "tenera", Count: 2, Max: 15, Min: 10, Median: 12.5}},
+		AgeCohorts: []models.TreeGroupStats{{Group: "prime", Count: 2, Max: 15, Min: 10, Median: 12.5}},
+	}
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID}, nil).Times(2)
+	// The second request is served from the cache
+	mockEstateRepo.EXPECT().GetEstateStatsBreakdown(estateID).Return(breakdown, nil)
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/stats/breakdown", nil)
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(estateID.String())
+
+		if assert.NoError(t, handler.GetEstateStatsBreakdown(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			var response models.EstateStatsBreakdown
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, *breakdown, response)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"sawitpro-recruitment/cache"
+	"sawitpro-recruitment/models"
+	"sawitpro-recruitment/repositories"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// findEstate parses the "id" path parameter and loads the estate. When the
+// returned estate is nil the error response has already been written and the
+// returned error is the result of writing it.
+func findEstate(c echo.Context, repo repositories.EstateRepository) (*models.Estate, error) {
+	id := c.Param("id")
+
+	// Convert to UUID
+	estateID, err := uuid.Parse(id)
+	if err != nil {
+		logrus.Warnf("Invalid estate ID format: %s", id)
+		return nil, c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Invalid estate ID format",
+		})
+	}
+
+	// Check if the estate exists
+	estate, err := repo.GetEstateByID(estateID)
+	if err != nil {
+		logrus.Errorf("Database error while retrieving estate ID %s: %v", estateID, err)
+		return nil, c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while retrieving estate",
+		})
+	}
+	if estate == nil {
+		logrus.Warnf("Estate not found: %s", estateID)
+		return nil, c.JSON(http.StatusNotFound, map[string]string{
+			"message": "Estate not found",
+		})
+	}
+	return estate, nil
+}
+
+// cacheGeneration returns the cache generation of the estate named by the
+// "id" path parameter, to be read before the estate and its trees so that a
+// value computed from data changed meanwhile is not cached.
+func cacheGeneration(c echo.Context, estates *cache.EstateCache) uint64 {
+	estateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return 0
+	}
+	return estates.Generation(estateID)
+}
+
+// findActiveEstate works like findEstate but answers 410 Gone for archived estates.
+func findActiveEstate(c echo.Context, repo repositories.EstateRepository) (*models.Estate, error) {
+	estate, err := findEstate(c, repo)
+	if estate == nil {
+		return nil, err
+	}
+	if estate.IsArchived() {
+		logrus.Warnf("Estate is archived: %s", estate.ID)
+		return nil, c.JSON(http.StatusGone, map[string]string{
+			"message": "Estate has been archived",
+		})
+	}
+	return estate, nil
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sawitpro-recruitment/models"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// maxPortfolioIDs caps the estate IDs a portfolio may be selected by.
+const maxPortfolioIDs = 100
+
+// GetPortfolioStats computes the stats of a set of estates
+// @Summary Get stats of a portfolio of estates
+// @Description Get the tree count, height stats and planted coverage of the active estates selected by IDs, tag and owning company, taken together and one by one. Estates are ranked by the chosen key, highest first; estates tied share a rank. At least one selector is required and estates must pass all of them.
+// @Tags estates
+// @Produce json
+// @Param ids query string false "Comma-separated estate IDs, at most 100"
+// @Param tag query string false "Tag carried by the estates"
+// @Param company query string false "Company owning the estates"
+// @Param rank_by query string false "Ranking key: trees (default), coverage, median_height, mean_height or area"
+// @Param percentiles query string false "Comma-separated percents to compute the height percentiles of, 10,25,75,90 by default"
+// @Param bucket_width query int false "Height histogram bucket width in meters, 5 by default"
+// @Success 200 {object} models.PortfolioStats
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /portfolio/stats [get]
+func (h *EstateHandler) GetPortfolioStats(c echo.Context) error {
+	query, err := portfolioQueryParam(c)
+	if err != nil {
+		logrus.Warnf("Invalid portfolio selection: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+	rankBy := c.QueryParam("rank_by")
+	if rankBy == "" {
+		rankBy = models.PortfolioRankTrees
+	}
+	if portfolioRankValue(rankBy) == nil {
+		logrus.Warnf("Invalid portfolio ranking key: %s", rankBy)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "rank_by must be one of " + strings.Join(models.PortfolioRankKeys, ", "),
+		})
+	}
+	options, err := statsOptionsParam(c)
+	if err != nil {
+		logrus.Warnf("Invalid stats options: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+
+	portfolio, err := h.EstateRepo.GetPortfolioStats(query, options)
+	if err != nil {
+		logrus.Errorf("Failed to get portfolio stats: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while fetching portfolio stats",
+		})
+	}
+	rankPortfolio(portfolio, rankBy)
+
+	logrus.Infof("Portfolio stats retrieved successfully for %d estates", portfolio.EstateCount)
+	return c.JSON(http.StatusOK, portfolio)
+}
+
+// portfolioQueryParam parses the ids, tag and company query parameters
+// selecting the estates of a portfolio.
+func portfolioQueryParam(c echo.Context) (models.PortfolioQuery, error) {
+	query := models.PortfolioQuery{
+		Tag:     strings.ToLower(strings.TrimSpace(c.QueryParam("tag"))),
+		Company: strings.TrimSpace(c.QueryParam("company")),
+	}
+	if raw := c.QueryParam("ids"); raw != "" {
+		parts := strings.Split(raw, ",")
+		if len(parts) > maxPortfolioIDs {
+			return query, fmt.Errorf("at most %d estate IDs may be given", maxPortfolioIDs)
+		}
+		for _, part := range parts {
+			id, err := uuid.Parse(strings.TrimSpace(part))
+			if err != nil {
+				return query, fmt.Errorf("invalid estate ID format: %s", part)
+			}
+			query.IDs = append(query.IDs, id)
+		}
+	}
+	if len(query.IDs) == 0 && query.Tag == "" && query.Company == "" {
+		return query, fmt.Errorf("ids, tag or company is required")
+	}
+	return query, nil
+}
+
+// portfolioRankValue returns the function reading the value estates are
+// ranked by for a ranking key, or nil for an unknown key.
+func portfolioRankValue(rankBy string) func(*models.PortfolioEstateStats) float64 {
+	switch rankBy {
+	case models.PortfolioRankTrees:
+		return func(e *models.PortfolioEstateStats) float64 { return float64(e.Trees) }
+	case models.PortfolioRankCoverage:
+		return func(e *models.PortfolioEstateStats) float64 { return e.CoveragePercent }
+	case models.PortfolioRankMedianHeight:
+		return func(e *models.PortfolioEstateStats) float64 { return e.Median }
+	case models.PortfolioRankMeanHeight:
+		return func(e *models.PortfolioEstateStats) float64 { return e.Mean }
+	case models.PortfolioRankArea:
+		return func(e *models.PortfolioEstateStats) float64 { return e.AreaHectares }
+	}
+	return nil
+}
+
+// rankPortfolio orders the estates of a portfolio by a ranking key, highest
+// first, keeping the order of estates tied, and numbers their ranks.
+func rankPortfolio(portfolio *models.PortfolioStats, rankBy string) {
+	value := portfolioRankValue(rankBy)
+	estates := portfolio.Estates
+	sort.SliceStable(estates, func(i, j int) bool {
+		return value(estates[i]) > value(estates[j])
+	})
+	for i, estate := range estates {
+		estate.Rank = i + 1
+		if i > 0 && value(estate) == value(estates[i-1]) {
+			estate.Rank = estates[i-1].Rank
+		}
+	}
+	portfolio.RankBy = rankBy
+}
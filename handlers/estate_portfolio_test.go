@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"sawitpro-recruitment/mocks"
+	"sawitpro-recruitment/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstateHandler_GetPortfolioStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	c, rec := newRequest(http.MethodGet, "/portfolio/stats", "tag=North&company=%20Acme%20&rank_by=coverage", "")
+
+	query := models.PortfolioQuery{Tag: "north", Company: "Acme"}
+	mockEstateRepo.EXPECT().GetPortfolioStats(query, models.StatsOptions{
+		Percentiles: models.DefaultStatsPercentiles,
+		BucketWidth: models.DefaultHistogramBucketWidth,
+	}).Return(&models.PortfolioStats{
+		EstateCount:     4,
+		CoveragePercent: 45,
+		HeightStats:     models.HeightStats{Count: 90},
+		// In name order, as the repository returns them
+		Estates: []*models.PortfolioEstateStats{
+			{Name: "Alpha", Trees: 10, CoveragePercent: 10},
+			{Name: "Bravo", Trees: 40, CoveragePercent: 60},
+			{Name: "Charlie", Trees: 30, CoveragePercent: 10},
+			{Name: "Delta", Trees: 10, CoveragePercent: 60},
+		},
+	}, nil)
+
+	if assert.NoError(t, handler.GetPortfolioStats(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var portfolio models.PortfolioStats
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &portfolio))
+		assert.Equal(t, "coverage", portfolio.RankBy)
+		assert.Equal(t, 90, portfolio.Count)
+		if assert.Len(t, portfolio.Estates, 4) {
+			// Estates tied share a rank and keep their name order
+			var names []string
+			var ranks []int
+			for _, estate := range portfolio.Estates {
+				names = append(names, estate.Name)
+				ranks = append(ranks, estate.Rank)
+			}
+			assert.Equal(t, []string{"Bravo", "Delta", "Alpha", "Charlie"}, names)
+			assert.Equal(t, []int{1, 1, 3, 3}, ranks)
+		}
+	}
+}
+
+func TestEstateHandler_GetPortfolioStats_IDs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	first, second := uuid.New(), uuid.New()
+	c, rec := newRequest(http.MethodGet, "/portfolio/stats", "ids="+first.String()+",%20"+second.String(), "")
+
+	mockEstateRepo.EXPECT().GetPortfolioStats(models.PortfolioQuery{IDs: []uuid.UUID{first, second}}, gomock.Any()).Return(&models.PortfolioStats{
+		EstateCount: 2,
+		Estates: []*models.PortfolioEstateStats{
+			{ID: first, Trees: 5},
+			{ID: second, Trees: 8},
+		},
+	}, nil)
+
+	if assert.NoError(t, handler.GetPortfolioStats(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var portfolio models.PortfolioStats
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &portfolio))
+		assert.Equal(t, "trees", portfolio.RankBy)
+		if assert.Len(t, portfolio.Estates, 2) {
+			assert.Equal(t, second, portfolio.Estates[0].ID)
+			assert.Equal(t, 1, portfolio.Estates[0].Rank)
+			assert.Equal(t, 2, portfolio.Estates[1].Rank)
+		}
+	}
+}
+
+func TestEstateHandler_GetPortfolioStats_InvalidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	tooMany := "ids=" + strings.TrimSuffix(strings.Repeat(uuid.NewString()+",", maxPortfolioIDs+1), ",")
+	for _, query := range []string{
+		"",
+		"company=%20",
+		"ids=not-a-uuid",
+		"ids=" + uuid.NewString() + ",",
+		"tag=north&rank_by=age",
+		"tag=north&percentiles=150",
+		"tag=north&bucket_width=0",
+		tooMany,
+	} {
+		c, rec := newRequest(http.MethodGet, "/portfolio/stats", query, "")
+
+		if assert.NoError(t, handler.GetPortfolioStats(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	}
+}
+
+func TestEstateHandler_GetPortfolioStats_DatabaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	c, rec := newRequest(http.MethodGet, "/portfolio/stats", "company=Acme", "")
+
+	mockEstateRepo.EXPECT().GetPortfolioStats(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+
+	if assert.NoError(t, handler.GetPortfolioStats(c)) {
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	}
+}
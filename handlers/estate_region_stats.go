@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sawitpro-recruitment/models"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxStatsRegions     = 20  // Most regions a client may request stats for at once
+	maxPolygonVertices  = 100 // Most vertices of a region polygon
+	maxRegionNameLength = 100
+	maxRegionCoordinate = 50000 // Largest coordinate of a region, the largest estate dimension
+)
+
+// GetEstateRegionStats computes the stats of several regions of an estate
+// @Summary Get stats of regions of an estate
+// @Description Get the same stats as GET /estate/{id}/stats for each of up to 20 regions of an estate, in the order given. A region combines a rectangle, row and column ranges and a polygon; a plot must pass all of them.
+// @Tags estates
+// @Accept json
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param as_of query string false "Compute the stats from the heights measured up to this date (YYYY-MM-DD)"
+// @Param percentiles query string false "Comma-separated percents to compute the height percentiles of, 10,25,75,90 by default"
+// @Param bucket_width query int false "Height histogram bucket width in meters, 5 by default"
+// @Param regions body models.RegionStatsRequest true "Regions"
+// @Success 200 {object} map[string][]models.EstateStats
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/stats/regions [post]
+func (h *EstateHandler) GetEstateRegionStats(c echo.Context) error {
+	request := new(models.RegionStatsRequest)
+	if err := c.Bind(request); err != nil {
+		logrus.Warnf("Failed to bind region stats request: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Invalid input format",
+		})
+	}
+	before, asOf, err := asOfParam(c)
+	var options models.StatsOptions
+	if err == nil {
+		options, err = statsOptionsParam(c)
+	}
+	if err == nil {
+		err = validateRegions(request.Regions)
+	}
+	if err != nil {
+		logrus.Warnf("Invalid region stats request: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+
+	generation := cacheGeneration(c, h.Cache)
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	results := make([]*models.EstateStats, len(request.Regions))
+	for i := range request.Regions {
+		results[i], err = h.estateStats(estate, generation, &request.Regions[i], options, before, asOf)
+		if err != nil {
+			logrus.Errorf("Failed to get region stats for estate ID %s: %v", estate.ID, err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": "Database error while fetching estate stats",
+			})
+		}
+	}
+
+	logrus.Infof("Stats of %d regions retrieved successfully for estate ID %s", len(results), estate.ID)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"regions": results,
+	})
+}
+
+// validateRegions checks the regions of a stats request.
+func validateRegions(regions []models.Region) error {
+	if len(regions) == 0 || len(regions) > maxStatsRegions {
+		return fmt.Errorf("between 1 and %d regions are required", maxStatsRegions)
+	}
+	for i := range regions {
+		region := &regions[i]
+		region.Name = strings.TrimSpace(region.Name)
+		if len(region.Polygon) == 0 {
+			region.Polygon = nil
+		}
+		if err := validateRegion(region); err != nil {
+			return fmt.Errorf("region %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// validateRegion checks that a region has at least one filter and that its
+// bounds are in order and within the largest estate.
+func validateRegion(region *models.Region) error {
+	inBounds := func(values ...int) bool {
+		for _, value := range values {
+			if value < 1 || value > maxRegionCoordinate {
+				return false
+			}
+		}
+		return true
+	}
+	if len([]rune(region.Name)) > maxRegionNameLength {
+		return fmt.Errorf("name must be at most %d characters", maxRegionNameLength)
+	}
+	if region.BBox == nil && region.Rows == nil && region.Columns == nil && len(region.Polygon) == 0 {
+		return fmt.Errorf("a bbox, rows, columns or polygon is required")
+	}
+	if box := region.BBox; box != nil {
+		if !inBounds(box.X1, box.Y1, box.X2, box.Y2) || box.X1 > box.X2 || box.Y1 > box.Y2 {
+			return fmt.Errorf("bbox must have 1 <= x1 <= x2 and 1 <= y1 <= y2, up to %d", maxRegionCoordinate)
+		}
+	}
+	if r := region.Rows; r != nil && (!inBounds(r.From, r.To) || r.From > r.To) {
+		return fmt.Errorf("rows must have 1 <= from <= to, up to %d", maxRegionCoordinate)
+	}
+	if r := region.Columns; r != nil && (!inBounds(r.From, r.To) || r.From > r.To) {
+		return fmt.Errorf("columns must have 1 <= from <= to, up to %d", maxRegionCoordinate)
+	}
+	if polygon := region.Polygon; len(polygon) > 0 {
+		if len(polygon) < 3 || len(polygon) > maxPolygonVertices {
+			return fmt.Errorf("polygon must have between 3 and %d vertices", maxPolygonVertices)
+		}
+		for _, vertex := range polygon {
+			if !inBounds(vertex.X, vertex.Y) {
+				return fmt.Errorf("polygon vertices must be between 1 and %d", maxRegionCoordinate)
+			}
+		}
+	}
+	return nil
+}
+
+// regionCacheKey identifies the plots of a region in cache keys. Names are
+// left out, so that regions covering the same plots share their stats.
+func regionCacheKey(region *models.Region) string {
+	if region == nil {
+		return "estate"
+	}
+	var key strings.Builder
+	if box := region.BBox; box != nil {
+		fmt.Fprintf(&key, "b%d,%d,%d,%d", box.X1, box.Y1, box.X2, box.Y2)
+	}
+	if rows := region.Rows; rows != nil {
+		fmt.Fprintf(&key, "r%d-%d", rows.From, rows.To)
+	}
+	if columns := region.Columns; columns != nil {
+		fmt.Fprintf(&key, "c%d-%d", columns.From, columns.To)
+	}
+	for i, vertex := range region.Polygon {
+		if i == 0 {
+			key.WriteString("p")
+		}
+		fmt.Fprintf(&key, "%d,%d;", vertex.X, vertex.Y)
+	}
+	return key.String()
+}
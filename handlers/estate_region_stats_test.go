@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sawitpro-recruitment/cache"
+	"sawitpro-recruitment/mocks"
+	"sawitpro-recruitment/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstateHandler_GetEstateStats_Region(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockHarvestRepo := mocks.NewMockHarvestRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+	handler.HarvestRepo = mockHarvestRepo
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/stats?rows=1-5&polygon=1,1,10,1,1,10", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	region := &models.Region{
+		Rows:    &models.Range{From: 1, To: 5},
+		Polygon: []models.Plot{{X: 1, Y: 1}, {X: 10, Y: 1}, {X: 1, Y: 10}},
+	}
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 100, Length: 100}, nil)
+	mockEstateRepo.EXPECT().GetEstateStats(estateID, region, gomock.Any()).Return(&models.HeightStats{Count: 3}, nil)
+	mockEstateRepo.EXPECT().GetHealthCounts(estateID, region).Return(map[string]int{"healthy": 3}, nil)
+	mockHarvestRepo.EXPECT().GetYieldTotals(estateID, region, gomock.Any(), gomock.Any()).Return(&models.YieldTotals{WeightKg: 400}, nil)
+
+	if assert.NoError(t, handler.GetEstateStats(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var stats models.EstateStats
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+		assert.Equal(t, region, stats.Region)
+		assert.Equal(t, 3, stats.Count)
+		// Rows 1 to 5 of the triangle hold 10 + 9 + 8 + 7 + 6 plots of 0.01 ha
+		assert.Equal(t, 0.4, stats.AreaHectares)
+		assert.Equal(t, 1.0, stats.TonnesPerHectare)
+	}
+}
+
+func TestEstateHandler_GetEstateStats_InvalidRegion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	for _, query := range []string{
+		"bbox=1,1,5",
+		"bbox=5,1,1,5",
+		"bbox=0,1,5,5",
+		"rows=10-5",
+		"rows=5",
+		"columns=a-b",
+		"polygon=1,1,5,5",
+		"polygon=1,1,5,1,5",
+		"polygon=1,1,5,1,60000,5",
+	} {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/estate/"+uuid.NewString()+"/stats?"+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(uuid.NewString())
+
+		if assert.NoError(t, handler.GetEstateStats(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	}
+}
+
+func TestEstateHandler_GetEstateRegionStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockHarvestRepo := mocks.NewMockHarvestRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+	handler.HarvestRepo = mockHarvestRepo
+	handler.Cache = cache.NewEstateCache()
+
+	estateID := uuid.New()
+	body := `{"regions": [
+		{"name": " Block A ", "bbox": {"x1": 1, "y1": 1, "x2": 5, "y2": 5}},
+		{"name": "North", "rows": {"from": 6, "to": 10}},
+		{"name": "Block A again", "bbox": {"x1": 1, "y1": 1, "x2": 5, "y2": 5}}
+	]}`
+	c, rec := newEstateRequest(http.MethodPost, "/stats/regions", estateID, "bucket_width=2", body)
+
+	blockA := &models.Region{Name: "Block A", BBox: &models.BoundingBox{X1: 1, Y1: 1, X2: 5, Y2: 5}}
+	north := &models.Region{Name: "North", Rows: &models.Range{From: 6, To: 10}}
+	options := models.StatsOptions{Percentiles: models.DefaultStatsPercentiles, BucketWidth: 2}
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 10}, nil)
+	gomock.InOrder(
+		mockEstateRepo.EXPECT().GetEstateStats(estateID, blockA, options).Return(&models.HeightStats{Count: 4}, nil),
+		mockEstateRepo.EXPECT().GetEstateStats(estateID, north, options).Return(&models.HeightStats{Count: 7}, nil),
+	)
+	mockEstateRepo.EXPECT().GetHealthCounts(estateID, gomock.Any()).Return(map[string]int{}, nil).Times(2)
+	mockHarvestRepo.EXPECT().GetYieldTotals(estateID, gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.YieldTotals{}, nil).Times(2)
+
+	if assert.NoError(t, handler.GetEstateRegionStats(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response map[string][]models.EstateStats
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		if assert.Len(t, response["regions"], 3) {
+			assert.Equal(t, "Block A", response["regions"][0].Region.Name)
+			assert.Equal(t, 4, response["regions"][0].Count)
+			assert.Equal(t, 0.25, response["regions"][0].AreaHectares)
+			assert.Equal(t, 7, response["regions"][1].Count)
+			assert.Equal(t, 0.5, response["regions"][1].AreaHectares)
+			// The same plots under another name are computed once
+			assert.Equal(t, "Block A again", response["regions"][2].Region.Name)
+			assert.Equal(t, 4, response["regions"][2].Count)
+		}
+	}
+}
+
+func TestEstateHandler_GetEstateRegionStats_InvalidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	tooMany := `{"regions": [` + strings.Repeat(`{"rows": {"from": 1, "to": 2}},`, maxStatsRegions) + `{"rows": {"from": 1, "to": 2}}]}`
+	for _, body := range []string{
+		`{"regions": []}`,
+		`{"regions": [{"name": "empty"}]}`,
+		`{"regions": [{"name": "` + strings.Repeat("é", maxRegionNameLength+1) + `", "rows": {"from": 1, "to": 2}}]}`,
+		`{"regions": [{"rows": {"from": 3, "to": 2}}]}`,
+		`{"regions": [{"polygon": [{"x": 1, "y": 1}, {"x": 2, "y": 2}]}]}`,
+		`{"regions": "all"}`,
+		tooMany,
+	} {
+		c, rec := newEstateRequest(http.MethodPost, "/stats/regions", uuid.New(), "", body)
+
+		if assert.NoError(t, handler.GetEstateRegionStats(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+		}
+	}
+}
+
+func TestValidateRegion_NameLength(t *testing.T) {
+	// The limit counts characters, not bytes
+	region := &models.Region{Name: strings.Repeat("é", maxRegionNameLength), Rows: &models.Range{From: 1, To: 2}}
+	assert.NoError(t, validateRegion(region))
+}
+
+func TestEstateHandler_GetEstateRegionStats_DatabaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodPost, "/stats/regions", estateID, "", `{"regions": [{"columns": {"from": 1, "to": 3}}]}`)
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 10}, nil)
+	mockEstateRepo.EXPECT().GetEstateStats(estateID, gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+
+	if assert.NoError(t, handler.GetEstateRegionStats(c)) {
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	}
+}
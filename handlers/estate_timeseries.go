@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sawitpro-recruitment/models"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	statsTimeseriesCacheKey = "stats-timeseries"
+	defaultTimeseriesPoints = 12  // Periods covered when from is absent
+	maxTimeseriesPoints     = 520 // Most periods of a series, ten years of weeks
+)
+
+// GetEstateTimeseries computes a stat of an estate over time
+// @Summary Get a stat of an estate over time
+// @Description Get a metric of the trees of an estate at the end of each week, month, quarter or year, from the latest measurement of each tree taken by then, with the growth rate of each age cohort over the series. Cohorts and the estate growing slower than expected are flagged.
+// @Tags estates
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param metric query string false "count, median_height (default), mean_height, max_height or min_height"
+// @Param interval query string false "week, month (default), quarter or year"
+// @Param from query string false "First day of the series (YYYY-MM-DD), moved back to the start of its period; 12 periods before to by default"
+// @Param to query string false "Last day of the series (YYYY-MM-DD), today by default"
+// @Success 200 {object} models.EstateTimeseries
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/stats/timeseries [get]
+func (h *EstateHandler) GetEstateTimeseries(c echo.Context) error {
+	series, periods, err := timeseriesParams(c)
+	if err != nil {
+		logrus.Warnf("Invalid time series request: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+
+	generation := cacheGeneration(c, h.Cache)
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	cacheKey := fmt.Sprintf("%s:%s:%s:%s:%s", statsTimeseriesCacheKey, series.Metric, series.Interval, series.From, series.To)
+	if cached, ok := h.Cache.Get(estate.ID, cacheKey); ok {
+		logrus.Infof("Estate time series served from cache for ID %s", estate.ID)
+		return c.JSON(http.StatusOK, cached)
+	}
+
+	end := series.To.AddDate(0, 0, 1)
+	series.Points, err = h.EstateRepo.GetEstateTimeseries(estate.ID, series.Metric, periods, end)
+	if err == nil {
+		series.Cohorts, err = h.EstateRepo.GetCohortGrowth(estate.ID, series.From.Time, end)
+	}
+	if err != nil {
+		logrus.Errorf("Failed to get time series for estate ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while fetching estate stats",
+		})
+	}
+	compareGrowth(series)
+	h.Cache.Set(estate.ID, cacheKey, generation, series)
+
+	logrus.Infof("Estate time series of %d points retrieved successfully for ID %s", len(series.Points), estate.ID)
+	return c.JSON(http.StatusOK, series)
+}
+
+// timeseriesParams parses the metric, interval, from and to query parameters
+// into an empty series, and lists the start of each of its periods.
+func timeseriesParams(c echo.Context) (*models.EstateTimeseries, []time.Time, error) {
+	series := &models.EstateTimeseries{
+		Metric:   c.QueryParam("metric"),
+		Interval: c.QueryParam("interval"),
+	}
+	if series.Metric == "" {
+		series.Metric = models.TimeseriesMetricMedianHeight
+	}
+	if !models.ValidTimeseriesMetric(series.Metric) {
+		return nil, nil, fmt.Errorf("metric must be one of %s", strings.Join(models.TimeseriesMetrics, ", "))
+	}
+	if series.Interval == "" {
+		series.Interval = models.TimeseriesIntervalMonth
+	}
+	if !models.ValidTimeseriesInterval(series.Interval) {
+		return nil, nil, fmt.Errorf("interval must be one of %s", strings.Join(models.TimeseriesIntervals, ", "))
+	}
+
+	to, err := optionalDateParam(c, "to")
+	if err != nil {
+		return nil, nil, err
+	}
+	if to == nil {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		to = &today
+	}
+	from, err := optionalDateParam(c, "from")
+	if err != nil {
+		return nil, nil, err
+	}
+	if from == nil {
+		first := addPeriods(periodStart(*to, series.Interval), series.Interval, 1-defaultTimeseriesPoints)
+		from = &first
+	}
+	if from.After(*to) {
+		return nil, nil, fmt.Errorf("from must not be after to")
+	}
+
+	var periods []time.Time
+	for start := periodStart(*from, series.Interval); !start.After(*to); start = addPeriods(start, series.Interval, 1) {
+		if len(periods) == maxTimeseriesPoints {
+			return nil, nil, fmt.Errorf("a series may have at most %d periods", maxTimeseriesPoints)
+		}
+		periods = append(periods, start)
+	}
+	series.From = models.Date{Time: periods[0]}
+	series.To = models.Date{Time: *to}
+	return series, periods, nil
+}
+
+// periodStart returns the first day of the period of an interval holding a
+// date. Weeks start on Monday.
+func periodStart(date time.Time, interval string) time.Time {
+	year, month, day := date.Date()
+	switch interval {
+	case models.TimeseriesIntervalWeek:
+		day -= (int(date.Weekday()) + 6) % 7
+	case models.TimeseriesIntervalMonth:
+		day = 1
+	case models.TimeseriesIntervalQuarter:
+		month, day = (month-1)/3*3+1, 1
+	case models.TimeseriesIntervalYear:
+		month, day = time.January, 1
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// addPeriods moves the start of a period by n periods of an interval.
+func addPeriods(start time.Time, interval string, n int) time.Time {
+	switch interval {
+	case models.TimeseriesIntervalWeek:
+		return start.AddDate(0, 0, 7*n)
+	case models.TimeseriesIntervalQuarter:
+		return start.AddDate(0, 3*n, 0)
+	case models.TimeseriesIntervalYear:
+		return start.AddDate(n, 0, 0)
+	}
+	return start.AddDate(0, n, 0)
+}
+
+// compareGrowth sets the expected growth of each age cohort of a series and of
+// the estate, and flags those growing slower than models.GrowthExpectationRatio
+// of it. Trees without a planting date have no expected growth.
+func compareGrowth(series *models.EstateTimeseries) {
+	expected := map[string]float64{}
+	for _, cohort := range models.AgeCohorts {
+		expected[cohort.Name] = cohort.ExpectedGrowth
+	}
+
+	trees := 0
+	var grown, expectedGrowth float64
+	for i := range series.Cohorts {
+		cohort := &series.Cohorts[i]
+		rate, ok := expected[cohort.Cohort]
+		if !ok {
+			continue
+		}
+		cohort.ExpectedGrowthRate = &rate
+		cohort.BelowExpectation = cohort.GrowthRate < rate*models.GrowthExpectationRatio
+		trees += cohort.Trees
+		grown += cohort.GrowthRate * float64(cohort.Trees)
+		expectedGrowth += rate * float64(cohort.Trees)
+	}
+	if trees == 0 {
+		return
+	}
+	growthRate := math.Round(grown/float64(trees)*100) / 100
+	expectedRate := math.Round(expectedGrowth/float64(trees)*100) / 100
+	series.GrowthRate = &growthRate
+	series.ExpectedGrowthRate = &expectedRate
+	series.BelowExpectation = growthRate < expectedRate*models.GrowthExpectationRatio
+}
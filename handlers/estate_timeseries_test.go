@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"sawitpro-recruitment/mocks"
+	"sawitpro-recruitment/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func utcDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestEstateHandler_GetEstateTimeseries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/stats/timeseries", estateID, "metric=mean_height&interval=quarter&from=2024-02-10&to=2024-08-01", "")
+
+	median := 11.5
+	periods := []time.Time{utcDate(2024, 1, 1), utcDate(2024, 4, 1), utcDate(2024, 7, 1)}
+	end := utcDate(2024, 8, 2)
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 10}, nil)
+	mockEstateRepo.EXPECT().GetEstateTimeseries(estateID, models.TimeseriesMetricMeanHeight, periods, end).Return([]models.TimeseriesPoint{
+		{Period: models.Date{Time: periods[0]}},
+		{Period: models.Date{Time: periods[1]}, Trees: 4, Value: &median},
+		{Period: models.Date{Time: periods[2]}, Trees: 5, Value: &median},
+	}, nil)
+	mockEstateRepo.EXPECT().GetCohortGrowth(estateID, periods[0], end).Return([]models.CohortGrowth{
+		{Cohort: "immature", Trees: 2, GrowthRate: 0.5},
+		{Cohort: "prime", Trees: 2, GrowthRate: 0.6},
+		{Cohort: models.AgeCohortUnknown, Trees: 1, GrowthRate: 0.1},
+	}, nil)
+
+	if assert.NoError(t, handler.GetEstateTimeseries(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var series models.EstateTimeseries
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &series))
+		assert.Equal(t, "mean_height", series.Metric)
+		assert.Equal(t, "2024-01-01", series.From.String())
+		assert.Equal(t, "2024-08-01", series.To.String())
+		if assert.Len(t, series.Points, 3) {
+			assert.Nil(t, series.Points[0].Value)
+			assert.Equal(t, 4, series.Points[1].Trees)
+		}
+		if assert.Len(t, series.Cohorts, 3) {
+			// Immature palms grew half as fast as expected, prime ones as expected
+			assert.Equal(t, 1.0, *series.Cohorts[0].ExpectedGrowthRate)
+			assert.True(t, series.Cohorts[0].BelowExpectation)
+			assert.Equal(t, 0.6, *series.Cohorts[1].ExpectedGrowthRate)
+			assert.False(t, series.Cohorts[1].BelowExpectation)
+			assert.Nil(t, series.Cohorts[2].ExpectedGrowthRate)
+			assert.False(t, series.Cohorts[2].BelowExpectation)
+		}
+		// The trees without a planting date are left out of the estate growth
+		assert.Equal(t, 0.55, *series.GrowthRate)
+		assert.Equal(t, 0.8, *series.ExpectedGrowthRate)
+		assert.True(t, series.BelowExpectation)
+	}
+}
+
+func TestEstateHandler_GetEstateTimeseries_Defaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/stats/timeseries", estateID, "", "")
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	thisMonth := utcDate(today.Year(), today.Month(), 1)
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 10}, nil)
+	mockEstateRepo.EXPECT().GetEstateTimeseries(estateID, models.TimeseriesMetricMedianHeight, gomock.Any(), today.AddDate(0, 0, 1)).
+		DoAndReturn(func(_ uuid.UUID, _ string, periods []time.Time, _ time.Time) ([]models.TimeseriesPoint, error) {
+			if assert.Len(t, periods, defaultTimeseriesPoints) {
+				assert.Equal(t, thisMonth.AddDate(0, -11, 0), periods[0])
+				assert.Equal(t, thisMonth, periods[len(periods)-1])
+			}
+			return []models.TimeseriesPoint{}, nil
+		})
+	mockEstateRepo.EXPECT().GetCohortGrowth(estateID, thisMonth.AddDate(0, -11, 0), today.AddDate(0, 0, 1)).Return([]models.CohortGrowth{}, nil)
+
+	if assert.NoError(t, handler.GetEstateTimeseries(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var series map[string]interface{}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &series))
+		assert.Equal(t, "month", series["interval"])
+		assert.Nil(t, series["growth_rate"])
+		assert.Equal(t, false, series["below_expectation"])
+	}
+}
+
+func TestPeriodStart(t *testing.T) {
+	// Wednesday 3 July 2024
+	day := utcDate(2024, 7, 3)
+	assert.Equal(t, utcDate(2024, 7, 1), periodStart(day, models.TimeseriesIntervalWeek))
+	assert.Equal(t, utcDate(2024, 7, 1), periodStart(day, models.TimeseriesIntervalMonth))
+	assert.Equal(t, utcDate(2024, 7, 1), periodStart(day, models.TimeseriesIntervalQuarter))
+	assert.Equal(t, utcDate(2024, 1, 1), periodStart(day, models.TimeseriesIntervalYear))
+	// A Sunday belongs to the week started the Monday before
+	assert.Equal(t, utcDate(2024, 6, 24), periodStart(utcDate(2024, 6, 30), models.TimeseriesIntervalWeek))
+	assert.Equal(t, utcDate(2024, 10, 1), periodStart(utcDate(2024, 12, 31), models.TimeseriesIntervalQuarter))
+}
+
+func TestEstateHandler_GetEstateTimeseries_InvalidParams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	for _, query := range []string{
+		"metric=volume",
+		"interval=day",
+		"from=2024-13-01",
+		"to=yesterday",
+		"from=2024-05-01&to=2024-04-30",
+		"interval=week&from=2000-01-01&to=2024-01-01",
+	} {
+		c, rec := newEstateRequest(http.MethodGet, "/stats/timeseries", uuid.New(), query, "")
+
+		if assert.NoError(t, handler.GetEstateTimeseries(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	}
+}
+
+func TestEstateHandler_GetEstateTimeseries_DatabaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/stats/timeseries", estateID, "from=2024-01-01&to=2024-03-31", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 10}, nil)
+	mockEstateRepo.EXPECT().GetEstateTimeseries(estateID, gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.TimeseriesPoint{}, nil)
+	mockEstateRepo.EXPECT().GetCohortGrowth(estateID, gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+
+	if assert.NoError(t, handler.GetEstateTimeseries(c)) {
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	}
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sawitpro-recruitment/models"
+	"sawitpro-recruitment/spreadsheet"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultYieldBlockSize = 10   // Side of a yield block in plots, 100 meters
+	maxYieldBlockSize     = 1000 // Largest block side accepted
+)
+
+// GetEstateYield reports the harvested yield of an estate in total and per block
+// @Summary Get the yield of an estate
+// @Description Get the bunches and weight harvested in an estate between two dates, both included, in total and per square block of plots, with the yield in tonnes per hectare. The range defaults to the 12 months up to today.
+// @Tags harvests
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param id path string true "Estate ID"
+// @Param from query string false "First harvest day included (YYYY-MM-DD)"
+// @Param to query string false "Last harvest day included (YYYY-MM-DD), defaults to today"
+// @Param block_size query int false "Side of a block in plots, 10 by default"
+// @Param format query string false "json (default) or xlsx, a workbook with a summary and a block sheet; XLSX is also chosen by an Accept header naming it"
+// @Success 200 {object} models.EstateYield
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/yield [get]
+func (h *EstateHandler) GetEstateYield(c echo.Context) error {
+	xlsx, err := xlsxParam(c)
+	if err != nil {
+		logrus.Warnf("Invalid yield format: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+	from, to, err := yieldRangeParams(c)
+	if err != nil {
+		logrus.Warnf("Invalid yield range: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+	blockSize, err := optionalIntParam(c, "block_size")
+	if err == nil && blockSize != nil && (*blockSize < 1 || *blockSize > maxYieldBlockSize) {
+		err = fmt.Errorf("block_size must be between 1 and %d", maxYieldBlockSize)
+	}
+	if err != nil {
+		logrus.Warnf("Invalid block_size: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+	size := defaultYieldBlockSize
+	if blockSize != nil {
+		size = *blockSize
+	}
+
+	generation := cacheGeneration(c, h.Cache)
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	respond := func(yield *models.EstateYield) error {
+		if xlsx {
+			return sendXLSX(c, fmt.Sprintf("estate-%s-yield", estate.ID), func(wb *spreadsheet.Workbook) error {
+				return writeYield(wb, yield)
+			})
+		}
+		return c.JSON(http.StatusOK, yield)
+	}
+
+	cacheKey := fmt.Sprintf("yield:%s:%s:%d", from.Format(models.DateLayout), to.Format(models.DateLayout), size)
+	if cached, ok := h.Cache.Get(estate.ID, cacheKey); ok {
+		logrus.Infof("Estate yield served from cache for ID %s", estate.ID)
+		return respond(cached.(*models.EstateYield))
+	}
+
+	blocks, err := h.HarvestRepo.GetBlockYields(estate.ID, from, to, size)
+	if err != nil {
+		logrus.Errorf("Failed to get block yields for estate ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while fetching estate yield",
+		})
+	}
+
+	yield := &models.EstateYield{
+		From:         models.Date{Time: from},
+		To:           models.Date{Time: to},
+		BlockSize:    size,
+		AreaHectares: estate.AreaHectares(),
+		Blocks:       blocks,
+	}
+	for _, block := range blocks {
+		block.X1, block.Y1 = (block.BlockX-1)*size+1, (block.BlockY-1)*size+1
+		block.X2, block.Y2 = min(block.BlockX*size, estate.Width), min(block.BlockY*size, estate.Length)
+		// Harvests recorded before the estate shrank may lie in blocks it no longer covers
+		plots := max(block.X2-block.X1+1, 0) * max(block.Y2-block.Y1+1, 0)
+		block.AreaHectares = float64(plots) * models.PlotAreaHectares
+		block.TonnesPerHectare = models.TonnesPerHectare(block.WeightKg, block.AreaHectares)
+
+		yield.Harvests += block.Harvests
+		yield.Bunches += block.Bunches
+		yield.WeightKg += block.WeightKg
+	}
+	yield.TonnesPerHectare = models.TonnesPerHectare(yield.WeightKg, yield.AreaHectares)
+	h.Cache.Set(estate.ID, cacheKey, generation, yield)
+
+	logrus.Infof("Estate yield retrieved successfully for ID %s", estate.ID)
+	return respond(yield)
+}
+
+// yieldRangeParams parses the from and to query parameters. Without them the
+// range covers the 12 months up to today.
+func yieldRangeParams(c echo.Context) (time.Time, time.Time, error) {
+	to, err := optionalDateParam(c, "to")
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	from, err := optionalDateParam(c, "from")
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if to == nil {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		to = &today
+	}
+	if from == nil {
+		start := trailingYearStart(*to)
+		from = &start
+	}
+	if from.After(*to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from must not be after to")
+	}
+	return *from, *to, nil
+}
+
+// trailingYearStart returns the first day of the 12 months ending on the given day.
+func trailingYearStart(to time.Time) time.Time {
+	return to.AddDate(-1, 0, 1)
+}
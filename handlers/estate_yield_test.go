@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"sawitpro-recruitment/mocks"
+	"sawitpro-recruitment/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstateHandler_GetEstateYield(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockHarvestRepo := mocks.NewMockHarvestRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+	handler.HarvestRepo = mockHarvestRepo
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/yield", estateID, "from=2024-01-01&to=2024-06-30&block_size=10", "")
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 15, Length: 10}, nil)
+	mockHarvestRepo.EXPECT().GetBlockYields(estateID, from, to, 10).Return([]*models.BlockYield{
+		{BlockX: 1, BlockY: 1, YieldTotals: models.YieldTotals{Harvests: 10, Bunches: 100, WeightKg: 2000}},
+		{BlockX: 2, BlockY: 1, YieldTotals: models.YieldTotals{Harvests: 5, Bunches: 40, WeightKg: 1000}},
+	}, nil)
+
+	if assert.NoError(t, handler.GetEstateYield(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var yield models.EstateYield
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &yield))
+		assert.Equal(t, "2024-01-01", yield.From.String())
+		assert.Equal(t, "2024-06-30", yield.To.String())
+		assert.Equal(t, models.YieldTotals{Harvests: 15, Bunches: 140, WeightKg: 3000}, yield.YieldTotals)
+		// 15 by 10 plots of 10 meters make 1.5 hectares
+		assert.Equal(t, 1.5, yield.AreaHectares)
+		assert.Equal(t, 2.0, yield.TonnesPerHectare)
+		if assert.Len(t, yield.Blocks, 2) {
+			assert.Equal(t, 2.0, yield.Blocks[0].TonnesPerHectare)
+			// The second block is cut short by the edge of the estate
+			edge := yield.Blocks[1]
+			assert.Equal(t, []int{11, 1, 15, 10}, []int{edge.X1, edge.Y1, edge.X2, edge.Y2})
+			assert.Equal(t, 0.5, edge.AreaHectares)
+			assert.Equal(t, 2.0, edge.TonnesPerHectare)
+		}
+	}
+}
+
+func TestEstateHandler_GetEstateYield_DefaultRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockHarvestRepo := mocks.NewMockHarvestRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+	handler.HarvestRepo = mockHarvestRepo
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/yield", estateID, "to=2024-06-30", "")
+
+	// The 12 months up to the to date
+	from := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 10}, nil)
+	mockHarvestRepo.EXPECT().GetBlockYields(estateID, from, to, defaultYieldBlockSize).Return([]*models.BlockYield{}, nil)
+
+	if assert.NoError(t, handler.GetEstateYield(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var yield models.EstateYield
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &yield))
+		assert.Equal(t, 0.0, yield.TonnesPerHectare)
+		assert.Empty(t, yield.Blocks)
+	}
+}
+
+func TestEstateHandler_GetEstateYield_InvalidParams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+
+	for _, query := range []string{"from=June", "to=2024-13-01", "from=2024-07-01&to=2024-06-30", "block_size=0", "block_size=big"} {
+		c, rec := newEstateRequest(http.MethodGet, "/yield", uuid.New(), query, "")
+
+		if assert.NoError(t, handler.GetEstateYield(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"fmt"
+	"sawitpro-recruitment/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultPageSize = 20  // Page size used when the limit query parameter is missing
+	maxPageSize     = 100 // Largest page size a client may request
+)
+
+// optionalIntParam parses an optional integer query parameter.
+// It returns nil when the parameter is absent.
+func optionalIntParam(c echo.Context, name string) (*int, error) {
+	raw := c.QueryParam(name)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value", name)
+	}
+	return &value, nil
+}
+
+// pageSizeParam parses the limit query parameter, applying the default page size.
+func pageSizeParam(c echo.Context) (int, error) {
+	limit, err := optionalIntParam(c, "limit")
+	if err != nil {
+		return 0, err
+	}
+	if limit == nil {
+		return defaultPageSize, nil
+	}
+	if *limit < 1 || *limit > maxPageSize {
+		return 0, fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+	}
+	return *limit, nil
+}
+
+// asOfParam parses the optional as_of query parameter, a date in UTC. It
+// returns the start of the following day, so that every measurement taken on
+// the as_of date is included, and the date as given for use in cache keys.
+// A nil time means the parameter is absent.
+func asOfParam(c echo.Context) (*time.Time, string, error) {
+	raw := c.QueryParam("as_of")
+	if raw == "" {
+		return nil, "", nil
+	}
+	date, err := time.Parse(models.DateLayout, raw)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid as_of value, expected YYYY-MM-DD")
+	}
+	before := date.AddDate(0, 0, 1)
+	return &before, raw, nil
+}
+
+// optionalDateParam parses an optional YYYY-MM-DD query parameter as a date in
+// UTC. It returns nil when the parameter is absent.
+func optionalDateParam(c echo.Context, name string) (*time.Time, error) {
+	raw := c.QueryParam(name)
+	if raw == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(models.DateLayout, raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value, expected YYYY-MM-DD", name)
+	}
+	return &date, nil
+}
+
+const (
+	maxStatsPercentiles     = 10  // Most percentiles a client may request
+	maxHistogramBucketWidth = 100 // Widest histogram bucket a client may request, in meters
+)
+
+// statsOptionsParam parses the percentiles query parameter, a comma-separated
+// list of percents, and the bucket_width query parameter, applying the
+// defaults of the height stats.
+func statsOptionsParam(c echo.Context) (models.StatsOptions, error) {
+	options := models.StatsOptions{
+		Percentiles: models.DefaultStatsPercentiles,
+		BucketWidth: models.DefaultHistogramBucketWidth,
+	}
+	if raw := c.QueryParam("percentiles"); raw != "" {
+		parts := strings.Split(raw, ",")
+		if len(parts) > maxStatsPercentiles {
+			return options, fmt.Errorf("at most %d percentiles may be requested", maxStatsPercentiles)
+		}
+		options.Percentiles = make([]float64, len(parts))
+		for i, part := range parts {
+			p, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil || p <= 0 || p >= 100 {
+				return options, fmt.Errorf("percentiles must be numbers above 0 and below 100")
+			}
+			options.Percentiles[i] = p
+		}
+	}
+	width, err := optionalIntParam(c, "bucket_width")
+	if err != nil {
+		return options, err
+	}
+	if width != nil {
+		if *width < 1 || *width > maxHistogramBucketWidth {
+			return options, fmt.Errorf("bucket_width must be between 1 and %d", maxHistogramBucketWidth)
+		}
+		options.BucketWidth = *width
+	}
+	return options, nil
+}
+
+// statsOptionsCacheKey identifies the stats computed with the options in cache keys.
+func statsOptionsCacheKey(options models.StatsOptions) string {
+	percentiles := make([]string, len(options.Percentiles))
+	for i, p := range options.Percentiles {
+		percentiles[i] = strconv.FormatFloat(p, 'g', -1, 64)
+	}
+	return fmt.Sprintf("%s:%d", strings.Join(percentiles, ","), options.BucketWidth)
+}
+
+// regionParam parses the region query parameters: bbox as x1,y1,x2,y2, rows
+// and columns as from-to, and polygon as the list of its vertices x1,y1,x2,y2,...
+// It returns nil when none is present. The region is not validated.
+func regionParam(c echo.Context) (*models.Region, error) {
+	region := &models.Region{}
+	present := false
+	if raw := c.QueryParam("bbox"); raw != "" {
+		values, err := intList(raw, ",")
+		if err != nil || len(values) != 4 {
+			return nil, fmt.Errorf("invalid bbox value, expected x1,y1,x2,y2")
+		}
+		region.BBox = &models.BoundingBox{X1: values[0], Y1: values[1], X2: values[2], Y2: values[3]}
+		present = true
+	}
+	for _, name := range []string{"rows", "columns"} {
+		raw := c.QueryParam(name)
+		if raw == "" {
+			continue
+		}
+		values, err := intList(raw, "-")
+		if err != nil || len(values) != 2 {
+			return nil, fmt.Errorf("invalid %s value, expected from-to", name)
+		}
+		if name == "rows" {
+			region.Rows = &models.Range{From: values[0], To: values[1]}
+		} else {
+			region.Columns = &models.Range{From: values[0], To: values[1]}
+		}
+		present = true
+	}
+	if raw := c.QueryParam("polygon"); raw != "" {
+		values, err := intList(raw, ",")
+		if err != nil || len(values)%2 != 0 {
+			return nil, fmt.Errorf("invalid polygon value, expected x1,y1,x2,y2,... vertices")
+		}
+		for i := 0; i < len(values); i += 2 {
+			region.Polygon = append(region.Polygon, models.Plot{X: values[i], Y: values[i+1]})
+		}
+		present = true
+	}
+	if !present {
+		return nil, nil
+	}
+	return region, nil
+}
+
+// intList parses a list of integers separated by sep.
+func intList(raw, sep string) ([]int, error) {
+	parts := strings.Split(raw, sep)
+	values := make([]int, len(parts))
+	for i, part := range parts {
+		value, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sawitpro-recruitment/models"
+	"sawitpro-recruitment/repositories"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// speciesCodePattern matches species codes such as "tenera" or "clone-mk60".
+var speciesCodePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+const (
+	maxSpeciesNameLength = 100 // Longest species name accepted
+	maxSpeciesHeight     = 150 // Largest max_height accepted for a species, in meters
+)
+
+// SpeciesHandler manages species-related requests.
+type SpeciesHandler struct {
+	SpeciesRepo repositories.SpeciesRepository
+}
+
+// NewSpeciesHandler creates a new SpeciesHandler.
+func NewSpeciesHandler(speciesRepo repositories.SpeciesRepository) *SpeciesHandler {
+	return &SpeciesHandler{
+		SpeciesRepo: speciesRepo,
+	}
+}
+
+// ListSpecies returns every species trees can be planted with
+// @Summary List species
+// @Description List the species trees can be planted with, ordered by code
+// @Tags species
+// @Produce json
+// @Success 200 {object} map[string][]models.Species
+// @Failure 500 {object} map[string]string
+// @Router /species [get]
+func (h *SpeciesHandler) ListSpecies(c echo.Context) error {
+	species, err := h.SpeciesRepo.ListSpecies()
+	if err != nil {
+		logrus.Errorf("Failed to list species: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while listing species",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string][]*models.Species{
+		"species": species,
+	})
+}
+
+// CreateSpecies adds a species with the height range accepted for its trees
+// @Summary Create a species
+// @Description Add a species, such as a palm variety or clone line, with the height range accepted for its trees
+// @Tags species
+// @Accept json
+// @Produce json
+// @Param species body models.Species true "Species"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /species [post]
+func (h *SpeciesHandler) CreateSpecies(c echo.Context) error {
+	species := new(models.Species)
+	if err := c.Bind(species); err != nil {
+		logrus.Warnf("Failed to bind species: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Invalid input format",
+		})
+	}
+
+	species.Code = strings.ToLower(strings.TrimSpace(species.Code))
+	species.Name = strings.TrimSpace(species.Name)
+	if err := validateSpecies(species); err != nil {
+		logrus.Warnf("Invalid species: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+	species.CreatedAt = time.Now().UTC()
+
+	err := h.SpeciesRepo.CreateSpecies(species)
+	if errors.Is(err, repositories.ErrDuplicateSpecies) {
+		logrus.Warnf("Species code already exists: %s", species.Code)
+		return c.JSON(http.StatusConflict, map[string]string{
+			"message": "Species code already exists",
+		})
+	}
+	if err != nil {
+		logrus.Errorf("Failed to store species in database: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Failed to store species in database",
+		})
+	}
+
+	logrus.Infof("Species created successfully: %s", species.Code)
+	return c.JSON(http.StatusOK, map[string]string{
+		"code": species.Code,
+	})
+}
+
+// validateSpecies checks the code, name and height range of a species.
+func validateSpecies(species *models.Species) error {
+	switch {
+	case !speciesCodePattern.MatchString(species.Code):
+		return errors.New("Species code must be 1 to 32 lowercase letters, digits, dashes or underscores")
+	case species.Name == "":
+		return errors.New("Species name is required")
+	case len([]rune(species.Name)) > maxSpeciesNameLength:
+		return fmt.Errorf("Species name must be at most %d characters", maxSpeciesNameLength)
+	case species.MinHeight < 1 || species.MaxHeight < species.MinHeight || species.MaxHeight > maxSpeciesHeight:
+		return fmt.Errorf("Species heights must satisfy 1 <= min_height <= max_height <= %d", maxSpeciesHeight)
+	}
+	return nil
+}
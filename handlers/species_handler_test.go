@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"sawitpro-recruitment/mocks"
+	"sawitpro-recruitment/models"
+	"sawitpro-recruitment/repositories"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpeciesHandler_ListSpecies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSpeciesRepo := mocks.NewMockSpeciesRepository(ctrl)
+	handler := NewSpeciesHandler(mockSpeciesRepo)
+	c, rec := newRequest(http.MethodGet, "/species", "", "")
+
+	mockSpeciesRepo.EXPECT().ListSpecies().Return([]*models.Species{
+		{Code: "dura", Name: "Dura", MinHeight: 1, MaxHeight: 30},
+	}, nil)
+
+	if assert.NoError(t, handler.ListSpecies(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response map[string][]models.Species
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		if assert.Len(t, response["species"], 1) {
+			assert.Equal(t, "dura", response["species"][0].Code)
+		}
+	}
+}
+
+func TestSpeciesHandler_CreateSpecies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSpeciesRepo := mocks.NewMockSpeciesRepository(ctrl)
+	handler := NewSpeciesHandler(mockSpeciesRepo)
+	c, rec := newRequest(http.MethodPost, "/species", "", `{"code": " Clone-MK60 ", "name": "Clone MK60", "min_height": 1, "max_height": 18}`)
+
+	mockSpeciesRepo.EXPECT().CreateSpecies(gomock.Any()).DoAndReturn(func(species *models.Species) error {
+		assert.Equal(t, "clone-mk60", species.Code)
+		assert.False(t, species.CreatedAt.IsZero())
+		return nil
+	})
+
+	if assert.NoError(t, handler.CreateSpecies(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"code": "clone-mk60"}`, rec.Body.String())
+	}
+}
+
+func TestSpeciesHandler_CreateSpecies_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewSpeciesHandler(mocks.NewMockSpeciesRepository(ctrl))
+	bodies := []string{
+		`{"code": "bad code", "name": "Bad", "min_height": 1, "max_height": 10}`,
+		`{"code": "noname", "name": " ", "min_height": 1, "max_height": 10}`,
+		`{"code": "inverted", "name": "Inverted", "min_height": 10, "max_height": 5}`,
+		`{"code": "giant", "name": "Giant", "min_height": 1, "max_height": 500}`,
+	}
+	for _, body := range bodies {
+		c, rec := newRequest(http.MethodPost, "/species", "", body)
+		if assert.NoError(t, handler.CreateSpecies(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+		}
+	}
+}
+
+func TestSpeciesHandler_CreateSpecies_Duplicate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSpeciesRepo := mocks.NewMockSpeciesRepository(ctrl)
+	handler := NewSpeciesHandler(mockSpeciesRepo)
+	c, rec := newRequest(http.MethodPost, "/species", "", `{"code": "dura", "name": "Dura", "min_height": 1, "max_height": 30}`)
+
+	mockSpeciesRepo.EXPECT().CreateSpecies(gomock.Any()).Return(repositories.ErrDuplicateSpecies)
+
+	if assert.NoError(t, handler.CreateSpecies(c)) {
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	}
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sawitpro-recruitment/anomalies"
+	"sawitpro-recruitment/models"
+	"sawitpro-recruitment/repositories"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultAnomalyListSize = 100  // Anomalies listed when the limit query parameter is missing
+	maxAnomalyListSize     = 1000 // Largest limit accepted
+	maxAnomalyNoteLength   = 2000 // Longest note accepted on a review
+)
+
+// DetectAnomalies scores the trees of an estate and stores the anomalies found
+// @Summary Detect height anomalies
+// @Description Score every tree of an estate against the trees around it, the trees of its age cohort and its previous measurement. Trees standing out are stored as anomalies; a tree already reported keeps its review status. Open anomalies no longer found are resolved and removed.
+// @Tags anomalies
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Success 200 {object} models.AnomalyDetection
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/anomalies/detect [post]
+func (h *TreeHandler) DetectAnomalies(c echo.Context) error {
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	cohorts, err := h.AnomalyRepo.GetCohortHeightCounts(estate.ID)
+	if err != nil {
+		logrus.Errorf("Failed to load cohort heights for estate ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while fetching cohort heights",
+		})
+	}
+	drops, err := h.AnomalyRepo.GetHeightDrops(estate.ID)
+	if err != nil {
+		logrus.Errorf("Failed to load height drops for estate ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while fetching measurements",
+		})
+	}
+
+	// Trees come row by row, as the detector needs them
+	detector := anomalies.NewDetector(anomalies.DefaultConfig(), cohorts, drops)
+	err = h.TreeRepo.ExportTrees(estate.ID, func(tree *models.Tree) error {
+		detector.Add(tree)
+		return nil
+	})
+	if err != nil {
+		logrus.Errorf("Failed to load trees for estate ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while fetching trees",
+		})
+	}
+	found, scored := detector.Finish()
+
+	detectedAt := time.Now().UTC()
+	resolved, err := h.AnomalyRepo.SaveAnomalies(estate.ID, found, detectedAt)
+	if err != nil {
+		logrus.Errorf("Failed to store anomalies for estate ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Failed to store anomalies in database",
+		})
+	}
+
+	detection := &models.AnomalyDetection{
+		Trees:     scored,
+		Anomalies: len(found),
+		Reasons: map[string]int{
+			models.AnomalyReasonHeightDrop: 0,
+			models.AnomalyReasonNeighbours: 0,
+			models.AnomalyReasonCohort:     0,
+		},
+		Resolved:   resolved,
+		DetectedAt: detectedAt,
+	}
+	for _, anomaly := range found {
+		for _, reason := range anomaly.Reasons {
+			detection.Reasons[reason.Code]++
+		}
+	}
+
+	logrus.Infof("Detected %d anomalies among %d trees for estate ID %s, %d resolved", len(found), scored, estate.ID, resolved)
+	return c.JSON(http.StatusOK, detection)
+}
+
+// ListAnomalies returns the anomalies of an estate
+// @Summary List height anomalies
+// @Description List the anomalies of an estate, highest score first, with the number of anomalies of each status.
+// @Tags anomalies
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param status query string false "Review status: open, acknowledged or dismissed"
+// @Param limit query int false "Anomalies listed, 100 by default, at most 1000"
+// @Success 200 {object} models.AnomalyList
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/anomalies [get]
+func (h *TreeHandler) ListAnomalies(c echo.Context) error {
+	query := models.AnomalyQuery{Status: c.QueryParam("status"), Limit: defaultAnomalyListSize}
+	if query.Status != "" && !models.ValidAnomalyStatus(query.Status) {
+		logrus.Warnf("Invalid anomaly status: %s", query.Status)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "status must be open, acknowledged or dismissed",
+		})
+	}
+	limit, err := optionalIntParam(c, "limit")
+	if err == nil && limit != nil && (*limit < 1 || *limit > maxAnomalyListSize) {
+		err = fmt.Errorf("limit must be between 1 and %d", maxAnomalyListSize)
+	}
+	if err != nil {
+		logrus.Warnf("Invalid anomaly list limit: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+	if limit != nil {
+		query.Limit = *limit
+	}
+
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	list, err := h.AnomalyRepo.ListAnomalies(estate.ID, query)
+	if err != nil {
+		logrus.Errorf("Database error while listing anomalies for estate ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while fetching anomalies",
+		})
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+// AcknowledgeAnomaly marks an anomaly as being followed up
+// @Summary Acknowledge an anomaly
+// @Description Mark an open anomaly as acknowledged, with an optional note. A dismissed anomaly cannot be acknowledged.
+// @Tags anomalies
+// @Accept json
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param anomalyId path string true "Anomaly ID"
+// @Param review body models.AnomalyReview false "Review note"
+// @Success 200 {object} models.TreeAnomaly
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/anomalies/{anomalyId}/acknowledge [post]
+func (h *TreeHandler) AcknowledgeAnomaly(c echo.Context) error {
+	return h.reviewAnomaly(c, models.AnomalyStatusAcknowledged)
+}
+
+// DismissAnomaly marks an anomaly as a false alarm
+// @Summary Dismiss an anomaly
+// @Description Mark an open or acknowledged anomaly as dismissed, with an optional note. Dismissed anomalies are kept when detection runs again.
+// @Tags anomalies
+// @Accept json
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param anomalyId path string true "Anomaly ID"
+// @Param review body models.AnomalyReview false "Review note"
+// @Success 200 {object} models.TreeAnomaly
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/anomalies/{anomalyId}/dismiss [post]
+func (h *TreeHandler) DismissAnomaly(c echo.Context) error {
+	return h.reviewAnomaly(c, models.AnomalyStatusDismissed)
+}
+
+// reviewAnomaly moves the anomaly named in the path to status. Reviewing an
+// anomaly already in status only updates its note.
+func (h *TreeHandler) reviewAnomaly(c echo.Context, status string) error {
+	id := c.Param("anomalyId")
+	anomalyID, err := uuid.Parse(id)
+	if err != nil {
+		logrus.Warnf("Invalid anomaly ID format: %s", id)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Invalid anomaly ID format",
+		})
+	}
+	var review models.AnomalyReview
+	if err := json.NewDecoder(c.Request().Body).Decode(&review); err != nil && !errors.Is(err, io.EOF) {
+		logrus.Warnf("Failed to read anomaly review: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Invalid request body",
+		})
+	}
+	if n := len([]rune(review.Note)); n > maxAnomalyNoteLength {
+		logrus.Warnf("Anomaly note too long: %d characters", n)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": fmt.Sprintf("note must be at most %d characters", maxAnomalyNoteLength),
+		})
+	}
+
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	anomaly, err := h.AnomalyRepo.GetAnomaly(estate.ID, anomalyID)
+	if err != nil {
+		logrus.Errorf("Database error while retrieving anomaly ID %s: %v", anomalyID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while retrieving anomaly",
+		})
+	}
+	if anomaly == nil {
+		logrus.Warnf("Anomaly %s not found in estate %s", anomalyID, estate.ID)
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"message": "Anomaly not found",
+		})
+	}
+	if status == models.AnomalyStatusAcknowledged && anomaly.Status == models.AnomalyStatusDismissed {
+		logrus.Warnf("Anomaly %s is dismissed and cannot be acknowledged", anomalyID)
+		return c.JSON(http.StatusConflict, map[string]string{
+			"message": "Anomaly has been dismissed",
+		})
+	}
+
+	reviewedAt := time.Now().UTC()
+	anomaly.Status = status
+	anomaly.Note = review.Note
+	anomaly.ReviewedAt = &reviewedAt
+	if err := h.AnomalyRepo.ReviewAnomaly(anomaly); err != nil {
+		// The anomaly was resolved by a detection run since it was read
+		if errors.Is(err, repositories.ErrAnomalyNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"message": "Anomaly not found",
+			})
+		}
+		// Or dismissed by a concurrent review
+		if errors.Is(err, repositories.ErrAnomalyDismissed) {
+			logrus.Warnf("Anomaly %s is dismissed and cannot be acknowledged", anomalyID)
+			return c.JSON(http.StatusConflict, map[string]string{
+				"message": "Anomaly has been dismissed",
+			})
+		}
+		logrus.Errorf("Failed to review anomaly ID %s: %v", anomalyID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Failed to update anomaly in database",
+		})
+	}
+
+	logrus.Infof("Anomaly %s of estate ID %s marked %s", anomalyID, estate.ID, status)
+	return c.JSON(http.StatusOK, anomaly)
+}
@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sawitpro-recruitment/mocks"
+	"sawitpro-recruitment/models"
+	"sawitpro-recruitment/repositories"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// newAnomalyReviewRequest builds the context of a request to review an anomaly
+// with action, such as "dismiss".
+func newAnomalyReviewRequest(estateID uuid.UUID, anomalyID, action, body string) (echo.Context, *httptest.ResponseRecorder) {
+	c, rec := newEstateRequest(http.MethodPost, "/anomalies/"+anomalyID+"/"+action, estateID, "", body)
+	c.SetParamNames("id", "anomalyId")
+	c.SetParamValues(estateID.String(), anomalyID)
+	return c, rec
+}
+
+func newAnomalyHandler(ctrl *gomock.Controller) (*TreeHandler, *mocks.MockTreeRepository, *mocks.MockEstateRepository, *mocks.MockAnomalyRepository) {
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockAnomalyRepo := mocks.NewMockAnomalyRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+	handler.AnomalyRepo = mockAnomalyRepo
+	return handler, mockTreeRepo, mockEstateRepo, mockAnomalyRepo
+}
+
+func TestTreeHandler_DetectAnomalies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, mockTreeRepo, mockEstateRepo, mockAnomalyRepo := newAnomalyHandler(ctrl)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodPost, "/anomalies/detect", estateID, "", "")
+
+	// A 5x5 stand of 15 m trees with a 4 m tree in the middle that was 9 m before
+	var trees []*models.Tree
+	for y := 1; y <= 5; y++ {
+		for x := 1; x <= 5; x++ {
+			trees = append(trees, &models.Tree{ID: uuid.New(), EstateID: estateID, X: x, Y: y, Height: 15})
+		}
+	}
+	short := trees[12]
+	short.Height = 4
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockAnomalyRepo.EXPECT().GetCohortHeightCounts(estateID).Return(map[string]map[int]int{models.AgeCohortUnknown: {15: 24, 4: 1}}, nil)
+	mockAnomalyRepo.EXPECT().GetHeightDrops(estateID).Return(map[uuid.UUID]int{short.ID: 9}, nil)
+	mockTreeRepo.EXPECT().ExportTrees(estateID, gomock.Any()).DoAndReturn(exportTreesOf(trees...))
+	mockAnomalyRepo.EXPECT().SaveAnomalies(estateID, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ uuid.UUID, found []*models.TreeAnomaly, _ time.Time) (int, error) {
+			if assert.Len(t, found, 1) {
+				assert.Equal(t, short.ID, found[0].TreeID)
+				assert.Equal(t, models.AnomalyReasonNeighbours, found[0].Reasons[0].Code)
+			}
+			return 2, nil
+		})
+
+	if assert.NoError(t, handler.DetectAnomalies(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var detection models.AnomalyDetection
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &detection))
+		assert.Equal(t, 25, detection.Trees)
+		assert.Equal(t, 1, detection.Anomalies)
+		assert.Equal(t, 2, detection.Resolved)
+		assert.Equal(t, map[string]int{"height_drop": 1, "neighbours": 1, "cohort": 0}, detection.Reasons)
+	}
+}
+
+func TestTreeHandler_DetectAnomalies_DatabaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, _, mockEstateRepo, mockAnomalyRepo := newAnomalyHandler(ctrl)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodPost, "/anomalies/detect", estateID, "", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockAnomalyRepo.EXPECT().GetCohortHeightCounts(estateID).Return(nil, errors.New("database error"))
+
+	if assert.NoError(t, handler.DetectAnomalies(c)) {
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	}
+}
+
+func TestTreeHandler_ListAnomalies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, _, mockEstateRepo, mockAnomalyRepo := newAnomalyHandler(ctrl)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/anomalies", estateID, "status=open&limit=20", "")
+
+	anomaly := &models.TreeAnomaly{ID: uuid.New(), EstateID: estateID, TreeID: uuid.New(), Score: 4.5, Status: models.AnomalyStatusOpen,
+		Reasons: []models.AnomalyReason{{Code: models.AnomalyReasonCohort, Expected: 18, Score: 4.5}}}
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockAnomalyRepo.EXPECT().ListAnomalies(estateID, models.AnomalyQuery{Status: "open", Limit: 20}).Return(&models.AnomalyList{
+		Anomalies: []*models.TreeAnomaly{anomaly},
+		Counts:    map[string]int{"open": 1, "acknowledged": 0, "dismissed": 3},
+	}, nil)
+
+	if assert.NoError(t, handler.ListAnomalies(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var list map[string]interface{}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+		assert.Len(t, list["anomalies"], 1)
+		assert.Equal(t, 3.0, list["counts"].(map[string]interface{})["dismissed"])
+		// Anomalies never reviewed have no review time
+		assert.NotContains(t, list["anomalies"].([]interface{})[0], "reviewed_at")
+	}
+}
+
+func TestTreeHandler_ListAnomalies_InvalidParams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, _, _, _ := newAnomalyHandler(ctrl)
+
+	for _, query := range []string{"status=closed", "limit=0", "limit=1001", "limit=many"} {
+		c, rec := newEstateRequest(http.MethodGet, "/anomalies", uuid.New(), query, "")
+
+		if assert.NoError(t, handler.ListAnomalies(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	}
+}
+
+func TestTreeHandler_AcknowledgeAnomaly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, _, mockEstateRepo, mockAnomalyRepo := newAnomalyHandler(ctrl)
+
+	estateID := uuid.New()
+	anomalyID := uuid.New()
+	c, rec := newAnomalyReviewRequest(estateID, anomalyID.String(), "acknowledge", `{"note": "Soil sample taken"}`)
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockAnomalyRepo.EXPECT().GetAnomaly(estateID, anomalyID).
+		Return(&models.TreeAnomaly{ID: anomalyID, EstateID: estateID, Status: models.AnomalyStatusOpen}, nil)
+	mockAnomalyRepo.EXPECT().ReviewAnomaly(gomock.Any()).DoAndReturn(func(anomaly *models.TreeAnomaly) error {
+		assert.Equal(t, models.AnomalyStatusAcknowledged, anomaly.Status)
+		assert.Equal(t, "Soil sample taken", anomaly.Note)
+		assert.NotNil(t, anomaly.ReviewedAt)
+		return nil
+	})
+
+	if assert.NoError(t, handler.AcknowledgeAnomaly(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var anomaly models.TreeAnomaly
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &anomaly))
+		assert.Equal(t, models.AnomalyStatusAcknowledged, anomaly.Status)
+	}
+}
+
+func TestTreeHandler_AcknowledgeAnomaly_Dismissed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, _, mockEstateRepo, mockAnomalyRepo := newAnomalyHandler(ctrl)
+
+	estateID := uuid.New()
+	anomalyID := uuid.New()
+	c, rec := newAnomalyReviewRequest(estateID, anomalyID.String(), "acknowledge", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockAnomalyRepo.EXPECT().GetAnomaly(estateID, anomalyID).
+		Return(&models.TreeAnomaly{ID: anomalyID, EstateID: estateID, Status: models.AnomalyStatusDismissed}, nil)
+
+	if assert.NoError(t, handler.AcknowledgeAnomaly(c)) {
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	}
+}
+
+func TestTreeHandler_AcknowledgeAnomaly_DismissedConcurrently(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, _, mockEstateRepo, mockAnomalyRepo := newAnomalyHandler(ctrl)
+
+	estateID := uuid.New()
+	anomalyID := uuid.New()
+	c, rec := newAnomalyReviewRequest(estateID, anomalyID.String(), "acknowledge", "")
+
+	// Open when read, dismissed by another review before the update
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockAnomalyRepo.EXPECT().GetAnomaly(estateID, anomalyID).
+		Return(&models.TreeAnomaly{ID: anomalyID, EstateID: estateID, Status: models.AnomalyStatusOpen}, nil)
+	mockAnomalyRepo.EXPECT().ReviewAnomaly(gomock.Any()).Return(repositories.ErrAnomalyDismissed)
+
+	if assert.NoError(t, handler.AcknowledgeAnomaly(c)) {
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Anomaly has been dismissed")
+	}
+}
+
+func TestTreeHandler_DismissAnomaly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, _, mockEstateRepo, mockAnomalyRepo := newAnomalyHandler(ctrl)
+
+	estateID := uuid.New()
+	anomalyID := uuid.New()
+	c, rec := newAnomalyReviewRequest(estateID, anomalyID.String(), "dismiss", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockAnomalyRepo.EXPECT().GetAnomaly(estateID, anomalyID).
+		Return(&models.TreeAnomaly{ID: anomalyID, EstateID: estateID, Status: models.AnomalyStatusAcknowledged, Note: "Checked"}, nil)
+	mockAnomalyRepo.EXPECT().ReviewAnomaly(gomock.Any()).Return(nil)
+
+	if assert.NoError(t, handler.DismissAnomaly(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var anomaly models.TreeAnomaly
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &anomaly))
+		assert.Equal(t, models.AnomalyStatusDismissed, anomaly.Status)
+		assert.Equal(t, "", anomaly.Note)
+	}
+}
+
+func TestTreeHandler_DismissAnomaly_MultibyteNote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, _, mockEstateRepo, mockAnomalyRepo := newAnomalyHandler(ctrl)
+
+	estateID := uuid.New()
+	anomalyID := uuid.New()
+	// The limit counts characters, not bytes
+	note := strings.Repeat("é", maxAnomalyNoteLength)
+	c, rec := newAnomalyReviewRequest(estateID, anomalyID.String(), "dismiss", `{"note": "`+note+`"}`)
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockAnomalyRepo.EXPECT().GetAnomaly(estateID, anomalyID).
+		Return(&models.TreeAnomaly{ID: anomalyID, EstateID: estateID, Status: models.AnomalyStatusOpen}, nil)
+	mockAnomalyRepo.EXPECT().ReviewAnomaly(gomock.Any()).Return(nil)
+
+	if assert.NoError(t, handler.DismissAnomaly(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var anomaly models.TreeAnomaly
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &anomaly))
+		assert.Equal(t, note, anomaly.Note)
+	}
+}
+
+func TestTreeHandler_DismissAnomaly_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, _, mockEstateRepo, mockAnomalyRepo := newAnomalyHandler(ctrl)
+
+	estateID := uuid.New()
+	anomalyID := uuid.New()
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil).Times(2)
+	// Missing, then resolved by a detection run between reading and reviewing it
+	mockAnomalyRepo.EXPECT().GetAnomaly(estateID, anomalyID).Return(nil, nil)
+	mockAnomalyRepo.EXPECT().GetAnomaly(estateID, anomalyID).
+		Return(&models.TreeAnomaly{ID: anomalyID, EstateID: estateID, Status: models.AnomalyStatusOpen}, nil)
+	mockAnomalyRepo.EXPECT().ReviewAnomaly(gomock.Any()).Return(repositories.ErrAnomalyNotFound)
+
+	for i := 0; i < 2; i++ {
+		c, rec := newAnomalyReviewRequest(estateID, anomalyID.String(), "dismiss", "")
+
+		if assert.NoError(t, handler.DismissAnomaly(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	}
+}
+
+func TestTreeHandler_ReviewAnomaly_InvalidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, _, _, _ := newAnomalyHandler(ctrl)
+
+	estateID := uuid.New()
+	anomalyID := uuid.New().String()
+	for _, request := range []struct{ anomalyID, body string }{
+		{"not-a-uuid", ""},
+		{anomalyID, `{"note": 5}`},
+		{anomalyID, `{"note": "` + strings.Repeat("a", maxAnomalyNoteLength+1) + `"}`},
+	} {
+		c, rec := newAnomalyReviewRequest(estateID, request.anomalyID, "dismiss", request.body)
+
+		if assert.NoError(t, handler.DismissAnomaly(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	}
+}
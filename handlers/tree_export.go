@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sawitpro-recruitment/models"
+	"sawitpro-recruitment/spreadsheet"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// Formats accepted by the tree export.
+const (
+	exportFormatCSV     = "csv"
+	exportFormatGeoJSON = "geojson"
+	exportFormatParquet = "parquet"
+	exportFormatXLSX    = "xlsx"
+)
+
+// parquetRowGroupSize keeps the rows buffered by the Parquet writer small,
+// since the file is streamed to the client.
+const parquetRowGroupSize = 8 * 1024 * 1024
+
+// treeExportWriter encodes exported trees in one of the export formats.
+type treeExportWriter interface {
+	Write(tree *models.Tree) error
+	Close() error
+}
+
+// exportFormats maps each export format to its content type, file extension
+// and writer. The XLSX writer also needs the stats of the estate, so it is
+// made by the export itself.
+var exportFormats = map[string]struct {
+	contentType string
+	extension   string
+	newWriter   func(w io.Writer) (treeExportWriter, error)
+}{
+	exportFormatCSV:     {"text/csv", "csv", newCSVTreeWriter},
+	exportFormatGeoJSON: {"application/geo+json", "geojson", newGeoJSONTreeWriter},
+	exportFormatParquet: {"application/vnd.apache.parquet", "parquet", newParquetTreeWriter},
+	exportFormatXLSX:    {spreadsheet.ContentType, "xlsx", nil},
+}
+
+// ExportTrees streams all trees of an estate as CSV, GeoJSON, Parquet or XLSX
+// @Summary Export the trees of an estate
+// @Description Stream every tree of an estate as a CSV, GeoJSON, Parquet or XLSX file. The XLSX workbook also has sheets with the estate stats, the trees split across sheets of at most 1,048,575 trees, the stats of each block of 10 × 10 plots and the survey drone plan.
+// @Tags trees
+// @Produce text/csv
+// @Produce application/geo+json
+// @Produce application/vnd.apache.parquet
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param id path string true "Estate ID"
+// @Param format query string false "Export format: csv (default), geojson, parquet or xlsx; XLSX is also chosen by an Accept header naming it"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/trees/export [get]
+func (h *TreeHandler) ExportTrees(c echo.Context) error {
+	format := c.QueryParam("format")
+	if format == "" {
+		format = exportFormatCSV
+		if acceptsXLSX(c) {
+			format = exportFormatXLSX
+		}
+	}
+	exportFormat, ok := exportFormats[format]
+	if !ok {
+		logrus.Warnf("Invalid export format: %s", format)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "format must be one of csv, geojson, parquet or xlsx",
+		})
+	}
+
+	generation := cacheGeneration(c, h.Cache)
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	newWriter := exportFormat.newWriter
+	if format == exportFormatXLSX {
+		// The summary holds the same stats as GET /estate/{id}/stats, sharing its cache
+		estates := &EstateHandler{EstateRepo: h.EstateRepo, HarvestRepo: h.HarvestRepo, Cache: h.Cache}
+		options := models.StatsOptions{Percentiles: models.DefaultStatsPercentiles, BucketWidth: models.DefaultHistogramBucketWidth}
+		stats, err := estates.estateStats(estate, generation, nil, options, nil, "")
+		if err != nil {
+			logrus.Errorf("Failed to get estate stats for the export of estate ID %s: %v", estate.ID, err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": "Database error while exporting trees",
+			})
+		}
+		newWriter = func(w io.Writer) (treeExportWriter, error) {
+			return newXLSXTreeWriter(w, estate, stats)
+		}
+	}
+
+	// The response is only started with the first tree, so that a failing
+	// query can still be answered with an error
+	var out treeExportWriter
+	start := func() error {
+		res := c.Response()
+		res.Header().Set(echo.HeaderContentType, exportFormat.contentType)
+		res.Header().Set(echo.HeaderContentDisposition,
+			fmt.Sprintf(`attachment; filename="estate-%s-trees.%s"`, estate.ID, exportFormat.extension))
+		res.WriteHeader(http.StatusOK)
+		w, err := newWriter(res)
+		out = w
+		return err
+	}
+
+	err = h.TreeRepo.ExportTrees(estate.ID, func(tree *models.Tree) error {
+		if out == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return out.Write(tree)
+	})
+	if err == nil && out == nil {
+		err = start()
+	}
+	if err == nil {
+		err = out.Close()
+	}
+	if err != nil {
+		logrus.Errorf("Failed to export trees for estate ID %s: %v", estate.ID, err)
+		if c.Response().Committed {
+			// The file is cut short; the client sees an incomplete download
+			return nil
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while exporting trees",
+		})
+	}
+
+	logrus.Infof("Trees exported successfully for estate ID %s as %s", estate.ID, format)
+	return nil
+}
+
+// csvTreeWriter writes trees as CSV rows with an
+// id,estate_id,x,y,height,species,planted_on,health_status header. Unknown species and
+// planting dates are left empty.
+type csvTreeWriter struct {
+	w *csv.Writer
+}
+
+func newCSVTreeWriter(w io.Writer) (treeExportWriter, error) {
+	out := &csvTreeWriter{w: csv.NewWriter(w)}
+	return out, out.w.Write([]string{"id", "estate_id", "x", "y", "height", "species", "planted_on", "health_status"})
+}
+
+func (cw *csvTreeWriter) Write(tree *models.Tree) error {
+	plantedOn := ""
+	if tree.PlantedOn != nil {
+		plantedOn = tree.PlantedOn.String()
+	}
+	return cw.w.Write([]string{
+		tree.ID.String(),
+		tree.EstateID.String(),
+		strconv.Itoa(tree.X),
+		strconv.Itoa(tree.Y),
+		strconv.Itoa(tree.Height),
+		tree.Species,
+		plantedOn,
+		tree.HealthStatus,
+	})
+}
+
+func (cw *csvTreeWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// geoJSONTreeWriter writes trees as a GeoJSON FeatureCollection of points. The
+// estate has no geographic reference, so the points use plot coordinates.
+type geoJSONTreeWriter struct {
+	w     io.Writer
+	enc   *json.Encoder
+	count int
+}
+
+// geoJSONFeature is a single tree in a GeoJSON export.
+type geoJSONFeature struct {
+	Type       string       `json:"type"`
+	ID         string       `json:"id"`
+	Geometry   geoJSONPoint `json:"geometry"`
+	Properties models.Tree  `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string `json:"type"`
+	Coordinates [2]int `json:"coordinates"`
+}
+
+func newGeoJSONTreeWriter(w io.Writer) (treeExportWriter, error) {
+	_, err := io.WriteString(w, `{"type":"FeatureCollection","features":[`)
+	return &geoJSONTreeWriter{w: w, enc: json.NewEncoder(w)}, err
+}
+
+func (gw *geoJSONTreeWriter) Write(tree *models.Tree) error {
+	if gw.count > 0 {
+		if _, err := io.WriteString(gw.w, ","); err != nil {
+			return err
+		}
+	}
+	gw.count++
+	return gw.enc.Encode(geoJSONFeature{
+		Type:       "Feature",
+		ID:         tree.ID.String(),
+		Geometry:   geoJSONPoint{Type: "Point", Coordinates: [2]int{tree.X, tree.Y}},
+		Properties: *tree,
+	})
+}
+
+func (gw *geoJSONTreeWriter) Close() error {
+	_, err := io.WriteString(gw.w, "]}\n")
+	return err
+}
+
+// parquetTree is the Parquet schema of an exported tree.
+type parquetTree struct {
+	ID           string `parquet:"name=id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	EstateID     string `parquet:"name=estate_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	X            int32  `parquet:"name=x, type=INT32"`
+	Y            int32  `parquet:"name=y, type=INT32"`
+	Height       int32  `parquet:"name=height, type=INT32"`
+	Species      string `parquet:"name=species, type=BYTE_ARRAY, convertedtype=UTF8"`
+	PlantedOn    *int32 `parquet:"name=planted_on, type=INT32, convertedtype=DATE, repetitiontype=OPTIONAL"` // Days since the Unix epoch
+	HealthStatus string `parquet:"name=health_status, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// parquetTreeWriter writes trees as a Snappy compressed Parquet file.
+type parquetTreeWriter struct {
+	pw *writer.ParquetWriter
+}
+
+func newParquetTreeWriter(w io.Writer) (treeExportWriter, error) {
+	pw, err := writer.NewParquetWriterFromWriter(w, new(parquetTree), 1)
+	if err != nil {
+		return nil, err
+	}
+	pw.RowGroupSize = parquetRowGroupSize
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+	return &parquetTreeWriter{pw: pw}, nil
+}
+
+func (pw *parquetTreeWriter) Write(tree *models.Tree) error {
+	var plantedOn *int32
+	if tree.PlantedOn != nil {
+		days := int32(tree.PlantedOn.Unix() / (24 * 60 * 60))
+		plantedOn = &days
+	}
+	return pw.pw.Write(parquetTree{
+		ID:           tree.ID.String(),
+		EstateID:     tree.EstateID.String(),
+		X:            int32(tree.X),
+		Y:            int32(tree.Y),
+		Height:       int32(tree.Height),
+		Species:      tree.Species,
+		PlantedOn:    plantedOn,
+		HealthStatus: tree.HealthStatus,
+	})
+}
+
+func (pw *parquetTreeWriter) Close() error {
+	return pw.pw.WriteStop()
+}
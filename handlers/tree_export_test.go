@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"sawitpro-recruitment/mocks"
+	"sawitpro-recruitment/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// exportTreesOf makes the mock repository stream the given trees.
+func exportTreesOf(trees ...*models.Tree) func(uuid.UUID, func(*models.Tree) error) error {
+	return func(_ uuid.UUID, fn func(*models.Tree) error) error {
+		for _, tree := range trees {
+			if err := fn(tree); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestTreeHandler_ExportTrees_CSV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	tree := &models.Tree{ID: uuid.New(), EstateID: estateID, X: 2, Y: 3, Height: 12, HealthStatus: "healthy"}
+	plantedOn, _ := models.ParseDate("2015-03-01")
+	planted := &models.Tree{ID: uuid.New(), EstateID: estateID, X: 3, Y: 3, Height: 9, Species: "tenera", PlantedOn: &plantedOn,
+		HealthStatus: "ganoderma"}
+	c, rec := newEstateRequest(http.MethodGet, "/trees/export", estateID, "format=", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().ExportTrees(estateID, gomock.Any()).DoAndReturn(exportTreesOf(tree, planted))
+
+	if assert.NoError(t, handler.ExportTrees(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv", rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), ".csv")
+		assert.Equal(t, "id,estate_id,x,y,height,species,planted_on,health_status\n"+
+			tree.ID.String()+","+estateID.String()+",2,3,12,,,healthy\n"+
+			planted.ID.String()+","+estateID.String()+",3,3,9,tenera,2015-03-01,ganoderma\n", rec.Body.String())
+	}
+}
+
+func TestTreeHandler_ExportTrees_GeoJSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	trees := []*models.Tree{
+		{ID: uuid.New(), EstateID: estateID, X: 1, Y: 1, Height: 10},
+		{ID: uuid.New(), EstateID: estateID, X: 2, Y: 1, Height: 12},
+	}
+	c, rec := newEstateRequest(http.MethodGet, "/trees/export", estateID, "format=geojson", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().ExportTrees(estateID, gomock.Any()).DoAndReturn(exportTreesOf(trees...))
+
+	if assert.NoError(t, handler.ExportTrees(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var collection struct {
+			Type     string `json:"type"`
+			Features []struct {
+				Geometry struct {
+					Coordinates []int `json:"coordinates"`
+				} `json:"geometry"`
+				Properties models.Tree `json:"properties"`
+			} `json:"features"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &collection))
+		assert.Equal(t, "FeatureCollection", collection.Type)
+		if assert.Len(t, collection.Features, 2) {
+			assert.Equal(t, []int{2, 1}, collection.Features[1].Geometry.Coordinates)
+			assert.Equal(t, 12, collection.Features[1].Properties.Height)
+		}
+	}
+}
+
+func TestTreeHandler_ExportTrees_GeoJSONEmpty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/trees/export", estateID, "format=geojson", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().ExportTrees(estateID, gomock.Any()).DoAndReturn(exportTreesOf())
+
+	if assert.NoError(t, handler.ExportTrees(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"type":"FeatureCollection","features":[]}`, rec.Body.String())
+	}
+}
+
+func TestTreeHandler_ExportTrees_Parquet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/trees/export", estateID, "format=parquet", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	plantedOn, _ := models.ParseDate("2015-03-01")
+	mockTreeRepo.EXPECT().ExportTrees(estateID, gomock.Any()).DoAndReturn(exportTreesOf(
+		&models.Tree{ID: uuid.New(), EstateID: estateID, X: 1, Y: 1, Height: 10},
+		&models.Tree{ID: uuid.New(), EstateID: estateID, X: 2, Y: 1, Height: 8, Species: "dura", PlantedOn: &plantedOn},
+	))
+
+	if assert.NoError(t, handler.ExportTrees(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/vnd.apache.parquet", rec.Header().Get(echo.HeaderContentType))
+		// Parquet files start and end with the PAR1 magic number
+		body := rec.Body.Bytes()
+		assert.True(t, bytes.HasPrefix(body, []byte("PAR1")))
+		assert.True(t, bytes.HasSuffix(body, []byte("PAR1")))
+	}
+}
+
+func TestTreeHandler_ExportTrees_InvalidFormat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	c, rec := newEstateRequest(http.MethodGet, "/trees/export", uuid.New(), "format=xml", "")
+
+	if assert.NoError(t, handler.ExportTrees(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestTreeHandler_ExportTrees_DatabaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/trees/export", estateID, "format=csv", "")
+
+	// Nothing has been written yet, so the error can still be reported
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().ExportTrees(estateID, gomock.Any()).Return(errors.New("database error"))
+
+	if assert.NoError(t, handler.ExportTrees(c)) {
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	}
+}
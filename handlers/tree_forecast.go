@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sawitpro-recruitment/forecast"
+	"sawitpro-recruitment/models"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultForecastHorizon = forecast.QuarterDays // Days forecast when the horizon query parameter is missing
+	maxForecastHorizon     = 365                  // Longest horizon accepted, in days
+)
+
+// plot identifies a plot of an estate by its coordinates.
+type plot struct {
+	x, y int
+}
+
+// GetEstateForecast forecasts the yield of an estate over the coming days
+// @Summary Forecast the yield of an estate
+// @Description Forecast the FFB weight an estate will yield over the given number of days from tomorrow, with an interval and the model and inputs it was derived from. Each tree is expected to yield along an age curve, scaled by how it performed against the curve over the last four quarters of harvests.
+// @Tags harvests
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param horizon query int false "Days forecast, 91 by default, at most 365"
+// @Success 200 {object} models.EstateForecast
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/forecast [get]
+func (h *TreeHandler) GetEstateForecast(c echo.Context) error {
+	horizon, err := optionalIntParam(c, "horizon")
+	if err == nil && horizon != nil && (*horizon < 1 || *horizon > maxForecastHorizon) {
+		err = fmt.Errorf("horizon must be between 1 and %d days", maxForecastHorizon)
+	}
+	if err != nil {
+		logrus.Warnf("Invalid forecast horizon: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+	days := defaultForecastHorizon
+	if horizon != nil {
+		days = *horizon
+	}
+
+	generation := cacheGeneration(c, h.Cache)
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	cacheKey := fmt.Sprintf("forecast:%s:%d", today.Format(models.DateLayout), days)
+	if cached, ok := h.Cache.Get(estate.ID, cacheKey); ok {
+		logrus.Infof("Estate forecast served from cache for ID %s", estate.ID)
+		return c.JSON(http.StatusOK, cached)
+	}
+
+	// The history is the last quarters up to today, oldest first
+	historyFrom := today.AddDate(0, 0, 1-forecast.HistoryQuarters*forecast.QuarterDays)
+	yields, err := h.HarvestRepo.GetPlotYields(estate.ID, historyFrom, today, forecast.QuarterDays)
+	if err != nil {
+		logrus.Errorf("Failed to get plot yields for estate ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while fetching harvests",
+		})
+	}
+	plotYields := make(map[plot][]*models.PlotYield)
+	for _, yield := range yields {
+		if yield.Period < 0 || yield.Period >= forecast.HistoryQuarters {
+			continue
+		}
+		key := plot{yield.X, yield.Y}
+		plotYields[key] = append(plotYields[key], yield)
+	}
+
+	// Harvests of plots without a tree today are left out, as nothing will
+	// be harvested there
+	trees := []*forecast.Tree{}
+	planted := 0
+	err = h.TreeRepo.ExportTrees(estate.ID, func(tree *models.Tree) error {
+		palm := &forecast.Tree{
+			Height:  tree.Height,
+			Dead:    tree.HealthStatus == models.HealthStatusDead,
+			History: make([]float64, forecast.HistoryQuarters),
+		}
+		if tree.PlantedOn != nil {
+			age := today.Sub(tree.PlantedOn.Time).Hours() / 24 / 365
+			palm.AgeYears = &age
+			planted++
+		}
+		for _, yield := range plotYields[plot{tree.X, tree.Y}] {
+			palm.History[yield.Period] += yield.WeightKg
+			palm.Harvests += yield.Harvests
+		}
+		trees = append(trees, palm)
+		return nil
+	})
+	if err != nil {
+		logrus.Errorf("Failed to load trees for estate ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while fetching trees",
+		})
+	}
+
+	model := forecast.DefaultModel()
+	result := model.Forecast(trees, days)
+	estimate := &models.EstateForecast{
+		From:             models.Date{Time: today.AddDate(0, 0, 1)},
+		To:               models.Date{Time: today.AddDate(0, 0, days)},
+		HorizonDays:      days,
+		PointKg:          roundKg(result.PointKg),
+		LowerKg:          roundKg(result.LowerKg),
+		UpperKg:          roundKg(result.UpperKg),
+		TonnesPerHectare: models.TonnesPerHectare(result.PointKg, estate.AreaHectares()),
+		Model:            model.Describe(result),
+		Inputs: models.ForecastInputs{
+			HistoryFrom:       models.Date{Time: historyFrom},
+			HistoryTo:         models.Date{Time: today},
+			Trees:             len(trees),
+			TreesPlanted:      planted,
+			TreesAgedByHeight: result.AgedByHeight,
+			TreesDead:         result.Dead,
+			TreesWithHistory:  result.WithHistory,
+			Harvests:          result.Harvests,
+			HistoryWeightKg:   roundKg(result.HistoryWeightKg),
+			MeanPerformance:   result.MeanPerformance,
+			QuarterRatios:     result.QuarterRatios,
+		},
+	}
+	h.Cache.Set(estate.ID, cacheKey, generation, estimate)
+
+	logrus.Infof("Estate forecast computed successfully for ID %s", estate.ID)
+	return c.JSON(http.StatusOK, estimate)
+}
+
+// roundKg rounds a weight to the gram.
+func roundKg(kg float64) float64 {
+	return math.Round(kg*1000) / 1000
+}
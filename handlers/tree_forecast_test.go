@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"sawitpro-recruitment/forecast"
+	"sawitpro-recruitment/mocks"
+	"sawitpro-recruitment/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreeHandler_GetEstateForecast(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockHarvestRepo := mocks.NewMockHarvestRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+	handler.HarvestRepo = mockHarvestRepo
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/forecast", estateID, "horizon=91", "")
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	historyFrom := today.AddDate(0, 0, 1-4*91)
+	planted := &models.Date{Time: today.AddDate(-12, 0, 0)}
+	// Palms at the peak of the age curve, harvested at half of it every quarter
+	quarterKg := 220.0 * 91 / 365 / 2
+	yields := []*models.PlotYield{}
+	for _, x := range []int{1, 2, 3, 4} {
+		for period := 0; period < 4; period++ {
+			yields = append(yields, &models.PlotYield{X: x, Y: 1, Period: period, Harvests: 3, WeightKg: quarterKg})
+		}
+	}
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 10}, nil)
+	mockHarvestRepo.EXPECT().GetPlotYields(estateID, historyFrom, today, forecast.QuarterDays).Return(yields, nil)
+	mockTreeRepo.EXPECT().ExportTrees(estateID, gomock.Any()).DoAndReturn(exportTreesOf(
+		&models.Tree{X: 1, Y: 1, Height: 8, PlantedOn: planted, HealthStatus: models.HealthStatusHealthy},
+		&models.Tree{X: 2, Y: 1, Height: 8, PlantedOn: planted, HealthStatus: models.HealthStatusGanoderma},
+		&models.Tree{X: 3, Y: 1, Height: 8, PlantedOn: planted, HealthStatus: models.HealthStatusDead},
+		// Aged by height: 7 meters at 0.6 meters a year is at the peak too
+		&models.Tree{X: 4, Y: 1, Height: 7},
+	))
+
+	if assert.NoError(t, handler.GetEstateForecast(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var estimate models.EstateForecast
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &estimate))
+		assert.Equal(t, today.AddDate(0, 0, 1), estimate.From.Time)
+		assert.Equal(t, today.AddDate(0, 0, 91), estimate.To.Time)
+		// Three living palms, all at half the curve
+		assert.InDelta(t, 3*quarterKg, estimate.PointKg, 0.01)
+		assert.Less(t, estimate.LowerKg, estimate.PointKg)
+		assert.Greater(t, estimate.UpperKg, estimate.PointKg)
+		assert.Equal(t, models.TonnesPerHectare(estimate.PointKg, 1), estimate.TonnesPerHectare)
+		assert.Equal(t, "age-curve-trend", estimate.Model.Name)
+
+		inputs := estimate.Inputs
+		assert.Equal(t, historyFrom, inputs.HistoryFrom.Time)
+		assert.Equal(t, []int{4, 3, 1, 1, 4, 48}, []int{inputs.Trees, inputs.TreesPlanted, inputs.TreesAgedByHeight,
+			inputs.TreesDead, inputs.TreesWithHistory, inputs.Harvests})
+		assert.InDelta(t, 16*quarterKg, inputs.HistoryWeightKg, 0.01)
+		assert.InDelta(t, 0.5, inputs.MeanPerformance, 1e-6)
+		assert.InDeltaSlice(t, []float64{0.5, 0.5, 0.5, 0.5}, inputs.QuarterRatios, 1e-6)
+	}
+}
+
+func TestTreeHandler_GetEstateForecast_InvalidHorizon(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	for _, query := range []string{"horizon=0", "horizon=366", "horizon=soon"} {
+		c, rec := newEstateRequest(http.MethodGet, "/forecast", uuid.New(), query, "")
+
+		if assert.NoError(t, handler.GetEstateForecast(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sawitpro-recruitment/gaps"
+	"sawitpro-recruitment/models"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultGapListSize = 100  // Regions and replanting plots listed when the limit query parameter is missing
+	maxGapListSize     = 1000 // Largest limit accepted
+)
+
+// GetEstateGaps reports the empty plots of an estate and where to replant
+// @Summary Analyse the gaps of an estate
+// @Description Count the empty plots of an estate, find the contiguous regions they form, report planting density and coverage, and propose plots to replant on the estate's grid or in a staggered triangular pattern.
+// @Tags trees
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param spacing query string false "Replanting spacing: grid (default) or triangular"
+// @Param limit query int false "Regions and replanting plots listed, 100 by default, at most 1000"
+// @Success 200 {object} models.GapAnalysis
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/gaps [get]
+func (h *TreeHandler) GetEstateGaps(c echo.Context) error {
+	spacing := c.QueryParam("spacing")
+	if spacing == "" {
+		spacing = models.SpacingGrid
+	}
+	if spacing != models.SpacingGrid && spacing != models.SpacingTriangular {
+		logrus.Warnf("Invalid replanting spacing: %s", spacing)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "spacing must be grid or triangular",
+		})
+	}
+	limit, err := optionalIntParam(c, "limit")
+	if err == nil && limit != nil && (*limit < 1 || *limit > maxGapListSize) {
+		err = fmt.Errorf("limit must be between 1 and %d", maxGapListSize)
+	}
+	if err != nil {
+		logrus.Warnf("Invalid gap list limit: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+	size := defaultGapListSize
+	if limit != nil {
+		size = *limit
+	}
+
+	generation := cacheGeneration(c, h.Cache)
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	cacheKey := fmt.Sprintf("gaps:%s:%d", spacing, size)
+	if cached, ok := h.Cache.Get(estate.ID, cacheKey); ok {
+		logrus.Infof("Estate gaps served from cache for ID %s", estate.ID)
+		return c.JSON(http.StatusOK, cached)
+	}
+
+	// Trees come row by row, so each row's columns are sorted
+	rows := make(map[int][]int)
+	err = h.TreeRepo.ExportTrees(estate.ID, func(tree *models.Tree) error {
+		rows[tree.Y] = append(rows[tree.Y], tree.X)
+		return nil
+	})
+	if err != nil {
+		logrus.Errorf("Failed to load trees for estate ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while fetching trees",
+		})
+	}
+
+	analysis := gaps.Analyze(estate, rows, spacing, size)
+	h.Cache.Set(estate.ID, cacheKey, generation, analysis)
+
+	logrus.Infof("Estate gaps analysed successfully for ID %s", estate.ID)
+	return c.JSON(http.StatusOK, analysis)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"sawitpro-recruitment/mocks"
+	"sawitpro-recruitment/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreeHandler_GetEstateGaps(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/gaps", estateID, "spacing=triangular&limit=2", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 4, Length: 2}, nil)
+	mockTreeRepo.EXPECT().ExportTrees(estateID, gomock.Any()).DoAndReturn(exportTreesOf(
+		&models.Tree{X: 1, Y: 1, Height: 10},
+		&models.Tree{X: 3, Y: 1, Height: 10},
+	))
+
+	if assert.NoError(t, handler.GetEstateGaps(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var analysis models.GapAnalysis
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &analysis))
+		assert.Equal(t, 8, analysis.Plots)
+		assert.Equal(t, 6, analysis.EmptyPlots)
+		assert.Equal(t, 25.0, analysis.CoveragePercent)
+		assert.Equal(t, 25.0, analysis.DensityPerHectare)
+		assert.Equal(t, 1, analysis.RegionCount)
+		assert.Equal(t, models.ReplantingPlan{
+			Spacing:           models.SpacingTriangular,
+			Total:             2,
+			Plots:             []models.Plot{{X: 2, Y: 2}, {X: 4, Y: 2}},
+			DensityPerHectare: 50,
+		}, analysis.Replanting)
+	}
+}
+
+func TestTreeHandler_GetEstateGaps_InvalidParams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	for _, query := range []string{"spacing=square", "limit=0", "limit=1001", "limit=all"} {
+		c, rec := newEstateRequest(http.MethodGet, "/gaps", uuid.New(), query, "")
+
+		if assert.NoError(t, handler.GetEstateGaps(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	}
+}
+
+func TestTreeHandler_GetEstateGaps_DatabaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/gaps", estateID, "", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 4, Length: 2}, nil)
+	mockTreeRepo.EXPECT().ExportTrees(estateID, gomock.Any()).Return(errors.New("connection lost"))
+
+	if assert.NoError(t, handler.GetEstateGaps(c)) {
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	}
+}
@@ -1,9 +1,17 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"sawitpro-recruitment/cache"
 	"sawitpro-recruitment/models"
 	"sawitpro-recruitment/repositories"
+	"sawitpro-recruitment/tiles"
+
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -12,8 +20,13 @@ import (
 
 // TreeHandler manages tree-related requests.
 type TreeHandler struct {
-	TreeRepo   repositories.TreeRepository
-	EstateRepo repositories.EstateRepository
+	TreeRepo    repositories.TreeRepository
+	EstateRepo  repositories.EstateRepository
+	SpeciesRepo repositories.SpeciesRepository // Looked up for trees with a species
+	HarvestRepo repositories.HarvestRepository // Stores the harvests of the estate's plots
+	AnomalyRepo repositories.AnomalyRepository // Stores the trees found standing out by anomaly detection
+	Cache       *cache.EstateCache             // Derived data invalidated when trees change; nil disables caching
+	Tiles       *tiles.DiskCache               // Map tiles invalidated when the trees drawn on them change; nil disables caching
 }
 
 // NewTreeHandler creates a new TreeHandler.
@@ -35,10 +48,10 @@ func NewTreeHandler(treeRepo repositories.TreeRepository, estateRepo repositorie
 // @Success 201 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /estate/{id}/tree [post]
 func (h *TreeHandler) AddTreeToEstate(c echo.Context) error {
-	estateID := c.Param("id")
 	tree := new(models.Tree)
 
 	// Bind the request body to the tree model
@@ -49,75 +62,732 @@ func (h *TreeHandler) AddTreeToEstate(c echo.Context) error {
 		})
 	}
 
-	// Validate tree dimensions and height
-	if tree.X < 1 || tree.Y < 1 || tree.Height < 1 || tree.Height > 30 {
-		logrus.Warnf("Invalid tree coordinates or height: x=%d, y=%d, height=%d", tree.X, tree.Y, tree.Height)
+	// Validate tree dimensions, height for its species and planting date
+	species, ok, err := h.findSpecies(c, tree.Species)
+	if !ok {
+		return err
+	}
+	if err := validateTree(tree, species, time.Now()); err != nil {
+		logrus.Warnf("Invalid tree: x=%d, y=%d, height=%d, species=%q: %v", tree.X, tree.Y, tree.Height, tree.Species, err)
 		return c.JSON(http.StatusBadRequest, map[string]string{
-			"message": "Invalid tree coordinates or height",
+			"message": err.Error(),
 		})
 	}
 
-	// Convert estate ID to UUID
-	estateUUID, err := uuid.Parse(estateID)
+	// Check if the estate exists and is not archived
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+	estateUUID := estate.ID
+
+	// Assign a new UUID to the tree and set the estate ID
+	tree.ID = uuid.New()
+	tree.EstateID = estateUUID
+
+	// Check the plot is inside the estate and free
+	if ok, err := h.checkPlot(c, estate, tree); !ok {
+		return err
+	}
+
+	// Add tree to the estate via repository
+	if err := h.TreeRepo.AddTreeToEstate(tree); err != nil {
+		return respondTreeWriteError(c, tree, err, "Failed to store tree in database")
+	}
+	h.invalidateTrees(estate, models.Plot{X: tree.X, Y: tree.Y})
+
+	logrus.Infof("Tree added successfully to estate ID %s: %v", estateUUID, tree.ID)
+	return c.JSON(http.StatusOK, map[string]string{
+		"id": tree.ID.String(),
+	})
+}
+
+// GetTree returns a single tree of an estate
+// @Summary Get a tree
+// @Description Get a single tree of an estate by its ID
+// @Tags trees
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param treeId path string true "Tree ID"
+// @Success 200 {object} models.Tree
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/tree/{treeId} [get]
+func (h *TreeHandler) GetTree(c echo.Context) error {
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	tree, err := h.findTree(c, estate)
+	if tree == nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, tree)
+}
+
+// ListTrees lists the trees of an estate with cursor pagination, sorting and filters
+// @Summary List the trees of an estate
+// @Description List the trees of an estate with cursor pagination, sorting by coordinate or height, and filtering by bounding box, height, species and health status
+// @Tags trees
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param sort query string false "Sort key: coordinate or height"
+// @Param order query string false "Sort order: asc or desc"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param limit query int false "Page size (1 to 100)"
+// @Param x1 query int false "Smallest x of the bounding box"
+// @Param y1 query int false "Smallest y of the bounding box"
+// @Param x2 query int false "Largest x of the bounding box"
+// @Param y2 query int false "Largest y of the bounding box"
+// @Param min_height query int false "Minimum height"
+// @Param max_height query int false "Maximum height"
+// @Param species query string false "Species code"
+// @Param health query string false "Current health status"
+// @Success 200 {object} models.TreePage
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/trees [get]
+func (h *TreeHandler) ListTrees(c echo.Context) error {
+	query := models.TreeQuery{
+		SortBy:       c.QueryParam("sort"),
+		Cursor:       c.QueryParam("cursor"),
+		Species:      c.QueryParam("species"),
+		HealthStatus: c.QueryParam("health"),
+	}
+	if query.HealthStatus != "" && !models.ValidHealthStatus(query.HealthStatus) {
+		logrus.Warnf("Invalid health filter: %s", query.HealthStatus)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": fmt.Sprintf("health must be one of %s", strings.Join(models.HealthStatuses, ", ")),
+		})
+	}
+
+	// Validate sort key and order
+	switch query.SortBy {
+	case "":
+		query.SortBy = models.TreeSortCoordinate
+	case models.TreeSortCoordinate, models.TreeSortHeight:
+	default:
+		logrus.Warnf("Invalid tree sort key: %s", query.SortBy)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "sort must be one of coordinate, height",
+		})
+	}
+	switch order := c.QueryParam("order"); order {
+	case "", "asc":
+	case "desc":
+		query.Desc = true
+	default:
+		logrus.Warnf("Invalid tree sort order: %s", order)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "order must be one of asc, desc",
+		})
+	}
+
+	limit, err := pageSizeParam(c)
 	if err != nil {
-		logrus.Warnf("Invalid estate ID format: %s", estateID)
+		logrus.Warnf("Invalid tree page size: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+	query.Limit = limit
+
+	// Parse the optional bounding box and height filters
+	filters := []struct {
+		name   string
+		target **int
+	}{
+		{"x1", &query.MinX},
+		{"y1", &query.MinY},
+		{"x2", &query.MaxX},
+		{"y2", &query.MaxY},
+		{"min_height", &query.MinHeight},
+		{"max_height", &query.MaxHeight},
+	}
+	for _, f := range filters {
+		value, err := optionalIntParam(c, f.name)
+		if err != nil {
+			logrus.Warnf("Invalid tree filter: %v", err)
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"message": err.Error(),
+			})
+		}
+		*f.target = value
+	}
+	if (query.MinX != nil && query.MaxX != nil && *query.MinX > *query.MaxX) ||
+		(query.MinY != nil && query.MaxY != nil && *query.MinY > *query.MaxY) {
+		logrus.Warnf("Invalid bounding box")
 		return c.JSON(http.StatusBadRequest, map[string]string{
-			"message": "Invalid estate ID format",
+			"message": "x1 and y1 must not be greater than x2 and y2",
 		})
 	}
 
-	// Check if the estate exists
-	estate, err := h.EstateRepo.GetEstateByID(estateUUID)
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	page, err := h.TreeRepo.ListTrees(estate.ID, query)
+	if errors.Is(err, repositories.ErrInvalidCursor) {
+		logrus.Warnf("Invalid tree cursor: %s", query.Cursor)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Invalid cursor",
+		})
+	}
 	if err != nil {
-		logrus.Errorf("Database error while retrieving estate ID %s: %v", estateUUID, err)
+		logrus.Errorf("Failed to list trees of estate ID %s: %v", estate.ID, err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"message": "Database error while retrieving estate",
+			"message": "Database error while listing trees",
+		})
+	}
+
+	logrus.Infof("Listed %d trees of estate ID %s", len(page.Trees), estate.ID)
+	return c.JSON(http.StatusOK, page)
+}
+
+// GetTreeAtPlot returns the tree standing on a plot
+// @Summary Get the tree on a plot
+// @Description Get the tree planted at the given plot coordinates of an estate
+// @Tags trees
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param x path int true "X coordinate of the plot"
+// @Param y path int true "Y coordinate of the plot"
+// @Success 200 {object} models.Tree
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/plot/{x}/{y} [get]
+func (h *TreeHandler) GetTreeAtPlot(c echo.Context) error {
+	x, errX := strconv.Atoi(c.Param("x"))
+	y, errY := strconv.Atoi(c.Param("y"))
+	if errX != nil || errY != nil || x < 1 || y < 1 {
+		logrus.Warnf("Invalid plot coordinates: x=%s, y=%s", c.Param("x"), c.Param("y"))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Invalid plot coordinates",
 		})
 	}
+
+	estate, err := findActiveEstate(c, h.EstateRepo)
 	if estate == nil {
-		logrus.Warnf("Estate not found: %s", estateUUID)
+		return err
+	}
+
+	if x > estate.Width || y > estate.Length {
+		logrus.Warnf("Plot coordinates out of bounds: x=%d, y=%d", x, y)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Tree coordinates out of bounds",
+		})
+	}
+
+	tree, err := h.TreeRepo.GetTreeByCoordinates(estate.ID, x, y)
+	if err != nil {
+		logrus.Errorf("Database error while retrieving tree at x=%d, y=%d for estate ID %s: %v", x, y, estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while retrieving tree",
+		})
+	}
+	if tree == nil {
 		return c.JSON(http.StatusNotFound, map[string]string{
-			"message": "Estate not found",
+			"message": "Tree not found",
 		})
 	}
 
-	// Validate coordinates within estate bounds
-	if tree.X > estate.Width || tree.Y > estate.Length {
-		logrus.Warnf("Tree coordinates out of bounds: x=%d, y=%d", tree.X, tree.Y)
+	return c.JSON(http.StatusOK, tree)
+}
+
+// UpdateTree partially updates a tree
+// @Summary Update a tree
+// @Description Correct the height of a tree or move it to an empty plot of the same estate
+// @Tags trees
+// @Accept json
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param treeId path string true "Tree ID"
+// @Param tree body models.TreeUpdate true "Fields to update"
+// @Success 200 {object} models.Tree
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/tree/{treeId} [patch]
+func (h *TreeHandler) UpdateTree(c echo.Context) error {
+	update := new(models.TreeUpdate)
+	if err := c.Bind(update); err != nil {
+		logrus.Warnf("Failed to bind tree update: %v", err)
 		return c.JSON(http.StatusBadRequest, map[string]string{
-			"message": "Tree coordinates out of bounds",
+			"message": "Invalid input format",
 		})
 	}
 
-	// Check if a tree already exists at the given coordinates
-	existingTree, err := h.TreeRepo.GetTreeByCoordinates(estateUUID, tree.X, tree.Y)
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	tree, err := h.findTree(c, estate)
+	if tree == nil {
+		return err
+	}
+
+	// A moved tree leaves the map tiles of its previous plot
+	previous := models.Plot{X: tree.X, Y: tree.Y}
+	update.Apply(tree)
+	now := time.Now()
+	tree.SetAge(now)
+	species, ok, err := h.findSpecies(c, tree.Species)
+	if !ok {
+		return err
+	}
+	if err := validateTree(tree, species, now); err != nil {
+		logrus.Warnf("Invalid tree: x=%d, y=%d, height=%d, species=%q: %v", tree.X, tree.Y, tree.Height, tree.Species, err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+	if ok, err := h.checkPlot(c, estate, tree); !ok {
+		return err
+	}
+
+	if err := h.TreeRepo.UpdateTree(tree); err != nil {
+		return respondTreeWriteError(c, tree, err, "Failed to update tree")
+	}
+	h.invalidateTrees(estate, previous, models.Plot{X: tree.X, Y: tree.Y})
+
+	logrus.Infof("Tree updated successfully in estate ID %s: %v", estate.ID, tree.ID)
+	return c.JSON(http.StatusOK, tree)
+}
+
+// DeleteTree removes a tree from an estate
+// @Summary Delete a tree
+// @Description Remove a tree, for example after it was felled
+// @Tags trees
+// @Param id path string true "Estate ID"
+// @Param treeId path string true "Tree ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/tree/{treeId} [delete]
+func (h *TreeHandler) DeleteTree(c echo.Context) error {
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	tree, err := h.findTree(c, estate)
+	if tree == nil {
+		return err
+	}
+
+	if err := h.TreeRepo.DeleteTree(estate.ID, tree.ID); err != nil {
+		return respondTreeWriteError(c, tree, err, "Failed to delete tree")
+	}
+	h.invalidateTrees(estate, models.Plot{X: tree.X, Y: tree.Y})
+
+	logrus.Infof("Tree deleted successfully from estate ID %s: %v", estate.ID, tree.ID)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetTreeMeasurements returns the height measurement history of a tree
+// @Summary Get the measurements of a tree
+// @Description Get the height measurement history of a tree, oldest first
+// @Tags trees
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param treeId path string true "Tree ID"
+// @Success 200 {object} map[string][]models.TreeMeasurement
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/tree/{treeId}/measurements [get]
+func (h *TreeHandler) GetTreeMeasurements(c echo.Context) error {
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	tree, err := h.findTree(c, estate)
+	if tree == nil {
+		return err
+	}
+
+	measurements, err := h.TreeRepo.GetMeasurements(tree.ID)
 	if err != nil {
-		logrus.Errorf("Database error while checking existing tree for estate ID %s: %v", estateUUID, err)
+		logrus.Errorf("Database error while retrieving measurements of tree ID %s: %v", tree.ID, err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"message": "Database error while checking existing tree",
+			"message": "Database error while retrieving measurements",
 		})
 	}
-	if existingTree != nil {
-		logrus.Warnf("A tree already exists at location x=%d, y=%d for estate ID %s", tree.X, tree.Y, estateUUID)
+
+	return c.JSON(http.StatusOK, map[string][]*models.TreeMeasurement{
+		"measurements": measurements,
+	})
+}
+
+// AddTreeMeasurement records a new height measurement of a tree
+// @Summary Record a tree measurement
+// @Description Record a height measurement of a tree. The latest measurement becomes the current height of the tree.
+// @Tags trees
+// @Accept json
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param treeId path string true "Tree ID"
+// @Param measurement body models.TreeMeasurement true "Measurement"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/tree/{treeId}/measurements [post]
+func (h *TreeHandler) AddTreeMeasurement(c echo.Context) error {
+	measurement := new(models.TreeMeasurement)
+	if err := c.Bind(measurement); err != nil {
+		logrus.Warnf("Failed to bind measurement: %v", err)
 		return c.JSON(http.StatusBadRequest, map[string]string{
-			"message": "A tree already exists at this location",
+			"message": "Invalid input format",
 		})
 	}
 
-	// Assign a new UUID to the tree and set the estate ID
-	tree.ID = uuid.New()
-	tree.EstateID = estateUUID
+	now := time.Now().UTC()
+	if measurement.Source == "" {
+		measurement.Source = models.MeasurementSourceManual
+	}
+	if measurement.MeasuredAt.IsZero() {
+		measurement.MeasuredAt = now
+	}
+	measurement.Surveyor = strings.TrimSpace(measurement.Surveyor)
+	if err := validateMeasurement(measurement, now); err != nil {
+		logrus.Warnf("Invalid measurement: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
 
-	// Add tree to the estate via repository
-	if err := h.TreeRepo.AddTreeToEstate(tree); err != nil {
-		logrus.Errorf("Failed to store tree in database for estate ID %s: %v", estateUUID, err)
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	tree, err := h.findTree(c, estate)
+	if tree == nil {
+		return err
+	}
+
+	// The height must also fit the species of the tree
+	species, ok, err := h.findSpecies(c, tree.Species)
+	if !ok {
+		return err
+	}
+	if !species.ValidHeight(measurement.Height) {
+		logrus.Warnf("Invalid measurement height %d for tree ID %s of species %q", measurement.Height, tree.ID, tree.Species)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Invalid tree height",
+		})
+	}
+
+	measurement.ID = uuid.New()
+	measurement.TreeID = tree.ID
+	measurement.CreatedAt = now
+
+	if err := h.TreeRepo.AddMeasurement(measurement); err != nil {
+		logrus.Errorf("Failed to store measurement of tree ID %s: %v", tree.ID, err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"message": "Failed to store tree in database",
+			"message": "Failed to store measurement in database",
 		})
 	}
+	h.invalidateTrees(estate, models.Plot{X: tree.X, Y: tree.Y})
 
-	logrus.Infof("Tree added successfully to estate ID %s: %v", estateUUID, tree.ID)
+	logrus.Infof("Measurement recorded successfully for tree ID %s: %v", tree.ID, measurement.ID)
 	return c.JSON(http.StatusOK, map[string]string{
-		"id": tree.ID.String(),
+		"id": measurement.ID.String(),
 	})
 }
+
+// GetTreeObservations returns the health observations of a tree
+// @Summary Get the health observations of a tree
+// @Description Get the health observations of a tree, oldest first
+// @Tags trees
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param treeId path string true "Tree ID"
+// @Success 200 {object} map[string][]models.TreeObservation
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/tree/{treeId}/observations [get]
+func (h *TreeHandler) GetTreeObservations(c echo.Context) error {
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	tree, err := h.findTree(c, estate)
+	if tree == nil {
+		return err
+	}
+
+	observations, err := h.TreeRepo.GetObservations(tree.ID)
+	if err != nil {
+		logrus.Errorf("Database error while retrieving observations of tree ID %s: %v", tree.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while retrieving observations",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string][]*models.TreeObservation{
+		"observations": observations,
+	})
+}
+
+// AddTreeObservation records a health observation of a tree
+// @Summary Record a tree health observation
+// @Description Record a field observation of the health of a tree. The latest observation becomes the current health status of the tree.
+// @Tags trees
+// @Accept json
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param treeId path string true "Tree ID"
+// @Param observation body models.TreeObservation true "Observation"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/tree/{treeId}/observations [post]
+func (h *TreeHandler) AddTreeObservation(c echo.Context) error {
+	observation := new(models.TreeObservation)
+	if err := c.Bind(observation); err != nil {
+		logrus.Warnf("Failed to bind observation: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Invalid input format",
+		})
+	}
+
+	now := time.Now().UTC()
+	if observation.ObservedAt.IsZero() {
+		observation.ObservedAt = now
+	}
+	observation.Notes = strings.TrimSpace(observation.Notes)
+	observation.Observer = strings.TrimSpace(observation.Observer)
+	if err := validateObservation(observation, now); err != nil {
+		logrus.Warnf("Invalid observation: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	tree, err := h.findTree(c, estate)
+	if tree == nil {
+		return err
+	}
+
+	observation.ID = uuid.New()
+	observation.TreeID = tree.ID
+	observation.CreatedAt = now
+
+	if err := h.TreeRepo.AddObservation(observation); err != nil {
+		logrus.Errorf("Failed to store observation of tree ID %s: %v", tree.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Failed to store observation in database",
+		})
+	}
+	h.invalidateTrees(estate, models.Plot{X: tree.X, Y: tree.Y})
+
+	logrus.Infof("Observation recorded successfully for tree ID %s: %v", tree.ID, observation.ID)
+	return c.JSON(http.StatusOK, map[string]string{
+		"id": observation.ID.String(),
+	})
+}
+
+// findTree parses the "treeId" path parameter and loads the tree from the
+// estate. Like findEstate, a nil tree means the response has been written.
+func (h *TreeHandler) findTree(c echo.Context, estate *models.Estate) (*models.Tree, error) {
+	id := c.Param("treeId")
+
+	treeID, err := uuid.Parse(id)
+	if err != nil {
+		logrus.Warnf("Invalid tree ID format: %s", id)
+		return nil, c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Invalid tree ID format",
+		})
+	}
+
+	tree, err := h.TreeRepo.GetTreeByID(estate.ID, treeID)
+	if err != nil {
+		logrus.Errorf("Database error while retrieving tree ID %s: %v", treeID, err)
+		return nil, c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while retrieving tree",
+		})
+	}
+	if tree == nil {
+		logrus.Warnf("Tree %s not found in estate %s", treeID, estate.ID)
+		return nil, c.JSON(http.StatusNotFound, map[string]string{
+			"message": "Tree not found",
+		})
+	}
+	return tree, nil
+}
+
+// checkPlot verifies the tree lies inside the estate and that no other tree
+// stands on its plot. When it returns false the response has been written.
+func (h *TreeHandler) checkPlot(c echo.Context, estate *models.Estate, tree *models.Tree) (bool, error) {
+	// Validate coordinates within estate bounds
+	if tree.X > estate.Width || tree.Y > estate.Length {
+		logrus.Warnf("Tree coordinates out of bounds: x=%d, y=%d", tree.X, tree.Y)
+		return false, c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Tree coordinates out of bounds",
+		})
+	}
+
+	// Check if another tree already exists at the given coordinates
+	existingTree, err := h.TreeRepo.GetTreeByCoordinates(estate.ID, tree.X, tree.Y)
+	if err != nil {
+		logrus.Errorf("Database error while checking existing tree for estate ID %s: %v", estate.ID, err)
+		return false, c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while checking existing tree",
+		})
+	}
+	if existingTree != nil && existingTree.ID != tree.ID {
+		logrus.Warnf("A tree already exists at location x=%d, y=%d for estate ID %s", tree.X, tree.Y, estate.ID)
+		return false, respondPlotOccupied(c)
+	}
+	return true, nil
+}
+
+// respondTreeWriteError answers a failed tree modification.
+func respondTreeWriteError(c echo.Context, tree *models.Tree, err error, message string) error {
+	switch {
+	case errors.Is(err, repositories.ErrTreeNotFound):
+		logrus.Warnf("Tree not found: %s", tree.ID)
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"message": "Tree not found",
+		})
+	case errors.Is(err, repositories.ErrPlotOccupied):
+		logrus.Warnf("A tree already exists at location x=%d, y=%d for estate ID %s", tree.X, tree.Y, tree.EstateID)
+		return respondPlotOccupied(c)
+	case errors.Is(err, repositories.ErrTreeOutOfBounds):
+		// The estate was shrunk since the coordinates were checked
+		logrus.Warnf("Tree coordinates out of bounds: x=%d, y=%d", tree.X, tree.Y)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Tree coordinates out of bounds",
+		})
+	case errors.Is(err, repositories.ErrEstateNotFound):
+		logrus.Warnf("Estate not found: %s", tree.EstateID)
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"message": "Estate not found",
+		})
+	}
+	logrus.Errorf("%s ID %s: %v", message, tree.ID, err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"message": message,
+	})
+}
+
+func respondPlotOccupied(c echo.Context) error {
+	return c.JSON(http.StatusBadRequest, map[string]string{
+		"message": "A tree already exists at this location",
+	})
+}
+
+// findSpecies loads the species of a tree. Trees without a species get a nil
+// species; when it returns false the response has been written.
+func (h *TreeHandler) findSpecies(c echo.Context, code string) (*models.Species, bool, error) {
+	if code == "" {
+		return nil, true, nil
+	}
+	species, err := h.SpeciesRepo.GetSpecies(code)
+	if err != nil {
+		logrus.Errorf("Database error while retrieving species %s: %v", code, err)
+		return nil, false, c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while retrieving species",
+		})
+	}
+	if species == nil {
+		logrus.Warnf("Unknown species: %s", code)
+		return nil, false, c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Unknown species",
+		})
+	}
+	return species, true, nil
+}
+
+// validateTree checks the coordinates, height and planting date of a tree. The
+// height must lie within the range of the tree's species, or from 1 to 30 for
+// a tree without one.
+func validateTree(tree *models.Tree, species *models.Species, now time.Time) error {
+	switch {
+	case tree.X < 1 || tree.Y < 1:
+		return errors.New("Invalid tree coordinates or height")
+	case species == nil && !species.ValidHeight(tree.Height):
+		return errors.New("Invalid tree coordinates or height")
+	case !species.ValidHeight(tree.Height):
+		return fmt.Errorf("Tree height must be between %d and %d for species %s", species.MinHeight, species.MaxHeight, species.Code)
+	case tree.PlantedOn != nil && tree.PlantedOn.After(now):
+		return errors.New("Planting date cannot be in the future")
+	}
+	return nil
+}
+
+// maxSurveyorLength is the longest surveyor name accepted on a measurement.
+const maxSurveyorLength = 100
+
+// Longest observer name and notes accepted on a health observation.
+const (
+	maxObserverLength         = 100
+	maxObservationNotesLength = 2000
+)
+
+// validateMeasurement checks the source, surveyor and date of a measurement and
+// that its height is positive. The height range depends on the species of the
+// measured tree and is checked once the tree is known.
+func validateMeasurement(measurement *models.TreeMeasurement, now time.Time) error {
+	switch {
+	case measurement.Height < 1:
+		return errors.New("Invalid tree height")
+	case !models.ValidMeasurementSource(measurement.Source):
+		return errors.New("Measurement source must be one of manual, drone or lidar")
+	case len([]rune(measurement.Surveyor)) > maxSurveyorLength:
+		return fmt.Errorf("Surveyor must be at most %d characters", maxSurveyorLength)
+	case measurement.MeasuredAt.After(now):
+		return errors.New("Measurement date cannot be in the future")
+	}
+	return nil
+}
+
+// validateObservation checks the status, severity, observer, notes and date of
+// a health observation. Healthy trees have a severity of 0, other statuses a
+// severity from 1 to 5.
+func validateObservation(observation *models.TreeObservation, now time.Time) error {
+	switch {
+	case !models.ValidHealthStatus(observation.Status):
+		return fmt.Errorf("Observation status must be one of %s", strings.Join(models.HealthStatuses, ", "))
+	case observation.Status == models.HealthStatusHealthy && observation.Severity != 0:
+		return errors.New("Healthy observations must have a severity of 0")
+	case observation.Status != models.HealthStatusHealthy &&
+		(observation.Severity < models.MinObservationSeverity || observation.Severity > models.MaxObservationSeverity):
+		return fmt.Errorf("Observation severity must be between %d and %d", models.MinObservationSeverity, models.MaxObservationSeverity)
+	case len([]rune(observation.Observer)) > maxObserverLength:
+		return fmt.Errorf("Observer must be at most %d characters", maxObserverLength)
+	case len([]rune(observation.Notes)) > maxObservationNotesLength:
+		return fmt.Errorf("Observation notes must be at most %d characters", maxObservationNotesLength)
+	case observation.ObservedAt.After(now):
+		return errors.New("Observation date cannot be in the future")
+	}
+	return nil
+}
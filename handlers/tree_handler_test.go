@@ -3,13 +3,17 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"sawitpro-recruitment/cache"
 	"sawitpro-recruitment/mocks"
 	"sawitpro-recruitment/models"
+	"sawitpro-recruitment/repositories"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
@@ -93,6 +97,31 @@ func TestTreeHandler_AddTreeToEstate_EstateNotFound(t *testing.T) {
 	}
 }
 
+func TestTreeHandler_AddTreeToEstate_EstateArchived(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate/"+estateID.String()+"/tree", strings.NewReader(`{"x": 1, "y": 1, "height": 15}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	archivedAt := time.Now().UTC()
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 10, ArchivedAt: &archivedAt}, nil)
+
+	if assert.NoError(t, handler.AddTreeToEstate(c)) {
+		assert.Equal(t, http.StatusGone, rec.Code)
+	}
+}
+
 func TestTreeHandler_AddTreeToEstate_TreeAlreadyExists(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -118,6 +147,36 @@ func TestTreeHandler_AddTreeToEstate_TreeAlreadyExists(t *testing.T) {
 	}
 }
 
+func TestTreeHandler_AddTreeToEstate_PlotTakenConcurrently(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New().String()
+	req := httptest.NewRequest(http.MethodPost, "/estate/"+estateID+"/tree", strings.NewReader(`{"x": 10, "y": 20, "height": 15}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID)
+
+	// Another tree is planted on the plot between the check and the insert
+	mockEstateRepo.EXPECT().GetEstateByID(gomock.Any()).Return(&models.Estate{ID: uuid.MustParse(estateID), Width: 100, Length: 200}, nil)
+	mockTreeRepo.EXPECT().GetTreeByCoordinates(gomock.Any(), 10, 20).Return(nil, nil)
+	mockTreeRepo.EXPECT().AddTreeToEstate(gomock.Any()).Return(repositories.ErrPlotOccupied)
+
+	if assert.NoError(t, handler.AddTreeToEstate(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var response map[string]string
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "A tree already exists at this location", response["message"])
+	}
+}
+
 func TestTreeHandler_AddTreeToEstate_InvalidEstateID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -209,3 +268,739 @@ func TestTreeHandler_AddTreeToEstate_InvalidTreeHeight(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 	}
 }
+
+func newTreeContext(e *echo.Echo, method, body string, estateID, treeID string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, "/estate/"+estateID+"/tree/"+treeID, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id", "treeId")
+	c.SetParamValues(estateID, treeID)
+	return c, rec
+}
+
+func TestTreeHandler_GetTree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	tree := &models.Tree{ID: uuid.New(), EstateID: estateID, X: 2, Y: 3, Height: 10}
+	c, rec := newTreeContext(echo.New(), http.MethodGet, "", estateID.String(), tree.ID.String())
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByID(estateID, tree.ID).Return(tree, nil)
+
+	if assert.NoError(t, handler.GetTree(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response models.Tree
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, *tree, response)
+	}
+}
+
+func TestTreeHandler_GetTree_InvalidTreeID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newTreeContext(echo.New(), http.MethodGet, "", estateID.String(), "invalid-uuid")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+
+	if assert.NoError(t, handler.GetTree(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var response map[string]string
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "Invalid tree ID format", response["message"])
+	}
+}
+
+func TestTreeHandler_GetTreeAtPlot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/plot/2/3", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id", "x", "y")
+	c.SetParamValues(estateID.String(), "2", "3")
+
+	tree := &models.Tree{ID: uuid.New(), EstateID: estateID, X: 2, Y: 3, Height: 10}
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByCoordinates(estateID, 2, 3).Return(tree, nil)
+
+	if assert.NoError(t, handler.GetTreeAtPlot(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response models.Tree
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, tree.ID, response.ID)
+	}
+}
+
+func TestTreeHandler_GetTreeAtPlot_EmptyPlot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/plot/2/3", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id", "x", "y")
+	c.SetParamValues(estateID.String(), "2", "3")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByCoordinates(estateID, 2, 3).Return(nil, nil)
+
+	if assert.NoError(t, handler.GetTreeAtPlot(c)) {
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestTreeHandler_GetTreeAtPlot_InvalidCoordinates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/plot/0/x", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id", "x", "y")
+	c.SetParamValues(estateID.String(), "0", "x")
+
+	if assert.NoError(t, handler.GetTreeAtPlot(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestTreeHandler_UpdateTree_Move(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+	handler.Cache = cache.NewEstateCache()
+
+	estateID := uuid.New()
+	treeID := uuid.New()
+	c, rec := newTreeContext(echo.New(), http.MethodPatch, `{"x": 4, "height": 12}`, estateID.String(), treeID.String())
+
+	handler.Cache.Set(estateID, "drone-plan:survey:0:", handler.Cache.Generation(estateID), "cached")
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByID(estateID, treeID).Return(&models.Tree{ID: treeID, EstateID: estateID, X: 1, Y: 1, Height: 10}, nil)
+	mockTreeRepo.EXPECT().GetTreeByCoordinates(estateID, 4, 1).Return(nil, nil)
+	mockTreeRepo.EXPECT().UpdateTree(&models.Tree{ID: treeID, EstateID: estateID, X: 4, Y: 1, Height: 12}).Return(nil)
+
+	if assert.NoError(t, handler.UpdateTree(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response models.Tree
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, 4, response.X)
+		assert.Equal(t, 12, response.Height)
+		_, ok := handler.Cache.Get(estateID, "drone-plan:survey:0:")
+		assert.False(t, ok)
+	}
+}
+
+func TestTreeHandler_UpdateTree_HeightOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	tree := &models.Tree{ID: uuid.New(), EstateID: estateID, X: 1, Y: 1, Height: 10}
+	c, rec := newTreeContext(echo.New(), http.MethodPatch, `{"height": 11}`, estateID.String(), tree.ID.String())
+
+	// The tree's own plot does not count as occupied
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByID(estateID, tree.ID).Return(tree, nil)
+	mockTreeRepo.EXPECT().GetTreeByCoordinates(estateID, 1, 1).Return(&models.Tree{ID: tree.ID, EstateID: estateID, X: 1, Y: 1, Height: 10}, nil)
+	mockTreeRepo.EXPECT().UpdateTree(gomock.Any()).Return(nil)
+
+	if assert.NoError(t, handler.UpdateTree(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+}
+
+func TestTreeHandler_UpdateTree_PlotOccupied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	treeID := uuid.New()
+	c, rec := newTreeContext(echo.New(), http.MethodPatch, `{"x": 2, "y": 2}`, estateID.String(), treeID.String())
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByID(estateID, treeID).Return(&models.Tree{ID: treeID, EstateID: estateID, X: 1, Y: 1, Height: 10}, nil)
+	mockTreeRepo.EXPECT().GetTreeByCoordinates(estateID, 2, 2).Return(&models.Tree{ID: uuid.New(), EstateID: estateID, X: 2, Y: 2, Height: 8}, nil)
+
+	if assert.NoError(t, handler.UpdateTree(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var response map[string]string
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "A tree already exists at this location", response["message"])
+	}
+}
+
+func TestTreeHandler_UpdateTree_OutOfBounds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	treeID := uuid.New()
+	c, rec := newTreeContext(echo.New(), http.MethodPatch, `{"y": 6}`, estateID.String(), treeID.String())
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByID(estateID, treeID).Return(&models.Tree{ID: treeID, EstateID: estateID, X: 1, Y: 1, Height: 10}, nil)
+
+	if assert.NoError(t, handler.UpdateTree(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var response map[string]string
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "Tree coordinates out of bounds", response["message"])
+	}
+}
+
+func TestTreeHandler_UpdateTree_EstateShrunk(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	treeID := uuid.New()
+	c, rec := newTreeContext(echo.New(), http.MethodPatch, `{"x": 4}`, estateID.String(), treeID.String())
+
+	// The estate is shrunk between the lookup and the update
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByID(estateID, treeID).Return(&models.Tree{ID: treeID, EstateID: estateID, X: 1, Y: 1, Height: 10}, nil)
+	mockTreeRepo.EXPECT().GetTreeByCoordinates(estateID, 4, 1).Return(nil, nil)
+	mockTreeRepo.EXPECT().UpdateTree(gomock.Any()).Return(repositories.ErrTreeOutOfBounds)
+
+	if assert.NoError(t, handler.UpdateTree(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var response map[string]string
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "Tree coordinates out of bounds", response["message"])
+	}
+}
+
+func TestTreeHandler_DeleteTree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	treeID := uuid.New()
+	c, rec := newTreeContext(echo.New(), http.MethodDelete, "", estateID.String(), treeID.String())
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByID(estateID, treeID).Return(&models.Tree{ID: treeID, EstateID: estateID, X: 1, Y: 1, Height: 10}, nil)
+	mockTreeRepo.EXPECT().DeleteTree(estateID, treeID).Return(nil)
+
+	if assert.NoError(t, handler.DeleteTree(c)) {
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	}
+}
+
+func TestTreeHandler_DeleteTree_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	treeID := uuid.New()
+	c, rec := newTreeContext(echo.New(), http.MethodDelete, "", estateID.String(), treeID.String())
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByID(estateID, treeID).Return(nil, nil)
+
+	if assert.NoError(t, handler.DeleteTree(c)) {
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestTreeHandler_GetTreeMeasurements(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	treeID := uuid.New()
+	c, rec := newTreeContext(echo.New(), http.MethodGet, "", estateID.String(), treeID.String())
+
+	measuredAt := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByID(estateID, treeID).Return(&models.Tree{ID: treeID, EstateID: estateID, X: 1, Y: 1, Height: 10}, nil)
+	mockTreeRepo.EXPECT().GetMeasurements(treeID).Return([]*models.TreeMeasurement{
+		{ID: uuid.New(), TreeID: treeID, Height: 10, MeasuredAt: measuredAt, Source: models.MeasurementSourceManual},
+	}, nil)
+
+	if assert.NoError(t, handler.GetTreeMeasurements(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response map[string][]models.TreeMeasurement
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		if assert.Len(t, response["measurements"], 1) {
+			assert.Equal(t, measuredAt, response["measurements"][0].MeasuredAt)
+		}
+	}
+}
+
+func TestTreeHandler_AddTreeMeasurement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	treeID := uuid.New()
+	body := `{"height": 14, "source": "lidar", "surveyor": " Team B ", "measured_at": "2024-06-01T08:00:00Z"}`
+	c, rec := newTreeContext(echo.New(), http.MethodPost, body, estateID.String(), treeID.String())
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByID(estateID, treeID).Return(&models.Tree{ID: treeID, EstateID: estateID, X: 1, Y: 1, Height: 10}, nil)
+	mockTreeRepo.EXPECT().AddMeasurement(gomock.Any()).DoAndReturn(func(m *models.TreeMeasurement) error {
+		assert.Equal(t, treeID, m.TreeID)
+		assert.Equal(t, 14, m.Height)
+		assert.Equal(t, models.MeasurementSourceLidar, m.Source)
+		assert.Equal(t, "Team B", m.Surveyor)
+		assert.Equal(t, time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC), m.MeasuredAt)
+		return nil
+	})
+
+	if assert.NoError(t, handler.AddTreeMeasurement(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response map[string]string
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.NotEmpty(t, response["id"])
+	}
+}
+
+func TestTreeHandler_AddTreeMeasurement_InvalidSource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	treeID := uuid.New()
+	c, rec := newTreeContext(echo.New(), http.MethodPost, `{"height": 14, "source": "guess"}`, estateID.String(), treeID.String())
+
+	if assert.NoError(t, handler.AddTreeMeasurement(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestTreeHandler_AddTreeMeasurement_FutureDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	treeID := uuid.New()
+	body := `{"height": 14, "measured_at": "` + time.Now().Add(48*time.Hour).UTC().Format(time.RFC3339) + `"}`
+	c, rec := newTreeContext(echo.New(), http.MethodPost, body, estateID.String(), treeID.String())
+
+	if assert.NoError(t, handler.AddTreeMeasurement(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var response map[string]string
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "Measurement date cannot be in the future", response["message"])
+	}
+}
+
+func TestValidateMeasurement_SurveyorLength(t *testing.T) {
+	now := time.Now()
+	// The limit counts characters, not bytes
+	measurement := &models.TreeMeasurement{Height: 14, Source: models.MeasurementSourceManual, Surveyor: strings.Repeat("é", maxSurveyorLength), MeasuredAt: now}
+	assert.NoError(t, validateMeasurement(measurement, now))
+
+	measurement.Surveyor += "é"
+	assert.EqualError(t, validateMeasurement(measurement, now), fmt.Sprintf("Surveyor must be at most %d characters", maxSurveyorLength))
+}
+
+func TestTreeHandler_ListTrees(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/trees?sort=height&order=desc&limit=5&x1=1&y1=1&x2=3&y2=4&max_height=20", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	one, three, four, twenty := 1, 3, 4, 20
+	tree := &models.Tree{ID: uuid.New(), EstateID: estateID, X: 2, Y: 2, Height: 15}
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().ListTrees(estateID, models.TreeQuery{
+		SortBy:    models.TreeSortHeight,
+		Desc:      true,
+		Limit:     5,
+		MinX:      &one,
+		MinY:      &one,
+		MaxX:      &three,
+		MaxY:      &four,
+		MaxHeight: &twenty,
+	}).Return(&models.TreePage{Trees: []*models.Tree{tree}, NextCursor: "next"}, nil)
+
+	if assert.NoError(t, handler.ListTrees(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var page models.TreePage
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		assert.Len(t, page.Trees, 1)
+		assert.Equal(t, "next", page.NextCursor)
+	}
+}
+
+func TestTreeHandler_ListTrees_InvalidParams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	for _, query := range []string{"sort=species", "order=up", "limit=0", "x1=a", "x1=4&x2=2", "y1=5&y2=1", "health=sick"} {
+		req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/trees?"+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(estateID.String())
+
+		if assert.NoError(t, handler.ListTrees(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	}
+}
+
+func TestTreeHandler_ListTrees_InvalidCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/trees?cursor=bogus", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().ListTrees(estateID, gomock.Any()).Return(nil, repositories.ErrInvalidCursor)
+
+	if assert.NoError(t, handler.ListTrees(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestTreeHandler_AddTreeToEstate_WithSpecies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockSpeciesRepo := mocks.NewMockSpeciesRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+	handler.SpeciesRepo = mockSpeciesRepo
+
+	e := echo.New()
+	estateID := uuid.New()
+	body := `{"x": 1, "y": 2, "height": 12, "species": "tenera", "planted_on": "2015-03-01"}`
+	req := httptest.NewRequest(http.MethodPost, "/estate/"+estateID.String()+"/tree", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	mockSpeciesRepo.EXPECT().GetSpecies("tenera").Return(&models.Species{Code: "tenera", MinHeight: 1, MaxHeight: 25}, nil)
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByCoordinates(estateID, 1, 2).Return(nil, nil)
+	mockTreeRepo.EXPECT().AddTreeToEstate(gomock.Any()).DoAndReturn(func(tree *models.Tree) error {
+		assert.Equal(t, "tenera", tree.Species)
+		if assert.NotNil(t, tree.PlantedOn) {
+			assert.Equal(t, "2015-03-01", tree.PlantedOn.String())
+		}
+		return nil
+	})
+
+	if assert.NoError(t, handler.AddTreeToEstate(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+}
+
+func TestTreeHandler_AddTreeToEstate_InvalidSpeciesTree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockSpeciesRepo := mocks.NewMockSpeciesRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+	handler.SpeciesRepo = mockSpeciesRepo
+
+	mockSpeciesRepo.EXPECT().GetSpecies("tenera").Return(&models.Species{Code: "tenera", MinHeight: 1, MaxHeight: 25}, nil).AnyTimes()
+	mockSpeciesRepo.EXPECT().GetSpecies("oak").Return(nil, nil)
+
+	tests := []struct {
+		body    string
+		message string
+	}{
+		{`{"x": 1, "y": 1, "height": 28, "species": "tenera"}`, "Tree height must be between 1 and 25 for species tenera"},
+		{`{"x": 1, "y": 1, "height": 10, "species": "oak"}`, "Unknown species"},
+		{`{"x": 1, "y": 1, "height": 10, "species": "tenera", "planted_on": "2999-01-01"}`, "Planting date cannot be in the future"},
+		{`{"x": 1, "y": 1, "height": 10, "planted_on": "01/02/2015"}`, "Invalid input format"},
+	}
+	e := echo.New()
+	estateID := uuid.New().String()
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/estate/"+estateID+"/tree", strings.NewReader(tt.body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(estateID)
+
+		if assert.NoError(t, handler.AddTreeToEstate(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code, tt.body)
+			assert.Contains(t, rec.Body.String(), tt.message)
+		}
+	}
+}
+
+func TestTreeHandler_UpdateTree_Species(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockSpeciesRepo := mocks.NewMockSpeciesRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+	handler.SpeciesRepo = mockSpeciesRepo
+
+	estateID := uuid.New()
+	treeID := uuid.New()
+	plantedOn := time.Now().AddDate(-5, 0, -1).Format(models.DateLayout)
+	c, rec := newTreeContext(echo.New(), http.MethodPatch, `{"species": "dura", "planted_on": "`+plantedOn+`"}`, estateID.String(), treeID.String())
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByID(estateID, treeID).Return(&models.Tree{ID: treeID, EstateID: estateID, X: 1, Y: 1, Height: 10}, nil)
+	mockSpeciesRepo.EXPECT().GetSpecies("dura").Return(&models.Species{Code: "dura", MinHeight: 1, MaxHeight: 30}, nil)
+	mockTreeRepo.EXPECT().GetTreeByCoordinates(estateID, 1, 1).Return(&models.Tree{ID: treeID}, nil)
+	mockTreeRepo.EXPECT().UpdateTree(gomock.Any()).Return(nil)
+
+	if assert.NoError(t, handler.UpdateTree(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var tree models.Tree
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tree))
+		assert.Equal(t, "dura", tree.Species)
+		if assert.NotNil(t, tree.AgeYears) {
+			assert.Equal(t, 5, *tree.AgeYears)
+		}
+	}
+}
+
+func TestTreeHandler_AddTreeMeasurement_SpeciesHeight(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockSpeciesRepo := mocks.NewMockSpeciesRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+	handler.SpeciesRepo = mockSpeciesRepo
+
+	estateID := uuid.New()
+	treeID := uuid.New()
+	c, rec := newTreeContext(echo.New(), http.MethodPost, `{"height": 22}`, estateID.String(), treeID.String())
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByID(estateID, treeID).Return(&models.Tree{ID: treeID, EstateID: estateID, X: 1, Y: 1, Height: 18, Species: "pisifera"}, nil)
+	mockSpeciesRepo.EXPECT().GetSpecies("pisifera").Return(&models.Species{Code: "pisifera", MinHeight: 1, MaxHeight: 20}, nil)
+
+	if assert.NoError(t, handler.AddTreeMeasurement(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Invalid tree height")
+	}
+}
+
+func TestTreeHandler_GetTreeObservations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	treeID := uuid.New()
+	c, rec := newTreeContext(echo.New(), http.MethodGet, "", estateID.String(), treeID.String())
+
+	observedAt := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByID(estateID, treeID).Return(&models.Tree{ID: treeID, EstateID: estateID, X: 1, Y: 1, Height: 10}, nil)
+	mockTreeRepo.EXPECT().GetObservations(treeID).Return([]*models.TreeObservation{
+		{ID: uuid.New(), TreeID: treeID, Status: models.HealthStatusOryctes, Severity: 2, ObservedAt: observedAt},
+	}, nil)
+
+	if assert.NoError(t, handler.GetTreeObservations(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response map[string][]models.TreeObservation
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		if assert.Len(t, response["observations"], 1) {
+			assert.Equal(t, models.HealthStatusOryctes, response["observations"][0].Status)
+			assert.Equal(t, observedAt, response["observations"][0].ObservedAt)
+		}
+	}
+}
+
+func TestTreeHandler_AddTreeObservation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	treeID := uuid.New()
+	body := `{"status": "ganoderma", "severity": 3, "notes": " Basal stem rot ", "observer": " Scout B ", "observed_at": "2024-06-01T08:00:00Z"}`
+	c, rec := newTreeContext(echo.New(), http.MethodPost, body, estateID.String(), treeID.String())
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByID(estateID, treeID).Return(&models.Tree{ID: treeID, EstateID: estateID, X: 1, Y: 1, Height: 10}, nil)
+	mockTreeRepo.EXPECT().AddObservation(gomock.Any()).DoAndReturn(func(o *models.TreeObservation) error {
+		assert.Equal(t, treeID, o.TreeID)
+		assert.Equal(t, models.HealthStatusGanoderma, o.Status)
+		assert.Equal(t, 3, o.Severity)
+		assert.Equal(t, "Basal stem rot", o.Notes)
+		assert.Equal(t, "Scout B", o.Observer)
+		assert.Equal(t, time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC), o.ObservedAt)
+		return nil
+	})
+
+	if assert.NoError(t, handler.AddTreeObservation(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response map[string]string
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.NotEmpty(t, response["id"])
+	}
+}
+
+func TestTreeHandler_AddTreeObservation_InvalidInput(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	treeID := uuid.New()
+	for _, body := range []string{
+		`{"status": "sick", "severity": 2}`,
+		`{"status": "healthy", "severity": 2}`,
+		`{"status": "ganoderma"}`,
+		`{"status": "dead", "severity": 6}`,
+		`{"status": "other", "severity": 1, "observed_at": "` + time.Now().Add(48*time.Hour).UTC().Format(time.RFC3339) + `"}`,
+	} {
+		c, rec := newTreeContext(echo.New(), http.MethodPost, body, estateID.String(), treeID.String())
+
+		if assert.NoError(t, handler.AddTreeObservation(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+		}
+	}
+}
+
+func TestTreeHandler_ListTrees_Health(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	e := echo.New()
+	estateID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/trees?health=ganoderma", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(estateID.String())
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().ListTrees(estateID, models.TreeQuery{
+		SortBy:       models.TreeSortCoordinate,
+		Limit:        defaultPageSize,
+		HealthStatus: models.HealthStatusGanoderma,
+	}).Return(&models.TreePage{Trees: []*models.Tree{}}, nil)
+
+	if assert.NoError(t, handler.ListTrees(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+}
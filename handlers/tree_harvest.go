@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sawitpro-recruitment/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxHarvestBatchSize = 1000 // Largest number of harvests recorded in one request
+	maxHarvesterLength  = 100  // Longest harvester name accepted on a harvest
+	maxBunchWeightKg    = 60   // Heaviest average bunch weight accepted, well above a ripe FFB bunch
+)
+
+// AddHarvests records a batch of harvests in an estate
+// @Summary Record harvests
+// @Description Record the fresh fruit bunches cut from the plots of an estate. Each harvest names a tree or a plot. The batch is stored only when every harvest is valid; otherwise the rejected harvests are reported.
+// @Tags harvests
+// @Accept json
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param harvests body []models.Harvest true "Harvests"
+// @Success 200 {object} models.HarvestBatchReport
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 422 {object} models.HarvestBatchReport
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/harvests [post]
+func (h *TreeHandler) AddHarvests(c echo.Context) error {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(c.Request().Body).Decode(&raw); err != nil {
+		logrus.Warnf("Failed to read harvests: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Body must be a JSON array of harvests",
+		})
+	}
+	if len(raw) == 0 || len(raw) > maxHarvestBatchSize {
+		logrus.Warnf("Invalid harvest batch size: %d", len(raw))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": fmt.Sprintf("A batch must hold between 1 and %d harvests", maxHarvestBatchSize),
+		})
+	}
+
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	report := &models.HarvestBatchReport{Errors: []models.TreeImportError{}}
+	harvests := make([]*models.Harvest, 0, len(raw))
+	trees := map[uuid.UUID]*models.Tree{} // Trees named in the batch, looked up once
+	for i, data := range raw {
+		harvest := &models.Harvest{}
+		if err := json.Unmarshal(data, harvest); err != nil {
+			rejectHarvest(report, i+1, "Invalid harvest")
+			continue
+		}
+		harvest.Harvester = strings.TrimSpace(harvest.Harvester)
+		if err := validateHarvest(harvest, now); err != nil {
+			rejectHarvest(report, i+1, err.Error())
+			continue
+		}
+		message, err := h.locateHarvest(estate, harvest, trees)
+		if err != nil {
+			logrus.Errorf("Database error while locating harvest %d for estate ID %s: %v", i+1, estate.ID, err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": "Database error while retrieving tree",
+			})
+		}
+		if message != "" {
+			rejectHarvest(report, i+1, message)
+			continue
+		}
+		harvest.ID = uuid.New()
+		harvest.EstateID = estate.ID
+		harvest.CreatedAt = now
+		harvests = append(harvests, harvest)
+	}
+
+	if report.Failed > 0 {
+		logrus.Warnf("Harvest batch for estate ID %s rejected: %d invalid harvests", estate.ID, report.Failed)
+		return c.JSON(http.StatusUnprocessableEntity, report)
+	}
+
+	if err := h.HarvestRepo.AddHarvests(harvests); err != nil {
+		logrus.Errorf("Failed to store harvests for estate ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Failed to store harvests in database",
+		})
+	}
+	h.Cache.Invalidate(estate.ID)
+
+	report.Recorded = len(harvests)
+	logrus.Infof("Recorded %d harvests for estate ID %s", report.Recorded, estate.ID)
+	return c.JSON(http.StatusOK, report)
+}
+
+// GetTreeHarvests returns the harvests of a tree
+// @Summary Get the harvests of a tree
+// @Description Get the harvests of a tree, oldest first
+// @Tags harvests
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param treeId path string true "Tree ID"
+// @Success 200 {object} map[string][]models.Harvest
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/tree/{treeId}/harvests [get]
+func (h *TreeHandler) GetTreeHarvests(c echo.Context) error {
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	tree, err := h.findTree(c, estate)
+	if tree == nil {
+		return err
+	}
+
+	harvests, err := h.HarvestRepo.GetTreeHarvests(tree.ID)
+	if err != nil {
+		logrus.Errorf("Database error while retrieving harvests of tree ID %s: %v", tree.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while retrieving harvests",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string][]*models.Harvest{
+		"harvests": harvests,
+	})
+}
+
+// validateHarvest checks the date, bunch count, weight and harvester of a
+// harvest. Its location is checked against the estate by locateHarvest.
+func validateHarvest(harvest *models.Harvest, now time.Time) error {
+	switch {
+	case harvest.HarvestedOn.IsZero():
+		return errors.New("Harvest date is required")
+	case harvest.HarvestedOn.After(now):
+		return errors.New("Harvest date cannot be in the future")
+	case harvest.Bunches < 1:
+		return errors.New("A harvest must have at least one bunch")
+	case harvest.WeightKg <= 0:
+		return errors.New("Harvest weight must be positive")
+	case harvest.WeightKg > float64(harvest.Bunches*maxBunchWeightKg):
+		return fmt.Errorf("Harvest weight cannot exceed %d kg per bunch", maxBunchWeightKg)
+	case len([]rune(harvest.Harvester)) > maxHarvesterLength:
+		return fmt.Errorf("Harvester must be at most %d characters", maxHarvesterLength)
+	}
+	return nil
+}
+
+// locateHarvest resolves the plot of a harvest. A harvest naming a tree takes
+// the tree's plot, and may only repeat it; otherwise the plot must lie in the
+// estate. It returns the reason the harvest is rejected, if any.
+func (h *TreeHandler) locateHarvest(estate *models.Estate, harvest *models.Harvest, trees map[uuid.UUID]*models.Tree) (string, error) {
+	if harvest.TreeID == nil {
+		if harvest.X < 1 || harvest.Y < 1 || harvest.X > estate.Width || harvest.Y > estate.Length {
+			return "Harvest plot out of bounds", nil
+		}
+		return "", nil
+	}
+
+	tree, ok := trees[*harvest.TreeID]
+	if !ok {
+		var err error
+		if tree, err = h.TreeRepo.GetTreeByID(estate.ID, *harvest.TreeID); err != nil {
+			return "", err
+		}
+		trees[*harvest.TreeID] = tree
+	}
+	switch {
+	case tree == nil:
+		return "Tree not found", nil
+	case (harvest.X != 0 || harvest.Y != 0) && (harvest.X != tree.X || harvest.Y != tree.Y):
+		return "Tree is not at the given plot", nil
+	}
+	harvest.X, harvest.Y = tree.X, tree.Y
+	return "", nil
+}
+
+// rejectHarvest counts a rejected harvest and lists it while the report has room.
+func rejectHarvest(report *models.HarvestBatchReport, row int, message string) {
+	report.Failed++
+	if len(report.Errors) < maxReportedImportErrors {
+		report.Errors = append(report.Errors, models.TreeImportError{Row: row, Message: message})
+	}
+}
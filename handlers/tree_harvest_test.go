@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"sawitpro-recruitment/mocks"
+	"sawitpro-recruitment/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreeHandler_AddHarvests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockHarvestRepo := mocks.NewMockHarvestRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+	handler.HarvestRepo = mockHarvestRepo
+
+	estateID := uuid.New()
+	treeID := uuid.New()
+	body := `[
+		{"tree_id": "` + treeID.String() + `", "harvested_on": "2024-06-01", "bunches": 3, "weight_kg": 62.5, "harvester": " Team A "},
+		{"tree_id": "` + treeID.String() + `", "harvested_on": "2024-06-15", "bunches": 1, "weight_kg": 21},
+		{"x": 4, "y": 2, "harvested_on": "2024-06-01", "bunches": 2, "weight_kg": 41}
+	]`
+	c, rec := newEstateRequest(http.MethodPost, "/harvests", estateID, "", body)
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	// The tree is looked up once for both of its harvests
+	mockTreeRepo.EXPECT().GetTreeByID(estateID, treeID).Return(&models.Tree{ID: treeID, EstateID: estateID, X: 2, Y: 3, Height: 10}, nil)
+	mockHarvestRepo.EXPECT().AddHarvests(gomock.Any()).DoAndReturn(func(harvests []*models.Harvest) error {
+		if assert.Len(t, harvests, 3) {
+			assert.Equal(t, estateID, harvests[0].EstateID)
+			assert.Equal(t, []int{2, 3}, []int{harvests[0].X, harvests[0].Y})
+			assert.Equal(t, "Team A", harvests[0].Harvester)
+			assert.Equal(t, "2024-06-01", harvests[0].HarvestedOn.String())
+			assert.Nil(t, harvests[2].TreeID)
+			assert.Equal(t, []int{4, 2}, []int{harvests[2].X, harvests[2].Y})
+		}
+		return nil
+	})
+
+	if assert.NoError(t, handler.AddHarvests(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var report models.HarvestBatchReport
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		assert.Equal(t, 3, report.Recorded)
+		assert.Empty(t, report.Errors)
+	}
+}
+
+func TestTreeHandler_AddHarvests_InvalidHarvests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockHarvestRepo := mocks.NewMockHarvestRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+	handler.HarvestRepo = mockHarvestRepo
+
+	estateID := uuid.New()
+	treeID := uuid.New()
+	otherTreeID := uuid.New()
+	tomorrow := time.Now().UTC().AddDate(0, 0, 2).Format(models.DateLayout)
+	body := `[
+		{"x": 1, "y": 1, "harvested_on": "2024-06-01", "bunches": 2, "weight_kg": 40},
+		{"x": 6, "y": 1, "harvested_on": "2024-06-01", "bunches": 2, "weight_kg": 40},
+		{"x": 1, "y": 1, "bunches": 2, "weight_kg": 40},
+		{"x": 1, "y": 1, "harvested_on": "` + tomorrow + `", "bunches": 2, "weight_kg": 40},
+		{"x": 1, "y": 1, "harvested_on": "2024-06-01", "bunches": 0, "weight_kg": 40},
+		{"x": 1, "y": 1, "harvested_on": "2024-06-01", "bunches": 1, "weight_kg": 400},
+		{"tree_id": "` + treeID.String() + `", "x": 1, "y": 1, "harvested_on": "2024-06-01", "bunches": 1, "weight_kg": 20},
+		{"tree_id": "` + otherTreeID.String() + `", "harvested_on": "2024-06-01", "bunches": 1, "weight_kg": 20},
+		{"x": "one"}
+	]`
+	c, rec := newEstateRequest(http.MethodPost, "/harvests", estateID, "", body)
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByID(estateID, treeID).Return(&models.Tree{ID: treeID, EstateID: estateID, X: 2, Y: 3, Height: 10}, nil)
+	mockTreeRepo.EXPECT().GetTreeByID(estateID, otherTreeID).Return(nil, nil)
+
+	if assert.NoError(t, handler.AddHarvests(c)) {
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		var report models.HarvestBatchReport
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		assert.Equal(t, 0, report.Recorded)
+		assert.Equal(t, 8, report.Failed)
+		assert.Equal(t, []models.TreeImportError{
+			{Row: 2, Message: "Harvest plot out of bounds"},
+			{Row: 3, Message: "Harvest date is required"},
+			{Row: 4, Message: "Harvest date cannot be in the future"},
+			{Row: 5, Message: "A harvest must have at least one bunch"},
+			{Row: 6, Message: "Harvest weight cannot exceed 60 kg per bunch"},
+			{Row: 7, Message: "Tree is not at the given plot"},
+			{Row: 8, Message: "Tree not found"},
+			{Row: 9, Message: "Invalid harvest"},
+		}, report.Errors)
+	}
+}
+
+func TestTreeHandler_AddHarvests_InvalidBody(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	for _, body := range []string{`{"x": 1}`, `[]`, `not json`} {
+		c, rec := newEstateRequest(http.MethodPost, "/harvests", uuid.New(), "", body)
+
+		if assert.NoError(t, handler.AddHarvests(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+		}
+	}
+}
+
+func TestTreeHandler_GetTreeHarvests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockHarvestRepo := mocks.NewMockHarvestRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+	handler.HarvestRepo = mockHarvestRepo
+
+	estateID := uuid.New()
+	treeID := uuid.New()
+	c, rec := newTreeContext(echo.New(), http.MethodGet, "", estateID.String(), treeID.String())
+
+	harvestedOn, _ := models.ParseDate("2024-06-01")
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreeByID(estateID, treeID).Return(&models.Tree{ID: treeID, EstateID: estateID, X: 1, Y: 1, Height: 10}, nil)
+	mockHarvestRepo.EXPECT().GetTreeHarvests(treeID).Return([]*models.Harvest{
+		{ID: uuid.New(), EstateID: estateID, TreeID: &treeID, X: 1, Y: 1, HarvestedOn: harvestedOn, Bunches: 3, WeightKg: 62.5},
+	}, nil)
+
+	if assert.NoError(t, handler.GetTreeHarvests(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response map[string][]models.Harvest
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		if assert.Len(t, response["harvests"], 1) {
+			assert.Equal(t, 62.5, response["harvests"][0].WeightKg)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sawitpro-recruitment/heatmap"
+	"sawitpro-recruitment/models"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// Heatmap image formats.
+const (
+	heatmapFormatPNG = "png"
+	heatmapFormatSVG = "svg"
+)
+
+// HeatmapPNG renders the tree heights of an estate as a PNG
+// @Summary Get the height heatmap of an estate as PNG
+// @Description Render one cell per plot coloured by tree height, empty plots transparent, with the height scale below the map. Large estates are downsampled to cells covering several plots, coloured by their mean height. A drone route can be drawn over the map.
+// @Tags trees
+// @Produce image/png
+// @Param id path string true "Estate ID"
+// @Param downsample query int false "Plots per cell side; by default the smallest keeping the map within 2048 cells a side"
+// @Param cell query int false "Pixels per cell side, 1 to 32; by default the largest keeping the map within 1024 pixels"
+// @Param route query string false "Drone route drawn over the map: survey or inspection"
+// @Param max_distance query int false "Maximum distance the drone can travel on the route"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/heatmap.png [get]
+func (h *TreeHandler) HeatmapPNG(c echo.Context) error {
+	return h.heatmap(c, heatmapFormatPNG)
+}
+
+// HeatmapSVG renders the tree heights of an estate as an SVG
+// @Summary Get the height heatmap of an estate as SVG
+// @Description Same map as the PNG heatmap, as an SVG of at most 512 cells a side.
+// @Tags trees
+// @Produce image/svg+xml
+// @Param id path string true "Estate ID"
+// @Param downsample query int false "Plots per cell side; by default the smallest keeping the map within 512 cells a side"
+// @Param cell query int false "Pixels per cell side, 1 to 32; by default the largest keeping the map within 1024 pixels"
+// @Param route query string false "Drone route drawn over the map: survey or inspection"
+// @Param max_distance query int false "Maximum distance the drone can travel on the route"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/heatmap.svg [get]
+func (h *TreeHandler) HeatmapSVG(c echo.Context) error {
+	return h.heatmap(c, heatmapFormatSVG)
+}
+
+// heatmap renders the heatmap of the estate in the path in one of the heatmap formats.
+func (h *TreeHandler) heatmap(c echo.Context, format string) error {
+	downsample, err := optionalIntParam(c, "downsample")
+	if err == nil && downsample != nil && *downsample < 1 {
+		err = fmt.Errorf("downsample must be at least 1")
+	}
+	var cell *int
+	if err == nil {
+		cell, err = optionalIntParam(c, "cell")
+	}
+	if err == nil && cell != nil && (*cell < 1 || *cell > heatmap.MaxCellSize) {
+		err = fmt.Errorf("cell must be between 1 and %d", heatmap.MaxCellSize)
+	}
+	var maxDistance *int
+	if err == nil {
+		maxDistance, err = optionalIntParam(c, "max_distance")
+	}
+	if err == nil && maxDistance != nil && *maxDistance <= 0 {
+		err = fmt.Errorf("invalid max_distance value")
+	}
+	route := c.QueryParam("route")
+	if err == nil && route != "" && route != droneRouteSurvey && route != droneRouteInspection {
+		err = fmt.Errorf("route must be survey or inspection")
+	}
+	if err == nil && route == "" && maxDistance != nil {
+		err = fmt.Errorf("max_distance requires a route")
+	}
+	if err != nil {
+		logrus.Warnf("Invalid heatmap parameters: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+
+	var plotsPerCell, cellSize, limit int
+	if downsample != nil {
+		plotsPerCell = *downsample
+	}
+	if cell != nil {
+		cellSize = *cell
+	}
+	if maxDistance != nil {
+		limit = *maxDistance
+	}
+
+	generation := cacheGeneration(c, h.Cache)
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	maxCells := heatmap.MaxCells
+	if format == heatmapFormatSVG {
+		maxCells = heatmap.MaxSVGCells
+	}
+	layout, err := heatmap.NewLayout(estate.Width, estate.Length, plotsPerCell, cellSize, maxCells)
+	if err != nil {
+		logrus.Warnf("Heatmap of estate ID %s does not fit: %v", estate.ID, err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+
+	contentType := "image/png"
+	if format == heatmapFormatSVG {
+		contentType = "image/svg+xml"
+	}
+	cacheKey := fmt.Sprintf("heatmap:%s:%d:%d:%s:%d", format, layout.Downsample, layout.Cell, route, limit)
+	if cached, ok := h.Cache.Get(estate.ID, cacheKey); ok {
+		return c.Blob(http.StatusOK, contentType, cached.([]byte))
+	}
+
+	// The survey route is planned over the heights read for the map
+	var treeHeights map[string]int
+	if route == droneRouteSurvey {
+		treeHeights = map[string]int{}
+	}
+	m := heatmap.New(layout)
+	err = h.TreeRepo.ExportTrees(estate.ID, func(tree *models.Tree) error {
+		m.Add(tree)
+		if treeHeights != nil {
+			treeHeights[fmt.Sprintf("%d,%d", tree.X, tree.Y)] = tree.Height
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.Errorf("Failed to load trees for estate ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while fetching trees",
+		})
+	}
+
+	switch route {
+	case droneRouteSurvey:
+		m.Route = surveyRoute(estate, surveyPlan(estate, treeHeights, limit))
+	case droneRouteInspection:
+		trees, err := h.TreeRepo.GetTreesToInspect(estate.ID)
+		if err != nil {
+			logrus.Errorf("Failed to load trees to inspect for estate ID %s: %v", estate.ID, err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": "Database error while fetching trees to inspect",
+			})
+		}
+		m.Route = inspectionRoute(trees, inspectionPlan(trees, limit))
+	}
+
+	var image bytes.Buffer
+	if format == heatmapFormatSVG {
+		err = m.EncodeSVG(&image)
+	} else {
+		err = m.EncodePNG(&image)
+	}
+	if err != nil {
+		logrus.Errorf("Failed to render heatmap for estate ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Failed to render heatmap",
+		})
+	}
+
+	h.Cache.Set(estate.ID, cacheKey, generation, image.Bytes())
+	logrus.Infof("Rendered %s heatmap of %d trees for estate ID %s", format, m.Trees(), estate.ID)
+	return c.Blob(http.StatusOK, contentType, image.Bytes())
+}
+
+// planRest returns the plot a drone plan rests at, if it stops short.
+func planRest(plan map[string]interface{}) *models.Plot {
+	rest, ok := plan["rest"].(map[string]int)
+	if !ok {
+		return nil
+	}
+	return &models.Plot{X: rest["x"], Y: rest["y"]}
+}
+
+// surveyRoute traces a survey plan: each row from its first plot to its last,
+// up to the plot before the rest point.
+func surveyRoute(estate *models.Estate, plan map[string]interface{}) *heatmap.Route {
+	route := &heatmap.Route{Rest: planRest(plan)}
+	for y := 1; y <= estate.Length; y++ {
+		last := estate.Width
+		if route.Rest != nil && y == route.Rest.Y {
+			last = route.Rest.X - 1
+		}
+		if last >= 1 {
+			route.Path = append(route.Path, models.Plot{X: 1, Y: y}, models.Plot{X: last, Y: y})
+		}
+		if route.Rest != nil && y == route.Rest.Y {
+			break
+		}
+	}
+	return route
+}
+
+// inspectionRoute traces an inspection plan from plot (1,1) through the trees
+// visited.
+func inspectionRoute(trees []*models.Tree, plan map[string]interface{}) *heatmap.Route {
+	route := &heatmap.Route{Path: []models.Plot{{X: 1, Y: 1}}, Rest: planRest(plan)}
+	for _, tree := range trees[:plan["trees"].(int)] {
+		route.Path = append(route.Path, models.Plot{X: tree.X, Y: tree.Y})
+	}
+	return route
+}
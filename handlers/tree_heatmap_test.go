@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"net/http"
+	"strings"
+	"testing"
+
+	"sawitpro-recruitment/cache"
+	"sawitpro-recruitment/mocks"
+	"sawitpro-recruitment/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreeHandler_HeatmapPNG(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+	handler.Cache = cache.NewEstateCache()
+
+	estateID := uuid.New()
+	trees := []*models.Tree{
+		{ID: uuid.New(), EstateID: estateID, X: 1, Y: 1, Height: 5},
+		{ID: uuid.New(), EstateID: estateID, X: 4, Y: 2, Height: 20},
+	}
+
+	// The tree repository is only read once; the second request is served from the cache
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 4, Length: 3}, nil).Times(2)
+	mockTreeRepo.EXPECT().ExportTrees(estateID, gomock.Any()).DoAndReturn(exportTreesOf(trees...))
+
+	var body []byte
+	for i := 0; i < 2; i++ {
+		c, rec := newEstateRequest(http.MethodGet, "/heatmap.png", estateID, "cell=10&route=survey&max_distance=50", "")
+		if assert.NoError(t, handler.HeatmapPNG(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "image/png", rec.Header().Get(echo.HeaderContentType))
+			if body != nil {
+				assert.Equal(t, body, rec.Body.Bytes())
+			}
+			body = rec.Body.Bytes()
+		}
+	}
+
+	img, err := png.Decode(bytes.NewReader(body))
+	if assert.NoError(t, err) {
+		// The map is 4x3 cells of 10 pixels, widened for the legend
+		assert.Equal(t, 160, img.Bounds().Dx())
+		assert.Equal(t, 30+34, img.Bounds().Dy())
+		_, _, _, alpha := img.At(25, 25).RGBA()
+		assert.Zero(t, alpha, "an empty plot is transparent")
+		r, g, b, _ := img.At(1, 1).RGBA()
+		assert.Equal(t, [3]uint32{0x4444, 0x0101, 0x5454}, [3]uint32{r, g, b}, "the shortest tree is at the bottom of the scale")
+		r, g, b, _ = img.At(38, 18).RGBA()
+		assert.Equal(t, [3]uint32{0xfdfd, 0xe7e7, 0x2525}, [3]uint32{r, g, b}, "the tallest tree is at the top of the scale")
+		// The drone flies the first row in 50 m and cannot reach (1,2)
+		r, g, b, _ = img.At(25, 5).RGBA()
+		assert.Equal(t, [3]uint32{0xe4e4, 0, 0x2b2b}, [3]uint32{r, g, b}, "the route runs along the first row")
+		r, g, b, _ = img.At(5, 15).RGBA()
+		assert.Equal(t, [3]uint32{0xffff, 0xffff, 0xffff}, [3]uint32{r, g, b}, "the drone rests at (1,2)")
+	}
+}
+
+func TestTreeHandler_HeatmapSVG_Inspection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	sick := &models.Tree{ID: uuid.New(), EstateID: estateID, X: 3, Y: 3, Height: 8, HealthStatus: "ganoderma"}
+	c, rec := newEstateRequest(http.MethodGet, "/heatmap.svg", estateID, "cell=10&route=inspection", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 3, Length: 3}, nil)
+	mockTreeRepo.EXPECT().ExportTrees(estateID, gomock.Any()).DoAndReturn(exportTreesOf(sick))
+	mockTreeRepo.EXPECT().GetTreesToInspect(estateID).Return([]*models.Tree{sick}, nil)
+
+	if assert.NoError(t, handler.HeatmapSVG(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "image/svg+xml", rec.Header().Get(echo.HeaderContentType))
+		svg := rec.Body.String()
+		assert.True(t, strings.HasPrefix(svg, "<svg "))
+		assert.Contains(t, svg, `<rect x="20" y="20" width="10" height="10" fill="#440154"/>`)
+		assert.Contains(t, svg, `d="M5 5L25 25"`)
+		assert.NotContains(t, svg, "<circle")
+		assert.Contains(t, svg, ">8 m</text>")
+	}
+}
+
+func TestTreeHandler_Heatmap_InvalidParameters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewTreeHandler(mocks.NewMockTreeRepository(ctrl), mocks.NewMockEstateRepository(ctrl))
+	estateID := uuid.New()
+
+	for _, query := range []string{"downsample=0", "cell=33", "cell=x", "route=orbit", "max_distance=100", "route=survey&max_distance=-1"} {
+		c, rec := newEstateRequest(http.MethodGet, "/heatmap.png", estateID, query, "")
+		if assert.NoError(t, handler.HeatmapPNG(c), query) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	}
+}
+
+func TestTreeHandler_Heatmap_TooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mocks.NewMockTreeRepository(ctrl), mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/heatmap.svg", estateID, "downsample=1", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 1000, Length: 200}, nil)
+
+	if assert.NoError(t, handler.HeatmapSVG(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "downsample must be at least 2")
+	}
+}
+
+func TestTreeHandler_Heatmap_DatabaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/heatmap.png", estateID, "", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 3, Length: 3}, nil)
+	mockTreeRepo.EXPECT().ExportTrees(estateID, gomock.Any()).Return(errors.New("db error"))
+
+	if assert.NoError(t, handler.HeatmapPNG(c)) {
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	}
+}
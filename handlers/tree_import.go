@@ -0,0 +1,381 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sawitpro-recruitment/models"
+	"sawitpro-recruitment/repositories"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	importBatchSize         = 5000    // Trees stored per COPY transaction when not importing atomically
+	maxReportedImportErrors = 1000    // Rejected rows listed in an import report
+	maxImportRows           = 1000000 // Largest number of rows accepted in one import
+)
+
+// importColumns are the CSV columns read for every tree.
+var importColumns = []string{"x", "y", "height"}
+
+// Optional CSV columns holding the species and planting date of a tree.
+const (
+	importSpeciesColumn   = "species"
+	importPlantedOnColumn = "planted_on"
+)
+
+// Reasons reported for valid rows that could not be stored.
+const (
+	msgImportStoreFailed = "Failed to store tree in database"       // The batch of the row failed
+	msgPlotOccupied      = "A tree already exists at this location" // Also reported for plots planted during the import
+)
+
+// importRow is a tree read from an import file, or the reason it could not be read.
+type importRow struct {
+	tree *models.Tree
+	err  string
+}
+
+// ImportTrees adds many trees to an estate from a CSV file or a JSON array
+// @Summary Import trees into an estate
+// @Description Import trees from a CSV file (header x,y,height with optional species and planted_on columns) or a JSON array of trees. Every row is validated like a single tree; valid rows are stored and rejected rows are reported. With atomic=true nothing is stored unless every row is valid.
+// @Tags trees
+// @Accept text/csv
+// @Accept json
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param atomic query bool false "Store no tree unless every row is valid"
+// @Success 200 {object} models.TreeImportReport
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 422 {object} models.TreeImportReport
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/tree/import [post]
+func (h *TreeHandler) ImportTrees(c echo.Context) error {
+	atomic := false
+	if raw := c.QueryParam("atomic"); raw != "" {
+		var err error
+		if atomic, err = strconv.ParseBool(raw); err != nil {
+			logrus.Warnf("Invalid atomic value: %s", raw)
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"message": "Invalid atomic value",
+			})
+		}
+	}
+
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	var rows []importRow
+	switch mediaType {
+	case "text/csv":
+		rows, err = readCSVTrees(c.Request().Body)
+	case echo.MIMEApplicationJSON:
+		rows, err = readJSONTrees(c.Request().Body)
+	default:
+		logrus.Warnf("Unsupported import content type: %s", mediaType)
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{
+			"message": "Content type must be text/csv or application/json",
+		})
+	}
+	if err != nil {
+		logrus.Warnf("Failed to read tree import for estate ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+
+	// Plots already planted, as well as plots used earlier in the file
+	occupied, err := h.TreeRepo.GetTreesByEstateID(estate.ID)
+	if err != nil {
+		logrus.Errorf("Database error while checking existing trees for estate ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while checking existing tree",
+		})
+	}
+	inFile := make(map[string]bool, len(rows))
+
+	// Species referred to by the file, loaded once
+	species, err := h.importSpecies(rows)
+	if err != nil {
+		logrus.Errorf("Database error while retrieving species for estate ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while retrieving species",
+		})
+	}
+
+	now := time.Now()
+	report := &models.TreeImportReport{Errors: []models.TreeImportError{}}
+	valid := make([]*models.Tree, 0, len(rows))
+	validRows := make([]int, 0, len(rows))
+	for i, row := range rows {
+		if message := validateImportRow(estate, row, species, occupied, inFile, now); message != "" {
+			rejectImportRow(report, i+1, message)
+			continue
+		}
+		row.tree.ID = uuid.New()
+		row.tree.EstateID = estate.ID
+		valid = append(valid, row.tree)
+		validRows = append(validRows, i+1)
+	}
+
+	if atomic && report.Failed > 0 {
+		logrus.Warnf("Atomic tree import for estate ID %s rejected: %d invalid rows", estate.ID, report.Failed)
+		return c.JSON(http.StatusUnprocessableEntity, report)
+	}
+
+	measuredAt := now.UTC()
+	batchSize := importBatchSize
+	if atomic {
+		batchSize = len(valid)
+	}
+	for start := 0; start < len(valid); start += batchSize {
+		end := min(start+batchSize, len(valid))
+		trees, treeRows := valid[start:end], validRows[start:end]
+		err := h.TreeRepo.ImportTrees(trees, measuredAt)
+		if errors.Is(err, repositories.ErrPlotOccupied) && !atomic {
+			trees, treeRows, err = h.importUnoccupied(estate.ID, trees, treeRows, measuredAt, report)
+		}
+		if err != nil {
+			logrus.Errorf("Failed to import trees %d to %d for estate ID %s: %v", start, end, estate.ID, err)
+			if atomic {
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"message": msgImportStoreFailed,
+				})
+			}
+			for _, row := range treeRows {
+				rejectImportRow(report, row, msgImportStoreFailed)
+			}
+			continue
+		}
+		report.Imported += len(trees)
+	}
+	if report.Imported > 0 {
+		h.Cache.Invalidate(estate.ID)
+		// Imports touch too many trees to find the map tiles they are drawn on
+		if err := h.Tiles.InvalidateEstate(estate.ID); err != nil {
+			logrus.Warnf("Failed to invalidate map tiles of estate ID %s: %v", estate.ID, err)
+		}
+	}
+
+	logrus.Infof("Imported %d trees into estate ID %s, %d rows rejected", report.Imported, estate.ID, report.Failed)
+	return c.JSON(http.StatusOK, report)
+}
+
+// importUnoccupied stores a batch that failed because some of its plots were
+// planted since the import began. The plots are read again, the trees on them
+// are rejected, and the rest of the batch is stored, until no tree conflicts.
+// It returns the trees stored, or the trees left and their rows on error.
+func (h *TreeHandler) importUnoccupied(estateID uuid.UUID, trees []*models.Tree, rows []int, measuredAt time.Time,
+	report *models.TreeImportReport) ([]*models.Tree, []int, error) {
+	err := repositories.ErrPlotOccupied
+	for errors.Is(err, repositories.ErrPlotOccupied) {
+		occupied, readErr := h.TreeRepo.GetTreesByEstateID(estateID)
+		if readErr != nil {
+			return trees, rows, readErr
+		}
+		free := make([]*models.Tree, 0, len(trees))
+		freeRows := make([]int, 0, len(rows))
+		for i, tree := range trees {
+			if _, ok := occupied[fmt.Sprintf("%d,%d", tree.X, tree.Y)]; ok {
+				logrus.Warnf("Plot x=%d, y=%d of estate ID %s was planted during the import", tree.X, tree.Y, estateID)
+				rejectImportRow(report, rows[i], msgPlotOccupied)
+				continue
+			}
+			free = append(free, tree)
+			freeRows = append(freeRows, rows[i])
+		}
+		if len(free) == len(trees) {
+			// The conflicting tree is gone again; nothing left to take out
+			return trees, rows, err
+		}
+		trees, rows = free, freeRows
+		err = h.TreeRepo.ImportTrees(trees, measuredAt)
+	}
+	return trees, rows, err
+}
+
+// importSpecies loads the species used by the imported rows, by code. Unknown
+// codes are left out.
+func (h *TreeHandler) importSpecies(rows []importRow) (map[string]*models.Species, error) {
+	bySpecies := map[string]*models.Species{}
+	used := false
+	for _, row := range rows {
+		if row.tree != nil && row.tree.Species != "" {
+			used = true
+			break
+		}
+	}
+	if !used {
+		return bySpecies, nil
+	}
+	all, err := h.SpeciesRepo.ListSpecies()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range all {
+		bySpecies[s.Code] = s
+	}
+	return bySpecies, nil
+}
+
+// validateImportRow applies the AddTreeToEstate rules to an imported row and
+// marks its plot as used. It returns the reason the row is rejected, if any.
+func validateImportRow(estate *models.Estate, row importRow, species map[string]*models.Species,
+	occupied map[string]int, inFile map[string]bool, now time.Time) string {
+	if row.err != "" {
+		return row.err
+	}
+	tree := row.tree
+	treeSpecies := species[tree.Species]
+	if tree.Species != "" && treeSpecies == nil {
+		return "Unknown species"
+	}
+	if err := validateTree(tree, treeSpecies, now); err != nil {
+		return err.Error()
+	}
+	if tree.X > estate.Width || tree.Y > estate.Length {
+		return "Tree coordinates out of bounds"
+	}
+	key := fmt.Sprintf("%d,%d", tree.X, tree.Y)
+	if _, ok := occupied[key]; ok {
+		return msgPlotOccupied
+	}
+	if inFile[key] {
+		return "Duplicate plot in file"
+	}
+	inFile[key] = true
+	return ""
+}
+
+// rejectImportRow counts a rejected row and lists it while the report has room.
+func rejectImportRow(report *models.TreeImportReport, row int, message string) {
+	report.Failed++
+	if len(report.Errors) >= maxReportedImportErrors {
+		report.Truncated = true
+		return
+	}
+	report.Errors = append(report.Errors, models.TreeImportError{Row: row, Message: message})
+}
+
+// readCSVTrees reads trees from a CSV file whose header names the x, y and
+// height columns, in any order. Rows with unreadable values are kept with an error.
+func readCSVTrees(body io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("CSV file must start with a header row")
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.TrimPrefix(name, "\ufeff") // Byte order mark written by spreadsheet tools
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range importColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the %s column", name)
+		}
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if len(rows) >= maxImportRows {
+			return nil, fmt.Errorf("Import is limited to %d rows", maxImportRows)
+		}
+		if err != nil {
+			// Quoting errors only affect the current record
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, errors.New("Invalid CSV file")
+			}
+			rows = append(rows, importRow{err: "Invalid CSV row"})
+			continue
+		}
+		rows = append(rows, parseCSVTree(record, columns))
+	}
+	return rows, nil
+}
+
+// parseCSVTree reads the x, y and height values of a CSV record, and the
+// species and planting date when the file has those columns.
+func parseCSVTree(record []string, columns map[string]int) importRow {
+	values := make([]int, len(importColumns))
+	for j, name := range importColumns {
+		i := columns[name]
+		if i >= len(record) {
+			return importRow{err: fmt.Sprintf("Missing %s value", name)}
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(record[i]))
+		if err != nil {
+			return importRow{err: fmt.Sprintf("Invalid %s value", name)}
+		}
+		values[j] = value
+	}
+	tree := &models.Tree{X: values[0], Y: values[1], Height: values[2]}
+
+	if i, ok := columns[importSpeciesColumn]; ok && i < len(record) {
+		tree.Species = strings.TrimSpace(record[i])
+	}
+	if i, ok := columns[importPlantedOnColumn]; ok && i < len(record) {
+		if value := strings.TrimSpace(record[i]); value != "" {
+			plantedOn, err := models.ParseDate(value)
+			if err != nil {
+				return importRow{err: fmt.Sprintf("Invalid %s value", importPlantedOnColumn)}
+			}
+			tree.PlantedOn = &plantedOn
+		}
+	}
+	return importRow{tree: tree}
+}
+
+// readJSONTrees reads trees from a JSON array. Elements that are not trees are
+// kept with an error.
+func readJSONTrees(body io.Reader) ([]importRow, error) {
+	decoder := json.NewDecoder(body)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, errors.New("JSON body must be an array of trees")
+	}
+
+	var rows []importRow
+	for decoder.More() {
+		if len(rows) >= maxImportRows {
+			return nil, fmt.Errorf("Import is limited to %d rows", maxImportRows)
+		}
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, errors.New("Invalid JSON array")
+		}
+		tree := &models.Tree{}
+		if err := json.Unmarshal(raw, tree); err != nil {
+			rows = append(rows, importRow{err: "Invalid tree"})
+			continue
+		}
+		rows = append(rows, importRow{tree: tree})
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, errors.New("Invalid JSON array")
+	}
+	return rows, nil
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"sawitpro-recruitment/mocks"
+	"sawitpro-recruitment/models"
+	"sawitpro-recruitment/repositories"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreeHandler_ImportTrees_CSV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	csv := "height,x,y\n" +
+		"10,1,1\n" + // valid
+		"12,2,1\n" + // valid
+		"15,6,1\n" + // out of bounds
+		"11,1,1\n" + // duplicate of row 1
+		"9,3,3\n" + // already planted
+		"abc,4,4\n" + // not a number
+		"31,4,4\n" // too tall
+	c, rec := newEstateRequest(http.MethodPost, "/tree/import", estateID, "", csv)
+	c.Request().Header.Set(echo.HeaderContentType, "text/csv")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreesByEstateID(estateID).Return(map[string]int{"3,3": 7}, nil)
+	mockTreeRepo.EXPECT().ImportTrees(gomock.Any(), gomock.Any()).DoAndReturn(func(trees []*models.Tree, _ interface{}) error {
+		if assert.Len(t, trees, 2) {
+			assert.Equal(t, 1, trees[0].X)
+			assert.Equal(t, 10, trees[0].Height)
+			assert.Equal(t, estateID, trees[1].EstateID)
+		}
+		return nil
+	})
+
+	if assert.NoError(t, handler.ImportTrees(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var report models.TreeImportReport
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		assert.Equal(t, 2, report.Imported)
+		assert.Equal(t, 5, report.Failed)
+		assert.Equal(t, []models.TreeImportError{
+			{Row: 3, Message: "Tree coordinates out of bounds"},
+			{Row: 4, Message: "Duplicate plot in file"},
+			{Row: 5, Message: "A tree already exists at this location"},
+			{Row: 6, Message: "Invalid height value"},
+			{Row: 7, Message: "Invalid tree coordinates or height"},
+		}, report.Errors)
+	}
+}
+
+func TestTreeHandler_ImportTrees_JSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	body := `[{"x": 1, "y": 1, "height": 10}, {"x": "one", "y": 1, "height": 10}]`
+	c, rec := newEstateRequest(http.MethodPost, "/tree/import", estateID, "", body)
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreesByEstateID(estateID).Return(map[string]int{}, nil)
+	mockTreeRepo.EXPECT().ImportTrees(gomock.Len(1), gomock.Any()).Return(nil)
+
+	if assert.NoError(t, handler.ImportTrees(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var report models.TreeImportReport
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		assert.Equal(t, 1, report.Imported)
+		assert.Equal(t, []models.TreeImportError{{Row: 2, Message: "Invalid tree"}}, report.Errors)
+	}
+}
+
+func TestTreeHandler_ImportTrees_AtomicRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodPost, "/tree/import", estateID, "atomic=true", "x,y,height\n1,1,10\n9,9,10\n")
+	c.Request().Header.Set(echo.HeaderContentType, "text/csv")
+
+	// Nothing is stored when one row is invalid
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreesByEstateID(estateID).Return(map[string]int{}, nil)
+
+	if assert.NoError(t, handler.ImportTrees(c)) {
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		var report models.TreeImportReport
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		assert.Equal(t, 0, report.Imported)
+		assert.Equal(t, 1, report.Failed)
+	}
+}
+
+func TestTreeHandler_ImportTrees_BatchFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodPost, "/tree/import", estateID, "", "x,y,height\n1,1,10\n2,1,10\n")
+	c.Request().Header.Set(echo.HeaderContentType, "text/csv")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreesByEstateID(estateID).Return(map[string]int{}, nil)
+	mockTreeRepo.EXPECT().ImportTrees(gomock.Any(), gomock.Any()).Return(errors.New("copy error"))
+
+	if assert.NoError(t, handler.ImportTrees(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var report models.TreeImportReport
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		assert.Equal(t, 0, report.Imported)
+		assert.Equal(t, 2, report.Failed)
+		assert.Equal(t, msgImportStoreFailed, report.Errors[0].Message)
+	}
+}
+
+func TestTreeHandler_ImportTrees_PlotPlantedDuringImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodPost, "/tree/import", estateID, "", "x,y,height\n1,1,10\n2,1,10\n3,1,10\n")
+	c.Request().Header.Set(echo.HeaderContentType, "text/csv")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreesByEstateID(estateID).Return(map[string]int{}, nil)
+	// Plot (2,1) is planted after the file was validated; the rest of the batch is stored again
+	gomock.InOrder(
+		mockTreeRepo.EXPECT().ImportTrees(gomock.Len(3), gomock.Any()).Return(repositories.ErrPlotOccupied),
+		mockTreeRepo.EXPECT().GetTreesByEstateID(estateID).Return(map[string]int{"2,1": 12}, nil),
+		mockTreeRepo.EXPECT().ImportTrees(gomock.Len(2), gomock.Any()).DoAndReturn(func(trees []*models.Tree, _ time.Time) error {
+			assert.Equal(t, 1, trees[0].X)
+			assert.Equal(t, 3, trees[1].X)
+			return nil
+		}),
+	)
+
+	if assert.NoError(t, handler.ImportTrees(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var report models.TreeImportReport
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		assert.Equal(t, 2, report.Imported)
+		assert.Equal(t, 1, report.Failed)
+		assert.Equal(t, []models.TreeImportError{{Row: 2, Message: msgPlotOccupied}}, report.Errors)
+	}
+}
+
+func TestTreeHandler_ImportTrees_MissingColumn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodPost, "/tree/import", estateID, "", "x,y\n1,1\n")
+	c.Request().Header.Set(echo.HeaderContentType, "text/csv")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+
+	if assert.NoError(t, handler.ImportTrees(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var response map[string]string
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "CSV header is missing the height column", response["message"])
+	}
+}
+
+func TestTreeHandler_ImportTrees_UnsupportedContentType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodPost, "/tree/import", estateID, "", "1,1,10")
+	c.Request().Header.Set(echo.HeaderContentType, "text/plain")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+
+	if assert.NoError(t, handler.ImportTrees(c)) {
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	}
+}
+
+func TestTreeHandler_ImportTrees_CSVSpecies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockSpeciesRepo := mocks.NewMockSpeciesRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+	handler.SpeciesRepo = mockSpeciesRepo
+
+	estateID := uuid.New()
+	csv := "x,y,height,species,planted_on\n" +
+		"1,1,10,tenera,2012-05-01\n" + // valid
+		"2,1,12,,\n" + // valid, no species
+		"3,1,28,tenera,\n" + // too tall for the species
+		"4,1,10,oak,\n" + // unknown species
+		"5,1,10,dura,May 2012\n" // unreadable date
+	c, rec := newEstateRequest(http.MethodPost, "/tree/import", estateID, "", csv)
+	c.Request().Header.Set(echo.HeaderContentType, "text/csv")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 5}, nil)
+	mockTreeRepo.EXPECT().GetTreesByEstateID(estateID).Return(map[string]int{}, nil)
+	mockSpeciesRepo.EXPECT().ListSpecies().Return([]*models.Species{
+		{Code: "dura", MinHeight: 1, MaxHeight: 30},
+		{Code: "tenera", MinHeight: 1, MaxHeight: 25},
+	}, nil)
+	mockTreeRepo.EXPECT().ImportTrees(gomock.Any(), gomock.Any()).DoAndReturn(func(trees []*models.Tree, _ interface{}) error {
+		if assert.Len(t, trees, 2) {
+			assert.Equal(t, "tenera", trees[0].Species)
+			if assert.NotNil(t, trees[0].PlantedOn) {
+				assert.Equal(t, "2012-05-01", trees[0].PlantedOn.String())
+			}
+			assert.Empty(t, trees[1].Species)
+			assert.Nil(t, trees[1].PlantedOn)
+		}
+		return nil
+	})
+
+	if assert.NoError(t, handler.ImportTrees(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var report models.TreeImportReport
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		assert.Equal(t, 2, report.Imported)
+		assert.Equal(t, []models.TreeImportError{
+			{Row: 3, Message: "Tree height must be between 1 and 25 for species tenera"},
+			{Row: 4, Message: "Unknown species"},
+			{Row: 5, Message: "Invalid planted_on value"},
+		}, report.Errors)
+	}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sawitpro-recruitment/models"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxNearbyRadius = 100 // Largest radius accepted, in plots
+	maxNearbyTrees  = 100 // Largest number of nearest trees accepted
+)
+
+// GetNearbyTrees lists the trees around a point of an estate
+// @Summary List the trees near a point
+// @Description List the trees of an estate within a radius of a point, or the k trees nearest to it, nearest first. Distances are straight lines, in plots and in meters. A tree standing on the point itself is included at distance 0.
+// @Tags trees
+// @Produce json
+// @Param id path string true "Estate ID"
+// @Param x query int true "X coordinate of the point"
+// @Param y query int true "Y coordinate of the point"
+// @Param radius query int false "Radius in plots, at most 100; exclusive with k"
+// @Param k query int false "Number of nearest trees, at most 100; exclusive with radius"
+// @Success 200 {object} map[string][]models.TreeNeighbour
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/trees/nearby [get]
+func (h *TreeHandler) GetNearbyTrees(c echo.Context) error {
+	params := map[string]*int{}
+	for _, name := range []string{"x", "y", "radius", "k"} {
+		value, err := optionalIntParam(c, name)
+		if err != nil {
+			logrus.Warnf("Invalid nearby trees query: %v", err)
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"message": err.Error(),
+			})
+		}
+		params[name] = value
+	}
+	x, y, radius, k := params["x"], params["y"], params["radius"], params["k"]
+
+	var err error
+	switch {
+	case x == nil || y == nil:
+		err = fmt.Errorf("x and y are required")
+	case (radius == nil) == (k == nil):
+		err = fmt.Errorf("exactly one of radius or k is required")
+	case radius != nil && (*radius < 1 || *radius > maxNearbyRadius):
+		err = fmt.Errorf("radius must be between 1 and %d", maxNearbyRadius)
+	case k != nil && (*k < 1 || *k > maxNearbyTrees):
+		err = fmt.Errorf("k must be between 1 and %d", maxNearbyTrees)
+	}
+	if err != nil {
+		logrus.Warnf("Invalid nearby trees query: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+	if *x < 1 || *y < 1 || *x > estate.Width || *y > estate.Length {
+		logrus.Warnf("Nearby trees point (%d, %d) out of bounds for estate ID %s", *x, *y, estate.ID)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": "Point out of bounds",
+		})
+	}
+
+	var neighbours []*models.TreeNeighbour
+	if radius != nil {
+		neighbours, err = h.TreeRepo.GetTreesWithin(estate.ID, *x, *y, *radius)
+	} else {
+		neighbours, err = h.TreeRepo.GetNearestTrees(estate.ID, *x, *y, *k)
+	}
+	if err != nil {
+		logrus.Errorf("Failed to get trees near (%d, %d) for estate ID %s: %v", *x, *y, estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while fetching trees",
+		})
+	}
+
+	logrus.Infof("Retrieved %d trees near (%d, %d) for estate ID %s", len(neighbours), *x, *y, estate.ID)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"trees": neighbours,
+	})
+}
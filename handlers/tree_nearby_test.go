@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"sawitpro-recruitment/mocks"
+	"sawitpro-recruitment/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreeHandler_GetNearbyTrees_Radius(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/trees/nearby", estateID, "x=5&y=5&radius=2", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 10}, nil)
+	mockTreeRepo.EXPECT().GetTreesWithin(estateID, 5, 5, 2).Return([]*models.TreeNeighbour{
+		{Tree: models.Tree{X: 6, Y: 6, Height: 10}, DistancePlots: 1.41, DistanceMeters: 14.14},
+	}, nil)
+
+	if assert.NoError(t, handler.GetNearbyTrees(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response map[string][]map[string]interface{}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		if assert.Len(t, response["trees"], 1) {
+			// The tree fields and its distance sit side by side
+			assert.Equal(t, 6.0, response["trees"][0]["x"])
+			assert.Equal(t, 14.14, response["trees"][0]["distance_meters"])
+		}
+	}
+}
+
+func TestTreeHandler_GetNearbyTrees_Nearest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/trees/nearby", estateID, "x=1&y=1&k=3", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 10}, nil)
+	mockTreeRepo.EXPECT().GetNearestTrees(estateID, 1, 1, 3).Return([]*models.TreeNeighbour{}, nil)
+
+	if assert.NoError(t, handler.GetNearbyTrees(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"trees": []}`, rec.Body.String())
+	}
+}
+
+func TestTreeHandler_GetNearbyTrees_InvalidQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	for _, query := range []string{
+		"y=1&k=3",
+		"x=1&y=1",
+		"x=1&y=1&k=3&radius=2",
+		"x=1&y=1&radius=0",
+		"x=1&y=1&radius=101",
+		"x=1&y=1&k=0",
+		"x=one&y=1&k=3",
+	} {
+		c, rec := newEstateRequest(http.MethodGet, "/trees/nearby", uuid.New(), query, "")
+
+		if assert.NoError(t, handler.GetNearbyTrees(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	}
+}
+
+func TestTreeHandler_GetNearbyTrees_OutOfBounds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/trees/nearby", estateID, "x=11&y=1&k=3", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 10}, nil)
+
+	if assert.NoError(t, handler.GetNearbyTrees(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Point out of bounds")
+	}
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+	"sawitpro-recruitment/heatmap"
+	"sawitpro-recruitment/models"
+	"sawitpro-recruitment/reports"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// Report formats.
+const (
+	reportFormatHTML = "html"
+	reportFormatPDF  = "pdf"
+)
+
+// reportMonthLayout is the layout of the month query parameter.
+const reportMonthLayout = "2006-01"
+
+// maxReportAnomalies is the number of open anomalies listed in a report.
+const maxReportAnomalies = 50
+
+// GetEstateReport renders the monthly report of an estate
+// @Summary Get the monthly report of an estate
+// @Description Render the report of an estate for a month as a standalone HTML page or an A4 PDF: its dimensions, tree height stats and histogram, health, height heatmap, the distance of a drone survey over every plot, and the open anomalies. Heights are as of the end of the month, or of now for the current month; anomalies are those open when the report is generated.
+// @Tags estates
+// @Produce text/html
+// @Produce application/pdf
+// @Param id path string true "Estate ID"
+// @Param format query string false "Report format: html (default) or pdf"
+// @Param month query string false "Month of the report (YYYY-MM), the current month by default"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/report [get]
+func (h *TreeHandler) GetEstateReport(c echo.Context) error {
+	format := c.QueryParam("format")
+	if format == "" {
+		format = reportFormatHTML
+	}
+	var err error
+	if format != reportFormatHTML && format != reportFormatPDF {
+		err = fmt.Errorf("format must be html or pdf")
+	}
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if raw := c.QueryParam("month"); err == nil && raw != "" {
+		requested, parseErr := time.Parse(reportMonthLayout, raw)
+		switch {
+		case parseErr != nil:
+			err = fmt.Errorf("invalid month value, expected YYYY-MM")
+		case requested.After(month):
+			err = fmt.Errorf("month must not be in the future")
+		default:
+			month = requested
+		}
+	}
+	if err != nil {
+		logrus.Warnf("Invalid report parameters: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+
+	report, err := h.estateReport(estate, month, now)
+	if err != nil {
+		logrus.Errorf("Failed to assemble the report of estate ID %s for %s: %v", estate.ID, month.Format(reportMonthLayout), err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Database error while assembling the report",
+		})
+	}
+
+	var body bytes.Buffer
+	contentType := echo.MIMETextHTMLCharsetUTF8
+	if format == reportFormatPDF {
+		contentType = "application/pdf"
+		err = report.WritePDF(&body)
+	} else {
+		err = report.WriteHTML(&body)
+	}
+	if err != nil {
+		logrus.Errorf("Failed to render the report of estate ID %s: %v", estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Failed to render report",
+		})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="%s.%s"`, report.Filename(), format))
+	logrus.Infof("Rendered %s report of estate ID %s for %s", format, estate.ID, month.Format(reportMonthLayout))
+	return c.Blob(http.StatusOK, contentType, body.Bytes())
+}
+
+// estateReport assembles the report of an estate for the month starting on
+// month. Heights are read as of the end of the month, unless it has not ended.
+func (h *TreeHandler) estateReport(estate *models.Estate, month, now time.Time) (*reports.Report, error) {
+	var before *time.Time
+	if end := month.AddDate(0, 1, 0); !end.After(now) {
+		before = &end
+	}
+
+	options := models.StatsOptions{Percentiles: models.DefaultStatsPercentiles, BucketWidth: models.DefaultHistogramBucketWidth}
+	var heights *models.HeightStats
+	var health, treeHeights map[string]int
+	var err error
+	if before != nil {
+		heights, err = h.EstateRepo.GetEstateStatsAsOf(estate.ID, *before, nil, options)
+		if err == nil {
+			health, err = h.EstateRepo.GetHealthCountsAsOf(estate.ID, *before, nil)
+		}
+		if err == nil {
+			treeHeights, err = h.TreeRepo.GetTreeHeightsAsOf(estate.ID, *before)
+		}
+	} else {
+		heights, err = h.EstateRepo.GetEstateStats(estate.ID, nil, options)
+		if err == nil {
+			health, err = h.EstateRepo.GetHealthCounts(estate.ID, nil)
+		}
+		if err == nil {
+			treeHeights, err = h.TreeRepo.GetTreesByEstateID(estate.ID)
+		}
+	}
+	var anomalies *models.AnomalyList
+	if err == nil {
+		anomalies, err = h.AnomalyRepo.ListAnomalies(estate.ID, models.AnomalyQuery{Status: models.AnomalyStatusOpen, Limit: maxReportAnomalies})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	layout, err := heatmap.NewLayout(estate.Width, estate.Length, 0, 0, heatmap.MaxCells)
+	if err != nil {
+		return nil, err
+	}
+	m := heatmap.New(layout)
+	for key, height := range treeHeights {
+		var x, y int
+		if _, err := fmt.Sscanf(key, "%d,%d", &x, &y); err == nil {
+			m.Add(&models.Tree{X: x, Y: y, Height: height})
+		}
+	}
+
+	area := float64(estate.Width*estate.Length) * models.PlotAreaHectares
+	plan := surveyPlan(estate, treeHeights, 0)
+	return &reports.Report{
+		Estate:      estate,
+		Month:       month,
+		GeneratedAt: now,
+		Stats: &models.EstateStats{
+			HeightStats:  *heights,
+			Health:       health,
+			AreaHectares: math.Round(area*100) / 100,
+		},
+		Survey:    reports.Survey{Distance: plan["distance"].(int), Plots: estate.Width * estate.Length},
+		Heatmap:   m,
+		Anomalies: anomalies,
+	}, nil
+}
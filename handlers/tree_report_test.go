@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"sawitpro-recruitment/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/ledongthuc/pdf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreeHandler_GetEstateReport_PastMonth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, mockTreeRepo, mockEstateRepo, mockAnomalyRepo := newAnomalyHandler(ctrl)
+	estateID := uuid.New()
+	estate := &models.Estate{ID: estateID, Width: 4, Length: 2, Code: "RIAU-01"}
+	options := models.StatsOptions{Percentiles: models.DefaultStatsPercentiles, BucketWidth: models.DefaultHistogramBucketWidth}
+	endOfMonth := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	// A past month is reported as of its end
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(estate, nil)
+	mockEstateRepo.EXPECT().GetEstateStatsAsOf(estateID, endOfMonth, gomock.Nil(), options).Return(&models.HeightStats{
+		Count: 2, Min: 10, Max: 20, Median: 15, Mean: 15, BucketWidth: 5,
+		Histogram: []models.HistogramBucket{{From: 10, To: 14, Count: 1}, {From: 15, To: 19}, {From: 20, To: 24, Count: 1}},
+	}, nil)
+	mockEstateRepo.EXPECT().GetHealthCountsAsOf(estateID, endOfMonth, gomock.Nil()).Return(map[string]int{models.HealthStatusHealthy: 2}, nil)
+	mockTreeRepo.EXPECT().GetTreeHeightsAsOf(estateID, endOfMonth).Return(map[string]int{"1,1": 10, "4,2": 20}, nil)
+	mockAnomalyRepo.EXPECT().ListAnomalies(estateID, models.AnomalyQuery{Status: models.AnomalyStatusOpen, Limit: maxReportAnomalies}).
+		Return(&models.AnomalyList{
+			Anomalies: []*models.TreeAnomaly{{X: 4, Y: 2, Height: 20, Score: 4.5, Reasons: []models.AnomalyReason{{Code: models.AnomalyReasonCohort, Expected: 25, Score: 4.5}}}},
+			Counts:    map[string]int{models.AnomalyStatusOpen: 1},
+		}, nil)
+
+	c, rec := newEstateRequest(http.MethodGet, "/report", estateID, "month=2024-03", "")
+	if assert.NoError(t, handler.GetEstateReport(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, echo.MIMETextHTMLCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, `attachment; filename="estate-riau-01-report-2024-03.html"`, rec.Header().Get(echo.HeaderContentDisposition))
+		body := rec.Body.String()
+		assert.Contains(t, body, "Estate report for March 2024")
+		// 8 plots of 10 m, climbing 10 m to the first tree, 10 m down past it and 20 m up to the last
+		assert.Contains(t, body, "<dd>120 m (0.12 km)</dd>")
+		assert.Contains(t, body, "cohort (expected 25 m)")
+	}
+}
+
+func TestTreeHandler_GetEstateReport_PDF(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, mockTreeRepo, mockEstateRepo, mockAnomalyRepo := newAnomalyHandler(ctrl)
+	estateID := uuid.New()
+
+	// The current month is reported with the latest heights
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 3, Length: 3}, nil)
+	mockEstateRepo.EXPECT().GetEstateStats(estateID, gomock.Nil(), gomock.Any()).Return(&models.HeightStats{}, nil)
+	mockEstateRepo.EXPECT().GetHealthCounts(estateID, gomock.Nil()).Return(map[string]int{}, nil)
+	mockTreeRepo.EXPECT().GetTreesByEstateID(estateID).Return(map[string]int{}, nil)
+	mockAnomalyRepo.EXPECT().ListAnomalies(estateID, gomock.Any()).Return(&models.AnomalyList{Counts: map[string]int{}}, nil)
+
+	c, rec := newEstateRequest(http.MethodGet, "/report", estateID, "format=pdf", "")
+	if assert.NoError(t, handler.GetEstateReport(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/pdf", rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), time.Now().UTC().Format("2006-01")+".pdf")
+		doc, err := pdf.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+		if assert.NoError(t, err) {
+			assert.NotZero(t, doc.NumPage())
+		}
+	}
+}
+
+func TestTreeHandler_GetEstateReport_InvalidParams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, _, _, _ := newAnomalyHandler(ctrl)
+	estateID := uuid.New()
+	nextMonth := time.Now().UTC().AddDate(0, 1, 0).Format("2006-01")
+
+	for query, message := range map[string]string{
+		"format=docx":        "format must be html or pdf",
+		"month=2024-13":      "invalid month value, expected YYYY-MM",
+		"month=" + nextMonth: "month must not be in the future",
+	} {
+		c, rec := newEstateRequest(http.MethodGet, "/report", estateID, query, "")
+		if assert.NoError(t, handler.GetEstateReport(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+			assert.Contains(t, rec.Body.String(), message, query)
+		}
+	}
+}
+
+func TestTreeHandler_GetEstateReport_DatabaseError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler, _, mockEstateRepo, _ := newAnomalyHandler(ctrl)
+	estateID := uuid.New()
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 3, Length: 3}, nil)
+	mockEstateRepo.EXPECT().GetEstateStatsAsOf(estateID, gomock.Any(), gomock.Nil(), gomock.Any()).Return(nil, errors.New("connection reset"))
+
+	c, rec := newEstateRequest(http.MethodGet, "/report", estateID, "month=2024-03", "")
+	if assert.NoError(t, handler.GetEstateReport(c)) {
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sawitpro-recruitment/models"
+	"sawitpro-recruitment/tiles"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// tileContentTypes are the content types of the tile formats.
+var tileContentTypes = map[string]string{
+	tiles.FormatPNG: "image/png",
+	tiles.FormatMVT: "application/vnd.mapbox-vector-tile",
+}
+
+// GetTile renders a map tile of the trees of an estate
+// @Summary Get a map tile of an estate
+// @Description Render the trees of a georeferenced estate on an XYZ map tile, as a PNG with a dot per tree coloured by height or health, or as a Mapbox Vector Tile with a point per tree in the layer "trees". Tiles are cached on disk until the trees drawn on them change.
+// @Tags trees
+// @Produce image/png
+// @Produce application/vnd.mapbox-vector-tile
+// @Param id path string true "Estate ID"
+// @Param z path int true "Zoom, 13 to 22"
+// @Param x path int true "Tile column"
+// @Param y path string true "Tile row and format, e.g. 12.png or 12.mvt"
+// @Param color query string false "Colour of PNG dots: height (default) or health"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /estate/{id}/tiles/{z}/{x}/{y} [get]
+func (h *TreeHandler) GetTile(c echo.Context) error {
+	tile, format, err := tiles.ParseTile(c.Param("z"), c.Param("x"), c.Param("y"))
+	colorBy := c.QueryParam("color")
+	if colorBy == "" {
+		colorBy = tiles.ColorByHeight
+	}
+	if err == nil && colorBy != tiles.ColorByHeight && colorBy != tiles.ColorByHealth {
+		err = fmt.Errorf("color must be height or health")
+	}
+	if err != nil {
+		logrus.Warnf("Invalid tile parameters: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+
+	// The generation is read before the estate and its trees, so that a tile
+	// rendered from data changed meanwhile is not cached
+	var generation uint64
+	if id, err := uuid.Parse(c.Param("id")); err == nil {
+		generation = h.Tiles.Generation(id)
+	}
+
+	estate, err := findActiveEstate(c, h.EstateRepo)
+	if estate == nil {
+		return err
+	}
+	projection := tiles.NewProjection(estate)
+	if projection == nil {
+		logrus.Warnf("Estate ID %s has no location for map tiles", estate.ID)
+		return c.JSON(http.StatusConflict, map[string]string{
+			"message": "Estate has no location",
+		})
+	}
+
+	contentType := tileContentTypes[format]
+	variant := tiles.Variant(format, colorBy)
+	if data, ok := h.Tiles.Get(estate.ID, tile, variant); ok {
+		return c.Blob(http.StatusOK, contentType, data)
+	}
+
+	renderer := tiles.NewRenderer(tile, format, colorBy, projection)
+	if box, ok := projection.PlotBox(tile, tiles.Margin()); ok {
+		err := h.TreeRepo.ScanTreesInBox(estate.ID, box, func(tree *models.Tree) error {
+			renderer.Add(tree)
+			return nil
+		})
+		if err != nil {
+			logrus.Errorf("Failed to retrieve trees of tile %s for estate ID %s: %v", tile, estate.ID, err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": "Failed to retrieve trees from database",
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Encode(&buf); err != nil {
+		logrus.Errorf("Failed to encode tile %s for estate ID %s: %v", tile, estate.ID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Failed to render tile",
+		})
+	}
+	if err := h.Tiles.Put(estate.ID, tile, variant, generation, buf.Bytes()); err != nil {
+		logrus.Warnf("Failed to cache tile %s for estate ID %s: %v", tile, estate.ID, err)
+	}
+	return c.Blob(http.StatusOK, contentType, buf.Bytes())
+}
+
+// invalidateTrees drops the data cached for an estate after trees on the given
+// plots changed, including the map tiles those trees are drawn on.
+func (h *TreeHandler) invalidateTrees(estate *models.Estate, plots ...models.Plot) {
+	h.Cache.Invalidate(estate.ID)
+	if err := h.Tiles.InvalidatePlots(estate, plots); err != nil {
+		logrus.Warnf("Failed to invalidate map tiles of estate ID %s: %v", estate.ID, err)
+	}
+}
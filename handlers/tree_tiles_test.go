@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"sawitpro-recruitment/mocks"
+	"sawitpro-recruitment/models"
+	"sawitpro-recruitment/tiles"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// Plot (1,1) of an estate at latitude and longitude 0 is on this tile.
+const (
+	equatorTileZ = 22
+	equatorTileX = 1 << 21
+	equatorTileY = 1 << 21
+)
+
+// newTileRequest builds the context of a request for tile z/x/name.
+func newTileRequest(estateID uuid.UUID, z, x int, name, query string) (echo.Context, *httptest.ResponseRecorder) {
+	c, rec := newEstateRequest(http.MethodGet, "/tiles/"+strconv.Itoa(z)+"/"+strconv.Itoa(x)+"/"+name, estateID, query, "")
+	c.SetParamNames("id", "z", "x", "y")
+	c.SetParamValues(estateID.String(), strconv.Itoa(z), strconv.Itoa(x), name)
+	return c, rec
+}
+
+// scanTreesOf returns a ScanTreesInBox stub passing the given trees to the callback.
+func scanTreesOf(trees ...*models.Tree) func(uuid.UUID, models.BoundingBox, func(*models.Tree) error) error {
+	return func(_ uuid.UUID, _ models.BoundingBox, fn func(*models.Tree) error) error {
+		for _, tree := range trees {
+			if err := fn(tree); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestTreeHandler_GetTile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+	handler.Tiles = tiles.NewDiskCache(t.TempDir())
+
+	estateID := uuid.New()
+	estate := &models.Estate{ID: estateID, Width: 10, Length: 10, Location: &models.Location{}}
+	tree := &models.Tree{ID: uuid.New(), EstateID: estateID, X: 1, Y: 1, Height: 12, HealthStatus: models.HealthStatusHealthy}
+
+	// The trees are only read once; the second request is served from the cache
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(estate, nil).Times(2)
+	mockTreeRepo.EXPECT().ScanTreesInBox(estateID, models.BoundingBox{X1: 1, Y1: 1, X2: 1, Y2: 1}, gomock.Any()).
+		DoAndReturn(scanTreesOf(tree))
+
+	var body []byte
+	for i := 0; i < 2; i++ {
+		c, rec := newTileRequest(estateID, equatorTileZ, equatorTileX, strconv.Itoa(equatorTileY)+".png", "color=health")
+		if assert.NoError(t, handler.GetTile(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "image/png", rec.Header().Get(echo.HeaderContentType))
+			if body != nil {
+				assert.Equal(t, body, rec.Body.Bytes())
+			}
+			body = rec.Body.Bytes()
+		}
+	}
+	img, err := png.Decode(bytes.NewReader(body))
+	if assert.NoError(t, err) {
+		assert.Equal(t, 256, img.Bounds().Dx())
+	}
+
+	// A changed tree drops the tiles it is drawn on
+	handler.invalidateTrees(estate, models.Plot{X: 1, Y: 1})
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(estate, nil)
+	mockTreeRepo.EXPECT().ScanTreesInBox(estateID, gomock.Any(), gomock.Any()).DoAndReturn(scanTreesOf())
+	c, rec := newTileRequest(estateID, equatorTileZ, equatorTileX, strconv.Itoa(equatorTileY)+".png", "color=health")
+	if assert.NoError(t, handler.GetTile(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEqual(t, body, rec.Body.Bytes())
+	}
+}
+
+func TestTreeHandler_GetTile_VectorTileOutsideEstate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).
+		Return(&models.Estate{ID: estateID, Width: 10, Length: 10, Location: &models.Location{}}, nil)
+
+	// The tile north of the estate holds no plot, so no trees are read
+	c, rec := newTileRequest(estateID, equatorTileZ, equatorTileX, strconv.Itoa(equatorTileY-1)+".mvt", "")
+	if assert.NoError(t, handler.GetTile(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/vnd.mapbox-vector-tile", rec.Header().Get(echo.HeaderContentType))
+		assert.Zero(t, rec.Body.Len())
+	}
+}
+
+func TestTreeHandler_GetTile_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	for _, tc := range []struct {
+		z           int
+		name, query string
+		message     string
+	}{
+		{equatorTileZ, "12.jpg", "", "tile format must be png or mvt"},
+		{12, "12.png", "", "zoom must be between 13 and 22"},
+		{equatorTileZ, "12.png", "color=species", "color must be height or health"},
+	} {
+		c, rec := newTileRequest(estateID, tc.z, equatorTileX, tc.name, tc.query)
+		if assert.NoError(t, handler.GetTile(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), tc.message)
+		}
+	}
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 10, Length: 10}, nil)
+	c, rec := newTileRequest(estateID, equatorTileZ, equatorTileX, "12.png", "")
+	if assert.NoError(t, handler.GetTile(c)) {
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Estate has no location")
+	}
+}
@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sawitpro-recruitment/models"
+	"sawitpro-recruitment/spreadsheet"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// Response formats of the endpoints with a spreadsheet export.
+const (
+	responseFormatJSON = "json"
+	responseFormatXLSX = "xlsx"
+)
+
+// xlsxParam tells whether a spreadsheet is requested, with format=xlsx or,
+// without a format, an Accept header naming XLSX.
+func xlsxParam(c echo.Context) (bool, error) {
+	switch c.QueryParam("format") {
+	case "":
+		return acceptsXLSX(c), nil
+	case responseFormatJSON:
+		return false, nil
+	case responseFormatXLSX:
+		return true, nil
+	}
+	return false, fmt.Errorf("format must be json or xlsx")
+}
+
+// acceptsXLSX tells whether the Accept header of a request names XLSX.
+func acceptsXLSX(c echo.Context) bool {
+	return strings.Contains(c.Request().Header.Get(echo.HeaderAccept), spreadsheet.ContentType)
+}
+
+// sendXLSX responds with a workbook as an attachment. The workbook is built
+// in memory first, so that a failure can still be answered with an error.
+func sendXLSX(c echo.Context, filename string, write func(wb *spreadsheet.Workbook) error) error {
+	var body bytes.Buffer
+	wb := spreadsheet.NewWorkbook(&body)
+	err := write(wb)
+	if err == nil {
+		err = wb.Close()
+	}
+	if err != nil {
+		logrus.Errorf("Failed to write spreadsheet %s: %v", filename, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"message": "Failed to write spreadsheet",
+		})
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.xlsx"`, filename))
+	return c.Blob(http.StatusOK, spreadsheet.ContentType, body.Bytes())
+}
+
+// writeStatsSummary writes the stats of an estate as a sheet of metrics, a
+// row each.
+func writeStatsSummary(wb *spreadsheet.Workbook, stats *models.EstateStats) error {
+	if err := wb.AddSheet("Summary", "Metric", "Value"); err != nil {
+		return err
+	}
+	rows := [][]interface{}{}
+	if stats.Region != nil && stats.Region.Name != "" {
+		rows = append(rows, []interface{}{"Region", stats.Region.Name})
+	}
+	rows = append(rows,
+		[]interface{}{"Trees", stats.Count},
+		[]interface{}{"Shortest (m)", stats.Min},
+		[]interface{}{"Tallest (m)", stats.Max},
+		[]interface{}{"Median (m)", stats.Median},
+		[]interface{}{"Mean (m)", stats.Mean},
+		[]interface{}{"Standard deviation (m)", stats.StdDev},
+	)
+	for _, p := range stats.Percentiles {
+		rows = append(rows, []interface{}{fmt.Sprintf("P%g (m)", p.P), p.Height})
+	}
+	rows = append(rows,
+		[]interface{}{"Area (ha)", stats.AreaHectares},
+		[]interface{}{"Yield, last 12 months (t/ha)", stats.TonnesPerHectare},
+	)
+	for _, status := range models.HealthStatuses {
+		rows = append(rows, []interface{}{"Trees " + strings.ReplaceAll(status, "_", " "), stats.Health[status]})
+	}
+	for _, row := range rows {
+		if err := wb.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeHistogram writes the height histogram of stats as a sheet.
+func writeHistogram(wb *spreadsheet.Workbook, stats *models.EstateStats) error {
+	if err := wb.AddSheet("Histogram", "From (m)", "To (m)", "Trees"); err != nil {
+		return err
+	}
+	for _, bucket := range stats.Histogram {
+		if err := wb.WriteRow(bucket.From, bucket.To, bucket.Count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeYield writes the yield of an estate as a summary sheet and a sheet of
+// its blocks.
+func writeYield(wb *spreadsheet.Workbook, yield *models.EstateYield) error {
+	if err := wb.AddSheet("Summary", "Metric", "Value"); err != nil {
+		return err
+	}
+	for _, row := range [][]interface{}{
+		{"From", yield.From.Time},
+		{"To", yield.To.Time},
+		{"Block size (plots)", yield.BlockSize},
+		{"Harvests", yield.Harvests},
+		{"Bunches", yield.Bunches},
+		{"Weight (kg)", yield.WeightKg},
+		{"Area (ha)", yield.AreaHectares},
+		{"Yield (t/ha)", yield.TonnesPerHectare},
+	} {
+		if err := wb.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+
+	err := wb.AddSheet("Blocks", "Block X", "Block Y", "First column", "First row", "Last column", "Last row",
+		"Harvests", "Bunches", "Weight (kg)", "Area (ha)", "Yield (t/ha)")
+	if err != nil {
+		return err
+	}
+	for _, b := range yield.Blocks {
+		err := wb.WriteRow(b.BlockX, b.BlockY, b.X1, b.Y1, b.X2, b.Y2,
+			b.Harvests, b.Bunches, b.WeightKg, b.AreaHectares, b.TonnesPerHectare)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDronePlan writes a drone plan as a sheet of metrics. The plan is the
+// JSON response of the drone plan, so that cached plans can be written too.
+func writeDronePlan(wb *spreadsheet.Workbook, plan map[string]interface{}) error {
+	if err := wb.AddSheet("Drone plan", "Metric", "Value"); err != nil {
+		return err
+	}
+	route, ok := plan["route"].(string)
+	if !ok {
+		route = droneRouteSurvey
+	}
+	rows := [][]interface{}{{"Route", route}, {"Distance (m)", plan["distance"]}}
+	if trees, ok := plan["trees"]; ok {
+		rows = append(rows, []interface{}{"Trees visited", trees})
+	}
+	if rest, ok := plan["rest"].(map[string]int); ok {
+		rows = append(rows, []interface{}{"Rests at column", rest["x"]}, []interface{}{"Rests at row", rest["y"]})
+	}
+	for _, row := range rows {
+		if err := wb.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// xlsxTreeWriter writes the tree inventory of an estate as a workbook: a
+// summary of its stats, then the trees as they are streamed, then the stats
+// of each block of plots and the survey drone plan over the trees written.
+// Trees that do not fit in a sheet continue on "Trees 2" and so on.
+//
+// Trees are streamed row by row, as ExportTrees reads them, so the survey
+// plan is flown along with them, without keeping the trees.
+type xlsxTreeWriter struct {
+	wb        *spreadsheet.Workbook
+	estate    *models.Estate
+	blocks    map[[2]int]*blockStats // Stats by block column and row
+	sheets    int                    // Tree sheets started
+	rows      int                    // Trees written to the last tree sheet
+	sheetRows int                    // Trees a sheet holds, below its header
+	plot      int                    // Plots the survey has flown over, up to the last tree
+	climb     int                    // Meters the survey has climbed and descended
+	height    int                    // Height of the survey over the last tree
+}
+
+// xlsxTreeHeader is the header of the tree sheets.
+var xlsxTreeHeader = []string{"ID", "X", "Y", "Height (m)", "Species", "Planted on", "Health"}
+
+// blockStats sums up the trees of a block of plots.
+type blockStats struct {
+	trees, min, max, total, unhealthy int
+}
+
+func newXLSXTreeWriter(w io.Writer, estate *models.Estate, stats *models.EstateStats) (treeExportWriter, error) {
+	out := &xlsxTreeWriter{
+		wb:        spreadsheet.NewWorkbook(w),
+		estate:    estate,
+		blocks:    map[[2]int]*blockStats{},
+		sheetRows: spreadsheet.MaxRows - 1,
+	}
+	if err := writeStatsSummary(out.wb, stats); err != nil {
+		return nil, err
+	}
+	return out, out.addTreeSheet()
+}
+
+// addTreeSheet starts the next sheet of trees.
+func (xw *xlsxTreeWriter) addTreeSheet() error {
+	xw.sheets++
+	xw.rows = 0
+	name := "Trees"
+	if xw.sheets > 1 {
+		name = fmt.Sprintf("Trees %d", xw.sheets)
+	}
+	return xw.wb.AddSheet(name, xlsxTreeHeader...)
+}
+
+func (xw *xlsxTreeWriter) Write(tree *models.Tree) error {
+	var species, plantedOn interface{}
+	if tree.Species != "" {
+		species = tree.Species
+	}
+	if tree.PlantedOn != nil {
+		plantedOn = tree.PlantedOn.Time
+	}
+	// Plots without a tree since the last one are flown over at ground level
+	plot := (tree.Y-1)*xw.estate.Width + tree.X
+	if plot > xw.plot+1 {
+		xw.climb += xw.height
+		xw.height = 0
+	}
+	xw.climb += abs(tree.Height - xw.height)
+	xw.plot, xw.height = plot, tree.Height
+
+	key := [2]int{(tree.X-1)/defaultYieldBlockSize + 1, (tree.Y-1)/defaultYieldBlockSize + 1}
+	block := xw.blocks[key]
+	if block == nil {
+		block = &blockStats{min: tree.Height, max: tree.Height}
+		xw.blocks[key] = block
+	}
+	block.trees++
+	block.min, block.max = min(block.min, tree.Height), max(block.max, tree.Height)
+	block.total += tree.Height
+	if tree.HealthStatus != models.HealthStatusHealthy {
+		block.unhealthy++
+	}
+
+	if xw.rows == xw.sheetRows {
+		if err := xw.addTreeSheet(); err != nil {
+			return err
+		}
+	}
+	xw.rows++
+	return xw.wb.WriteRow(tree.ID.String(), tree.X, tree.Y, tree.Height, species, plantedOn, tree.HealthStatus)
+}
+
+func (xw *xlsxTreeWriter) Close() error {
+	err := xw.wb.AddSheet("Blocks", "Block X", "Block Y", "First column", "First row", "Last column", "Last row",
+		"Trees", "Shortest (m)", "Tallest (m)", "Mean (m)", "Unhealthy trees")
+	if err != nil {
+		return err
+	}
+	// Blocks row by row, as in the yield
+	keys := make([][2]int, 0, len(xw.blocks))
+	for key := range xw.blocks {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][1] != keys[j][1] {
+			return keys[i][1] < keys[j][1]
+		}
+		return keys[i][0] < keys[j][0]
+	})
+	size := defaultYieldBlockSize
+	for _, key := range keys {
+		b := xw.blocks[key]
+		err := xw.wb.WriteRow(key[0], key[1], (key[0]-1)*size+1, (key[1]-1)*size+1,
+			min(key[0]*size, xw.estate.Width), min(key[1]*size, xw.estate.Length),
+			b.trees, b.min, b.max, float64(b.total)/float64(b.trees), b.unhealthy)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The same distance as surveyPlan without a max distance
+	plots := xw.estate.Width * xw.estate.Length
+	if xw.plot < plots {
+		xw.climb += xw.height
+	}
+	plan := map[string]interface{}{"distance": plots*models.PlotSizeMeters + xw.climb}
+	if err := writeDronePlan(xw.wb, plan); err != nil {
+		return err
+	}
+	return xw.wb.Close()
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"sawitpro-recruitment/mocks"
+	"sawitpro-recruitment/models"
+	"sawitpro-recruitment/spreadsheet"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// xlsxSheets returns the XML of the sheets of a workbook by name, in order.
+func xlsxSheets(t *testing.T, data []byte) ([]string, map[string]string) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		parts[f.Name] = string(content)
+	}
+
+	var names []string
+	sheets := map[string]string{}
+	for i, match := range regexp.MustCompile(`<sheet name="([^"]+)"`).FindAllStringSubmatch(parts["xl/workbook.xml"], -1) {
+		names = append(names, match[1])
+		sheets[match[1]] = parts[fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1)]
+	}
+	return names, sheets
+}
+
+func TestEstateHandler_GetEstateStats_XLSX(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockHarvestRepo := mocks.NewMockHarvestRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+	handler.HarvestRepo = mockHarvestRepo
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/stats", estateID, "format=xlsx", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 100, Length: 50}, nil)
+	mockEstateRepo.EXPECT().GetEstateStats(estateID, gomock.Nil(), gomock.Any()).Return(&models.HeightStats{
+		Count: 10, Max: 20, Min: 5, Median: 15.5, Mean: 13.2, StdDev: 4.1,
+		Percentiles: []models.Percentile{{P: 10, Height: 6}, {P: 90, Height: 19.1}},
+		BucketWidth: 5,
+		Histogram:   []models.HistogramBucket{{From: 5, To: 9, Count: 3}, {From: 10, To: 14, Count: 7}},
+	}, nil)
+	mockEstateRepo.EXPECT().GetHealthCounts(estateID, gomock.Nil()).Return(map[string]int{"healthy": 9, "nutrient_deficiency": 1}, nil)
+	mockHarvestRepo.EXPECT().GetYieldTotals(estateID, gomock.Nil(), gomock.Any(), gomock.Any()).Return(&models.YieldTotals{WeightKg: 12500}, nil)
+
+	if assert.NoError(t, handler.GetEstateStats(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, spreadsheet.ContentType, rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, fmt.Sprintf(`attachment; filename="estate-%s-stats.xlsx"`, estateID), rec.Header().Get(echo.HeaderContentDisposition))
+
+		names, sheets := xlsxSheets(t, rec.Body.Bytes())
+		assert.Equal(t, []string{"Summary", "Histogram"}, names)
+		assert.Contains(t, sheets["Summary"], `state="frozen"`)
+		assert.Contains(t, sheets["Summary"], `<t xml:space="preserve">Median (m)</t></is></c><c r="B5" s="0"><v>15.5</v></c>`)
+		assert.Contains(t, sheets["Summary"], `<t xml:space="preserve">P90 (m)</t></is></c><c r="B9" s="0"><v>19.1</v></c>`)
+		assert.Contains(t, sheets["Summary"], `<t xml:space="preserve">Yield, last 12 months (t/ha)</t></is></c><c r="B11" s="0"><v>0.25</v></c>`)
+		assert.Contains(t, sheets["Summary"], `<t xml:space="preserve">Trees nutrient deficiency</t></is></c><c r="B15" s="0"><v>1</v></c>`)
+		assert.Contains(t, sheets["Histogram"], `<row r="3"><c r="A3" s="0"><v>10</v></c><c r="B3" s="0"><v>14</v></c><c r="C3" s="0"><v>7</v></c></row>`)
+	}
+}
+
+func TestEstateHandler_GetEstateYield_XLSX(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockHarvestRepo := mocks.NewMockHarvestRepository(ctrl)
+	handler := NewEstateHandler(mockEstateRepo)
+	handler.HarvestRepo = mockHarvestRepo
+
+	estateID := uuid.New()
+	// A spreadsheet is chosen by the Accept header alone
+	c, rec := newEstateRequest(http.MethodGet, "/yield", estateID, "from=2024-01-01&to=2024-06-30", "")
+	c.Request().Header.Set(echo.HeaderAccept, spreadsheet.ContentType)
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 15, Length: 10}, nil)
+	mockHarvestRepo.EXPECT().GetBlockYields(estateID, gomock.Any(), gomock.Any(), 10).Return([]*models.BlockYield{
+		{BlockX: 2, BlockY: 1, YieldTotals: models.YieldTotals{Harvests: 5, Bunches: 40, WeightKg: 1000}},
+	}, nil)
+
+	if assert.NoError(t, handler.GetEstateYield(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		names, sheets := xlsxSheets(t, rec.Body.Bytes())
+		assert.Equal(t, []string{"Summary", "Blocks"}, names)
+		// 2024-01-01 is day 45292 of Excel, written as a date
+		assert.Contains(t, sheets["Summary"], `<c r="B2" s="2"><v>45292</v></c>`)
+		// The block covers columns 11 to 15 and rows 1 to 10, half a hectare
+		assert.Contains(t, sheets["Blocks"], `<row r="2"><c r="A2" s="0"><v>2</v></c><c r="B2" s="0"><v>1</v></c>`+
+			`<c r="C2" s="0"><v>11</v></c><c r="D2" s="0"><v>1</v></c><c r="E2" s="0"><v>15</v></c><c r="F2" s="0"><v>10</v></c>`+
+			`<c r="G2" s="0"><v>5</v></c><c r="H2" s="0"><v>40</v></c><c r="I2" s="0"><v>1000</v></c>`+
+			`<c r="J2" s="0"><v>0.5</v></c><c r="K2" s="0"><v>2</v></c></row>`)
+	}
+}
+
+func TestDroneHandler_CalculateDronePlan_XLSX(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	handler := NewDroneHandler(mockTreeRepo, mockEstateRepo)
+
+	estateID := uuid.New()
+	c, rec := newEstateRequest(http.MethodGet, "/drone-plan", estateID, "format=xlsx&max_distance=25", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 5, Length: 1}, nil)
+	mockTreeRepo.EXPECT().GetTreesByEstateID(estateID).Return(map[string]int{"2,1": 10}, nil)
+
+	if assert.NoError(t, handler.CalculateDronePlanWithLimit(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		names, sheets := xlsxSheets(t, rec.Body.Bytes())
+		assert.Equal(t, []string{"Drone plan"}, names)
+		assert.Contains(t, sheets["Drone plan"], `<t xml:space="preserve">survey</t>`)
+		// 10 meters to the first plot, then 10 across and 10 up would pass 25
+		assert.Contains(t, sheets["Drone plan"], `<t xml:space="preserve">Distance (m)</t></is></c><c r="B3" s="0"><v>10</v></c>`)
+		assert.Contains(t, sheets["Drone plan"], `<t xml:space="preserve">Rests at column</t></is></c><c r="B4" s="0"><v>2</v></c>`)
+	}
+}
+
+func TestTreeHandler_ExportTrees_XLSX(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTreeRepo := mocks.NewMockTreeRepository(ctrl)
+	mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+	mockHarvestRepo := mocks.NewMockHarvestRepository(ctrl)
+	handler := NewTreeHandler(mockTreeRepo, mockEstateRepo)
+	handler.HarvestRepo = mockHarvestRepo
+
+	estateID := uuid.New()
+	plantedOn, _ := models.ParseDate("2015-03-01")
+	trees := []*models.Tree{
+		{ID: uuid.New(), EstateID: estateID, X: 1, Y: 1, Height: 10, HealthStatus: models.HealthStatusHealthy},
+		{ID: uuid.New(), EstateID: estateID, X: 2, Y: 1, Height: 20, HealthStatus: models.HealthStatusHealthy},
+		{ID: uuid.New(), EstateID: estateID, X: 12, Y: 1, Height: 14, Species: "tenera", PlantedOn: &plantedOn, HealthStatus: models.HealthStatusGanoderma},
+	}
+	c, rec := newEstateRequest(http.MethodGet, "/trees/export", estateID, "format=xlsx", "")
+
+	mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(&models.Estate{ID: estateID, Width: 15, Length: 1}, nil)
+	mockEstateRepo.EXPECT().GetEstateStats(estateID, gomock.Nil(), gomock.Any()).Return(&models.HeightStats{Count: 3, Min: 10, Max: 20}, nil)
+	mockEstateRepo.EXPECT().GetHealthCounts(estateID, gomock.Nil()).Return(map[string]int{"healthy": 2, "ganoderma": 1}, nil)
+	mockHarvestRepo.EXPECT().GetYieldTotals(estateID, gomock.Nil(), gomock.Any(), gomock.Any()).Return(&models.YieldTotals{}, nil)
+	mockTreeRepo.EXPECT().ExportTrees(estateID, gomock.Any()).DoAndReturn(exportTreesOf(trees...))
+
+	if assert.NoError(t, handler.ExportTrees(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, spreadsheet.ContentType, rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "-trees.xlsx")
+
+		names, sheets := xlsxSheets(t, rec.Body.Bytes())
+		assert.Equal(t, []string{"Summary", "Trees", "Blocks", "Drone plan"}, names)
+		assert.Contains(t, sheets["Summary"], `<c r="B2" s="0"><v>3</v></c>`)
+		assert.Contains(t, sheets["Trees"], `<c r="B4" s="0"><v>12</v></c><c r="C4" s="0"><v>1</v></c><c r="D4" s="0"><v>14</v></c>`+
+			`<c r="E4" s="0" t="inlineStr"><is><t xml:space="preserve">tenera</t></is></c><c r="F4" s="2"><v>42064</v></c>`)
+		// Trees in the first 10 columns make the first block
+		assert.Contains(t, sheets["Blocks"], `<row r="2"><c r="A2" s="0"><v>1</v></c><c r="B2" s="0"><v>1</v></c>`+
+			`<c r="C2" s="0"><v>1</v></c><c r="D2" s="0"><v>1</v></c><c r="E2" s="0"><v>10</v></c><c r="F2" s="0"><v>1</v></c>`+
+			`<c r="G2" s="0"><v>2</v></c><c r="H2" s="0"><v>10</v></c><c r="I2" s="0"><v>20</v></c><c r="J2" s="0"><v>15</v></c><c r="K2" s="0"><v>0</v></c></row>`)
+		assert.Contains(t, sheets["Blocks"], `<c r="E3" s="0"><v>15</v></c>`)
+		// 15 plots, climbing 10 m, 10 more, down 20 and up 14 and back down
+		assert.Contains(t, sheets["Drone plan"], `<c r="B3" s="0"><v>218</v></c>`)
+	}
+}
+
+func TestXLSXTreeWriter_SheetFull(t *testing.T) {
+	estateID := uuid.New()
+	var buf bytes.Buffer
+	w, err := newXLSXTreeWriter(&buf, &models.Estate{ID: estateID, Width: 5, Length: 1}, &models.EstateStats{})
+	require.NoError(t, err)
+	// Trees past the rows of a sheet continue on the next one
+	w.(*xlsxTreeWriter).sheetRows = 2
+	for x := 1; x <= 5; x++ {
+		require.NoError(t, w.Write(&models.Tree{ID: uuid.New(), EstateID: estateID, X: x, Y: 1, Height: 10, HealthStatus: models.HealthStatusHealthy}))
+	}
+	require.NoError(t, w.Close())
+
+	names, sheets := xlsxSheets(t, buf.Bytes())
+	assert.Equal(t, []string{"Summary", "Trees", "Trees 2", "Trees 3", "Blocks", "Drone plan"}, names)
+	assert.Contains(t, sheets["Trees 2"], `<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">ID</t></is></c>`)
+	assert.Contains(t, sheets["Trees 2"], `<c r="B3" s="0"><v>4</v></c>`)
+	assert.NotContains(t, sheets["Trees 2"], `<row r="4">`)
+	assert.Contains(t, sheets["Trees 3"], `<c r="B2" s="0"><v>5</v></c>`)
+}
+
+func TestXLSXTreeWriter_SurveyDistance(t *testing.T) {
+	estate := &models.Estate{ID: uuid.New(), Width: 4, Length: 4}
+	// Trees at the end of a row, at the start of the next and none in the last row
+	trees := []*models.Tree{
+		{ID: uuid.New(), X: 4, Y: 1, Height: 5},
+		{ID: uuid.New(), X: 1, Y: 2, Height: 7},
+		{ID: uuid.New(), X: 2, Y: 2, Height: 3},
+		{ID: uuid.New(), X: 4, Y: 3, Height: 12},
+	}
+	heights := map[string]int{}
+	var buf bytes.Buffer
+	w, err := newXLSXTreeWriter(&buf, estate, &models.EstateStats{})
+	require.NoError(t, err)
+	for _, tree := range trees {
+		heights[fmt.Sprintf("%d,%d", tree.X, tree.Y)] = tree.Height
+		require.NoError(t, w.Write(tree))
+	}
+	require.NoError(t, w.Close())
+
+	_, sheets := xlsxSheets(t, buf.Bytes())
+	distance := surveyPlan(estate, heights, 0)["distance"]
+	assert.Contains(t, sheets["Drone plan"], fmt.Sprintf(`<c r="B3" s="0"><v>%d</v></c>`, distance))
+}
+
+func TestXLSXParam_InvalidFormat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewEstateHandler(mocks.NewMockEstateRepository(ctrl))
+	c, rec := newEstateRequest(http.MethodGet, "/stats", uuid.New(), "format=ods", "")
+	if assert.NoError(t, handler.GetEstateStats(c)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "format must be json or xlsx")
+	}
+}
@@ -0,0 +1,239 @@
+// Package heatmap renders the trees of an estate as an image, one cell per
+// plot coloured by tree height, as PNG or SVG.
+//
+// Large estates are downsampled: each cell then covers a square of plots and
+// is coloured by the mean height of the trees in it. Cells without trees are
+// left transparent. A drone route can be drawn over the map.
+package heatmap
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sawitpro-recruitment/models"
+)
+
+// Size limits.
+const (
+	MaxCells     = 2048 // Longest side of a PNG map, in cells
+	MaxSVGCells  = 512  // Longest side of an SVG map, in cells, since each cell is an element
+	MaxCellSize  = 32   // Largest cell, in pixels
+	MaxPixels    = 4096 // Longest side of a map, in pixels
+	targetPixels = 1024 // Longest side of a map when the cell size is chosen
+)
+
+// Levels is the number of colours of the height scale.
+const Levels = 64
+
+// scaleStops are the colours the height scale goes through, from the shortest
+// to the tallest trees (viridis).
+var scaleStops = []color.NRGBA{
+	{0x44, 0x01, 0x54, 0xff},
+	{0x3b, 0x52, 0x8b, 0xff},
+	{0x21, 0x91, 0x8c, 0xff},
+	{0x5e, 0xc9, 0x62, 0xff},
+	{0xfd, 0xe7, 0x25, 0xff},
+}
+
+// Colours of the drone route overlay.
+var (
+	routeColor = color.NRGBA{0xe4, 0x00, 0x2b, 0xff}
+	restColor  = color.NRGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+// Layout places the plots of an estate on a grid of cells.
+type Layout struct {
+	Width, Length int // Estate size, in plots
+	Downsample    int // Plots per cell side
+	Cell          int // Pixels per cell side
+	Columns, Rows int // Grid size, in cells
+}
+
+// NewLayout fits an estate to a grid of at most maxCells cells a side. A zero
+// downsample uses the smallest that fits, a zero cell size the largest that
+// keeps the map within targetPixels.
+func NewLayout(width, length, downsample, cell, maxCells int) (Layout, error) {
+	longest := width
+	if length > longest {
+		longest = length
+	}
+	if downsample == 0 {
+		downsample = (longest + maxCells - 1) / maxCells
+	}
+	layout := Layout{
+		Width:      width,
+		Length:     length,
+		Downsample: downsample,
+		Columns:    (width + downsample - 1) / downsample,
+		Rows:       (length + downsample - 1) / downsample,
+	}
+	cells := (longest + downsample - 1) / downsample
+	if cells > maxCells {
+		return Layout{}, fmt.Errorf("downsample must be at least %d for this estate", (longest+maxCells-1)/maxCells)
+	}
+	if cell == 0 {
+		cell = targetPixels / cells
+		if cell < 1 {
+			cell = 1
+		}
+		if cell > MaxCellSize {
+			cell = MaxCellSize
+		}
+	}
+	if cells*cell > MaxPixels {
+		return Layout{}, fmt.Errorf("cell must be at most %d for this estate", MaxPixels/cells)
+	}
+	layout.Cell = cell
+	return layout, nil
+}
+
+// cellOf returns the index of the cell holding a plot.
+func (l Layout) cellOf(x, y int) int {
+	return (y-1)/l.Downsample*l.Columns + (x-1)/l.Downsample
+}
+
+// center returns the pixel at the center of the cell holding a plot.
+func (l Layout) center(p models.Plot) (float64, float64) {
+	return (float64((p.X-1)/l.Downsample) + 0.5) * float64(l.Cell),
+		(float64((p.Y-1)/l.Downsample) + 0.5) * float64(l.Cell)
+}
+
+// Route is a drone route drawn over the map.
+type Route struct {
+	Path []models.Plot // Plots the drone flies through, in order
+	Rest *models.Plot  // Plot the drone rests at, when the route stops short
+}
+
+// Heatmap holds the heights of the trees placed on a layout.
+type Heatmap struct {
+	Layout
+	Route *Route // Optional overlay
+
+	sums     []float64
+	counts   []int32
+	trees    int
+	min, max int
+}
+
+// New creates an empty heatmap.
+func New(layout Layout) *Heatmap {
+	return &Heatmap{
+		Layout: layout,
+		sums:   make([]float64, layout.Columns*layout.Rows),
+		counts: make([]int32, layout.Columns*layout.Rows),
+	}
+}
+
+// Add places a tree on the map. Trees outside the estate are ignored.
+func (h *Heatmap) Add(tree *models.Tree) {
+	if tree.X < 1 || tree.Y < 1 || tree.X > h.Width || tree.Y > h.Length {
+		return
+	}
+	i := h.cellOf(tree.X, tree.Y)
+	h.sums[i] += float64(tree.Height)
+	h.counts[i]++
+	if h.trees == 0 || tree.Height < h.min {
+		h.min = tree.Height
+	}
+	if h.trees == 0 || tree.Height > h.max {
+		h.max = tree.Height
+	}
+	h.trees++
+}
+
+// Trees returns the number of trees placed on the map.
+func (h *Heatmap) Trees() int {
+	return h.trees
+}
+
+// level returns the colour level of a cell, from 1 for the shortest trees to
+// Levels for the tallest, or 0 for a cell without trees.
+func (h *Heatmap) level(i int) int {
+	if h.counts[i] == 0 {
+		return 0
+	}
+	if h.max == h.min {
+		return 1
+	}
+	mean := h.sums[i] / float64(h.counts[i])
+	return 1 + int(math.Round((mean-float64(h.min))/float64(h.max-h.min)*(Levels-1)))
+}
+
+// levelColor returns the colour of a level of the height scale.
+func levelColor(level int) color.NRGBA {
+	return ScaleColor(float64(level-1) / (Levels - 1))
+}
+
+// ScaleColor returns the colour of the height scale at a fraction of it, from
+// 0 for the shortest trees to 1 for the tallest.
+func ScaleColor(fraction float64) color.NRGBA {
+	t := math.Max(0, math.Min(1, fraction)) * float64(len(scaleStops)-1)
+	i := int(t)
+	if i >= len(scaleStops)-1 {
+		return scaleStops[len(scaleStops)-1]
+	}
+	from, to, f := scaleStops[i], scaleStops[i+1], t-float64(i)
+	mix := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a) + f*(float64(b)-float64(a))))
+	}
+	return color.NRGBA{mix(from.R, to.R), mix(from.G, to.G), mix(from.B, to.B), 0xff}
+}
+
+// segment is a leg of the route between two pixels.
+type segment struct {
+	x1, y1, x2, y2 float64
+}
+
+// routeSegments returns the legs of the route in pixels. Legs falling on one
+// already drawn, as rows do once downsampled, are dropped.
+func (h *Heatmap) routeSegments() []segment {
+	if h.Route == nil || len(h.Route.Path) < 2 {
+		return nil
+	}
+	var segments []segment
+	seen := map[segment]bool{}
+	x1, y1 := h.center(h.Route.Path[0])
+	for _, p := range h.Route.Path[1:] {
+		x2, y2 := h.center(p)
+		s := segment{x1, y1, x2, y2}
+		if (x1 != x2 || y1 != y2) && !seen[s] {
+			seen[s] = true
+			segments = append(segments, s)
+		}
+		x1, y1 = x2, y2
+	}
+	return segments
+}
+
+// lineWidth returns the width of the route, in pixels.
+func (h *Heatmap) lineWidth() int {
+	if h.Cell < 4 {
+		return 1
+	}
+	return h.Cell / 4
+}
+
+// restRadius returns the radius of the rest point marker, in pixels.
+func (h *Heatmap) restRadius() int {
+	if h.Cell < 4 {
+		return 4
+	}
+	return h.Cell
+}
+
+// Legend layout, in pixels.
+const (
+	legendMinWidth = 160 // Narrowest image, so the legend stays readable
+	legendHeight   = 34
+	legendMargin   = 6
+	legendBar      = 10 // Height of the colour bar
+)
+
+// imageSize returns the size of the image: the map with the legend below.
+func (h *Heatmap) imageSize() (int, int) {
+	width := h.Columns * h.Cell
+	if width < legendMinWidth {
+		width = legendMinWidth
+	}
+	return width, h.Rows*h.Cell + legendHeight
+}
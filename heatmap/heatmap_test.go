@@ -0,0 +1,110 @@
+package heatmap
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"sawitpro-recruitment/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLayout(t *testing.T) {
+	// A small estate gets one cell per plot, as large as fits the target size
+	layout, err := NewLayout(100, 40, 0, 0, MaxCells)
+	assert.NoError(t, err)
+	assert.Equal(t, Layout{Width: 100, Length: 40, Downsample: 1, Cell: 10, Columns: 100, Rows: 40}, layout)
+
+	layout, err = NewLayout(3, 2, 0, 0, MaxCells)
+	assert.NoError(t, err)
+	assert.Equal(t, MaxCellSize, layout.Cell)
+
+	// A huge estate is downsampled to fit
+	layout, err = NewLayout(50000, 1200, 0, 0, MaxCells)
+	assert.NoError(t, err)
+	assert.Equal(t, 25, layout.Downsample)
+	assert.Equal(t, 2000, layout.Columns)
+	assert.Equal(t, 48, layout.Rows)
+	assert.Equal(t, 1, layout.Cell)
+
+	_, err = NewLayout(50000, 1200, 10, 0, MaxCells)
+	assert.EqualError(t, err, "downsample must be at least 25 for this estate")
+	_, err = NewLayout(1000, 10, 0, 16, MaxSVGCells)
+	assert.EqualError(t, err, "cell must be at most 8 for this estate")
+}
+
+func TestHeatmap_Downsample(t *testing.T) {
+	layout, err := NewLayout(4, 4, 2, 1, MaxCells)
+	assert.NoError(t, err)
+	h := New(layout)
+	h.Add(&models.Tree{X: 1, Y: 1, Height: 4})
+	h.Add(&models.Tree{X: 2, Y: 2, Height: 10})
+	h.Add(&models.Tree{X: 4, Y: 4, Height: 10})
+	h.Add(&models.Tree{X: 5, Y: 1, Height: 30}) // Outside the estate
+
+	assert.Equal(t, 3, h.Trees())
+	// The first cell holds the mean of 4 m and 10 m, halfway up the scale
+	assert.Equal(t, 1+Levels/2, h.level(0))
+	assert.Equal(t, 0, h.level(1))
+	assert.Equal(t, 0, h.level(2))
+	assert.Equal(t, Levels, h.level(3))
+}
+
+func TestHeatmap_RouteSegments(t *testing.T) {
+	layout, err := NewLayout(4, 4, 2, 10, MaxCells)
+	assert.NoError(t, err)
+	h := New(layout)
+	// Rows 1 and 2 fall on the same cells once downsampled
+	h.Route = &Route{Path: []models.Plot{{X: 1, Y: 1}, {X: 4, Y: 1}, {X: 1, Y: 2}, {X: 4, Y: 2}, {X: 1, Y: 3}, {X: 4, Y: 3}}}
+
+	assert.Equal(t, []segment{{5, 5, 15, 5}, {15, 5, 5, 5}, {15, 5, 5, 15}, {5, 15, 15, 15}}, h.routeSegments())
+}
+
+func TestHeatmap_EncodePNG(t *testing.T) {
+	layout, err := NewLayout(200, 100, 0, 0, MaxCells)
+	assert.NoError(t, err)
+	h := New(layout)
+	h.Add(&models.Tree{X: 1, Y: 1, Height: 3})
+	h.Add(&models.Tree{X: 200, Y: 100, Height: 27})
+
+	var out bytes.Buffer
+	assert.NoError(t, h.EncodePNG(&out))
+	img, err := png.Decode(&out)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1000, img.Bounds().Dx())
+		assert.Equal(t, 500+legendHeight, img.Bounds().Dy())
+		// The legend labels are drawn in the text colour
+		text := 0
+		for y := 500 + legendMargin + legendBar; y < img.Bounds().Dy(); y++ {
+			for x := 0; x < 40; x++ {
+				if r, _, _, _ := img.At(x, y).RGBA(); r == 0x2020 {
+					text++
+				}
+			}
+		}
+		assert.NotZero(t, text)
+	}
+}
+
+func TestHeatmap_EncodeSVG(t *testing.T) {
+	layout, err := NewLayout(4, 1, 1, 5, MaxSVGCells)
+	assert.NoError(t, err)
+	h := New(layout)
+	for x := 1; x <= 3; x++ {
+		h.Add(&models.Tree{X: x, Y: 1, Height: 12})
+	}
+	h.Route = &Route{Path: []models.Plot{{X: 1, Y: 1}, {X: 2, Y: 1}}, Rest: &models.Plot{X: 3, Y: 1}}
+
+	var out bytes.Buffer
+	assert.NoError(t, h.EncodeSVG(&out))
+	svg := out.String()
+	// Cells of one colour in a row make one rectangle
+	assert.Contains(t, svg, `<rect x="0" y="0" width="15" height="5" fill="#440154"/>`)
+	assert.Equal(t, 1, strings.Count(svg, `fill="#440154"`))
+	assert.Contains(t, svg, `d="M2.5 2.5L7.5 2.5"`)
+	assert.Contains(t, svg, "<title>Rest at (3,1)</title>")
+	assert.Contains(t, svg, ">12 m</text>")
+	assert.True(t, strings.HasSuffix(svg, "</svg>\n"))
+}
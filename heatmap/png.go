@@ -0,0 +1,162 @@
+package heatmap
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strconv"
+)
+
+// Palette indexes besides the height levels, 1 to Levels.
+const (
+	transparentIndex = 0
+	routeIndex       = Levels + 1
+	restIndex        = Levels + 2
+	legendIndex      = Levels + 3 // Legend background
+	textIndex        = Levels + 4
+)
+
+// palette returns the colours of a PNG map.
+func palette() color.Palette {
+	p := color.Palette{color.NRGBA{}}
+	for level := 1; level <= Levels; level++ {
+		p = append(p, levelColor(level))
+	}
+	return append(p, routeColor, restColor, color.NRGBA{0xff, 0xff, 0xff, 0xff}, color.NRGBA{0x20, 0x20, 0x20, 0xff})
+}
+
+// glyphs is a pixel font for the legend labels, drawn at glyphScale.
+var glyphs = map[rune][]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", "###", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", ".#.", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	'm': {".....", ".....", "####.", "#.#.#", "#.#.#"},
+}
+
+const (
+	glyphScale = 2
+	glyphRows  = 5
+)
+
+// textWidth returns the width of a label, in pixels.
+func textWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		width += (len(glyphs[r][0]) + 1) * glyphScale
+	}
+	return width - glyphScale
+}
+
+// drawText draws a label with its top left corner at (x, y).
+func drawText(img *image.Paletted, x, y int, text string) {
+	for _, r := range text {
+		glyph := glyphs[r]
+		for row, line := range glyph {
+			for col, c := range line {
+				if c == '#' {
+					fill(img, x+col*glyphScale, y+row*glyphScale, glyphScale, glyphScale, textIndex)
+				}
+			}
+		}
+		x += (len(glyph[0]) + 1) * glyphScale
+	}
+}
+
+// fill paints a rectangle of the image.
+func fill(img *image.Paletted, x, y, width, height int, index uint8) {
+	r := image.Rect(x, y, x+width, y+height).Intersect(img.Rect)
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		row := img.Pix[img.PixOffset(r.Min.X, py):img.PixOffset(r.Max.X, py)]
+		for i := range row {
+			row[i] = index
+		}
+	}
+}
+
+// drawLine draws a line of the given width between two pixels.
+func drawLine(img *image.Paletted, s segment, width int, index uint8) {
+	dx, dy := s.x2-s.x1, s.y2-s.y1
+	steps := int(max(abs(dx), abs(dy)))
+	for i := 0; i <= steps; i++ {
+		t := 0.0
+		if steps > 0 {
+			t = float64(i) / float64(steps)
+		}
+		x, y := int(s.x1+t*dx), int(s.y1+t*dy)
+		fill(img, x-width/2, y-width/2, width, width, index)
+	}
+}
+
+// drawDisc draws a filled circle.
+func drawDisc(img *image.Paletted, cx, cy, radius int, index uint8) {
+	for y := -radius; y <= radius; y++ {
+		for x := -radius; x <= radius; x++ {
+			if x*x+y*y <= radius*radius && image.Pt(cx+x, cy+y).In(img.Rect) {
+				img.SetColorIndex(cx+x, cy+y, index)
+			}
+		}
+	}
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// EncodePNG writes the map as a PNG, with the height scale below it.
+func (h *Heatmap) EncodePNG(w io.Writer) error {
+	return png.Encode(w, h.Image())
+}
+
+// Image draws the map with the height scale below it. Plots without trees are
+// transparent.
+func (h *Heatmap) Image() *image.Paletted {
+	width, height := h.imageSize()
+	img := image.NewPaletted(image.Rect(0, 0, width, height), palette())
+
+	for row := 0; row < h.Rows; row++ {
+		for col := 0; col < h.Columns; col++ {
+			if level := h.level(row*h.Columns + col); level > 0 {
+				fill(img, col*h.Cell, row*h.Cell, h.Cell, h.Cell, uint8(level))
+			}
+		}
+	}
+
+	if h.Route != nil {
+		for _, s := range h.routeSegments() {
+			drawLine(img, s, h.lineWidth(), routeIndex)
+		}
+		if h.Route.Rest != nil {
+			x, y := h.center(*h.Route.Rest)
+			radius := h.restRadius()
+			drawDisc(img, int(x), int(y), radius, routeIndex)
+			drawDisc(img, int(x), int(y), radius/2, restIndex)
+		}
+	}
+
+	// The legend shows the colour of each height, shortest on the left
+	top := h.Rows * h.Cell
+	fill(img, 0, top, width, legendHeight, legendIndex)
+	barWidth := width - 2*legendMargin
+	for x := 0; x < barWidth; x++ {
+		level := 1 + x*(Levels-1)/max(barWidth-1, 1)
+		fill(img, legendMargin+x, top+legendMargin, 1, legendBar, uint8(level))
+	}
+	if h.trees > 0 {
+		labelTop := top + legendMargin + legendBar + 4
+		low, high := strconv.Itoa(h.min)+"m", strconv.Itoa(h.max)+"m"
+		drawText(img, legendMargin, labelTop, low)
+		drawText(img, width-legendMargin-textWidth(high), labelTop, high)
+	}
+	return img
+}
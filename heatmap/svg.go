@@ -0,0 +1,84 @@
+package heatmap
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+)
+
+// hex formats a colour for SVG.
+func hex(c color.NRGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// EncodeSVG writes the map as an SVG, with the height scale below it. Runs of
+// cells of one colour in a row are drawn as one rectangle.
+func (h *Heatmap) EncodeSVG(w io.Writer) error {
+	out := bufio.NewWriter(w)
+	width, height := h.imageSize()
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+
+	fmt.Fprintf(out, `<g shape-rendering="crispEdges">`+"\n")
+	for row := 0; row < h.Rows; row++ {
+		for col := 0; col < h.Columns; {
+			level := h.level(row*h.Columns + col)
+			run := 1
+			for col+run < h.Columns && h.level(row*h.Columns+col+run) == level {
+				run++
+			}
+			if level > 0 {
+				fmt.Fprintf(out, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+					col*h.Cell, row*h.Cell, run*h.Cell, h.Cell, hex(levelColor(level)))
+			}
+			col += run
+		}
+	}
+	fmt.Fprintf(out, "</g>\n")
+
+	if h.Route != nil {
+		if segments := h.routeSegments(); len(segments) > 0 {
+			fmt.Fprintf(out, `<path fill="none" stroke="%s" stroke-width="%d" stroke-linejoin="round" stroke-linecap="round" d="`, hex(routeColor), h.lineWidth())
+			var lastX, lastY float64
+			for i, s := range segments {
+				if i == 0 || s.x1 != lastX || s.y1 != lastY {
+					fmt.Fprintf(out, "M%s %s", number(s.x1), number(s.y1))
+				}
+				fmt.Fprintf(out, "L%s %s", number(s.x2), number(s.y2))
+				lastX, lastY = s.x2, s.y2
+			}
+			fmt.Fprintf(out, "\"/>\n")
+		}
+		if h.Route.Rest != nil {
+			x, y := h.center(*h.Route.Rest)
+			radius := h.restRadius()
+			fmt.Fprintf(out, `<circle cx="%s" cy="%s" r="%d" fill="%s" stroke="%s" stroke-width="%d"><title>Rest at (%d,%d)</title></circle>`+"\n",
+				number(x), number(y), radius*3/4, hex(restColor), hex(routeColor), max(radius/2, 1), h.Route.Rest.X, h.Route.Rest.Y)
+		}
+	}
+
+	// The legend shows the colour of each height, shortest on the left
+	top := h.Rows * h.Cell
+	fmt.Fprintf(out, `<defs><linearGradient id="heights">`)
+	for i, stop := range scaleStops {
+		fmt.Fprintf(out, `<stop offset="%d%%" stop-color="%s"/>`, i*100/(len(scaleStops)-1), hex(stop))
+	}
+	fmt.Fprintf(out, "</linearGradient></defs>\n")
+	fmt.Fprintf(out, `<rect x="0" y="%d" width="%d" height="%d" fill="#ffffff"/>`+"\n", top, width, legendHeight)
+	fmt.Fprintf(out, `<rect x="%d" y="%d" width="%d" height="%d" fill="url(#heights)"/>`+"\n",
+		legendMargin, top+legendMargin, width-2*legendMargin, legendBar)
+	if h.trees > 0 {
+		labelY := top + legendHeight - legendMargin
+		fmt.Fprintf(out, `<text x="%d" y="%d" font-family="sans-serif" font-size="11" fill="#202020">%d m</text>`+"\n", legendMargin, labelY, h.min)
+		fmt.Fprintf(out, `<text x="%d" y="%d" font-family="sans-serif" font-size="11" fill="#202020" text-anchor="end">%d m</text>`+"\n", width-legendMargin, labelY, h.max)
+	}
+
+	fmt.Fprintf(out, "</svg>\n")
+	return out.Flush()
+}
+
+// number formats a pixel coordinate without trailing zeros.
+func number(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repositories/anomaly_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	models "sawitpro-recruitment/models"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockAnomalyRepository is a mock of AnomalyRepository interface.
+type MockAnomalyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnomalyRepositoryMockRecorder
+}
+
+// MockAnomalyRepositoryMockRecorder is the mock recorder for MockAnomalyRepository.
+type MockAnomalyRepositoryMockRecorder struct {
+	mock *MockAnomalyRepository
+}
+
+// NewMockAnomalyRepository creates a new mock instance.
+func NewMockAnomalyRepository(ctrl *gomock.Controller) *MockAnomalyRepository {
+	mock := &MockAnomalyRepository{ctrl: ctrl}
+	mock.recorder = &MockAnomalyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnomalyRepository) EXPECT() *MockAnomalyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetAnomaly mocks base method.
+func (m *MockAnomalyRepository) GetAnomaly(estateID, anomalyID uuid.UUID) (*models.TreeAnomaly, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnomaly", estateID, anomalyID)
+	ret0, _ := ret[0].(*models.TreeAnomaly)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnomaly indicates an expected call of GetAnomaly.
+func (mr *MockAnomalyRepositoryMockRecorder) GetAnomaly(estateID, anomalyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnomaly", reflect.TypeOf((*MockAnomalyRepository)(nil).GetAnomaly), estateID, anomalyID)
+}
+
+// GetCohortHeightCounts mocks base method.
+func (m *MockAnomalyRepository) GetCohortHeightCounts(estateID uuid.UUID) (map[string]map[int]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCohortHeightCounts", estateID)
+	ret0, _ := ret[0].(map[string]map[int]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCohortHeightCounts indicates an expected call of GetCohortHeightCounts.
+func (mr *MockAnomalyRepositoryMockRecorder) GetCohortHeightCounts(estateID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCohortHeightCounts", reflect.TypeOf((*MockAnomalyRepository)(nil).GetCohortHeightCounts), estateID)
+}
+
+// GetHeightDrops mocks base method.
+func (m *MockAnomalyRepository) GetHeightDrops(estateID uuid.UUID) (map[uuid.UUID]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeightDrops", estateID)
+	ret0, _ := ret[0].(map[uuid.UUID]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHeightDrops indicates an expected call of GetHeightDrops.
+func (mr *MockAnomalyRepositoryMockRecorder) GetHeightDrops(estateID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeightDrops", reflect.TypeOf((*MockAnomalyRepository)(nil).GetHeightDrops), estateID)
+}
+
+// ListAnomalies mocks base method.
+func (m *MockAnomalyRepository) ListAnomalies(estateID uuid.UUID, query models.AnomalyQuery) (*models.AnomalyList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAnomalies", estateID, query)
+	ret0, _ := ret[0].(*models.AnomalyList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAnomalies indicates an expected call of ListAnomalies.
+func (mr *MockAnomalyRepositoryMockRecorder) ListAnomalies(estateID, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAnomalies", reflect.TypeOf((*MockAnomalyRepository)(nil).ListAnomalies), estateID, query)
+}
+
+// ReviewAnomaly mocks base method.
+func (m *MockAnomalyRepository) ReviewAnomaly(anomaly *models.TreeAnomaly) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReviewAnomaly", anomaly)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReviewAnomaly indicates an expected call of ReviewAnomaly.
+func (mr *MockAnomalyRepositoryMockRecorder) ReviewAnomaly(anomaly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReviewAnomaly", reflect.TypeOf((*MockAnomalyRepository)(nil).ReviewAnomaly), anomaly)
+}
+
+// SaveAnomalies mocks base method.
+func (m *MockAnomalyRepository) SaveAnomalies(estateID uuid.UUID, anomalies []*models.TreeAnomaly, detectedAt time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAnomalies", estateID, anomalies, detectedAt)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveAnomalies indicates an expected call of SaveAnomalies.
+func (mr *MockAnomalyRepositoryMockRecorder) SaveAnomalies(estateID, anomalies, detectedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAnomalies", reflect.TypeOf((*MockAnomalyRepository)(nil).SaveAnomalies), estateID, anomalies, detectedAt)
+}
@@ -7,11 +7,53 @@ package mocks
 import (
 	reflect "reflect"
 	models "sawitpro-recruitment/models"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
 )
 
+// MockrowScanner is a mock of rowScanner interface.
+type MockrowScanner struct {
+	ctrl     *gomock.Controller
+	recorder *MockrowScannerMockRecorder
+}
+
+// MockrowScannerMockRecorder is the mock recorder for MockrowScanner.
+type MockrowScannerMockRecorder struct {
+	mock *MockrowScanner
+}
+
+// NewMockrowScanner creates a new mock instance.
+func NewMockrowScanner(ctrl *gomock.Controller) *MockrowScanner {
+	mock := &MockrowScanner{ctrl: ctrl}
+	mock.recorder = &MockrowScannerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockrowScanner) EXPECT() *MockrowScannerMockRecorder {
+	return m.recorder
+}
+
+// Scan mocks base method.
+func (m *MockrowScanner) Scan(dest ...interface{}) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range dest {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Scan", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Scan indicates an expected call of Scan.
+func (mr *MockrowScannerMockRecorder) Scan(dest ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockrowScanner)(nil).Scan), dest...)
+}
+
 // MockEstateRepository is a mock of EstateRepository interface.
 type MockEstateRepository struct {
 	ctrl     *gomock.Controller
@@ -35,6 +77,20 @@ func (m *MockEstateRepository) EXPECT() *MockEstateRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ArchiveEstate mocks base method.
+func (m *MockEstateRepository) ArchiveEstate(id uuid.UUID, archivedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveEstate", id, archivedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ArchiveEstate indicates an expected call of ArchiveEstate.
+func (mr *MockEstateRepositoryMockRecorder) ArchiveEstate(id, archivedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveEstate", reflect.TypeOf((*MockEstateRepository)(nil).ArchiveEstate), id, archivedAt)
+}
+
 // CreateEstate mocks base method.
 func (m *MockEstateRepository) CreateEstate(estate *models.Estate) error {
 	m.ctrl.T.Helper()
@@ -49,6 +105,35 @@ func (mr *MockEstateRepositoryMockRecorder) CreateEstate(estate interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEstate", reflect.TypeOf((*MockEstateRepository)(nil).CreateEstate), estate)
 }
 
+// DeleteEstate mocks base method.
+func (m *MockEstateRepository) DeleteEstate(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEstate", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEstate indicates an expected call of DeleteEstate.
+func (mr *MockEstateRepositoryMockRecorder) DeleteEstate(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEstate", reflect.TypeOf((*MockEstateRepository)(nil).DeleteEstate), id)
+}
+
+// GetCohortGrowth mocks base method.
+func (m *MockEstateRepository) GetCohortGrowth(id uuid.UUID, from, to time.Time) ([]models.CohortGrowth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCohortGrowth", id, from, to)
+	ret0, _ := ret[0].([]models.CohortGrowth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCohortGrowth indicates an expected call of GetCohortGrowth.
+func (mr *MockEstateRepositoryMockRecorder) GetCohortGrowth(id, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCohortGrowth", reflect.TypeOf((*MockEstateRepository)(nil).GetCohortGrowth), id, from, to)
+}
+
 // GetEstateByID mocks base method.
 func (m *MockEstateRepository) GetEstateByID(id uuid.UUID) (*models.Estate, error) {
 	m.ctrl.T.Helper()
@@ -65,19 +150,194 @@ func (mr *MockEstateRepositoryMockRecorder) GetEstateByID(id interface{}) *gomoc
 }
 
 // GetEstateStats mocks base method.
-func (m *MockEstateRepository) GetEstateStats(id uuid.UUID) (int, int, int, int, error) {
+func (m *MockEstateRepository) GetEstateStats(id uuid.UUID, region *models.Region, options models.StatsOptions) (*models.HeightStats, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEstateStats", id)
-	ret0, _ := ret[0].(int)
-	ret1, _ := ret[1].(int)
-	ret2, _ := ret[2].(int)
-	ret3, _ := ret[3].(int)
-	ret4, _ := ret[4].(error)
-	return ret0, ret1, ret2, ret3, ret4
+	ret := m.ctrl.Call(m, "GetEstateStats", id, region, options)
+	ret0, _ := ret[0].(*models.HeightStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // GetEstateStats indicates an expected call of GetEstateStats.
-func (mr *MockEstateRepositoryMockRecorder) GetEstateStats(id interface{}) *gomock.Call {
+func (mr *MockEstateRepositoryMockRecorder) GetEstateStats(id, region, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEstateStats", reflect.TypeOf((*MockEstateRepository)(nil).GetEstateStats), id, region, options)
+}
+
+// GetEstateStatsAsOf mocks base method.
+func (m *MockEstateRepository) GetEstateStatsAsOf(id uuid.UUID, before time.Time, region *models.Region, options models.StatsOptions) (*models.HeightStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEstateStatsAsOf", id, before, region, options)
+	ret0, _ := ret[0].(*models.HeightStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEstateStatsAsOf indicates an expected call of GetEstateStatsAsOf.
+func (mr *MockEstateRepositoryMockRecorder) GetEstateStatsAsOf(id, before, region, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEstateStatsAsOf", reflect.TypeOf((*MockEstateRepository)(nil).GetEstateStatsAsOf), id, before, region, options)
+}
+
+// GetEstateStatsBreakdown mocks base method.
+func (m *MockEstateRepository) GetEstateStatsBreakdown(id uuid.UUID) (*models.EstateStatsBreakdown, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEstateStatsBreakdown", id)
+	ret0, _ := ret[0].(*models.EstateStatsBreakdown)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEstateStatsBreakdown indicates an expected call of GetEstateStatsBreakdown.
+func (mr *MockEstateRepositoryMockRecorder) GetEstateStatsBreakdown(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEstateStatsBreakdown", reflect.TypeOf((*MockEstateRepository)(nil).GetEstateStatsBreakdown), id)
+}
+
+// GetEstateTimeseries mocks base method.
+func (m *MockEstateRepository) GetEstateTimeseries(id uuid.UUID, metric string, periods []time.Time, end time.Time) ([]models.TimeseriesPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEstateTimeseries", id, metric, periods, end)
+	ret0, _ := ret[0].([]models.TimeseriesPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEstateTimeseries indicates an expected call of GetEstateTimeseries.
+func (mr *MockEstateRepositoryMockRecorder) GetEstateTimeseries(id, metric, periods, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEstateTimeseries", reflect.TypeOf((*MockEstateRepository)(nil).GetEstateTimeseries), id, metric, periods, end)
+}
+
+// GetHealthCounts mocks base method.
+func (m *MockEstateRepository) GetHealthCounts(id uuid.UUID, region *models.Region) (map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHealthCounts", id, region)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHealthCounts indicates an expected call of GetHealthCounts.
+func (mr *MockEstateRepositoryMockRecorder) GetHealthCounts(id, region interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHealthCounts", reflect.TypeOf((*MockEstateRepository)(nil).GetHealthCounts), id, region)
+}
+
+// GetHealthCountsAsOf mocks base method.
+func (m *MockEstateRepository) GetHealthCountsAsOf(id uuid.UUID, before time.Time, region *models.Region) (map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHealthCountsAsOf", id, before, region)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHealthCountsAsOf indicates an expected call of GetHealthCountsAsOf.
+func (mr *MockEstateRepositoryMockRecorder) GetHealthCountsAsOf(id, before, region interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHealthCountsAsOf", reflect.TypeOf((*MockEstateRepository)(nil).GetHealthCountsAsOf), id, before, region)
+}
+
+// GetPortfolioStats mocks base method.
+func (m *MockEstateRepository) GetPortfolioStats(query models.PortfolioQuery, options models.StatsOptions) (*models.PortfolioStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPortfolioStats", query, options)
+	ret0, _ := ret[0].(*models.PortfolioStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPortfolioStats indicates an expected call of GetPortfolioStats.
+func (mr *MockEstateRepositoryMockRecorder) GetPortfolioStats(query, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPortfolioStats", reflect.TypeOf((*MockEstateRepository)(nil).GetPortfolioStats), query, options)
+}
+
+// GetTreesOutOfBounds mocks base method.
+func (m *MockEstateRepository) GetTreesOutOfBounds(estateID uuid.UUID, width, length, limit int) ([]*models.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTreesOutOfBounds", estateID, width, length, limit)
+	ret0, _ := ret[0].([]*models.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTreesOutOfBounds indicates an expected call of GetTreesOutOfBounds.
+func (mr *MockEstateRepositoryMockRecorder) GetTreesOutOfBounds(estateID, width, length, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreesOutOfBounds", reflect.TypeOf((*MockEstateRepository)(nil).GetTreesOutOfBounds), estateID, width, length, limit)
+}
+
+// ListEstateIDs mocks base method.
+func (m *MockEstateRepository) ListEstateIDs() ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEstateIDs")
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEstateIDs indicates an expected call of ListEstateIDs.
+func (mr *MockEstateRepositoryMockRecorder) ListEstateIDs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEstateIDs", reflect.TypeOf((*MockEstateRepository)(nil).ListEstateIDs))
+}
+
+// ListEstates mocks base method.
+func (m *MockEstateRepository) ListEstates(query models.EstateQuery) (*models.EstatePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEstates", query)
+	ret0, _ := ret[0].(*models.EstatePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEstates indicates an expected call of ListEstates.
+func (mr *MockEstateRepositoryMockRecorder) ListEstates(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEstates", reflect.TypeOf((*MockEstateRepository)(nil).ListEstates), query)
+}
+
+// RebuildHeightSummary mocks base method.
+func (m *MockEstateRepository) RebuildHeightSummary(id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildHeightSummary", id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebuildHeightSummary indicates an expected call of RebuildHeightSummary.
+func (mr *MockEstateRepositoryMockRecorder) RebuildHeightSummary(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildHeightSummary", reflect.TypeOf((*MockEstateRepository)(nil).RebuildHeightSummary), id)
+}
+
+// RestoreEstate mocks base method.
+func (m *MockEstateRepository) RestoreEstate(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreEstate", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreEstate indicates an expected call of RestoreEstate.
+func (mr *MockEstateRepositoryMockRecorder) RestoreEstate(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreEstate", reflect.TypeOf((*MockEstateRepository)(nil).RestoreEstate), id)
+}
+
+// UpdateEstate mocks base method.
+func (m *MockEstateRepository) UpdateEstate(estate *models.Estate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEstate", estate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEstate indicates an expected call of UpdateEstate.
+func (mr *MockEstateRepositoryMockRecorder) UpdateEstate(estate interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEstateStats", reflect.TypeOf((*MockEstateRepository)(nil).GetEstateStats), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEstate", reflect.TypeOf((*MockEstateRepository)(nil).UpdateEstate), estate)
 }
@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repositories/harvest_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	models "sawitpro-recruitment/models"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockHarvestRepository is a mock of HarvestRepository interface.
+type MockHarvestRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockHarvestRepositoryMockRecorder
+}
+
+// MockHarvestRepositoryMockRecorder is the mock recorder for MockHarvestRepository.
+type MockHarvestRepositoryMockRecorder struct {
+	mock *MockHarvestRepository
+}
+
+// NewMockHarvestRepository creates a new mock instance.
+func NewMockHarvestRepository(ctrl *gomock.Controller) *MockHarvestRepository {
+	mock := &MockHarvestRepository{ctrl: ctrl}
+	mock.recorder = &MockHarvestRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHarvestRepository) EXPECT() *MockHarvestRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddHarvests mocks base method.
+func (m *MockHarvestRepository) AddHarvests(harvests []*models.Harvest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddHarvests", harvests)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddHarvests indicates an expected call of AddHarvests.
+func (mr *MockHarvestRepositoryMockRecorder) AddHarvests(harvests interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddHarvests", reflect.TypeOf((*MockHarvestRepository)(nil).AddHarvests), harvests)
+}
+
+// GetBlockYields mocks base method.
+func (m *MockHarvestRepository) GetBlockYields(estateID uuid.UUID, from, to time.Time, blockSize int) ([]*models.BlockYield, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockYields", estateID, from, to, blockSize)
+	ret0, _ := ret[0].([]*models.BlockYield)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockYields indicates an expected call of GetBlockYields.
+func (mr *MockHarvestRepositoryMockRecorder) GetBlockYields(estateID, from, to, blockSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockYields", reflect.TypeOf((*MockHarvestRepository)(nil).GetBlockYields), estateID, from, to, blockSize)
+}
+
+// GetPlotYields mocks base method.
+func (m *MockHarvestRepository) GetPlotYields(estateID uuid.UUID, from, to time.Time, periodDays int) ([]*models.PlotYield, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlotYields", estateID, from, to, periodDays)
+	ret0, _ := ret[0].([]*models.PlotYield)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlotYields indicates an expected call of GetPlotYields.
+func (mr *MockHarvestRepositoryMockRecorder) GetPlotYields(estateID, from, to, periodDays interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlotYields", reflect.TypeOf((*MockHarvestRepository)(nil).GetPlotYields), estateID, from, to, periodDays)
+}
+
+// GetTreeHarvests mocks base method.
+func (m *MockHarvestRepository) GetTreeHarvests(treeID uuid.UUID) ([]*models.Harvest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTreeHarvests", treeID)
+	ret0, _ := ret[0].([]*models.Harvest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTreeHarvests indicates an expected call of GetTreeHarvests.
+func (mr *MockHarvestRepositoryMockRecorder) GetTreeHarvests(treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreeHarvests", reflect.TypeOf((*MockHarvestRepository)(nil).GetTreeHarvests), treeID)
+}
+
+// GetYieldTotals mocks base method.
+func (m *MockHarvestRepository) GetYieldTotals(estateID uuid.UUID, region *models.Region, from, to time.Time) (*models.YieldTotals, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetYieldTotals", estateID, region, from, to)
+	ret0, _ := ret[0].(*models.YieldTotals)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetYieldTotals indicates an expected call of GetYieldTotals.
+func (mr *MockHarvestRepositoryMockRecorder) GetYieldTotals(estateID, region, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetYieldTotals", reflect.TypeOf((*MockHarvestRepository)(nil).GetYieldTotals), estateID, region, from, to)
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repositories/species_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	models "sawitpro-recruitment/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSpeciesRepository is a mock of SpeciesRepository interface.
+type MockSpeciesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpeciesRepositoryMockRecorder
+}
+
+// MockSpeciesRepositoryMockRecorder is the mock recorder for MockSpeciesRepository.
+type MockSpeciesRepositoryMockRecorder struct {
+	mock *MockSpeciesRepository
+}
+
+// NewMockSpeciesRepository creates a new mock instance.
+func NewMockSpeciesRepository(ctrl *gomock.Controller) *MockSpeciesRepository {
+	mock := &MockSpeciesRepository{ctrl: ctrl}
+	mock.recorder = &MockSpeciesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpeciesRepository) EXPECT() *MockSpeciesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateSpecies mocks base method.
+func (m *MockSpeciesRepository) CreateSpecies(species *models.Species) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSpecies", species)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSpecies indicates an expected call of CreateSpecies.
+func (mr *MockSpeciesRepositoryMockRecorder) CreateSpecies(species interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSpecies", reflect.TypeOf((*MockSpeciesRepository)(nil).CreateSpecies), species)
+}
+
+// GetSpecies mocks base method.
+func (m *MockSpeciesRepository) GetSpecies(code string) (*models.Species, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSpecies", code)
+	ret0, _ := ret[0].(*models.Species)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSpecies indicates an expected call of GetSpecies.
+func (mr *MockSpeciesRepositoryMockRecorder) GetSpecies(code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpecies", reflect.TypeOf((*MockSpeciesRepository)(nil).GetSpecies), code)
+}
+
+// ListSpecies mocks base method.
+func (m *MockSpeciesRepository) ListSpecies() ([]*models.Species, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSpecies")
+	ret0, _ := ret[0].([]*models.Species)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSpecies indicates an expected call of ListSpecies.
+func (mr *MockSpeciesRepositoryMockRecorder) ListSpecies() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSpecies", reflect.TypeOf((*MockSpeciesRepository)(nil).ListSpecies))
+}
@@ -7,6 +7,7 @@ package mocks
 import (
 	reflect "reflect"
 	models "sawitpro-recruitment/models"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -35,6 +36,34 @@ func (m *MockTreeRepository) EXPECT() *MockTreeRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddMeasurement mocks base method.
+func (m *MockTreeRepository) AddMeasurement(measurement *models.TreeMeasurement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMeasurement", measurement)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMeasurement indicates an expected call of AddMeasurement.
+func (mr *MockTreeRepositoryMockRecorder) AddMeasurement(measurement interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMeasurement", reflect.TypeOf((*MockTreeRepository)(nil).AddMeasurement), measurement)
+}
+
+// AddObservation mocks base method.
+func (m *MockTreeRepository) AddObservation(observation *models.TreeObservation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddObservation", observation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddObservation indicates an expected call of AddObservation.
+func (mr *MockTreeRepositoryMockRecorder) AddObservation(observation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddObservation", reflect.TypeOf((*MockTreeRepository)(nil).AddObservation), observation)
+}
+
 // AddTreeToEstate mocks base method.
 func (m *MockTreeRepository) AddTreeToEstate(tree *models.Tree) error {
 	m.ctrl.T.Helper()
@@ -49,6 +78,79 @@ func (mr *MockTreeRepositoryMockRecorder) AddTreeToEstate(tree interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTreeToEstate", reflect.TypeOf((*MockTreeRepository)(nil).AddTreeToEstate), tree)
 }
 
+// DeleteTree mocks base method.
+func (m *MockTreeRepository) DeleteTree(estateID, treeID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTree", estateID, treeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTree indicates an expected call of DeleteTree.
+func (mr *MockTreeRepositoryMockRecorder) DeleteTree(estateID, treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTree", reflect.TypeOf((*MockTreeRepository)(nil).DeleteTree), estateID, treeID)
+}
+
+// ExportTrees mocks base method.
+func (m *MockTreeRepository) ExportTrees(estateID uuid.UUID, fn func(*models.Tree) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportTrees", estateID, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportTrees indicates an expected call of ExportTrees.
+func (mr *MockTreeRepositoryMockRecorder) ExportTrees(estateID, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTrees", reflect.TypeOf((*MockTreeRepository)(nil).ExportTrees), estateID, fn)
+}
+
+// GetMeasurements mocks base method.
+func (m *MockTreeRepository) GetMeasurements(treeID uuid.UUID) ([]*models.TreeMeasurement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMeasurements", treeID)
+	ret0, _ := ret[0].([]*models.TreeMeasurement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMeasurements indicates an expected call of GetMeasurements.
+func (mr *MockTreeRepositoryMockRecorder) GetMeasurements(treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMeasurements", reflect.TypeOf((*MockTreeRepository)(nil).GetMeasurements), treeID)
+}
+
+// GetNearestTrees mocks base method.
+func (m *MockTreeRepository) GetNearestTrees(estateID uuid.UUID, x, y, k int) ([]*models.TreeNeighbour, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNearestTrees", estateID, x, y, k)
+	ret0, _ := ret[0].([]*models.TreeNeighbour)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNearestTrees indicates an expected call of GetNearestTrees.
+func (mr *MockTreeRepositoryMockRecorder) GetNearestTrees(estateID, x, y, k interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNearestTrees", reflect.TypeOf((*MockTreeRepository)(nil).GetNearestTrees), estateID, x, y, k)
+}
+
+// GetObservations mocks base method.
+func (m *MockTreeRepository) GetObservations(treeID uuid.UUID) ([]*models.TreeObservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetObservations", treeID)
+	ret0, _ := ret[0].([]*models.TreeObservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObservations indicates an expected call of GetObservations.
+func (mr *MockTreeRepositoryMockRecorder) GetObservations(treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObservations", reflect.TypeOf((*MockTreeRepository)(nil).GetObservations), treeID)
+}
+
 // GetTreeByCoordinates mocks base method.
 func (m *MockTreeRepository) GetTreeByCoordinates(estateID uuid.UUID, x, y int) (*models.Tree, error) {
 	m.ctrl.T.Helper()
@@ -64,6 +166,36 @@ func (mr *MockTreeRepositoryMockRecorder) GetTreeByCoordinates(estateID, x, y in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreeByCoordinates", reflect.TypeOf((*MockTreeRepository)(nil).GetTreeByCoordinates), estateID, x, y)
 }
 
+// GetTreeByID mocks base method.
+func (m *MockTreeRepository) GetTreeByID(estateID, treeID uuid.UUID) (*models.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTreeByID", estateID, treeID)
+	ret0, _ := ret[0].(*models.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTreeByID indicates an expected call of GetTreeByID.
+func (mr *MockTreeRepositoryMockRecorder) GetTreeByID(estateID, treeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreeByID", reflect.TypeOf((*MockTreeRepository)(nil).GetTreeByID), estateID, treeID)
+}
+
+// GetTreeHeightsAsOf mocks base method.
+func (m *MockTreeRepository) GetTreeHeightsAsOf(estateID uuid.UUID, before time.Time) (map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTreeHeightsAsOf", estateID, before)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTreeHeightsAsOf indicates an expected call of GetTreeHeightsAsOf.
+func (mr *MockTreeRepositoryMockRecorder) GetTreeHeightsAsOf(estateID, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreeHeightsAsOf", reflect.TypeOf((*MockTreeRepository)(nil).GetTreeHeightsAsOf), estateID, before)
+}
+
 // GetTreesByEstateID mocks base method.
 func (m *MockTreeRepository) GetTreesByEstateID(estateID uuid.UUID) (map[string]int, error) {
 	m.ctrl.T.Helper()
@@ -78,3 +210,90 @@ func (mr *MockTreeRepositoryMockRecorder) GetTreesByEstateID(estateID interface{
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreesByEstateID", reflect.TypeOf((*MockTreeRepository)(nil).GetTreesByEstateID), estateID)
 }
+
+// GetTreesToInspect mocks base method.
+func (m *MockTreeRepository) GetTreesToInspect(estateID uuid.UUID) ([]*models.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTreesToInspect", estateID)
+	ret0, _ := ret[0].([]*models.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTreesToInspect indicates an expected call of GetTreesToInspect.
+func (mr *MockTreeRepositoryMockRecorder) GetTreesToInspect(estateID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreesToInspect", reflect.TypeOf((*MockTreeRepository)(nil).GetTreesToInspect), estateID)
+}
+
+// GetTreesWithin mocks base method.
+func (m *MockTreeRepository) GetTreesWithin(estateID uuid.UUID, x, y, radius int) ([]*models.TreeNeighbour, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTreesWithin", estateID, x, y, radius)
+	ret0, _ := ret[0].([]*models.TreeNeighbour)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTreesWithin indicates an expected call of GetTreesWithin.
+func (mr *MockTreeRepositoryMockRecorder) GetTreesWithin(estateID, x, y, radius interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreesWithin", reflect.TypeOf((*MockTreeRepository)(nil).GetTreesWithin), estateID, x, y, radius)
+}
+
+// ImportTrees mocks base method.
+func (m *MockTreeRepository) ImportTrees(trees []*models.Tree, measuredAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportTrees", trees, measuredAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportTrees indicates an expected call of ImportTrees.
+func (mr *MockTreeRepositoryMockRecorder) ImportTrees(trees, measuredAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTrees", reflect.TypeOf((*MockTreeRepository)(nil).ImportTrees), trees, measuredAt)
+}
+
+// ListTrees mocks base method.
+func (m *MockTreeRepository) ListTrees(estateID uuid.UUID, query models.TreeQuery) (*models.TreePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrees", estateID, query)
+	ret0, _ := ret[0].(*models.TreePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrees indicates an expected call of ListTrees.
+func (mr *MockTreeRepositoryMockRecorder) ListTrees(estateID, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrees", reflect.TypeOf((*MockTreeRepository)(nil).ListTrees), estateID, query)
+}
+
+// ScanTreesInBox mocks base method.
+func (m *MockTreeRepository) ScanTreesInBox(estateID uuid.UUID, box models.BoundingBox, fn func(*models.Tree) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanTreesInBox", estateID, box, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScanTreesInBox indicates an expected call of ScanTreesInBox.
+func (mr *MockTreeRepositoryMockRecorder) ScanTreesInBox(estateID, box, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanTreesInBox", reflect.TypeOf((*MockTreeRepository)(nil).ScanTreesInBox), estateID, box, fn)
+}
+
+// UpdateTree mocks base method.
+func (m *MockTreeRepository) UpdateTree(tree *models.Tree) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTree", tree)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTree indicates an expected call of UpdateTree.
+func (mr *MockTreeRepositoryMockRecorder) UpdateTree(tree interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTree", reflect.TypeOf((*MockTreeRepository)(nil).UpdateTree), tree)
+}
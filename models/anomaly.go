@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reasons a tree is reported as an anomaly.
+const (
+	AnomalyReasonHeightDrop = "height_drop" // Latest measurement well below the previous one
+	AnomalyReasonNeighbours = "neighbours"  // Far shorter than the trees around it
+	AnomalyReasonCohort     = "cohort"      // Far shorter than the trees of its age cohort
+)
+
+// Review statuses of an anomaly. New anomalies are open; an acknowledged
+// anomaly is being followed up, a dismissed one was a false alarm.
+const (
+	AnomalyStatusOpen         = "open"
+	AnomalyStatusAcknowledged = "acknowledged"
+	AnomalyStatusDismissed    = "dismissed"
+)
+
+// AnomalyStatuses lists the review statuses of an anomaly.
+var AnomalyStatuses = []string{AnomalyStatusOpen, AnomalyStatusAcknowledged, AnomalyStatusDismissed}
+
+// ValidAnomalyStatus reports whether status is a known anomaly status.
+func ValidAnomalyStatus(status string) bool {
+	for _, known := range AnomalyStatuses {
+		if status == known {
+			return true
+		}
+	}
+	return false
+}
+
+// AnomalyReason explains why a tree stands out.
+type AnomalyReason struct {
+	Code     string  `json:"code"`     // height_drop, neighbours or cohort
+	Expected float64 `json:"expected"` // Previous height, or median height of the neighbours or cohort, in meters
+	Score    float64 `json:"score"`    // Shortfall from the expected height in robust standard deviations
+}
+
+// TreeAnomaly is a tree found standing out by the anomaly detection, with its
+// review status. A tree has at most one anomaly, updated by every detection.
+type TreeAnomaly struct {
+	ID         uuid.UUID       `json:"id"`
+	EstateID   uuid.UUID       `json:"estate_id"`
+	TreeID     uuid.UUID       `json:"tree_id"`
+	X          int             `json:"x"`
+	Y          int             `json:"y"`
+	Height     int             `json:"height"`  // Height of the tree when detected
+	Score      float64         `json:"score"`   // Highest score of its reasons
+	Reasons    []AnomalyReason `json:"reasons"` // Highest score first
+	Status     string          `json:"status"`
+	Note       string          `json:"note"`                  // Note left by the last review
+	DetectedAt time.Time       `json:"detected_at"`           // Time of the latest detection that found it
+	ReviewedAt *time.Time      `json:"reviewed_at,omitempty"` // Time it was last acknowledged or dismissed
+}
+
+// AnomalyQuery filters and bounds a list of anomalies.
+type AnomalyQuery struct {
+	Status string // Only anomalies with this status; empty for any
+	Limit  int
+}
+
+// AnomalyList is the anomalies of an estate, highest score first, with the
+// number of anomalies per status.
+type AnomalyList struct {
+	Anomalies []*TreeAnomaly `json:"anomalies"`
+	Counts    map[string]int `json:"counts"` // Every status listed
+}
+
+// AnomalyReview is the body of an acknowledgement or dismissal.
+type AnomalyReview struct {
+	Note string `json:"note"`
+}
+
+// AnomalyDetection reports a run of the anomaly detection over an estate.
+type AnomalyDetection struct {
+	Trees      int            `json:"trees"`     // Trees scored
+	Anomalies  int            `json:"anomalies"` // Trees found standing out
+	Reasons    map[string]int `json:"reasons"`   // Anomalies per reason, every reason listed
+	Resolved   int            `json:"resolved"`  // Open anomalies no longer found, removed
+	DetectedAt time.Time      `json:"detected_at"`
+}
@@ -1,10 +1,125 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // Estate represents a plantation estate with dimensions.
 type Estate struct {
-	ID     uuid.UUID `json:"id"`     // Unique identifier for the estate
-	Width  int       `json:"width"`  // Width of the estate in 10m plots
-	Length int       `json:"length"` // Length of the estate in 10m plots
+	ID         uuid.UUID  `json:"id"`                    // Unique identifier for the estate
+	Width      int        `json:"width"`                 // Width of the estate in 10m plots
+	Length     int        `json:"length"`                // Length of the estate in 10m plots
+	Name       string     `json:"name"`                  // Human readable name of the estate
+	Code       string     `json:"code"`                  // Unique short code of the estate, e.g. "RIAU-01"
+	Company    string     `json:"company"`               // Company owning the estate
+	Region     string     `json:"region"`                // Region the estate is located in
+	Notes      string     `json:"notes"`                 // Free-text notes
+	Tags       []string   `json:"tags"`                  // Lowercase labels grouping estates, e.g. "organic"
+	Location   *Location  `json:"location,omitempty"`    // Where the estate lies on the map, nil when unknown
+	CreatedAt  time.Time  `json:"created_at"`            // Time the estate was created
+	UpdatedAt  time.Time  `json:"updated_at"`            // Time the estate was last updated
+	ArchivedAt *time.Time `json:"archived_at,omitempty"` // Time the estate was archived, nil while active
+}
+
+// Location places an estate on the map. Rows of plots run east from the
+// corner and columns south, turned clockwise by the rotation.
+type Location struct {
+	Latitude  float64 `json:"latitude"`  // WGS 84 latitude of the outer corner of plot (1,1)
+	Longitude float64 `json:"longitude"` // WGS 84 longitude of the same corner
+	Rotation  float64 `json:"rotation"`  // Degrees clockwise, from -360 to 360
+}
+
+// PlotSizeMeters is the side of the square plot holding a single tree.
+const PlotSizeMeters = 10
+
+// PlotAreaHectares is the area of a single plot.
+const PlotAreaHectares = PlotSizeMeters * PlotSizeMeters / 10000.0
+
+// AreaHectares returns the area of the estate.
+func (e *Estate) AreaHectares() float64 {
+	return float64(e.Width) * float64(e.Length) * PlotAreaHectares
+}
+
+// IsArchived reports whether the estate has been archived.
+func (e *Estate) IsArchived() bool {
+	return e.ArchivedAt != nil
+}
+
+// Sort keys accepted when listing estates.
+const (
+	EstateSortCreatedAt = "created_at"
+	EstateSortArea      = "area"
+)
+
+// EstateQuery holds the filters, sort order and cursor used to list estates.
+// Nil bounds are not applied.
+type EstateQuery struct {
+	SortBy    string // EstateSortCreatedAt or EstateSortArea
+	Desc      bool   // Sort in descending order
+	Cursor    string // Opaque cursor returned by the previous page
+	Limit     int    // Maximum number of estates to return
+	MinWidth  *int
+	MaxWidth  *int
+	MinLength *int
+	MaxLength *int
+	MinTrees  *int
+	MaxTrees  *int
+	Search    string // Case-insensitive match on name or code
+	Company   string // Exact company match
+	Region    string // Exact region match
+	Tag       string // Estates carrying the tag
+	Archived  bool   // List archived estates instead of active ones
+}
+
+// EstatePage is one page of an estate listing.
+type EstatePage struct {
+	Estates    []*Estate `json:"estates"`               // Estates on this page
+	NextCursor string    `json:"next_cursor,omitempty"` // Cursor for the next page, empty on the last page
+}
+
+// EstateUpdate holds the fields of a partial estate update.
+// Nil fields are left unchanged.
+type EstateUpdate struct {
+	Width    *int      `json:"width"`
+	Length   *int      `json:"length"`
+	Name     *string   `json:"name"`
+	Code     *string   `json:"code"`
+	Company  *string   `json:"company"`
+	Region   *string   `json:"region"`
+	Notes    *string   `json:"notes"`
+	Tags     *[]string `json:"tags"`
+	Location *Location `json:"location"`
+}
+
+// Apply copies the fields set in the update onto the estate.
+func (u *EstateUpdate) Apply(estate *Estate) {
+	if u.Width != nil {
+		estate.Width = *u.Width
+	}
+	if u.Length != nil {
+		estate.Length = *u.Length
+	}
+	if u.Name != nil {
+		estate.Name = *u.Name
+	}
+	if u.Code != nil {
+		estate.Code = *u.Code
+	}
+	if u.Company != nil {
+		estate.Company = *u.Company
+	}
+	if u.Region != nil {
+		estate.Region = *u.Region
+	}
+	if u.Notes != nil {
+		estate.Notes = *u.Notes
+	}
+	if u.Tags != nil {
+		estate.Tags = *u.Tags
+	}
+	if u.Location != nil {
+		estate.Location = u.Location
+	}
 }
@@ -0,0 +1,57 @@
+package models
+
+// AgeCurvePoint is a point of the yield curve of a palm by age. The curve is
+// linear between points and flat after the last one.
+type AgeCurvePoint struct {
+	AgeYears  float64 `json:"age_years"`
+	KgPerYear float64 `json:"kg_per_year"` // Expected FFB weight of one palm over a year at this age
+}
+
+// ForecastModel describes the fitted model, so that a forecast can be explained.
+type ForecastModel struct {
+	Name                string          `json:"name"`
+	AgeCurve            []AgeCurvePoint `json:"age_curve"`
+	HeightGrowth        float64         `json:"height_growth_m_per_year"` // Used to estimate the age of palms without a planting date
+	HistoryQuarters     int             `json:"history_quarters"`         // Quarters of harvests used to fit the trend
+	QuarterWeights      []float64       `json:"quarter_weights"`          // Weight of each history quarter, oldest first
+	CredibilityHarvests int             `json:"credibility_harvests"`     // Harvests at which a tree's own history weighs as much as the estate's
+	ResidualSigma       float64         `json:"residual_sigma"`           // Spread of the log ratio of actual to expected estate yield per quarter
+	Confidence          float64         `json:"confidence"`               // Coverage of the interval
+}
+
+// ForecastInputs summarises the local data a forecast was fitted on.
+type ForecastInputs struct {
+	HistoryFrom       Date      `json:"history_from"`
+	HistoryTo         Date      `json:"history_to"`
+	Trees             int       `json:"trees"`
+	TreesPlanted      int       `json:"trees_with_planting_date"`
+	TreesAgedByHeight int       `json:"trees_aged_by_height"`
+	TreesDead         int       `json:"trees_dead"`
+	TreesWithHistory  int       `json:"trees_with_history"`
+	Harvests          int       `json:"harvests"`
+	HistoryWeightKg   float64   `json:"history_weight_kg"`
+	MeanPerformance   float64   `json:"mean_performance"` // Mean factor applied to the age curve over living trees
+	QuarterRatios     []float64 `json:"quarter_ratios"`   // Actual over expected estate yield per history quarter with harvests, oldest first
+}
+
+// EstateForecast is the forecast FFB yield of an estate over a horizon.
+type EstateForecast struct {
+	From             Date           `json:"from"` // First day forecast
+	To               Date           `json:"to"`   // Last day forecast
+	HorizonDays      int            `json:"horizon_days"`
+	PointKg          float64        `json:"point_kg"`
+	LowerKg          float64        `json:"lower_kg"`
+	UpperKg          float64        `json:"upper_kg"`
+	TonnesPerHectare float64        `json:"tonnes_per_hectare"` // Point estimate over the estate area
+	Model            ForecastModel  `json:"model"`
+	Inputs           ForecastInputs `json:"inputs"`
+}
+
+// PlotYield is the weight harvested from a plot during one period.
+type PlotYield struct {
+	X        int
+	Y        int
+	Period   int // Index of the period, 0 for the first
+	Harvests int
+	WeightKg float64
+}
@@ -0,0 +1,43 @@
+package models
+
+// Replanting spacings.
+const (
+	SpacingGrid       = "grid"       // Every empty plot is replanted
+	SpacingTriangular = "triangular" // Alternate rows are staggered by a plot
+)
+
+// Plot identifies a plot of an estate by its coordinates.
+type Plot struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// GapRegion is a contiguous region of empty plots, joined by their sides.
+type GapRegion struct {
+	X1    int `json:"x1"` // Bounding box of the region, bounds included
+	Y1    int `json:"y1"`
+	X2    int `json:"x2"`
+	Y2    int `json:"y2"`
+	Plots int `json:"plots"` // Empty plots in the region
+}
+
+// ReplantingPlan lists the plots proposed for replanting.
+type ReplantingPlan struct {
+	Spacing           string  `json:"spacing"`             // One of SpacingGrid or SpacingTriangular
+	Total             int     `json:"total"`               // Plots proposed in all
+	Plots             []Plot  `json:"plots"`               // Plots proposed, row by row, up to the requested limit
+	Truncated         bool    `json:"truncated"`           // Set when more plots are proposed than listed
+	DensityPerHectare float64 `json:"density_per_hectare"` // Trees per hectare once replanted
+}
+
+// GapAnalysis reports where the trees of an estate are missing.
+type GapAnalysis struct {
+	Plots             int            `json:"plots"` // Plots of the estate's grid
+	Trees             int            `json:"trees"`
+	EmptyPlots        int            `json:"empty_plots"`
+	CoveragePercent   float64        `json:"coverage_percent"` // Share of plots holding a tree
+	DensityPerHectare float64        `json:"density_per_hectare"`
+	RegionCount       int            `json:"region_count"`
+	Regions           []GapRegion    `json:"regions"` // Largest regions first, up to the requested limit
+	Replanting        ReplantingPlan `json:"replanting"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Harvest records the fresh fruit bunches (FFB) cut from a plot on a day.
+// Harvests of a planted plot are linked to its tree.
+type Harvest struct {
+	ID          uuid.UUID  `json:"id"`                // Unique identifier for the harvest
+	EstateID    uuid.UUID  `json:"estate_id"`         // ID of the harvested estate
+	TreeID      *uuid.UUID `json:"tree_id,omitempty"` // ID of the harvested tree, if any
+	X           int        `json:"x"`                 // X coordinate of the harvested plot
+	Y           int        `json:"y"`                 // Y coordinate of the harvested plot
+	HarvestedOn Date       `json:"harvested_on"`      // Day the bunches were cut
+	Bunches     int        `json:"bunches"`           // Number of bunches cut
+	WeightKg    float64    `json:"weight_kg"`         // Total weight of the bunches in kilograms
+	Harvester   string     `json:"harvester"`         // Harvester or harvesting team
+	CreatedAt   time.Time  `json:"created_at"`        // Time the harvest was recorded
+}
+
+// HarvestBatchReport is the result of recording a batch of harvests. A batch
+// is stored whole or not at all, so Recorded is 0 whenever Failed is not.
+type HarvestBatchReport struct {
+	Recorded int               `json:"recorded"` // Harvests stored
+	Failed   int               `json:"failed"`   // Harvests rejected
+	Errors   []TreeImportError `json:"errors"`   // Rejected harvests, numbered from 1
+}
+
+// YieldTotals sums the harvests of an area over a period.
+type YieldTotals struct {
+	Harvests int     `json:"harvests"`  // Number of harvest records
+	Bunches  int     `json:"bunches"`   // Bunches cut
+	WeightKg float64 `json:"weight_kg"` // Weight of the bunches in kilograms
+}
+
+// BlockYield is the yield of a square block of plots. Blocks are numbered
+// from 1 like plots; blocks on the edge of an estate may be smaller.
+type BlockYield struct {
+	BlockX           int     `json:"block_x"`
+	BlockY           int     `json:"block_y"`
+	X1               int     `json:"x1"` // First plot column of the block
+	Y1               int     `json:"y1"` // First plot row of the block
+	X2               int     `json:"x2"` // Last plot column of the block, inclusive
+	Y2               int     `json:"y2"` // Last plot row of the block, inclusive
+	AreaHectares     float64 `json:"area_hectares"`
+	TonnesPerHectare float64 `json:"tonnes_per_hectare"`
+	YieldTotals
+}
+
+// EstateYield is the yield of an estate over a date range, in total and per block.
+type EstateYield struct {
+	From             Date          `json:"from"`       // First harvest day included
+	To               Date          `json:"to"`         // Last harvest day included
+	BlockSize        int           `json:"block_size"` // Side of a block in plots
+	AreaHectares     float64       `json:"area_hectares"`
+	TonnesPerHectare float64       `json:"tonnes_per_hectare"`
+	Blocks           []*BlockYield `json:"blocks"` // Blocks with harvests, row by row
+	YieldTotals
+}
+
+// TonnesPerHectare converts a harvested weight over an area into the usual
+// FFB yield measure, rounded to two decimals. It is 0 for an empty area.
+func TonnesPerHectare(weightKg, hectares float64) float64 {
+	if hectares <= 0 {
+		return 0
+	}
+	return math.Round(weightKg/1000/hectares*100) / 100
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sources a tree height measurement can come from.
+const (
+	MeasurementSourceManual = "manual"
+	MeasurementSourceDrone  = "drone"
+	MeasurementSourceLidar  = "lidar"
+)
+
+// TreeMeasurement is a single recorded height of a tree. The current height of
+// a tree is the height of its latest measurement.
+type TreeMeasurement struct {
+	ID         uuid.UUID `json:"id"`          // Unique identifier for the measurement
+	TreeID     uuid.UUID `json:"tree_id"`     // ID of the measured tree
+	Height     int       `json:"height"`      // Measured height in meters (1 to 30)
+	MeasuredAt time.Time `json:"measured_at"` // Time the measurement was taken
+	Source     string    `json:"source"`      // How the height was measured: manual, drone or lidar
+	Surveyor   string    `json:"surveyor"`    // Person or team that took the measurement
+	CreatedAt  time.Time `json:"created_at"`  // Time the measurement was recorded
+}
+
+// ValidMeasurementSource reports whether source is a known measurement source.
+func ValidMeasurementSource(source string) bool {
+	switch source {
+	case MeasurementSourceManual, MeasurementSourceDrone, MeasurementSourceLidar:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Health statuses recorded by field scouts.
+const (
+	HealthStatusHealthy            = "healthy"
+	HealthStatusGanoderma          = "ganoderma"           // Ganoderma basal stem rot
+	HealthStatusOryctes            = "oryctes"             // Rhinoceros beetle damage
+	HealthStatusNutrientDeficiency = "nutrient_deficiency" // Nutrient deficiency symptoms
+	HealthStatusOther              = "other"               // Any other disease or damage, described in the notes
+	HealthStatusDead               = "dead"
+)
+
+// HealthStatuses lists every health status, healthy first.
+var HealthStatuses = []string{
+	HealthStatusHealthy,
+	HealthStatusGanoderma,
+	HealthStatusOryctes,
+	HealthStatusNutrientDeficiency,
+	HealthStatusOther,
+	HealthStatusDead,
+}
+
+// ValidHealthStatus reports whether status is a known health status.
+func ValidHealthStatus(status string) bool {
+	for _, s := range HealthStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Severity range of an observation of an unhealthy tree. Healthy observations
+// have a severity of 0.
+const (
+	MinObservationSeverity = 1
+	MaxObservationSeverity = 5
+)
+
+// TreeObservation is a field observation of the health of a tree. The current
+// health status of a tree is the status of its latest observation.
+type TreeObservation struct {
+	ID         uuid.UUID `json:"id"`          // Unique identifier for the observation
+	TreeID     uuid.UUID `json:"tree_id"`     // ID of the observed tree
+	Status     string    `json:"status"`      // Health status, one of HealthStatuses
+	Severity   int       `json:"severity"`    // 1 (mild) to 5 (severe); 0 for healthy trees
+	Notes      string    `json:"notes"`       // Free-text notes
+	Observer   string    `json:"observer"`    // Scout who made the observation
+	ObservedAt time.Time `json:"observed_at"` // Time the tree was observed
+	CreatedAt  time.Time `json:"created_at"`  // Time the observation was recorded
+}
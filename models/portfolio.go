@@ -0,0 +1,54 @@
+package models
+
+import "github.com/google/uuid"
+
+// Keys estates can be ranked by in portfolio stats.
+const (
+	PortfolioRankTrees        = "trees"
+	PortfolioRankCoverage     = "coverage"
+	PortfolioRankMedianHeight = "median_height"
+	PortfolioRankMeanHeight   = "mean_height"
+	PortfolioRankArea         = "area"
+)
+
+// PortfolioRankKeys lists the keys estates can be ranked by.
+var PortfolioRankKeys = []string{
+	PortfolioRankTrees, PortfolioRankCoverage, PortfolioRankMedianHeight, PortfolioRankMeanHeight, PortfolioRankArea,
+}
+
+// PortfolioQuery selects the active estates of a portfolio. An estate is
+// selected when it passes every filter set.
+type PortfolioQuery struct {
+	IDs     []uuid.UUID // Estates among these IDs
+	Tag     string      // Estates carrying the tag
+	Company string      // Estates owned by the company
+}
+
+// PortfolioEstateStats holds the stats of one estate of a portfolio.
+type PortfolioEstateStats struct {
+	Rank            int          `json:"rank"` // 1 for the first estate; estates tied share a rank
+	ID              uuid.UUID    `json:"id"`
+	Name            string       `json:"name"`
+	Code            string       `json:"code"`
+	Company         string       `json:"company"`
+	Trees           int          `json:"trees"`
+	AreaHectares    float64      `json:"area_hectares"`
+	CoveragePercent float64      `json:"coverage_percent"` // Share of the plots holding a tree
+	Max             int          `json:"max"`
+	Min             int          `json:"min"`
+	Median          float64      `json:"median"`
+	Mean            float64      `json:"mean"`
+	StdDev          float64      `json:"stddev"`
+	Percentiles     []Percentile `json:"percentiles"`
+}
+
+// PortfolioStats holds the stats of a set of estates taken together, and of
+// each of them ranked.
+type PortfolioStats struct {
+	EstateCount     int                     `json:"estate_count"`
+	AreaHectares    float64                 `json:"area_hectares"`
+	CoveragePercent float64                 `json:"coverage_percent"` // Share of the plots of all estates holding a tree
+	HeightStats                             // Heights of the trees of all estates
+	RankBy          string                  `json:"rank_by"`
+	Estates         []*PortfolioEstateStats `json:"estates"` // Ranked, first rank first
+}
@@ -0,0 +1,30 @@
+package models
+
+// Range is a range of plot rows or columns, both bounds included.
+type Range struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// BoundingBox is a rectangle of plots, bounds included.
+type BoundingBox struct {
+	X1 int `json:"x1"`
+	Y1 int `json:"y1"`
+	X2 int `json:"x2"`
+	Y2 int `json:"y2"`
+}
+
+// Region selects part of an estate. A plot belongs to the region when it
+// passes every filter set; a region without filters is the whole estate.
+type Region struct {
+	Name    string       `json:"name,omitempty"`
+	BBox    *BoundingBox `json:"bbox,omitempty"`
+	Rows    *Range       `json:"rows,omitempty"`    // Range of y coordinates
+	Columns *Range       `json:"columns,omitempty"` // Range of x coordinates
+	Polygon []Plot       `json:"polygon,omitempty"` // Vertices in order; plots on an edge are inside
+}
+
+// RegionStatsRequest lists the regions to compute stats for.
+type RegionStatsRequest struct {
+	Regions []Region `json:"regions"`
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Height range accepted for trees without a species.
+const (
+	DefaultMinTreeHeight = 1
+	DefaultMaxTreeHeight = 30
+)
+
+// Species is a crop variety trees are planted with, such as the Tenera or Dura
+// oil palm or a clone line. It bounds the heights accepted for its trees.
+type Species struct {
+	Code      string    `json:"code"`       // Unique short code, e.g. "tenera"
+	Name      string    `json:"name"`       // Human readable name
+	MinHeight int       `json:"min_height"` // Smallest accepted tree height in meters
+	MaxHeight int       `json:"max_height"` // Largest accepted tree height in meters
+	CreatedAt time.Time `json:"created_at"` // Time the species was added
+}
+
+// ValidHeight reports whether height is accepted for a tree of the species.
+// A nil species accepts the default range.
+func (s *Species) ValidHeight(height int) bool {
+	if s == nil {
+		return height >= DefaultMinTreeHeight && height <= DefaultMaxTreeHeight
+	}
+	return height >= s.MinHeight && height <= s.MaxHeight
+}
+
+// Date is a calendar date, written as YYYY-MM-DD in JSON.
+type Date struct {
+	time.Time
+}
+
+// DateLayout is the format of a Date.
+const DateLayout = "2006-01-02"
+
+// ParseDate parses a YYYY-MM-DD date.
+func ParseDate(value string) (Date, error) {
+	t, err := time.Parse(DateLayout, value)
+	return Date{t}, err
+}
+
+// String formats the date as YYYY-MM-DD.
+func (d Date) String() string {
+	return d.Format(DateLayout)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	parsed, err := ParseDate(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// AgeCohort is a range of tree ages, in whole years since planting, that
+// trees are grouped by in stats.
+type AgeCohort struct {
+	Name           string  // Name of the cohort
+	MinAge         int     // Youngest age in the cohort
+	MaxAge         int     // Oldest age in the cohort, or -1 for no limit
+	ExpectedGrowth float64 // Meters a healthy palm of the cohort grows in a year
+}
+
+// AgeCohorts are the stages of an oil palm's productive life, youngest first.
+// Palms grow fastest before their first harvests and slow down with age.
+var AgeCohorts = []AgeCohort{
+	{Name: "immature", MinAge: 0, MaxAge: 3, ExpectedGrowth: 1},
+	{Name: "young", MinAge: 4, MaxAge: 8, ExpectedGrowth: 0.75},
+	{Name: "prime", MinAge: 9, MaxAge: 18, ExpectedGrowth: 0.6},
+	{Name: "old", MinAge: 19, MaxAge: 25, ExpectedGrowth: 0.45},
+	{Name: "senile", MinAge: 26, MaxAge: -1, ExpectedGrowth: 0.3},
+}
+
+// AgeCohortUnknown groups the trees without a planting date.
+const AgeCohortUnknown = "unknown"
+
+// AgeCohortOf names the age cohort of a tree from its age in whole years, or
+// returns AgeCohortUnknown when the age is unknown.
+func AgeCohortOf(ageYears *int) string {
+	if ageYears == nil {
+		return AgeCohortUnknown
+	}
+	for _, cohort := range AgeCohorts {
+		if cohort.MaxAge < 0 || *ageYears <= cohort.MaxAge {
+			return cohort.Name
+		}
+	}
+	return AgeCohortUnknown
+}
+
+// TreeGroupStats holds the height stats of one group of trees of an estate.
+type TreeGroupStats struct {
+	Group  string  `json:"group"` // Species code or age cohort name; empty for trees without a species
+	Count  int     `json:"count"`
+	Max    int     `json:"max"`
+	Min    int     `json:"min"`
+	Median float64 `json:"median"`
+}
+
+// EstateStatsBreakdown holds the height stats of an estate per species and
+// per age cohort.
+type EstateStatsBreakdown struct {
+	Species    []TreeGroupStats `json:"species"`     // One entry per species, ordered by code
+	AgeCohorts []TreeGroupStats `json:"age_cohorts"` // One entry per cohort with trees, youngest first, unknown last
+}
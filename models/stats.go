@@ -0,0 +1,51 @@
+package models
+
+// DefaultStatsPercentiles are the percentiles computed when none are requested.
+var DefaultStatsPercentiles = []float64{10, 25, 75, 90}
+
+// DefaultHistogramBucketWidth is the height histogram bucket width, in meters,
+// used when none is requested.
+const DefaultHistogramBucketWidth = 5
+
+// StatsOptions selects the percentiles and histogram of the height stats.
+type StatsOptions struct {
+	Percentiles []float64 // Percentiles to compute, each above 0 and below 100
+	BucketWidth int       // Meters per histogram bucket
+}
+
+// Percentile is the height below which a share of the trees stand.
+type Percentile struct {
+	P      float64 `json:"p"` // Percent of the trees, above 0 and below 100
+	Height float64 `json:"height"`
+}
+
+// HistogramBucket counts the trees with a height from From to To, both included.
+type HistogramBucket struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
+}
+
+// HeightStats summarises the heights of the trees of an estate. Percentiles
+// and the median are interpolated between heights, so they may be fractional.
+type HeightStats struct {
+	Count       int               `json:"count"`
+	Max         int               `json:"max"`
+	Min         int               `json:"min"`
+	Median      float64           `json:"median"`
+	Mean        float64           `json:"mean"`
+	StdDev      float64           `json:"stddev"` // Population standard deviation
+	Percentiles []Percentile      `json:"percentiles"`
+	BucketWidth int               `json:"bucket_width"`
+	Histogram   []HistogramBucket `json:"histogram"` // Every bucket from the shortest to the tallest tree, empty ones included
+}
+
+// EstateStats holds the stats of an estate, or of a region of it: the heights
+// of its trees, their health and its yield.
+type EstateStats struct {
+	Region *Region `json:"region,omitempty"` // Region the stats are narrowed to, if any
+	HeightStats
+	Health           map[string]int `json:"health"` // Trees per health status, every status listed
+	AreaHectares     float64        `json:"area_hectares"`
+	TonnesPerHectare float64        `json:"tonnes_per_hectare"`
+}
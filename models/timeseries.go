@@ -0,0 +1,88 @@
+package models
+
+// Metrics an estate time series can follow.
+const (
+	TimeseriesMetricCount        = "count"
+	TimeseriesMetricMedianHeight = "median_height"
+	TimeseriesMetricMeanHeight   = "mean_height"
+	TimeseriesMetricMaxHeight    = "max_height"
+	TimeseriesMetricMinHeight    = "min_height"
+)
+
+// TimeseriesMetrics lists the metrics an estate time series can follow.
+var TimeseriesMetrics = []string{
+	TimeseriesMetricCount, TimeseriesMetricMedianHeight, TimeseriesMetricMeanHeight, TimeseriesMetricMaxHeight, TimeseriesMetricMinHeight,
+}
+
+// ValidTimeseriesMetric reports whether metric is a known time series metric.
+func ValidTimeseriesMetric(metric string) bool {
+	for _, known := range TimeseriesMetrics {
+		if metric == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Intervals the points of an estate time series can be spaced by. Weeks start
+// on Monday, and every period is taken in UTC.
+const (
+	TimeseriesIntervalWeek    = "week"
+	TimeseriesIntervalMonth   = "month"
+	TimeseriesIntervalQuarter = "quarter"
+	TimeseriesIntervalYear    = "year"
+)
+
+// TimeseriesIntervals lists the intervals an estate time series can be spaced by.
+var TimeseriesIntervals = []string{
+	TimeseriesIntervalWeek, TimeseriesIntervalMonth, TimeseriesIntervalQuarter, TimeseriesIntervalYear,
+}
+
+// ValidTimeseriesInterval reports whether interval is a known time series
+// interval.
+func ValidTimeseriesInterval(interval string) bool {
+	for _, known := range TimeseriesIntervals {
+		if interval == known {
+			return true
+		}
+	}
+	return false
+}
+
+// GrowthExpectationRatio is the share of the expected growth below which
+// trees are flagged as growing below expectation.
+const GrowthExpectationRatio = 0.8
+
+// TimeseriesPoint holds the value of a metric at the end of a period: the
+// stats of the latest measurement of each tree taken before the period ends.
+type TimeseriesPoint struct {
+	Period Date     `json:"period"` // First day of the period
+	Trees  int      `json:"trees"`  // Trees measured by the end of the period
+	Value  *float64 `json:"value"`  // Absent when no tree was measured yet
+}
+
+// CohortGrowth holds how fast the trees of an age cohort grew over a time
+// series. Only trees measured at least twice, far enough apart, are counted.
+type CohortGrowth struct {
+	Cohort             string   `json:"cohort"`               // Age cohort name, by age at the end of the series
+	Trees              int      `json:"trees"`                // Trees the growth rate is measured on
+	GrowthRate         float64  `json:"growth_rate"`          // Mean meters grown per year
+	ExpectedGrowthRate *float64 `json:"expected_growth_rate"` // Absent for trees without a planting date
+	BelowExpectation   bool     `json:"below_expectation"`
+}
+
+// EstateTimeseries holds a metric of an estate over time, and the growth of
+// its trees over the same time.
+type EstateTimeseries struct {
+	Metric   string            `json:"metric"`
+	Interval string            `json:"interval"`
+	From     Date              `json:"from"` // First day of the first period
+	To       Date              `json:"to"`   // Last day covered
+	Points   []TimeseriesPoint `json:"points"`
+	Cohorts  []CohortGrowth    `json:"cohorts"` // One entry per cohort with growth measured, youngest first, unknown last
+	// Growth of the estate, over the trees of every cohort with an expected
+	// growth, weighted by their number
+	GrowthRate         *float64 `json:"growth_rate"`
+	ExpectedGrowthRate *float64 `json:"expected_growth_rate"`
+	BelowExpectation   bool     `json:"below_expectation"`
+}
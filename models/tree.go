@@ -1,12 +1,115 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // Tree represents a tree in a plantation estate.
 type Tree struct {
-	ID       uuid.UUID `json:"id"`       // Unique identifier for the tree
-	EstateID uuid.UUID `json:"estate_id"` // ID of the estate this tree belongs to
-	X        int       `json:"x"`        // X coordinate of the tree in its plot
-	Y        int       `json:"y"`        // Y coordinate of the tree in its plot
-	Height   int       `json:"height"`   // Height of the tree in meters (1 to 30)
+	ID           uuid.UUID `json:"id"`                      // Unique identifier for the tree
+	EstateID     uuid.UUID `json:"estate_id"`               // ID of the estate this tree belongs to
+	X            int       `json:"x"`                       // X coordinate of the tree in its plot
+	Y            int       `json:"y"`                       // Y coordinate of the tree in its plot
+	Height       int       `json:"height"`                  // Height of the tree in meters, within the range of its species
+	Species      string    `json:"species,omitempty"`       // Code of the tree's species, empty when unknown
+	PlantedOn    *Date     `json:"planted_on,omitempty"`    // Date the tree was planted
+	AgeYears     *int      `json:"age_years,omitempty"`     // Whole years since planting, derived from PlantedOn
+	HealthStatus string    `json:"health_status,omitempty"` // Status of the latest health observation, healthy when never observed
+}
+
+// TreeNeighbour is a tree found near a point, with its distance to the point.
+type TreeNeighbour struct {
+	Tree
+	DistancePlots  float64 `json:"distance_plots"`  // Straight-line distance in plots
+	DistanceMeters float64 `json:"distance_meters"` // Straight-line distance in meters, at PlotSizeMeters per plot
+}
+
+// TreeUpdate holds the fields of a partial tree update.
+// Nil fields are left unchanged.
+type TreeUpdate struct {
+	X         *int    `json:"x"`
+	Y         *int    `json:"y"`
+	Height    *int    `json:"height"`
+	Species   *string `json:"species"` // An empty code clears the species
+	PlantedOn *Date   `json:"planted_on"`
+}
+
+// Apply copies the fields set in the update onto the tree.
+func (u *TreeUpdate) Apply(tree *Tree) {
+	if u.X != nil {
+		tree.X = *u.X
+	}
+	if u.Y != nil {
+		tree.Y = *u.Y
+	}
+	if u.Height != nil {
+		tree.Height = *u.Height
+	}
+	if u.Species != nil {
+		tree.Species = *u.Species
+	}
+	if u.PlantedOn != nil {
+		tree.PlantedOn = u.PlantedOn
+	}
+}
+
+// SetAge derives AgeYears from the planting date as of now, in whole years.
+// It clears AgeYears when the planting date is unknown.
+func (t *Tree) SetAge(now time.Time) {
+	if t.PlantedOn == nil {
+		t.AgeYears = nil
+		return
+	}
+	planted := t.PlantedOn.Time
+	years := now.Year() - planted.Year()
+	if now.Month() < planted.Month() || (now.Month() == planted.Month() && now.Day() < planted.Day()) {
+		years--
+	}
+	years = max(years, 0)
+	t.AgeYears = &years
+}
+
+// TreeImportError describes why a row of a tree import was rejected.
+type TreeImportError struct {
+	Row     int    `json:"row"`     // 1-based position of the row in the file, not counting the CSV header
+	Message string `json:"message"` // Reason the row was rejected
+}
+
+// TreeImportReport summarises a bulk tree import.
+type TreeImportReport struct {
+	Imported  int               `json:"imported"`  // Number of trees stored
+	Failed    int               `json:"failed"`    // Number of rows rejected
+	Errors    []TreeImportError `json:"errors"`    // Rejected rows, at most a limited number of them
+	Truncated bool              `json:"truncated"` // Whether rejected rows were left out of Errors
+}
+
+// Sort keys accepted when listing trees.
+const (
+	TreeSortCoordinate = "coordinate" // Row by row: y, then x
+	TreeSortHeight     = "height"
+)
+
+// TreeQuery holds the filters, sort order and cursor used to list the trees of
+// an estate. Nil bounds are not applied; the bounding box bounds are inclusive.
+type TreeQuery struct {
+	SortBy       string // TreeSortCoordinate or TreeSortHeight
+	Desc         bool   // Sort in descending order
+	Cursor       string // Opaque cursor returned by the previous page
+	Limit        int    // Maximum number of trees to return
+	MinX         *int
+	MinY         *int
+	MaxX         *int
+	MaxY         *int
+	MinHeight    *int
+	MaxHeight    *int
+	Species      string // Exact species code match
+	HealthStatus string // Exact health status match
+}
+
+// TreePage is one page of a tree listing.
+type TreePage struct {
+	Trees      []*Tree `json:"trees"`                 // Trees on this page
+	NextCursor string  `json:"next_cursor,omitempty"` // Cursor for the next page, empty on the last page
 }
@@ -0,0 +1,139 @@
+// Package regions measures the parts of an estate selected by a region.
+//
+// A polygon may cover millions of plots, so plots are counted row by row from
+// the crossings of the row with the polygon's edges instead of one by one. A
+// plot is inside when its point is inside the polygon by the even-odd rule or
+// on one of its edges, as with the PostgreSQL point <@ polygon operator used to
+// select the trees.
+package regions
+
+import (
+	"sawitpro-recruitment/models"
+	"sort"
+)
+
+// Bounds returns the smallest rectangle of plots of an estate of the given
+// size holding the region. The rectangle is empty when x1 > x2 or y1 > y2.
+func Bounds(region *models.Region, width, length int) (x1, y1, x2, y2 int) {
+	x1, y1, x2, y2 = 1, 1, width, length
+	if region == nil {
+		return
+	}
+	if box := region.BBox; box != nil {
+		x1, y1, x2, y2 = max(x1, box.X1), max(y1, box.Y1), min(x2, box.X2), min(y2, box.Y2)
+	}
+	if rows := region.Rows; rows != nil {
+		y1, y2 = max(y1, rows.From), min(y2, rows.To)
+	}
+	if columns := region.Columns; columns != nil {
+		x1, x2 = max(x1, columns.From), min(x2, columns.To)
+	}
+	if len(region.Polygon) > 0 {
+		px1, py1, px2, py2 := region.Polygon[0].X, region.Polygon[0].Y, region.Polygon[0].X, region.Polygon[0].Y
+		for _, vertex := range region.Polygon[1:] {
+			px1, py1 = min(px1, vertex.X), min(py1, vertex.Y)
+			px2, py2 = max(px2, vertex.X), max(py2, vertex.Y)
+		}
+		x1, y1, x2, y2 = max(x1, px1), max(y1, py1), min(x2, px2), min(y2, py2)
+	}
+	return
+}
+
+// Plots counts the plots of an estate of the given size inside the region.
+func Plots(region *models.Region, width, length int) int {
+	x1, y1, x2, y2 := Bounds(region, width, length)
+	if x1 > x2 || y1 > y2 {
+		return 0
+	}
+	if region == nil || len(region.Polygon) == 0 {
+		return (x2 - x1 + 1) * (y2 - y1 + 1)
+	}
+	plots := 0
+	for y := y1; y <= y2; y++ {
+		plots += count(rowSpans(region.Polygon, y), x1, x2)
+	}
+	return plots
+}
+
+// fraction is the exact x coordinate num/den where an edge crosses a row.
+type fraction struct {
+	num, den int // den > 0
+}
+
+func (f fraction) less(g fraction) bool {
+	return f.num*g.den < g.num*f.den
+}
+
+// span is a run of plots from x1 to x2 included.
+type span struct {
+	x1, x2 int
+}
+
+// rowSpans returns the runs of plots of row y inside the polygon, possibly
+// overlapping.
+func rowSpans(polygon []models.Plot, y int) []span {
+	var crossings []fraction
+	var spans []span
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		if a.Y == b.Y {
+			// A horizontal edge on the row is boundary from end to end
+			if a.Y == y {
+				spans = append(spans, span{min(a.X, b.X), max(a.X, b.X)})
+			}
+			continue
+		}
+		if y < min(a.Y, b.Y) || y > max(a.Y, b.Y) {
+			continue
+		}
+		if a.Y > b.Y {
+			a, b = b, a
+		}
+		x := fraction{num: a.X*(b.Y-a.Y) + (y-a.Y)*(b.X-a.X), den: b.Y - a.Y}
+		// A plot right on the edge is inside
+		if x.num%x.den == 0 {
+			spans = append(spans, span{x.num / x.den, x.num / x.den})
+		}
+		// Edges count from their lower end up to, not including, their upper
+		// end, so that a vertex shared by two edges is crossed once
+		if y < b.Y {
+			crossings = append(crossings, x)
+		}
+	}
+
+	// Crossings pair up into the stretches inside the polygon
+	sort.Slice(crossings, func(i, j int) bool { return crossings[i].less(crossings[j]) })
+	for i := 0; i+1 < len(crossings); i += 2 {
+		from, to := ceilDiv(crossings[i].num, crossings[i].den), floorDiv(crossings[i+1].num, crossings[i+1].den)
+		if from <= to {
+			spans = append(spans, span{from, to})
+		}
+	}
+	return spans
+}
+
+// count counts the plots from x1 to x2 covered by the spans.
+func count(spans []span, x1, x2 int) int {
+	sort.Slice(spans, func(i, j int) bool { return spans[i].x1 < spans[j].x1 })
+	plots, next := 0, x1 // next is the first plot not counted yet
+	for _, s := range spans {
+		from, to := max(s.x1, next), min(s.x2, x2)
+		if from <= to {
+			plots += to - from + 1
+			next = to + 1
+		}
+	}
+	return plots
+}
+
+func floorDiv(num, den int) int {
+	q := num / den
+	if num%den != 0 && num < 0 {
+		q--
+	}
+	return q
+}
+
+func ceilDiv(num, den int) int {
+	return -floorDiv(-num, den)
+}
@@ -0,0 +1,98 @@
+package regions
+
+import (
+	"math/rand"
+	"testing"
+
+	"sawitpro-recruitment/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlots_Rectangles(t *testing.T) {
+	assert.Equal(t, 100, Plots(nil, 10, 10))
+	assert.Equal(t, 100, Plots(&models.Region{}, 10, 10))
+
+	// Rows 3 to 5, clipped to columns 2 to 10
+	region := &models.Region{Rows: &models.Range{From: 3, To: 5}, Columns: &models.Range{From: 2, To: 20}}
+	assert.Equal(t, 27, Plots(region, 10, 10))
+
+	// A box across the edge of the estate
+	region = &models.Region{BBox: &models.BoundingBox{X1: 8, Y1: 8, X2: 12, Y2: 12}}
+	assert.Equal(t, 9, Plots(region, 10, 10))
+
+	// Filters that do not meet
+	region = &models.Region{BBox: &models.BoundingBox{X1: 1, Y1: 1, X2: 3, Y2: 3}, Rows: &models.Range{From: 5, To: 6}}
+	assert.Equal(t, 0, Plots(region, 10, 10))
+}
+
+func TestPlots_Polygon(t *testing.T) {
+	// A right triangle with its edges included: 1 + 2 + ... + 5 plots
+	triangle := &models.Region{Polygon: []models.Plot{{X: 1, Y: 1}, {X: 5, Y: 1}, {X: 1, Y: 5}}}
+	assert.Equal(t, 15, Plots(triangle, 10, 10))
+
+	// Combined with a row range
+	triangle.Rows = &models.Range{From: 1, To: 2}
+	assert.Equal(t, 9, Plots(triangle, 10, 10))
+
+	// A diamond whose vertices sit on single plots
+	diamond := &models.Region{Polygon: []models.Plot{{X: 5, Y: 1}, {X: 9, Y: 5}, {X: 5, Y: 9}, {X: 1, Y: 5}}}
+	assert.Equal(t, 41, Plots(diamond, 10, 10))
+}
+
+// inside reports whether a point is inside a polygon or on one of its edges,
+// the slow way.
+func inside(polygon []models.Plot, x, y int) bool {
+	in := false
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		cross := (b.X-a.X)*(y-a.Y) - (b.Y-a.Y)*(x-a.X)
+		if cross == 0 && x >= min(a.X, b.X) && x <= max(a.X, b.X) && y >= min(a.Y, b.Y) && y <= max(a.Y, b.Y) {
+			return true
+		}
+		if (a.Y > y) != (b.Y > y) {
+			// x of the edge at row y compared with the point, without division
+			lhs := float64(x-a.X) * float64(b.Y-a.Y)
+			rhs := float64(y-a.Y) * float64(b.X-a.X)
+			if (b.Y > a.Y && lhs < rhs) || (b.Y < a.Y && lhs > rhs) {
+				in = !in
+			}
+		}
+	}
+	return in
+}
+
+func TestPlots_MatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	for i := 0; i < 500; i++ {
+		width, length := 1+random.Intn(15), 1+random.Intn(15)
+		polygon := make([]models.Plot, 3+random.Intn(6))
+		for j := range polygon {
+			polygon[j] = models.Plot{X: random.Intn(18), Y: random.Intn(18)}
+		}
+		region := &models.Region{Polygon: polygon}
+		if random.Intn(2) == 0 {
+			region.Columns = &models.Range{From: 1 + random.Intn(8), To: 5 + random.Intn(10)}
+		}
+
+		want := 0
+		for y := 1; y <= length; y++ {
+			for x := 1; x <= width; x++ {
+				inColumns := region.Columns == nil || (x >= region.Columns.From && x <= region.Columns.To)
+				if inColumns && inside(polygon, x, y) {
+					want++
+				}
+			}
+		}
+		if !assert.Equal(t, want, Plots(region, width, length), "polygon %v on %dx%d", polygon, width, length) {
+			return
+		}
+	}
+}
+
+func TestPlots_LargePolygon(t *testing.T) {
+	// A triangle over half of the largest estate is counted without visiting
+	// its plots one by one
+	region := &models.Region{Polygon: []models.Plot{{X: 1, Y: 1}, {X: 50000, Y: 1}, {X: 1, Y: 50000}}}
+	assert.Equal(t, 50000*50001/2, Plots(region, 50000, 50000))
+}
@@ -0,0 +1,137 @@
+package reports
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// A4 page size, in points.
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+)
+
+// Fonts of the PDF, among the standard fonts every reader provides.
+type pdfFont int
+
+const (
+	fontRegular pdfFont = iota
+	fontBold
+)
+
+// fontFamily is the family of every font; fontStyles are the fpdf styles of
+// each font.
+const fontFamily = "Helvetica"
+
+var fontStyles = []string{"", "B"}
+
+// pdfDocument draws pages of text, rectangles, lines and images, and writes
+// them as a PDF. Positions are in points from the top left of the page.
+type pdfDocument struct {
+	pdf    *fpdf.Fpdf
+	encode func(string) string // Encodes a text in the code page of the fonts
+	images int
+}
+
+func newPDFDocument() *pdfDocument {
+	pdf := fpdf.New(fpdf.OrientationPortrait, fpdf.UnitPoint, fpdf.PageSizeA4, "")
+	// Pages are broken by the layout, which knows what to keep together
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetMargins(0, 0, 0)
+	// Characters the standard fonts lack are written as dots
+	return &pdfDocument{pdf: pdf, encode: pdf.UnicodeTranslatorFromDescriptor("cp1252")}
+}
+
+// pages returns the number of pages added.
+func (d *pdfDocument) pages() int {
+	return d.pdf.PageCount()
+}
+
+// addPage starts a new page and draws on it.
+func (d *pdfDocument) addPage() {
+	d.pdf.AddPage()
+}
+
+// setPage draws on a page already added, from 0.
+func (d *pdfDocument) setPage(i int) {
+	d.pdf.SetPage(i + 1)
+}
+
+func (d *pdfDocument) setFont(font pdfFont, size float64) {
+	d.pdf.SetFont(fontFamily, fontStyles[font], size)
+}
+
+// text draws a line of text with its baseline at y.
+func (d *pdfDocument) text(x, y float64, font pdfFont, size float64, c color.NRGBA, s string) {
+	d.setFont(font, size)
+	d.pdf.SetTextColor(int(c.R), int(c.G), int(c.B))
+	d.pdf.Text(x, y, d.encode(s))
+}
+
+// rect fills a rectangle.
+func (d *pdfDocument) rect(x, y, width, height float64, c color.NRGBA) {
+	d.pdf.SetFillColor(int(c.R), int(c.G), int(c.B))
+	d.pdf.Rect(x, y, width, height, "F")
+}
+
+// line strokes a line.
+func (d *pdfDocument) line(x1, y1, x2, y2, width float64, c color.NRGBA) {
+	d.pdf.SetDrawColor(int(c.R), int(c.G), int(c.B))
+	d.pdf.SetLineWidth(width)
+	d.pdf.Line(x1, y1, x2, y2)
+}
+
+// image draws an image scaled to a rectangle. It is embedded as a PNG, its
+// transparent pixels showing the page.
+func (d *pdfDocument) image(x, y, width, height float64, img image.Image) error {
+	var data bytes.Buffer
+	if err := png.Encode(&data, img); err != nil {
+		return err
+	}
+	d.images++
+	name := fmt.Sprintf("image%d", d.images)
+	options := fpdf.ImageOptions{ImageType: "PNG"}
+	d.pdf.RegisterImageOptionsReader(name, options, &data)
+	d.pdf.ImageOptions(name, x, y, width, height, false, options, 0, "")
+	return d.pdf.Error()
+}
+
+// textWidth returns the width of a text, in points.
+func (d *pdfDocument) textWidth(s string, font pdfFont, size float64) float64 {
+	d.setFont(font, size)
+	return d.pdf.GetStringWidth(d.encode(s))
+}
+
+// Encode writes the document.
+func (d *pdfDocument) Encode(out io.Writer) error {
+	return d.pdf.Output(out)
+}
+
+// wrap splits a text into lines at most width points wide, at spaces. A word
+// wider than a line is left whole.
+func (d *pdfDocument) wrap(s string, font pdfFont, size, width float64) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line != "" && d.textWidth(candidate, font, size) > width {
+			lines = append(lines, line)
+			candidate = word
+		}
+		line = candidate
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
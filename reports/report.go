@@ -0,0 +1,208 @@
+// Package reports renders the monthly report of an estate, as an HTML page or
+// a PDF document: its dimensions, tree height stats and histogram, health,
+// height heatmap, drone survey and open anomalies.
+//
+// PDFs are written with fpdf, in pure Go, with the standard Helvetica fonts
+// every PDF reader provides, so that no external service or font file is
+// needed.
+package reports
+
+import (
+	"fmt"
+	"sawitpro-recruitment/heatmap"
+	"sawitpro-recruitment/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Report holds the data of the report of an estate for a month.
+type Report struct {
+	Estate      *models.Estate
+	Month       time.Time           // First day of the month, in UTC
+	GeneratedAt time.Time           // Heights are as of the end of the month, or of this time for the current month
+	Stats       *models.EstateStats // Heights and health of the trees
+	Survey      Survey              // Drone survey of the whole estate
+	Heatmap     *heatmap.Heatmap    // Tree heights by plot
+	Anomalies   *models.AnomalyList // Open anomalies, highest score first, with the counts of every status
+}
+
+// Survey is the cost of flying the drone over every plot of the estate.
+type Survey struct {
+	Distance int // Meters flown, horizontally and vertically
+	Plots    int // Plots flown over
+}
+
+// Fact is a labelled value of the report.
+type Fact struct {
+	Label, Value string
+}
+
+// Bar is a bucket of the height histogram.
+type Bar struct {
+	Label    string
+	Count    int
+	Fraction float64 // Of the largest bucket, for the length of its bar
+}
+
+// AnomalyRow is an anomaly as listed in the report.
+type AnomalyRow struct {
+	Plot, Height, Score, Reasons, Detected string
+}
+
+// Title names the estate by its name, else its code, else its ID.
+func (r *Report) Title() string {
+	switch {
+	case r.Estate.Name != "":
+		return r.Estate.Name
+	case r.Estate.Code != "":
+		return r.Estate.Code
+	}
+	return "Estate " + r.Estate.ID.String()
+}
+
+// Period names the month of the report, e.g. "March 2026".
+func (r *Report) Period() string {
+	return r.Month.Format("January 2006")
+}
+
+// Generated is the time the report was generated.
+func (r *Report) Generated() string {
+	return r.GeneratedAt.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+// Filename names the report file with the estate and month, without extension.
+func (r *Report) Filename() string {
+	name := r.Estate.ID.String()
+	if r.Estate.Code != "" {
+		name = strings.ToLower(r.Estate.Code)
+	}
+	return fmt.Sprintf("estate-%s-report-%s", name, r.Month.Format("2006-01"))
+}
+
+// EstateFacts describes the estate.
+func (r *Report) EstateFacts() []Fact {
+	e := r.Estate
+	facts := []Fact{}
+	for _, f := range []Fact{{"Code", e.Code}, {"Company", e.Company}, {"Region", e.Region}} {
+		if f.Value != "" {
+			facts = append(facts, f)
+		}
+	}
+	facts = append(facts,
+		Fact{"Dimensions", fmt.Sprintf("%d × %d plots (%d m × %d m)", e.Width, e.Length,
+			e.Width*models.PlotSizeMeters, e.Length*models.PlotSizeMeters)},
+		Fact{"Area", fmt.Sprintf("%.2f ha", r.Stats.AreaHectares)},
+	)
+	if l := e.Location; l != nil {
+		facts = append(facts, Fact{"Location", fmt.Sprintf("%.5f, %.5f, rotated %g°", l.Latitude, l.Longitude, l.Rotation)})
+	}
+	if len(e.Tags) > 0 {
+		facts = append(facts, Fact{"Tags", strings.Join(e.Tags, ", ")})
+	}
+	return facts
+}
+
+// HeightFacts summarises the tree heights.
+func (r *Report) HeightFacts() []Fact {
+	s := r.Stats
+	facts := []Fact{{"Trees", strconv.Itoa(s.Count)}}
+	if s.Count == 0 {
+		return facts
+	}
+	facts = append(facts,
+		Fact{"Shortest", meters(float64(s.Min))},
+		Fact{"Tallest", meters(float64(s.Max))},
+		Fact{"Mean", meters(s.Mean)},
+		Fact{"Median", meters(s.Median)},
+		Fact{"Standard deviation", meters(s.StdDev)},
+	)
+	for _, p := range s.Percentiles {
+		facts = append(facts, Fact{ordinal(p.P) + " percentile", meters(p.Height)})
+	}
+	return facts
+}
+
+// HealthFacts counts the trees of every health status.
+func (r *Report) HealthFacts() []Fact {
+	facts := make([]Fact, 0, len(models.HealthStatuses))
+	for _, status := range models.HealthStatuses {
+		label := strings.ReplaceAll(status, "_", " ")
+		facts = append(facts, Fact{strings.ToUpper(label[:1]) + label[1:], strconv.Itoa(r.Stats.Health[status])})
+	}
+	return facts
+}
+
+// Histogram returns the buckets of the height histogram.
+func (r *Report) Histogram() []Bar {
+	largest := 0
+	for _, b := range r.Stats.Histogram {
+		largest = max(largest, b.Count)
+	}
+	bars := make([]Bar, 0, len(r.Stats.Histogram))
+	for _, b := range r.Stats.Histogram {
+		bar := Bar{Label: fmt.Sprintf("%d–%d m", b.From, b.To), Count: b.Count}
+		if largest > 0 {
+			bar.Fraction = float64(b.Count) / float64(largest)
+		}
+		bars = append(bars, bar)
+	}
+	return bars
+}
+
+// SurveyFacts describes the drone survey.
+func (r *Report) SurveyFacts() []Fact {
+	return []Fact{
+		{"Plots flown over", strconv.Itoa(r.Survey.Plots)},
+		{"Distance", fmt.Sprintf("%d m (%.2f km)", r.Survey.Distance, float64(r.Survey.Distance)/1000)},
+	}
+}
+
+// AnomalyFacts counts the anomalies of every review status.
+func (r *Report) AnomalyFacts() []Fact {
+	facts := make([]Fact, 0, len(models.AnomalyStatuses))
+	for _, status := range models.AnomalyStatuses {
+		facts = append(facts, Fact{strings.ToUpper(status[:1]) + status[1:], strconv.Itoa(r.Anomalies.Counts[status])})
+	}
+	return facts
+}
+
+// AnomalyRows lists the open anomalies.
+func (r *Report) AnomalyRows() []AnomalyRow {
+	rows := make([]AnomalyRow, 0, len(r.Anomalies.Anomalies))
+	for _, a := range r.Anomalies.Anomalies {
+		reasons := make([]string, 0, len(a.Reasons))
+		for _, reason := range a.Reasons {
+			reasons = append(reasons, fmt.Sprintf("%s (expected %s)", strings.ReplaceAll(reason.Code, "_", " "), meters(reason.Expected)))
+		}
+		rows = append(rows, AnomalyRow{
+			Plot:     fmt.Sprintf("(%d, %d)", a.X, a.Y),
+			Height:   meters(float64(a.Height)),
+			Score:    fmt.Sprintf("%.1f", a.Score),
+			Reasons:  strings.Join(reasons, ", "),
+			Detected: a.DetectedAt.UTC().Format(models.DateLayout),
+		})
+	}
+	return rows
+}
+
+// meters formats a height with at most one decimal.
+func meters(v float64) string {
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", v), ".0") + " m"
+}
+
+// ordinal formats a percentile as an ordinal number, e.g. "1st" or "12.5th".
+func ordinal(p float64) string {
+	suffix := "th"
+	if n := int(p); float64(n) == p && n%100/10 != 1 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.FormatFloat(p, 'f', -1, 64) + suffix
+}
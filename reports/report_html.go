@@ -0,0 +1,37 @@
+package reports
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"image/png"
+	"io"
+)
+
+//go:embed report.html
+var htmlSource string
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent": func(fraction float64) string {
+		return fmt.Sprintf("%.1f%%", 100*fraction)
+	},
+}).Parse(htmlSource))
+
+// htmlReport is the data of the HTML template: the report with its heatmap
+// inlined as a data URL, so that the page stands alone.
+type htmlReport struct {
+	*Report
+	HeatmapURL template.URL
+}
+
+// WriteHTML writes the report as a standalone HTML page.
+func (r *Report) WriteHTML(w io.Writer) error {
+	var heatmap bytes.Buffer
+	if err := png.Encode(&heatmap, r.Heatmap.Image()); err != nil {
+		return err
+	}
+	url := "data:image/png;base64," + base64.StdEncoding.EncodeToString(heatmap.Bytes())
+	return htmlTemplate.Execute(w, htmlReport{Report: r, HeatmapURL: template.URL(url)})
+}
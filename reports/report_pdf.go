@@ -0,0 +1,186 @@
+package reports
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Page layout, in points.
+const (
+	pageMargin    = 50.0
+	contentWidth  = pageWidth - 2*pageMargin
+	labelWidth    = 150.0 // Of the label column of facts
+	lineHeight    = 14.0
+	bodySize      = 10.0
+	headingSize   = 13.0
+	titleSize     = 20.0
+	footerSize    = 8.0
+	maxImageRatio = 0.75 // Of the content height a heatmap may take
+
+	headingHeight = 2.4*headingSize + 6 // Space a heading takes, above and below its text
+)
+
+// Colours of the PDF.
+var (
+	textColor   = color.NRGBA{0x21, 0x21, 0x21, 0xff}
+	mutedColor  = color.NRGBA{0x75, 0x75, 0x75, 0xff}
+	ruleColor   = color.NRGBA{0xbd, 0xbd, 0xbd, 0xff}
+	barColor    = color.NRGBA{0x21, 0x91, 0x8c, 0xff}
+	headerColor = color.NRGBA{0xee, 0xee, 0xee, 0xff}
+)
+
+// anomalyColumns are the columns of the anomaly table: their title, left edge
+// and width, in points from the content edge.
+var anomalyColumns = []struct {
+	title       string
+	left, width float64
+}{
+	{"Plot", 0, 60},
+	{"Height", 60, 50},
+	{"Score", 110, 40},
+	{"Reasons", 150, 275},
+	{"Detected", 425, 70},
+}
+
+// pdfLayout places the sections of the report down the pages.
+type pdfLayout struct {
+	doc *pdfDocument
+	y   float64 // Top of the space left on the page
+}
+
+// need starts a new page unless height points are left on the page.
+func (l *pdfLayout) need(height float64) {
+	if l.doc.pages() == 0 || l.y+height > pageHeight-pageMargin {
+		l.doc.addPage()
+		l.y = pageMargin
+	}
+}
+
+// line writes a line of text and moves below it.
+func (l *pdfLayout) line(x float64, font pdfFont, size float64, c color.NRGBA, s string) {
+	l.need(size * 1.4)
+	l.y += size * 1.4
+	l.doc.text(x, l.y-size*0.3, font, size, c, s)
+}
+
+// heading starts a section, on a new page unless it fits with the first keep
+// points of its content.
+func (l *pdfLayout) heading(s string, keep float64) {
+	l.need(headingHeight + keep)
+	l.y += headingSize
+	l.line(pageMargin, fontBold, headingSize, textColor, s)
+	l.doc.line(pageMargin, l.y+2, pageMargin+contentWidth, l.y+2, 0.5, ruleColor)
+	l.y += 6
+}
+
+// facts writes labelled values, the values wrapped to their column.
+func (l *pdfLayout) facts(facts []Fact) {
+	for _, f := range facts {
+		lines := l.doc.wrap(f.Value, fontRegular, bodySize, contentWidth-labelWidth)
+		l.need(float64(len(lines)) * lineHeight)
+		l.doc.text(pageMargin, l.y+lineHeight-4, fontBold, bodySize, mutedColor, f.Label)
+		for _, text := range lines {
+			l.y += lineHeight
+			l.doc.text(pageMargin+labelWidth, l.y-4, fontRegular, bodySize, textColor, text)
+		}
+	}
+}
+
+// histogram draws a bar per bucket, with its count after it.
+func (l *pdfLayout) histogram(bars []Bar) {
+	const barLeft, countWidth = 80.0, 50.0
+	for _, bar := range bars {
+		l.need(lineHeight)
+		l.doc.text(pageMargin, l.y+lineHeight-4, fontRegular, bodySize, textColor, bar.Label)
+		length := bar.Fraction * (contentWidth - barLeft - countWidth)
+		if bar.Count > 0 {
+			l.doc.rect(pageMargin+barLeft, l.y+2, math.Max(length, 1), lineHeight-4, barColor)
+		}
+		l.doc.text(pageMargin+barLeft+length+4, l.y+lineHeight-4, fontRegular, bodySize, mutedColor, strconv.Itoa(bar.Count))
+		l.y += lineHeight
+	}
+}
+
+// anomalies draws the table of anomalies, repeating its header on every page.
+func (l *pdfLayout) anomalies(rows []AnomalyRow) {
+	header := func() {
+		l.need(2 * lineHeight)
+		l.doc.rect(pageMargin, l.y, contentWidth, lineHeight, headerColor)
+		for _, column := range anomalyColumns {
+			l.doc.text(pageMargin+column.left+2, l.y+lineHeight-4, fontBold, bodySize, textColor, column.title)
+		}
+		l.y += lineHeight
+	}
+	header()
+	for _, row := range rows {
+		reasons := l.doc.wrap(row.Reasons, fontRegular, bodySize, anomalyColumns[3].width-4)
+		height := float64(len(reasons)) * lineHeight
+		if l.y+height > pageHeight-pageMargin {
+			l.need(height + lineHeight)
+			header()
+		}
+		cells := [][]string{{row.Plot}, {row.Height}, {row.Score}, reasons, {row.Detected}}
+		for c, column := range anomalyColumns {
+			for i, text := range cells[c] {
+				l.doc.text(pageMargin+column.left+2, l.y+float64(i+1)*lineHeight-4, fontRegular, bodySize, textColor, text)
+			}
+		}
+		l.y += height
+		l.doc.line(pageMargin, l.y, pageMargin+contentWidth, l.y, 0.25, ruleColor)
+	}
+}
+
+// WritePDF writes the report as an A4 PDF.
+func (r *Report) WritePDF(w io.Writer) error {
+	l := &pdfLayout{doc: newPDFDocument()}
+	l.need(0)
+	l.line(pageMargin, fontBold, titleSize, textColor, r.Title())
+	l.line(pageMargin, fontRegular, bodySize, mutedColor, fmt.Sprintf("Estate report for %s, generated %s", r.Period(), r.Generated()))
+
+	l.heading("Estate", 3*lineHeight)
+	l.facts(r.EstateFacts())
+	if r.Estate.Notes != "" {
+		l.facts([]Fact{{"Notes", r.Estate.Notes}})
+	}
+
+	l.heading("Tree heights", 3*lineHeight)
+	l.facts(r.HeightFacts())
+	if bars := r.Histogram(); len(bars) > 0 {
+		l.heading("Height histogram", 3*lineHeight)
+		l.histogram(bars)
+	}
+
+	l.heading("Tree health", 3*lineHeight)
+	l.facts(r.HealthFacts())
+
+	l.heading("Drone survey", 3*lineHeight)
+	l.facts(r.SurveyFacts())
+
+	img := r.Heatmap.Image()
+	bounds := img.Bounds()
+	scale := math.Min(contentWidth/float64(bounds.Dx()), (pageHeight-2*pageMargin)*maxImageRatio/float64(bounds.Dy()))
+	width, height := float64(bounds.Dx())*scale, float64(bounds.Dy())*scale
+	l.heading("Height heatmap", height)
+	if err := l.doc.image(pageMargin, l.y, width, height, img); err != nil {
+		return err
+	}
+	l.y += height
+
+	l.heading("Open anomalies", 3*lineHeight)
+	l.facts(r.AnomalyFacts())
+	if rows := r.AnomalyRows(); len(rows) > 0 {
+		l.y += lineHeight / 2
+		l.anomalies(rows)
+	}
+
+	pages := l.doc.pages()
+	for i := 0; i < pages; i++ {
+		l.doc.setPage(i)
+		footer := fmt.Sprintf("%s • %s • page %d of %d", r.Title(), r.Period(), i+1, pages)
+		l.doc.text(pageMargin, pageHeight-pageMargin/2, fontRegular, footerSize, mutedColor, footer)
+	}
+	return l.doc.Encode(w)
+}
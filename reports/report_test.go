@@ -0,0 +1,182 @@
+package reports
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sawitpro-recruitment/heatmap"
+	"sawitpro-recruitment/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ledongthuc/pdf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testReport(t *testing.T, anomalies int) *Report {
+	estate := &models.Estate{
+		ID:       uuid.New(),
+		Width:    20,
+		Length:   10,
+		Name:     "Riau Block A",
+		Code:     "RIAU-01",
+		Company:  "PT Sawit Makmur",
+		Notes:    "Acquired 2019 <with drainage>",
+		Tags:     []string{"north", "organic"},
+		Location: &models.Location{Latitude: 0.51, Longitude: 101.45, Rotation: 15},
+	}
+	layout, err := heatmap.NewLayout(estate.Width, estate.Length, 0, 0, heatmap.MaxCells)
+	require.NoError(t, err)
+	m := heatmap.New(layout)
+	m.Add(&models.Tree{X: 1, Y: 1, Height: 5})
+	m.Add(&models.Tree{X: 20, Y: 10, Height: 20})
+
+	list := &models.AnomalyList{Counts: map[string]int{models.AnomalyStatusOpen: anomalies, models.AnomalyStatusDismissed: 2}}
+	for i := 0; i < anomalies; i++ {
+		list.Anomalies = append(list.Anomalies, &models.TreeAnomaly{
+			X: i%20 + 1, Y: i/20 + 1, Height: 4, Score: 5.25,
+			Reasons: []models.AnomalyReason{
+				{Code: models.AnomalyReasonHeightDrop, Expected: 12, Score: 5.25},
+				{Code: models.AnomalyReasonNeighbours, Expected: 11.5, Score: 4},
+			},
+			DetectedAt: time.Date(2026, 3, 4, 8, 0, 0, 0, time.UTC),
+		})
+	}
+
+	return &Report{
+		Estate:      estate,
+		Month:       time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		GeneratedAt: time.Date(2026, 4, 1, 9, 30, 0, 0, time.UTC),
+		Stats: &models.EstateStats{
+			HeightStats: models.HeightStats{
+				Count: 2, Min: 5, Max: 20, Median: 12.5, Mean: 12.5, StdDev: 7.5,
+				Percentiles: []models.Percentile{{P: 10, Height: 6.5}, {P: 90, Height: 18.5}},
+				BucketWidth: 5,
+				Histogram:   []models.HistogramBucket{{From: 5, To: 9, Count: 1}, {From: 10, To: 14}, {From: 15, To: 19}, {From: 20, To: 24, Count: 1}},
+			},
+			Health:       map[string]int{models.HealthStatusHealthy: 1, models.HealthStatusGanoderma: 1},
+			AreaHectares: 2,
+		},
+		Survey:    Survey{Distance: 2030, Plots: 200},
+		Heatmap:   m,
+		Anomalies: list,
+	}
+}
+
+func TestReport_Facts(t *testing.T) {
+	r := testReport(t, 1)
+
+	assert.Equal(t, "Riau Block A", r.Title())
+	assert.Equal(t, "March 2026", r.Period())
+	assert.Equal(t, "estate-riau-01-report-2026-03", r.Filename())
+	assert.Contains(t, r.EstateFacts(), Fact{"Dimensions", "20 × 10 plots (200 m × 100 m)"})
+	assert.Contains(t, r.EstateFacts(), Fact{"Location", "0.51000, 101.45000, rotated 15°"})
+	assert.Contains(t, r.HeightFacts(), Fact{"90th percentile", "18.5 m"})
+	assert.Contains(t, r.HealthFacts(), Fact{"Nutrient deficiency", "0"})
+	assert.Contains(t, r.SurveyFacts(), Fact{"Distance", "2030 m (2.03 km)"})
+	assert.Equal(t, Bar{Label: "20–24 m", Count: 1, Fraction: 1}, r.Histogram()[3])
+	assert.Equal(t, []AnomalyRow{{
+		Plot: "(1, 1)", Height: "4 m", Score: "5.2",
+		Reasons:  "height drop (expected 12 m), neighbours (expected 11.5 m)",
+		Detected: "2026-03-04",
+	}}, r.AnomalyRows())
+
+	assert.Equal(t, []string{"1st", "2nd", "3rd", "11th", "12.5th", "22nd"},
+		[]string{ordinal(1), ordinal(2), ordinal(3), ordinal(11), ordinal(12.5), ordinal(22)})
+}
+
+func TestReport_WriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testReport(t, 1).WriteHTML(&buf))
+	html := buf.String()
+
+	assert.Contains(t, html, "<h1>Riau Block A</h1>")
+	assert.Contains(t, html, "Estate report for March 2026, generated 2026-04-01 09:30 UTC")
+	assert.Contains(t, html, "Acquired 2019 &lt;with drainage&gt;")
+	assert.Contains(t, html, `src="data:image/png;base64,`)
+	assert.Contains(t, html, `style="width: 100.0%"`)
+	assert.Contains(t, html, "<td>(1, 1)</td>")
+}
+
+// readPDF parses a PDF with a reader independent of the writer.
+func readPDF(t *testing.T, data []byte) *pdf.Reader {
+	require.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+	doc, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	return doc
+}
+
+// pdfPages returns the text of each page of a PDF and its decompressed
+// content stream.
+func pdfPages(t *testing.T, doc *pdf.Reader) (texts, contents []string) {
+	for i := 1; i <= doc.NumPage(); i++ {
+		page := doc.Page(i)
+		text, err := page.GetPlainText(nil)
+		require.NoError(t, err)
+		content, err := io.ReadAll(page.V.Key("Contents").Reader())
+		require.NoError(t, err)
+		texts, contents = append(texts, text), append(contents, string(content))
+	}
+	return texts, contents
+}
+
+func TestReport_WritePDF(t *testing.T) {
+	r := testReport(t, 1)
+	var buf bytes.Buffer
+	require.NoError(t, r.WritePDF(&buf))
+	doc := readPDF(t, buf.Bytes())
+
+	page := doc.Page(1)
+	fonts := []string{}
+	for _, name := range page.Fonts() {
+		fonts = append(fonts, page.Font(name).BaseFont())
+	}
+	assert.ElementsMatch(t, []string{"Helvetica", "Helvetica-Bold"}, fonts)
+	images := page.Resources().Key("XObject")
+	require.Len(t, images.Keys(), 1)
+	bounds := r.Heatmap.Image().Bounds()
+	assert.Equal(t, int64(bounds.Dx()), images.Key(images.Keys()[0]).Key("Width").Int64())
+
+	texts, contents := pdfPages(t, doc)
+	require.Len(t, texts, 2)
+	assert.Contains(t, texts[0], "Riau Block A")
+	assert.Contains(t, texts[0], "Acquired 2019 <with drainage>")
+	assert.Contains(t, texts[0], "20 × 10 plots (200 m × 100 m)")
+	assert.Contains(t, texts[0], "2030 m (2.03 km)")
+	// The heatmap moves to the next page with its heading
+	assert.NotContains(t, texts[0], "Height heatmap")
+	assert.NotContains(t, contents[0], " Do")
+	assert.Contains(t, texts[1], "Height heatmap")
+	assert.Contains(t, contents[1], " Do")
+	assert.Contains(t, texts[1], "height drop (expected 12 m), neighbours (expected 11.5 m)")
+	assert.Contains(t, texts[1], "Riau Block A • March 2026 • page 2 of 2")
+}
+
+func TestReport_WritePDF_Pages(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testReport(t, 120).WritePDF(&buf))
+	texts, _ := pdfPages(t, readPDF(t, buf.Bytes()))
+	pages := len(texts)
+	assert.Greater(t, pages, 3)
+
+	// The anomaly table header is repeated on the pages it continues on
+	last := texts[pages-1]
+	assert.Contains(t, last, "Reasons")
+	assert.Contains(t, last, "(20, 6)")
+	assert.Contains(t, last, fmt.Sprintf("page %d of %d", pages, pages))
+}
+
+func TestWrap(t *testing.T) {
+	d := newPDFDocument()
+	assert.Equal(t, []string{""}, d.wrap("", fontRegular, 10, 100))
+	// A digit is 5.56 points wide at size 10
+	assert.Equal(t, []string{"1111 2222", "3333"}, d.wrap("1111 2222 3333", fontRegular, 10, 60))
+	assert.Equal(t, []string{"111111111111"}, d.wrap("111111111111", fontRegular, 10, 20))
+}
+
+func TestPDFEncoding(t *testing.T) {
+	// Texts are written in the WinAnsi code page of the standard fonts
+	assert.Equal(t, "15\xb0 \x96 \xe9t\xe9 .", newPDFDocument().encode("15° – été 漢"))
+}
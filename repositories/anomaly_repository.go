@@ -0,0 +1,298 @@
+package repositories
+
+import (
+    "database/sql"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "sawitpro-recruitment/models"
+    "time"
+
+    "github.com/google/uuid"
+    "github.com/sirupsen/logrus"
+)
+
+// ErrAnomalyNotFound is returned when an anomaly to review does not exist in the estate.
+var ErrAnomalyNotFound = errors.New("anomaly not found")
+
+// ErrAnomalyDismissed is returned when acknowledging an anomaly dismissed since it was read.
+var ErrAnomalyDismissed = errors.New("anomaly dismissed")
+
+// anomalyColumns lists the anomaly columns in the order read by scanAnomaly.
+const anomalyColumns = "id, estate_id, tree_id, x, y, height, score, reasons, status, note, detected_at, reviewed_at"
+
+// scanAnomaly reads an anomaly selected with anomalyColumns.
+func scanAnomaly(row rowScanner) (*models.TreeAnomaly, error) {
+    anomaly := &models.TreeAnomaly{}
+    var reasons []byte
+    var reviewedAt sql.NullTime
+    err := row.Scan(&anomaly.ID, &anomaly.EstateID, &anomaly.TreeID, &anomaly.X, &anomaly.Y, &anomaly.Height,
+        &anomaly.Score, &reasons, &anomaly.Status, &anomaly.Note, &anomaly.DetectedAt, &reviewedAt)
+    if err != nil {
+        return nil, err
+    }
+    if err := json.Unmarshal(reasons, &anomaly.Reasons); err != nil {
+        return nil, fmt.Errorf("invalid reasons of anomaly %v: %w", anomaly.ID, err)
+    }
+    if reviewedAt.Valid {
+        anomaly.ReviewedAt = &reviewedAt.Time
+    }
+    return anomaly, nil
+}
+
+// AnomalyRepository defines the methods for tree anomaly database operations.
+type AnomalyRepository interface {
+    GetCohortHeightCounts(estateID uuid.UUID) (map[string]map[int]int, error)
+    GetHeightDrops(estateID uuid.UUID) (map[uuid.UUID]int, error)
+    SaveAnomalies(estateID uuid.UUID, anomalies []*models.TreeAnomaly, detectedAt time.Time) (int, error)
+    ListAnomalies(estateID uuid.UUID, query models.AnomalyQuery) (*models.AnomalyList, error)
+    GetAnomaly(estateID, anomalyID uuid.UUID) (*models.TreeAnomaly, error)
+    ReviewAnomaly(anomaly *models.TreeAnomaly) error
+}
+
+// anomalyRepository is the concrete implementation of the AnomalyRepository interface.
+type anomalyRepository struct {
+    db *sql.DB
+}
+
+// NewAnomalyRepository returns a new instance of anomalyRepository.
+func NewAnomalyRepository(db *sql.DB) AnomalyRepository {
+    return &anomalyRepository{
+        db: db,
+    }
+}
+
+// GetCohortHeightCounts counts the trees of an estate per age cohort and height.
+func (r *anomalyRepository) GetCohortHeightCounts(estateID uuid.UUID) (map[string]map[int]int, error) {
+    logrus.Infof("Retrieving cohort height counts for estate ID: %v", estateID)
+    rows, err := r.db.Query(`
+        SELECT `+ageCohortExpression("planted_on", "AGE(planted_on)")+` AS cohort, height, COUNT(*)
+        FROM trees
+        WHERE estate_id = $1
+        GROUP BY cohort, height
+    `, estateID)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve cohort height counts for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    counts := map[string]map[int]int{}
+    for rows.Next() {
+        var cohort string
+        var height, count int
+        if err := rows.Scan(&cohort, &height, &count); err != nil {
+            logrus.Errorf("Failed to scan cohort height count for estate ID %v: %v", estateID, err)
+            return nil, err
+        }
+        if counts[cohort] == nil {
+            counts[cohort] = map[int]int{}
+        }
+        counts[cohort][height] = count
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during rows iteration for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    return counts, nil
+}
+
+// GetHeightDrops finds the trees of an estate whose latest measurement is
+// lower than the one before it, with the height of the one before.
+func (r *anomalyRepository) GetHeightDrops(estateID uuid.UUID) (map[uuid.UUID]int, error) {
+    logrus.Infof("Retrieving height drops for estate ID: %v", estateID)
+    rows, err := r.db.Query(`
+        SELECT tree_id, previous
+        FROM (
+            SELECT m.tree_id, m.height,
+                LAG(m.height) OVER (PARTITION BY m.tree_id ORDER BY measured_at, created_at) AS previous,
+                ROW_NUMBER() OVER (PARTITION BY m.tree_id ORDER BY `+latestMeasurementOrder+`) AS latest
+            FROM tree_measurements m JOIN trees t ON t.id = m.tree_id
+            WHERE t.estate_id = $1
+        ) readings
+        WHERE latest = 1 AND previous > height
+    `, estateID)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve height drops for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    drops := map[uuid.UUID]int{}
+    for rows.Next() {
+        var treeID uuid.UUID
+        var previous int
+        if err := rows.Scan(&treeID, &previous); err != nil {
+            logrus.Errorf("Failed to scan height drop for estate ID %v: %v", estateID, err)
+            return nil, err
+        }
+        drops[treeID] = previous
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during rows iteration for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    return drops, nil
+}
+
+// SaveAnomalies stores the anomalies found by a detection run over an estate
+// in a single transaction. The anomaly of a tree already reported is updated
+// and keeps its ID and review status, which are read back into it. Open
+// anomalies the run did not find again are removed, and their number returned;
+// reviewed ones are kept.
+func (r *anomalyRepository) SaveAnomalies(estateID uuid.UUID, anomalies []*models.TreeAnomaly, detectedAt time.Time) (int, error) {
+    logrus.Infof("Saving %d anomalies for estate ID: %v", len(anomalies), estateID)
+    tx, err := r.db.Begin()
+    if err != nil {
+        logrus.Errorf("Failed to begin transaction for saving anomalies of estate ID %v: %v", estateID, err)
+        return 0, err
+    }
+    defer tx.Rollback()
+
+    stmt, err := tx.Prepare(`INSERT INTO tree_anomalies (id, estate_id, tree_id, x, y, height, score, reasons, detected_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8::JSONB, $9)
+        ON CONFLICT (tree_id) DO UPDATE SET x = EXCLUDED.x, y = EXCLUDED.y, height = EXCLUDED.height,
+            score = EXCLUDED.score, reasons = EXCLUDED.reasons, detected_at = EXCLUDED.detected_at
+        RETURNING id, status, note, reviewed_at`)
+    if err != nil {
+        logrus.Errorf("Failed to prepare anomaly upsert: %v", err)
+        return 0, err
+    }
+    defer stmt.Close()
+
+    for _, anomaly := range anomalies {
+        reasons, err := json.Marshal(anomaly.Reasons)
+        if err != nil {
+            return 0, err
+        }
+        var reviewedAt sql.NullTime
+        err = stmt.QueryRow(uuid.New(), estateID, anomaly.TreeID, anomaly.X, anomaly.Y, anomaly.Height,
+            anomaly.Score, string(reasons), detectedAt).Scan(&anomaly.ID, &anomaly.Status, &anomaly.Note, &reviewedAt)
+        if err != nil {
+            logrus.Errorf("Failed to save anomaly of tree ID %v: %v", anomaly.TreeID, err)
+            return 0, err
+        }
+        anomaly.EstateID = estateID
+        anomaly.DetectedAt = detectedAt
+        anomaly.ReviewedAt = nil
+        if reviewedAt.Valid {
+            anomaly.ReviewedAt = &reviewedAt.Time
+        }
+    }
+
+    result, err := tx.Exec(`DELETE FROM tree_anomalies WHERE estate_id = $1 AND status = $2 AND detected_at < $3`,
+        estateID, models.AnomalyStatusOpen, detectedAt)
+    if err != nil {
+        logrus.Errorf("Failed to remove resolved anomalies of estate ID %v: %v", estateID, err)
+        return 0, err
+    }
+    resolved, err := result.RowsAffected()
+    if err != nil {
+        return 0, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        logrus.Errorf("Failed to commit anomalies of estate ID %v: %v", estateID, err)
+        return 0, err
+    }
+    logrus.Infof("Saved %d anomalies and resolved %d for estate ID: %v", len(anomalies), resolved, estateID)
+    return int(resolved), nil
+}
+
+// ListAnomalies retrieves the anomalies of an estate matching the query,
+// highest score first, with the number of anomalies of each status.
+func (r *anomalyRepository) ListAnomalies(estateID uuid.UUID, query models.AnomalyQuery) (*models.AnomalyList, error) {
+    logrus.Infof("Listing anomalies with status %q for estate ID: %v", query.Status, estateID)
+    sqlQuery := "SELECT " + anomalyColumns + " FROM tree_anomalies WHERE estate_id = $1"
+    args := []interface{}{estateID}
+    if query.Status != "" {
+        args = append(args, query.Status)
+        sqlQuery += fmt.Sprintf(" AND status = $%d", len(args))
+    }
+    args = append(args, query.Limit)
+    sqlQuery += fmt.Sprintf(" ORDER BY score DESC, id LIMIT $%d", len(args))
+
+    rows, err := r.db.Query(sqlQuery, args...)
+    if err != nil {
+        logrus.Errorf("Failed to list anomalies for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    list := &models.AnomalyList{Anomalies: []*models.TreeAnomaly{}}
+    for rows.Next() {
+        anomaly, err := scanAnomaly(rows)
+        if err != nil {
+            logrus.Errorf("Failed to scan anomaly row for estate ID %v: %v", estateID, err)
+            return nil, err
+        }
+        list.Anomalies = append(list.Anomalies, anomaly)
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during rows iteration for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+
+    list.Counts, err = countByStatus(r.db, `SELECT status, COUNT(*) FROM tree_anomalies WHERE estate_id = $1 GROUP BY status`, estateID)
+    if err != nil {
+        logrus.Errorf("Failed to count anomalies for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    for _, status := range models.AnomalyStatuses {
+        list.Counts[status] += 0
+    }
+    return list, nil
+}
+
+// GetAnomaly retrieves an anomaly of an estate by ID. It returns nil when the
+// estate has no such anomaly.
+func (r *anomalyRepository) GetAnomaly(estateID, anomalyID uuid.UUID) (*models.TreeAnomaly, error) {
+    logrus.Infof("Retrieving anomaly ID %v for estate ID: %v", anomalyID, estateID)
+    row := r.db.QueryRow("SELECT "+anomalyColumns+" FROM tree_anomalies WHERE id = $1 AND estate_id = $2", anomalyID, estateID)
+    anomaly, err := scanAnomaly(row)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            logrus.Warnf("No anomaly found with ID %v for estate ID: %v", anomalyID, estateID)
+            return nil, nil
+        }
+        logrus.Errorf("Failed to retrieve anomaly ID %v for estate ID %v: %v", anomalyID, estateID, err)
+        return nil, err
+    }
+    return anomaly, nil
+}
+
+// ReviewAnomaly stores the status, note and review time of an anomaly. A
+// dismissed anomaly is never acknowledged, even when it was dismissed by a
+// concurrent review. It returns ErrAnomalyNotFound when the anomaly no longer
+// exists and ErrAnomalyDismissed when it cannot be acknowledged.
+func (r *anomalyRepository) ReviewAnomaly(anomaly *models.TreeAnomaly) error {
+    logrus.Infof("Reviewing anomaly ID %v as %s", anomaly.ID, anomaly.Status)
+    result, err := r.db.Exec(`
+        UPDATE tree_anomalies SET status = $3, note = $4, reviewed_at = $5
+        WHERE id = $1 AND estate_id = $2 AND NOT (status = 'dismissed' AND $3 = 'acknowledged')
+    `, anomaly.ID, anomaly.EstateID, anomaly.Status, anomaly.Note, anomaly.ReviewedAt)
+    if err != nil {
+        logrus.Errorf("Failed to review anomaly ID %v: %v", anomaly.ID, err)
+        return err
+    }
+    updated, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if updated > 0 {
+        return nil
+    }
+
+    // Nothing was updated: the anomaly was either resolved or dismissed
+    var exists bool
+    err = r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM tree_anomalies WHERE id = $1 AND estate_id = $2)`,
+        anomaly.ID, anomaly.EstateID).Scan(&exists)
+    if err != nil {
+        logrus.Errorf("Failed to check anomaly ID %v: %v", anomaly.ID, err)
+        return err
+    }
+    if exists {
+        return ErrAnomalyDismissed
+    }
+    return ErrAnomalyNotFound
+}
@@ -0,0 +1,222 @@
+package repositories
+
+import (
+    "database/sql"
+    "errors"
+    "testing"
+    "sawitpro-recruitment/models"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "github.com/google/uuid"
+    "github.com/stretchr/testify/assert"
+)
+
+// anomalyRowColumns are the columns returned by queries selecting anomalyColumns.
+var anomalyRowColumns = []string{"id", "estate_id", "tree_id", "x", "y", "height", "score", "reasons", "status", "note", "detected_at", "reviewed_at"}
+
+func TestAnomalyRepository_GetCohortHeightCounts(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewAnomalyRepository(db)
+
+    estateID := uuid.New()
+    mock.ExpectQuery(`SELECT CASE .+ AS cohort, height, COUNT\(\*\)\s+FROM trees\s+WHERE estate_id = \$1\s+GROUP BY cohort, height`).
+        WithArgs(estateID).
+        WillReturnRows(sqlmock.NewRows([]string{"cohort", "height", "count"}).
+            AddRow("prime", 18, 12).
+            AddRow("prime", 17, 3).
+            AddRow("unknown", 5, 1))
+
+    counts, err := repo.GetCohortHeightCounts(estateID)
+    assert.NoError(t, err)
+    assert.Equal(t, map[string]map[int]int{"prime": {18: 12, 17: 3}, "unknown": {5: 1}}, counts)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnomalyRepository_GetHeightDrops(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewAnomalyRepository(db)
+
+    estateID := uuid.New()
+    treeID := uuid.New()
+    mock.ExpectQuery(`SELECT tree_id, previous\s+FROM \(.+LAG\(m.height\).+\) readings\s+WHERE latest = 1 AND previous > height`).
+        WithArgs(estateID).
+        WillReturnRows(sqlmock.NewRows([]string{"tree_id", "previous"}).AddRow(treeID, 14))
+
+    drops, err := repo.GetHeightDrops(estateID)
+    assert.NoError(t, err)
+    assert.Equal(t, map[uuid.UUID]int{treeID: 14}, drops)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnomalyRepository_SaveAnomalies(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewAnomalyRepository(db)
+
+    estateID := uuid.New()
+    detectedAt := time.Now().UTC()
+    reviewedAt := detectedAt.Add(-time.Hour)
+    reasons := []models.AnomalyReason{{Code: models.AnomalyReasonNeighbours, Expected: 15, Score: 10}}
+    fresh := &models.TreeAnomaly{TreeID: uuid.New(), X: 4, Y: 4, Height: 5, Score: 10, Reasons: reasons, Status: models.AnomalyStatusOpen}
+    reviewed := &models.TreeAnomaly{TreeID: uuid.New(), X: 7, Y: 2, Height: 6, Score: 10, Reasons: reasons, Status: models.AnomalyStatusOpen}
+    freshID := uuid.New()
+    reviewedID := uuid.New()
+
+    mock.ExpectBegin()
+    upsert := mock.ExpectPrepare(`INSERT INTO tree_anomalies \(id, estate_id, tree_id, x, y, height, score, reasons, detected_at\)\s+` +
+        `VALUES .+ON CONFLICT \(tree_id\) DO UPDATE SET .+RETURNING id, status, note, reviewed_at`)
+    upsert.ExpectQuery().
+        WithArgs(sqlmock.AnyArg(), estateID, fresh.TreeID, 4, 4, 5, 10.0, `[{"code":"neighbours","expected":15,"score":10}]`, detectedAt).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "status", "note", "reviewed_at"}).AddRow(freshID, "open", "", nil))
+    upsert.ExpectQuery().
+        WithArgs(sqlmock.AnyArg(), estateID, reviewed.TreeID, 7, 2, 6, 10.0, sqlmock.AnyArg(), detectedAt).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "status", "note", "reviewed_at"}).AddRow(reviewedID, "acknowledged", "Checked by Team A", reviewedAt))
+    mock.ExpectExec(`DELETE FROM tree_anomalies WHERE estate_id = \$1 AND status = \$2 AND detected_at < \$3`).
+        WithArgs(estateID, models.AnomalyStatusOpen, detectedAt).
+        WillReturnResult(sqlmock.NewResult(0, 3))
+    mock.ExpectCommit()
+
+    resolved, err := repo.SaveAnomalies(estateID, []*models.TreeAnomaly{fresh, reviewed}, detectedAt)
+    assert.NoError(t, err)
+    assert.Equal(t, 3, resolved)
+    assert.Equal(t, freshID, fresh.ID)
+    assert.Equal(t, estateID, fresh.EstateID)
+    assert.Equal(t, detectedAt, fresh.DetectedAt)
+    assert.Nil(t, fresh.ReviewedAt)
+    // A reviewed anomaly found again keeps its review
+    assert.Equal(t, reviewedID, reviewed.ID)
+    assert.Equal(t, models.AnomalyStatusAcknowledged, reviewed.Status)
+    assert.Equal(t, "Checked by Team A", reviewed.Note)
+    assert.Equal(t, reviewedAt, *reviewed.ReviewedAt)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnomalyRepository_SaveAnomalies_RollsBackOnError(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewAnomalyRepository(db)
+
+    estateID := uuid.New()
+    anomaly := &models.TreeAnomaly{TreeID: uuid.New(), Reasons: []models.AnomalyReason{}}
+
+    mock.ExpectBegin()
+    upsert := mock.ExpectPrepare(`INSERT INTO tree_anomalies`)
+    upsert.ExpectQuery().WillReturnError(errors.New("database error"))
+    mock.ExpectRollback()
+
+    _, err = repo.SaveAnomalies(estateID, []*models.TreeAnomaly{anomaly}, time.Now())
+    assert.Error(t, err)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnomalyRepository_ListAnomalies(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewAnomalyRepository(db)
+
+    estateID := uuid.New()
+    anomalyID := uuid.New()
+    treeID := uuid.New()
+    detectedAt := time.Now().UTC()
+    mock.ExpectQuery(`SELECT id, estate_id, tree_id, .+ FROM tree_anomalies WHERE estate_id = \$1 AND status = \$2 ORDER BY score DESC, id LIMIT \$3`).
+        WithArgs(estateID, "open", 50).
+        WillReturnRows(sqlmock.NewRows(anomalyRowColumns).
+            AddRow(anomalyID, estateID, treeID, 4, 4, 5, 10.0, []byte(`[{"code":"neighbours","expected":15,"score":10}]`), "open", "", detectedAt, nil))
+    mock.ExpectQuery(`SELECT status, COUNT\(\*\) FROM tree_anomalies WHERE estate_id = \$1 GROUP BY status`).
+        WithArgs(estateID).
+        WillReturnRows(sqlmock.NewRows([]string{"status", "count"}).AddRow("open", 1).AddRow("dismissed", 4))
+
+    list, err := repo.ListAnomalies(estateID, models.AnomalyQuery{Status: "open", Limit: 50})
+    assert.NoError(t, err)
+    if assert.Len(t, list.Anomalies, 1) {
+        assert.Equal(t, anomalyID, list.Anomalies[0].ID)
+        assert.Equal(t, []models.AnomalyReason{{Code: "neighbours", Expected: 15, Score: 10}}, list.Anomalies[0].Reasons)
+        assert.Nil(t, list.Anomalies[0].ReviewedAt)
+    }
+    assert.Equal(t, map[string]int{"open": 1, "acknowledged": 0, "dismissed": 4}, list.Counts)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnomalyRepository_GetAnomaly_NotFound(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewAnomalyRepository(db)
+
+    estateID := uuid.New()
+    anomalyID := uuid.New()
+    mock.ExpectQuery(`FROM tree_anomalies WHERE id = \$1 AND estate_id = \$2`).
+        WithArgs(anomalyID, estateID).
+        WillReturnError(sql.ErrNoRows)
+
+    anomaly, err := repo.GetAnomaly(estateID, anomalyID)
+    assert.NoError(t, err)
+    assert.Nil(t, anomaly)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// reviewAnomalyQuery matches the update of ReviewAnomaly, which never
+// acknowledges a dismissed anomaly.
+const reviewAnomalyQuery = `UPDATE tree_anomalies SET status = \$3, note = \$4, reviewed_at = \$5\s+` +
+    `WHERE id = \$1 AND estate_id = \$2 AND NOT \(status = 'dismissed' AND \$3 = 'acknowledged'\)`
+
+func TestAnomalyRepository_ReviewAnomaly(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewAnomalyRepository(db)
+
+    reviewedAt := time.Now().UTC()
+    anomaly := &models.TreeAnomaly{ID: uuid.New(), EstateID: uuid.New(), Status: models.AnomalyStatusDismissed,
+        Note: "Replanted last month", ReviewedAt: &reviewedAt}
+    mock.ExpectExec(reviewAnomalyQuery).
+        WithArgs(anomaly.ID, anomaly.EstateID, "dismissed", "Replanted last month", &reviewedAt).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    // Resolved by a detection run
+    mock.ExpectExec(reviewAnomalyQuery).
+        WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM tree_anomalies WHERE id = \$1 AND estate_id = \$2\)`).
+        WithArgs(anomaly.ID, anomaly.EstateID).
+        WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+    assert.NoError(t, repo.ReviewAnomaly(anomaly))
+    assert.Equal(t, ErrAnomalyNotFound, repo.ReviewAnomaly(anomaly))
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnomalyRepository_ReviewAnomaly_Dismissed(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewAnomalyRepository(db)
+
+    reviewedAt := time.Now().UTC()
+    anomaly := &models.TreeAnomaly{ID: uuid.New(), EstateID: uuid.New(), Status: models.AnomalyStatusAcknowledged,
+        ReviewedAt: &reviewedAt}
+    // Dismissed by a concurrent review after the handler read it as open
+    mock.ExpectExec(reviewAnomalyQuery).
+        WithArgs(anomaly.ID, anomaly.EstateID, "acknowledged", "", &reviewedAt).
+        WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM tree_anomalies WHERE id = \$1 AND estate_id = \$2\)`).
+        WithArgs(anomaly.ID, anomaly.EstateID).
+        WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+    assert.Equal(t, ErrAnomalyDismissed, repo.ReviewAnomaly(anomaly))
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,16 +2,103 @@ package repositories
 
 import (
     "database/sql"
+    "encoding/base64"
+    "errors"
+    "fmt"
+    "math"
     "sawitpro-recruitment/models"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
     "github.com/google/uuid"
+    "github.com/lib/pq"
     "github.com/sirupsen/logrus"
 )
 
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrDuplicateEstateCode is returned when another estate already uses the code.
+var ErrDuplicateEstateCode = errors.New("estate code already exists")
+
+// ErrTreesOutOfBounds is returned when resizing an estate would leave trees outside of it.
+var ErrTreesOutOfBounds = errors.New("trees would be out of bounds")
+
+// ErrEstateNotFound is returned when an estate to modify does not exist.
+var ErrEstateNotFound = errors.New("estate not found")
+
+// estateColumns lists the estate columns in the order read by scanEstate.
+const estateColumns = "id, width, length, name, COALESCE(code, ''), company, region, notes, created_at, updated_at, archived_at, tags, latitude, longitude, rotation"
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+    Scan(dest ...interface{}) error
+}
+
+// scanEstate reads an estate selected with estateColumns.
+func scanEstate(row rowScanner) (*models.Estate, error) {
+    estate := &models.Estate{}
+    var archivedAt sql.NullTime
+    var tags pq.StringArray
+    var latitude, longitude, rotation sql.NullFloat64
+    err := row.Scan(&estate.ID, &estate.Width, &estate.Length, &estate.Name, &estate.Code,
+        &estate.Company, &estate.Region, &estate.Notes, &estate.CreatedAt, &estate.UpdatedAt, &archivedAt, &tags,
+        &latitude, &longitude, &rotation)
+    if err != nil {
+        return nil, err
+    }
+    if latitude.Valid {
+        estate.Location = &models.Location{Latitude: latitude.Float64, Longitude: longitude.Float64, Rotation: rotation.Float64}
+    }
+    estate.Tags = []string(tags)
+    if estate.Tags == nil {
+        estate.Tags = []string{}
+    }
+    if archivedAt.Valid {
+        estate.ArchivedAt = &archivedAt.Time
+    }
+    return estate, nil
+}
+
+// locationValues returns the location columns of an estate, all NULL when it
+// is not on the map.
+func locationValues(estate *models.Estate) (sql.NullFloat64, sql.NullFloat64, sql.NullFloat64) {
+    if estate.Location == nil {
+        return sql.NullFloat64{}, sql.NullFloat64{}, sql.NullFloat64{}
+    }
+    return sql.NullFloat64{Float64: estate.Location.Latitude, Valid: true},
+        sql.NullFloat64{Float64: estate.Location.Longitude, Valid: true},
+        sql.NullFloat64{Float64: estate.Location.Rotation, Valid: true}
+}
+
+// isUniqueViolation reports whether err is a PostgreSQL unique constraint violation.
+func isUniqueViolation(err error) bool {
+    var pqErr *pq.Error
+    return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
 // EstateRepository defines the methods for estate-related database operations.
 type EstateRepository interface {
     CreateEstate(estate *models.Estate) error
     GetEstateByID(id uuid.UUID) (*models.Estate, error)
-    GetEstateStats(id uuid.UUID) (int, int, int, int, error)
+    GetEstateStats(id uuid.UUID, region *models.Region, options models.StatsOptions) (*models.HeightStats, error)
+    RebuildHeightSummary(id uuid.UUID) (bool, error)
+    ListEstateIDs() ([]uuid.UUID, error)
+    GetEstateStatsAsOf(id uuid.UUID, before time.Time, region *models.Region, options models.StatsOptions) (*models.HeightStats, error)
+    GetEstateStatsBreakdown(id uuid.UUID) (*models.EstateStatsBreakdown, error)
+    GetEstateTimeseries(id uuid.UUID, metric string, periods []time.Time, end time.Time) ([]models.TimeseriesPoint, error)
+    GetCohortGrowth(id uuid.UUID, from, to time.Time) ([]models.CohortGrowth, error)
+    GetPortfolioStats(query models.PortfolioQuery, options models.StatsOptions) (*models.PortfolioStats, error)
+    GetHealthCounts(id uuid.UUID, region *models.Region) (map[string]int, error)
+    GetHealthCountsAsOf(id uuid.UUID, before time.Time, region *models.Region) (map[string]int, error)
+    ListEstates(query models.EstateQuery) (*models.EstatePage, error)
+    UpdateEstate(estate *models.Estate) error
+    GetTreesOutOfBounds(estateID uuid.UUID, width, length, limit int) ([]*models.Tree, error)
+    ArchiveEstate(id uuid.UUID, archivedAt time.Time) error
+    RestoreEstate(id uuid.UUID) error
+    DeleteEstate(id uuid.UUID) error
 }
 
 // estateRepository is the concrete implementation of the EstateRepository interface.
@@ -26,21 +113,188 @@ func NewEstateRepository(db *sql.DB) EstateRepository {
     }
 }
 
-// CreateEstate inserts a new estate into the database.
+// CreateEstate inserts a new estate into the database, with an empty height
+// summary kept up to date as trees are planted.
 func (r *estateRepository) CreateEstate(estate *models.Estate) error {
     logrus.Infof("Creating estate with ID: %v", estate.ID)
-    _, err := r.db.Exec("INSERT INTO estates (id, width, length) VALUES ($1, $2, $3)", estate.ID, estate.Width, estate.Length)
+    latitude, longitude, rotation := locationValues(estate)
+    _, err := r.db.Exec(`WITH estate AS (
+            INSERT INTO estates (id, width, length, name, code, company, region, notes, created_at, updated_at, tags, latitude, longitude, rotation)
+            VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10, $11, $12, $13, $14)
+            RETURNING id
+        )
+        INSERT INTO estate_height_summaries (estate_id) SELECT id FROM estate`,
+        estate.ID, estate.Width, estate.Length, estate.Name, estate.Code,
+        estate.Company, estate.Region, estate.Notes, estate.CreatedAt, estate.UpdatedAt, pq.StringArray(estate.Tags),
+        latitude, longitude, rotation)
     if err != nil {
         logrus.Errorf("Failed to create estate with ID %v: %v", estate.ID, err)
+        if isUniqueViolation(err) {
+            return ErrDuplicateEstateCode
+        }
     }
     return err
 }
 
+// UpdateEstate stores the dimensions and metadata of an existing estate. The
+// update is refused with ErrTreesOutOfBounds when a tree lies outside the new
+// dimensions. The estate row is locked before its trees are checked, and tree
+// writes share lock it, so a shrink cannot race with a tree being planted.
+func (r *estateRepository) UpdateEstate(estate *models.Estate) error {
+    logrus.Infof("Updating estate with ID: %v", estate.ID)
+    tx, err := r.db.Begin()
+    if err != nil {
+        logrus.Errorf("Failed to begin transaction for updating estate ID %v: %v", estate.ID, err)
+        return err
+    }
+    defer tx.Rollback()
+
+    // Waits for the trees being planted to commit, so the check below sees them
+    var id uuid.UUID
+    err = tx.QueryRow("SELECT id FROM estates WHERE id = $1 FOR UPDATE", estate.ID).Scan(&id)
+    if err == sql.ErrNoRows {
+        logrus.Warnf("No estate found with ID: %v", estate.ID)
+        return ErrEstateNotFound
+    }
+    if err != nil {
+        logrus.Errorf("Failed to lock estate ID %v: %v", estate.ID, err)
+        return err
+    }
+
+    latitude, longitude, rotation := locationValues(estate)
+    result, err := tx.Exec(`UPDATE estates
+        SET width = $2, length = $3, name = $4, code = NULLIF($5, ''), company = $6, region = $7, notes = $8, updated_at = $9, tags = $10,
+            latitude = $11, longitude = $12, rotation = $13
+        WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM trees WHERE estate_id = $1 AND (x > $2 OR y > $3))`,
+        estate.ID, estate.Width, estate.Length, estate.Name, estate.Code,
+        estate.Company, estate.Region, estate.Notes, estate.UpdatedAt, pq.StringArray(estate.Tags),
+        latitude, longitude, rotation)
+    if err != nil {
+        logrus.Errorf("Failed to update estate with ID %v: %v", estate.ID, err)
+        if isUniqueViolation(err) {
+            return ErrDuplicateEstateCode
+        }
+        return err
+    }
+    affected, err := result.RowsAffected()
+    if err != nil {
+        logrus.Errorf("Failed to read affected rows for estate ID %v: %v", estate.ID, err)
+        return err
+    }
+    // The estate is locked, so only its trees can have prevented the update
+    if affected == 0 {
+        logrus.Warnf("Estate ID %v not updated: trees would be out of bounds", estate.ID)
+        return ErrTreesOutOfBounds
+    }
+    if err := tx.Commit(); err != nil {
+        logrus.Errorf("Failed to commit update of estate ID %v: %v", estate.ID, err)
+        return err
+    }
+    logrus.Infof("Estate updated successfully with ID: %v", estate.ID)
+    return nil
+}
+
+// ArchiveEstate soft deletes an estate, hiding it from listings while keeping its trees.
+func (r *estateRepository) ArchiveEstate(id uuid.UUID, archivedAt time.Time) error {
+    logrus.Infof("Archiving estate with ID: %v", id)
+    return r.setArchivedAt(id, sql.NullTime{Time: archivedAt, Valid: true})
+}
+
+// RestoreEstate brings an archived estate back.
+func (r *estateRepository) RestoreEstate(id uuid.UUID) error {
+    logrus.Infof("Restoring estate with ID: %v", id)
+    return r.setArchivedAt(id, sql.NullTime{})
+}
+
+// setArchivedAt stores the archive time of an estate, NULL meaning active.
+func (r *estateRepository) setArchivedAt(id uuid.UUID, archivedAt sql.NullTime) error {
+    result, err := r.db.Exec("UPDATE estates SET archived_at = $2 WHERE id = $1", id, archivedAt)
+    if err != nil {
+        logrus.Errorf("Failed to set archived_at for estate ID %v: %v", id, err)
+        return err
+    }
+    affected, err := result.RowsAffected()
+    if err != nil {
+        logrus.Errorf("Failed to read affected rows for estate ID %v: %v", id, err)
+        return err
+    }
+    if affected == 0 {
+        logrus.Warnf("No estate found with ID: %v", id)
+        return ErrEstateNotFound
+    }
+    return nil
+}
+
+// DeleteEstate permanently removes an estate and all of its trees in one transaction.
+func (r *estateRepository) DeleteEstate(id uuid.UUID) error {
+    logrus.Infof("Deleting estate with ID: %v", id)
+    tx, err := r.db.Begin()
+    if err != nil {
+        logrus.Errorf("Failed to begin transaction for deleting estate ID %v: %v", id, err)
+        return err
+    }
+    defer tx.Rollback()
+
+    trees, err := tx.Exec("DELETE FROM trees WHERE estate_id = $1", id)
+    if err != nil {
+        logrus.Errorf("Failed to delete trees of estate ID %v: %v", id, err)
+        return err
+    }
+    result, err := tx.Exec("DELETE FROM estates WHERE id = $1", id)
+    if err != nil {
+        logrus.Errorf("Failed to delete estate ID %v: %v", id, err)
+        return err
+    }
+    affected, err := result.RowsAffected()
+    if err != nil {
+        logrus.Errorf("Failed to read affected rows for estate ID %v: %v", id, err)
+        return err
+    }
+    if affected == 0 {
+        logrus.Warnf("No estate found with ID: %v", id)
+        return ErrEstateNotFound
+    }
+    if err := tx.Commit(); err != nil {
+        logrus.Errorf("Failed to commit deletion of estate ID %v: %v", id, err)
+        return err
+    }
+
+    deletedTrees, _ := trees.RowsAffected()
+    logrus.Infof("Estate deleted successfully with ID: %v (%d trees removed)", id, deletedTrees)
+    return nil
+}
+
+// GetTreesOutOfBounds retrieves up to limit trees of an estate lying outside the given dimensions.
+func (r *estateRepository) GetTreesOutOfBounds(estateID uuid.UUID, width, length, limit int) ([]*models.Tree, error) {
+    logrus.Infof("Retrieving trees outside %dx%d for estate ID: %v", width, length, estateID)
+    rows, err := r.db.Query("SELECT id, estate_id, x, y, height FROM trees WHERE estate_id = $1 AND (x > $2 OR y > $3) ORDER BY y, x LIMIT $4", estateID, width, length, limit)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve out of bounds trees for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    trees := []*models.Tree{}
+    for rows.Next() {
+        tree := &models.Tree{}
+        if err := rows.Scan(&tree.ID, &tree.EstateID, &tree.X, &tree.Y, &tree.Height); err != nil {
+            logrus.Errorf("Failed to scan tree row for estate ID %v: %v", estateID, err)
+            return nil, err
+        }
+        trees = append(trees, tree)
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during rows iteration for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    logrus.Infof("Found %d out of bounds trees for estate ID: %v", len(trees), estateID)
+    return trees, nil
+}
+
 // GetEstateByID retrieves an estate by its ID.
 func (r *estateRepository) GetEstateByID(id uuid.UUID) (*models.Estate, error) {
     logrus.Infof("Retrieving estate with ID: %v", id)
-    estate := &models.Estate{}
-    err := r.db.QueryRow("SELECT id, width, length FROM estates WHERE id = $1", id).Scan(&estate.ID, &estate.Width, &estate.Length)
+    estate, err := scanEstate(r.db.QueryRow("SELECT "+estateColumns+" FROM estates WHERE id = $1", id))
     if err != nil {
         if err == sql.ErrNoRows {
             logrus.Warnf("No estate found with ID: %v", id)
@@ -53,45 +307,848 @@ func (r *estateRepository) GetEstateByID(id uuid.UUID) (*models.Estate, error) {
     return estate, nil
 }
 
-// GetEstateStats retrieves statistics about trees in a specified estate.
-func (r *estateRepository) GetEstateStats(estateID uuid.UUID) (int, int, int, int, error) {
+// GetEstateStats computes the height stats of the trees of an estate, or of
+// the trees in a region of it when the region is not nil. The stats of a whole
+// estate come from its height summary when every tree is counted in it, and
+// from its trees otherwise.
+func (r *estateRepository) GetEstateStats(estateID uuid.UUID, region *models.Region, options models.StatsOptions) (*models.HeightStats, error) {
     logrus.Infof("Retrieving estate stats for ID: %v", estateID)
-    var count int
-    var max, min, median sql.NullInt64
 
-    query := `
-        SELECT 
-            COUNT(*), 
-            MAX(height), 
-            MIN(height), 
-            PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY height) 
-        FROM trees 
-        WHERE estate_id = $1
-    `
+    if region == nil {
+        counts, err := scanHeightCounts(r.db.QueryRow(`SELECT `+heightCountsColumns+` FROM estate_height_summaries WHERE estate_id = $1`, estateID))
+        if err != nil && err != sql.ErrNoRows {
+            logrus.Errorf("Failed to retrieve height summary for estate ID %v: %v", estateID, err)
+            return nil, err
+        }
+        if err == nil && counts.exact() {
+            logrus.Infof("Estate stats retrieved from height summary for ID: %v", estateID)
+            return counts.stats(options), nil
+        }
+    }
 
-    row := r.db.QueryRow(query, estateID)
-    err := row.Scan(&count, &max, &min, &median)
+    filter, args := regionFilter(region, "x", "y", "location", []interface{}{estateID})
+    stats, err := r.heightStats(`SELECT height FROM trees WHERE estate_id = $1`+filter, options, args...)
     if err != nil {
         logrus.Errorf("Failed to retrieve estate stats for ID %v: %v", estateID, err)
-        return 0, 0, 0, 0, err
+        return nil, err
+    }
+
+    logrus.Infof("Estate stats retrieved successfully for ID: %v", estateID)
+    return stats, nil
+}
+
+// RebuildHeightSummary recomputes the height summary of an estate from its
+// trees, creating it if missing, and reports whether an existing summary had
+// drifted from them. It returns ErrEstateNotFound for an unknown estate.
+func (r *estateRepository) RebuildHeightSummary(estateID uuid.UUID) (bool, error) {
+    logrus.Infof("Rebuilding height summary for estate ID: %v", estateID)
+    tx, err := r.db.Begin()
+    if err != nil {
+        logrus.Errorf("Failed to begin transaction for rebuilding height summary of estate ID %v: %v", estateID, err)
+        return false, err
+    }
+    defer tx.Rollback()
+
+    // Locking the summary first makes tree changes wait for the rebuild, or
+    // the rebuild wait for them to be committed and counted
+    result, err := tx.Exec(`INSERT INTO estate_height_summaries (estate_id) SELECT id FROM estates WHERE id = $1
+        ON CONFLICT (estate_id) DO NOTHING`, estateID)
+    if err != nil {
+        logrus.Errorf("Failed to create height summary of estate ID %v: %v", estateID, err)
+        return false, err
+    }
+    created, err := result.RowsAffected()
+    if err != nil {
+        logrus.Errorf("Failed to read affected rows for estate ID %v: %v", estateID, err)
+        return false, err
+    }
+    stored, err := scanHeightCounts(tx.QueryRow(`SELECT `+heightCountsColumns+` FROM estate_height_summaries WHERE estate_id = $1 FOR UPDATE`, estateID))
+    if err == sql.ErrNoRows {
+        logrus.Warnf("No estate found with ID: %v", estateID)
+        return false, ErrEstateNotFound
+    }
+    if err != nil {
+        logrus.Errorf("Failed to lock height summary of estate ID %v: %v", estateID, err)
+        return false, err
+    }
+
+    rows, err := tx.Query(`SELECT height, COUNT(*) FROM trees WHERE estate_id = $1 GROUP BY height`, estateID)
+    if err != nil {
+        logrus.Errorf("Failed to count tree heights of estate ID %v: %v", estateID, err)
+        return false, err
+    }
+    defer rows.Close()
+    counts := newHeightCounts()
+    for rows.Next() {
+        var height, count int
+        if err := rows.Scan(&height, &count); err != nil {
+            logrus.Errorf("Failed to scan tree height count for estate ID %v: %v", estateID, err)
+            return false, err
+        }
+        counts.add(height, count)
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during rows iteration for estate ID %v: %v", estateID, err)
+        return false, err
+    }
+    rows.Close()
+
+    drifted := created == 0 && !stored.equal(counts)
+    if err := storeHeightSummary(tx, estateID, counts); err != nil {
+        logrus.Errorf("Failed to store height summary of estate ID %v: %v", estateID, err)
+        return false, err
+    }
+    if err := tx.Commit(); err != nil {
+        logrus.Errorf("Failed to commit height summary of estate ID %v: %v", estateID, err)
+        return false, err
+    }
+    if drifted {
+        logrus.Warnf("Height summary of estate ID %v had drifted from its trees", estateID)
     }
+    return drifted, nil
+}
 
-    // Convert sql.NullInt64 to int, handling NULL values
-    maxValue := 0
-    if max.Valid {
-        maxValue = int(max.Int64)
+// ListEstateIDs retrieves the IDs of every estate, archived ones included.
+func (r *estateRepository) ListEstateIDs() ([]uuid.UUID, error) {
+    logrus.Info("Listing estate IDs")
+    rows, err := r.db.Query(`SELECT id FROM estates ORDER BY id`)
+    if err != nil {
+        logrus.Errorf("Failed to list estate IDs: %v", err)
+        return nil, err
     }
+    defer rows.Close()
 
-    minValue := 0
-    if min.Valid {
-        minValue = int(min.Int64)
+    ids := []uuid.UUID{}
+    for rows.Next() {
+        var id uuid.UUID
+        if err := rows.Scan(&id); err != nil {
+            logrus.Errorf("Failed to scan estate ID: %v", err)
+            return nil, err
+        }
+        ids = append(ids, id)
     }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during rows iteration: %v", err)
+        return nil, err
+    }
+    return ids, nil
+}
 
-    medianValue := 0
-    if median.Valid {
-        medianValue = int(median.Int64)
+// GetEstateStatsAsOf computes the same stats as GetEstateStats from the latest
+// measurement of each tree taken before the given time. Trees without a
+// measurement by then are not counted.
+func (r *estateRepository) GetEstateStatsAsOf(estateID uuid.UUID, before time.Time, region *models.Region, options models.StatsOptions) (*models.HeightStats, error) {
+    logrus.Infof("Retrieving estate stats before %v for ID: %v", before, estateID)
+
+    filter, args := regionFilter(region, "t.x", "t.y", "t.location", []interface{}{estateID, before})
+    heights := `
+        SELECT DISTINCT ON (m.tree_id) m.height
+        FROM tree_measurements m JOIN trees t ON t.id = m.tree_id
+        WHERE t.estate_id = $1 AND m.measured_at < $2` + filter + `
+        ORDER BY m.tree_id, m.measured_at DESC, m.created_at DESC`
+
+    stats, err := r.heightStats(heights, options, args...)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve estate stats before %v for ID %v: %v", before, estateID, err)
+        return nil, err
     }
 
     logrus.Infof("Estate stats retrieved successfully for ID: %v", estateID)
-    return count, maxValue, minValue, medianValue, nil
-}
\ No newline at end of file
+    return stats, nil
+}
+
+// heightStats computes the stats of the heights selected by a query taking the
+// given arguments. The summary and the histogram are read in two queries over
+// the same heights.
+func (r *estateRepository) heightStats(heights string, options models.StatsOptions, args ...interface{}) (*models.HeightStats, error) {
+    summary := fmt.Sprintf(`
+        WITH heights AS (%s)
+        SELECT %s
+        FROM heights
+    `, heights, heightSummaryColumns(len(args)+1))
+
+    var row heightSummary
+    err := r.db.QueryRow(summary, append(args, percentileFractions(options))...).Scan(row.dest()...)
+    if err != nil {
+        return nil, err
+    }
+    stats := row.stats(options)
+    if stats.Count == 0 {
+        return stats, nil
+    }
+
+    histogram := fmt.Sprintf(`
+        WITH heights AS (%s)
+        SELECT height / $%[2]d * $%[2]d AS bucket, COUNT(*)
+        FROM heights
+        GROUP BY bucket
+        ORDER BY bucket
+    `, heights, len(args)+1)
+
+    rows, err := r.db.Query(histogram, append(args, options.BucketWidth)...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    counts := map[int]int{}
+    for rows.Next() {
+        var bucket, count int
+        if err := rows.Scan(&bucket, &count); err != nil {
+            return nil, err
+        }
+        counts[bucket] = count
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+    stats.Histogram = histogramBuckets(stats.Min, stats.Max, options.BucketWidth, counts)
+    return stats, nil
+}
+
+// heightSummaryColumns aggregates the height column of a group into the
+// columns read by heightSummary, the percentiles being those of the array of
+// fractions in argument $percentilesArg.
+func heightSummaryColumns(percentilesArg int) string {
+    return fmt.Sprintf(`COUNT(height), MAX(height), MIN(height),
+            PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY height),
+            AVG(height), STDDEV_POP(height),
+            PERCENTILE_CONT($%d::FLOAT8[]) WITHIN GROUP (ORDER BY height)`, percentilesArg)
+}
+
+// percentileFractions converts the requested percentiles into the fractions
+// taken by PERCENTILE_CONT.
+func percentileFractions(options models.StatsOptions) pq.Float64Array {
+    fractions := make(pq.Float64Array, len(options.Percentiles))
+    for i, p := range options.Percentiles {
+        fractions[i] = p / 100
+    }
+    return fractions
+}
+
+// heightSummary receives the columns of heightSummaryColumns. Every column
+// but the count is NULL for a group without heights.
+type heightSummary struct {
+    count                int
+    max, min             sql.NullInt64
+    median, mean, stddev sql.NullFloat64
+    percentiles          pq.Float64Array
+}
+
+func (s *heightSummary) dest() []interface{} {
+    return []interface{}{&s.count, &s.max, &s.min, &s.median, &s.mean, &s.stddev, &s.percentiles}
+}
+
+// stats returns the summary as height stats without a histogram.
+func (s *heightSummary) stats(options models.StatsOptions) *models.HeightStats {
+    stats := &models.HeightStats{Count: s.count, BucketWidth: options.BucketWidth, Percentiles: []models.Percentile{}, Histogram: []models.HistogramBucket{}}
+    if s.count == 0 {
+        return stats
+    }
+    stats.Max, stats.Min = int(s.max.Int64), int(s.min.Int64)
+    stats.Median = round2(s.median.Float64)
+    stats.Mean = round2(s.mean.Float64)
+    stats.StdDev = round2(s.stddev.Float64)
+    for i, p := range options.Percentiles {
+        stats.Percentiles = append(stats.Percentiles, models.Percentile{P: p, Height: round2(s.percentiles[i])})
+    }
+    return stats
+}
+
+// histogramBuckets lists the buckets of the given width from the one holding
+// min to the one holding max, with their counts keyed by lower bound.
+func histogramBuckets(min, max, width int, counts map[int]int) []models.HistogramBucket {
+    buckets := []models.HistogramBucket{}
+    for from := min / width * width; from <= max; from += width {
+        buckets = append(buckets, models.HistogramBucket{From: from, To: from + width - 1, Count: counts[from]})
+    }
+    return buckets
+}
+
+// regionFilter returns the conditions selecting the plots of a region, to be
+// appended to a WHERE clause, and args followed by their arguments. x and y
+// name the plot coordinates and location their point. A nil region selects
+// every plot.
+func regionFilter(region *models.Region, x, y, location string, args []interface{}) (string, []interface{}) {
+    if region == nil {
+        return "", args
+    }
+    var filter strings.Builder
+    between := func(column string, from, to int) {
+        args = append(args, from, to)
+        fmt.Fprintf(&filter, " AND %s BETWEEN $%d AND $%d", column, len(args)-1, len(args))
+    }
+    if box := region.BBox; box != nil {
+        between(x, box.X1, box.X2)
+        between(y, box.Y1, box.Y2)
+    }
+    if rows := region.Rows; rows != nil {
+        between(y, rows.From, rows.To)
+    }
+    if columns := region.Columns; columns != nil {
+        between(x, columns.From, columns.To)
+    }
+    if len(region.Polygon) > 0 {
+        vertices := make([]string, len(region.Polygon))
+        for i, vertex := range region.Polygon {
+            vertices[i] = fmt.Sprintf("(%d,%d)", vertex.X, vertex.Y)
+        }
+        args = append(args, "("+strings.Join(vertices, ",")+")")
+        fmt.Fprintf(&filter, " AND %s <@ $%d::polygon", location, len(args))
+    }
+    return filter.String(), args
+}
+
+// round2 rounds a stat to two decimals.
+func round2(value float64) float64 {
+    return math.Round(value*100) / 100
+}
+
+// GetHealthCounts counts the trees of an estate, or of a region of it, per
+// current health status. Statuses without trees are left out.
+func (r *estateRepository) GetHealthCounts(estateID uuid.UUID, region *models.Region) (map[string]int, error) {
+    logrus.Infof("Retrieving health counts for estate ID: %v", estateID)
+    filter, args := regionFilter(region, "x", "y", "location", []interface{}{estateID})
+    counts, err := countByStatus(r.db, `SELECT health_status, COUNT(*) FROM trees WHERE estate_id = $1`+filter+` GROUP BY health_status`, args...)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve health counts for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    return counts, nil
+}
+
+// GetHealthCountsAsOf counts the same trees as GetEstateStatsAsOf per health
+// status of their latest observation before the given time. Trees not observed
+// by then count as healthy.
+func (r *estateRepository) GetHealthCountsAsOf(estateID uuid.UUID, before time.Time, region *models.Region) (map[string]int, error) {
+    logrus.Infof("Retrieving health counts before %v for estate ID: %v", before, estateID)
+    filter, args := regionFilter(region, "t.x", "t.y", "t.location", []interface{}{estateID, before, models.HealthStatusHealthy})
+    query := `
+        SELECT COALESCE(o.status, $3), COUNT(*)
+        FROM trees t
+        LEFT JOIN LATERAL (
+            SELECT status FROM tree_observations
+            WHERE tree_id = t.id AND observed_at < $2
+            ORDER BY ` + latestObservationOrder + `
+            LIMIT 1
+        ) o ON TRUE
+        WHERE t.estate_id = $1
+            AND EXISTS (SELECT 1 FROM tree_measurements m WHERE m.tree_id = t.id AND m.measured_at < $2)` + filter + `
+        GROUP BY 1
+    `
+    counts, err := countByStatus(r.db, query, args...)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve health counts before %v for estate ID %v: %v", before, estateID, err)
+        return nil, err
+    }
+    return counts, nil
+}
+
+// countByStatus reads the status and count rows of a query into a map.
+func countByStatus(db *sql.DB, query string, args ...interface{}) (map[string]int, error) {
+    rows, err := db.Query(query, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    counts := map[string]int{}
+    for rows.Next() {
+        var status string
+        var count int
+        if err := rows.Scan(&status, &count); err != nil {
+            return nil, err
+        }
+        counts[status] = count
+    }
+    return counts, rows.Err()
+}
+
+// GetEstateStatsBreakdown retrieves the height stats of the trees of an estate
+// per species and per age cohort. Cohorts are listed in the order of
+// models.AgeCohorts, followed by the trees without a planting date.
+func (r *estateRepository) GetEstateStatsBreakdown(estateID uuid.UUID) (*models.EstateStatsBreakdown, error) {
+    logrus.Infof("Retrieving estate stats breakdown for ID: %v", estateID)
+
+    species, err := r.groupTreeStats(estateID, "COALESCE(species, '')")
+    if err != nil {
+        logrus.Errorf("Failed to retrieve species stats for ID %v: %v", estateID, err)
+        return nil, err
+    }
+    cohorts, err := r.groupTreeStats(estateID, ageCohortExpression("planted_on", "AGE(planted_on)"))
+    if err != nil {
+        logrus.Errorf("Failed to retrieve age cohort stats for ID %v: %v", estateID, err)
+        return nil, err
+    }
+
+    rank := ageCohortRanks()
+    sort.SliceStable(cohorts, func(i, j int) bool {
+        return rank[cohorts[i].Group] < rank[cohorts[j].Group]
+    })
+
+    logrus.Infof("Estate stats breakdown retrieved successfully for ID: %v", estateID)
+    return &models.EstateStatsBreakdown{Species: species, AgeCohorts: cohorts}, nil
+}
+
+// groupTreeStats computes the count, max, min and median height of the trees
+// of an estate for each value of a grouping expression.
+func (r *estateRepository) groupTreeStats(estateID uuid.UUID, groupExpr string) ([]models.TreeGroupStats, error) {
+    query := fmt.Sprintf(`
+        SELECT %s AS tree_group, COUNT(*), MAX(height), MIN(height),
+            PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY height)
+        FROM trees
+        WHERE estate_id = $1
+        GROUP BY tree_group
+        ORDER BY tree_group
+    `, groupExpr)
+
+    rows, err := r.db.Query(query, estateID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    groups := []models.TreeGroupStats{}
+    for rows.Next() {
+        var group models.TreeGroupStats
+        if err := rows.Scan(&group.Group, &group.Count, &group.Max, &group.Min, &group.Median); err != nil {
+            return nil, err
+        }
+        group.Median = round2(group.Median)
+        groups = append(groups, group)
+    }
+    return groups, rows.Err()
+}
+
+// ageCohortExpression builds the SQL expression naming the age cohort of a
+// tree from its planting date column and the SQL expression of its age.
+func ageCohortExpression(plantedOn, age string) string {
+    var b strings.Builder
+    fmt.Fprintf(&b, "CASE WHEN %s IS NULL THEN '%s'", plantedOn, models.AgeCohortUnknown)
+    for _, cohort := range models.AgeCohorts {
+        if cohort.MaxAge < 0 {
+            fmt.Fprintf(&b, " ELSE '%s'", cohort.Name)
+            break
+        }
+        fmt.Fprintf(&b, " WHEN DATE_PART('year', %s) <= %d THEN '%s'", age, cohort.MaxAge, cohort.Name)
+    }
+    b.WriteString(" END")
+    return b.String()
+}
+
+// ageCohortRanks numbers the age cohorts youngest first, the trees without a
+// planting date last.
+func ageCohortRanks() map[string]int {
+    rank := map[string]int{models.AgeCohortUnknown: len(models.AgeCohorts)}
+    for i, cohort := range models.AgeCohorts {
+        rank[cohort.Name] = i
+    }
+    return rank
+}
+
+// timeseriesMetricColumns aggregates the heights read at the end of a period
+// into the value of each time series metric.
+var timeseriesMetricColumns = map[string]string{
+    models.TimeseriesMetricCount:        "COUNT(r.height)::FLOAT8",
+    models.TimeseriesMetricMedianHeight: "PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY r.height)",
+    models.TimeseriesMetricMeanHeight:   "AVG(r.height)::FLOAT8",
+    models.TimeseriesMetricMaxHeight:    "MAX(r.height)::FLOAT8",
+    models.TimeseriesMetricMinHeight:    "MIN(r.height)::FLOAT8",
+}
+
+// GetEstateTimeseries computes a metric of the trees of an estate at the end
+// of each period, from the latest measurement of each tree taken by then, as
+// GetEstateStatsAsOf does for a single date. Periods start at the given times,
+// each one ending where the next starts and the last one at end.
+func (r *estateRepository) GetEstateTimeseries(estateID uuid.UUID, metric string, periods []time.Time, end time.Time) ([]models.TimeseriesPoint, error) {
+    logrus.Infof("Retrieving %s time series over %d periods for estate ID: %v", metric, len(periods), estateID)
+
+    column, ok := timeseriesMetricColumns[metric]
+    if !ok {
+        return nil, fmt.Errorf("unknown time series metric %q", metric)
+    }
+    starts := make(pq.StringArray, len(periods))
+    ends := make(pq.StringArray, len(periods))
+    for i, start := range periods {
+        starts[i] = start.Format(time.RFC3339)
+        ends[i] = end.Format(time.RFC3339)
+        if i+1 < len(periods) {
+            ends[i] = periods[i+1].Format(time.RFC3339)
+        }
+    }
+
+    // A measurement holds from the time it was taken until the next
+    // measurement of the same tree, so each period reads the measurements
+    // still holding when it ends
+    query := fmt.Sprintf(`
+        WITH periods AS (
+            SELECT * FROM unnest($2::TIMESTAMPTZ[], $3::TIMESTAMPTZ[]) AS p(period_start, period_end)
+        ), readings AS (
+            SELECT m.height, m.measured_at,
+                LEAD(m.measured_at) OVER (PARTITION BY m.tree_id ORDER BY m.measured_at, m.created_at) AS superseded_at
+            FROM tree_measurements m JOIN trees t ON t.id = m.tree_id
+            WHERE t.estate_id = $1 AND m.measured_at < $4
+        )
+        SELECT p.period_start, COUNT(r.height), %s
+        FROM periods p
+        LEFT JOIN readings r ON r.measured_at < p.period_end
+            AND (r.superseded_at IS NULL OR r.superseded_at >= p.period_end)
+        GROUP BY p.period_start
+        ORDER BY p.period_start
+    `, column)
+
+    rows, err := r.db.Query(query, estateID, starts, ends, end)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve time series for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    points := []models.TimeseriesPoint{}
+    for rows.Next() {
+        var point models.TimeseriesPoint
+        var value sql.NullFloat64
+        if err := rows.Scan(&point.Period.Time, &point.Trees, &value); err != nil {
+            logrus.Errorf("Failed to scan time series row: %v", err)
+            return nil, err
+        }
+        point.Period.Time = point.Period.UTC()
+        if value.Valid {
+            rounded := round2(value.Float64)
+            point.Value = &rounded
+        }
+        points = append(points, point)
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during time series rows iteration: %v", err)
+        return nil, err
+    }
+    return points, nil
+}
+
+const (
+    // minGrowthSpanDays is the shortest time between the first and last
+    // measurements of a tree for its growth rate to be measured.
+    minGrowthSpanDays = 30
+    secondsPerYear    = 365.25 * 24 * 60 * 60
+)
+
+// GetCohortGrowth computes the mean growth rate of the trees of an estate per
+// age cohort, from the first and last measurements of each tree taken from
+// from to before to. Cohorts are named by age at to and listed youngest first,
+// the trees without a planting date last; expectations are left to the caller.
+func (r *estateRepository) GetCohortGrowth(estateID uuid.UUID, from, to time.Time) ([]models.CohortGrowth, error) {
+    logrus.Infof("Retrieving cohort growth from %v to %v for estate ID: %v", from, to, estateID)
+
+    query := fmt.Sprintf(`
+        WITH readings AS (
+            SELECT m.tree_id, m.height, m.measured_at,
+                ROW_NUMBER() OVER (PARTITION BY m.tree_id ORDER BY m.measured_at, m.created_at) AS first_rank,
+                ROW_NUMBER() OVER (PARTITION BY m.tree_id ORDER BY m.measured_at DESC, m.created_at DESC) AS last_rank
+            FROM tree_measurements m JOIN trees t ON t.id = m.tree_id
+            WHERE t.estate_id = $1 AND m.measured_at >= $2 AND m.measured_at < $3
+        ), growth AS (
+            SELECT tree_id,
+                (MAX(height) FILTER (WHERE last_rank = 1) - MAX(height) FILTER (WHERE first_rank = 1))::FLOAT8
+                    / (EXTRACT(EPOCH FROM MAX(measured_at) - MIN(measured_at))::FLOAT8 / %[1]f) AS rate
+            FROM readings
+            GROUP BY tree_id
+            HAVING MAX(measured_at) - MIN(measured_at) >= INTERVAL '%[2]d days'
+        )
+        SELECT %[3]s AS cohort, COUNT(*), AVG(g.rate)
+        FROM growth g JOIN trees t ON t.id = g.tree_id
+        GROUP BY cohort
+    `, secondsPerYear, minGrowthSpanDays, ageCohortExpression("t.planted_on", "AGE($3, t.planted_on)"))
+
+    rows, err := r.db.Query(query, estateID, from, to)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve cohort growth for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    cohorts := []models.CohortGrowth{}
+    for rows.Next() {
+        var cohort models.CohortGrowth
+        if err := rows.Scan(&cohort.Cohort, &cohort.Trees, &cohort.GrowthRate); err != nil {
+            logrus.Errorf("Failed to scan cohort growth row: %v", err)
+            return nil, err
+        }
+        cohort.GrowthRate = round2(cohort.GrowthRate)
+        cohorts = append(cohorts, cohort)
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during cohort growth rows iteration: %v", err)
+        return nil, err
+    }
+
+    rank := ageCohortRanks()
+    sort.SliceStable(cohorts, func(i, j int) bool {
+        return rank[cohorts[i].Cohort] < rank[cohorts[j].Cohort]
+    })
+    return cohorts, nil
+}
+
+// Grouping sets of the portfolio stats query, as numbered by
+// GROUPING(estate_id, bucket).
+const (
+    portfolioEstateRow = 1 // Heights of one estate
+    portfolioBucketRow = 2 // Heights of all estates in one histogram bucket
+    portfolioTotalRow  = 3 // Heights of all estates
+)
+
+// GetPortfolioStats computes the height stats and planted coverage of the
+// active estates selected by a query, taken together and one by one, in a
+// single query. Estates are listed by name, code and ID; the histogram covers
+// the trees of all estates.
+func (r *estateRepository) GetPortfolioStats(query models.PortfolioQuery, options models.StatsOptions) (*models.PortfolioStats, error) {
+    logrus.Infof("Retrieving portfolio stats for %d estate IDs, tag %q and company %q", len(query.IDs), query.Tag, query.Company)
+
+    conditions := []string{"e.archived_at IS NULL"}
+    var args []interface{}
+    if len(query.IDs) > 0 {
+        ids := make(pq.StringArray, len(query.IDs))
+        for i, id := range query.IDs {
+            ids[i] = id.String()
+        }
+        args = append(args, ids)
+        conditions = append(conditions, fmt.Sprintf("e.id = ANY($%d::UUID[])", len(args)))
+    }
+    if query.Tag != "" {
+        args = append(args, query.Tag)
+        conditions = append(conditions, fmt.Sprintf("e.tags @> ARRAY[$%d]::TEXT[]", len(args)))
+    }
+    if query.Company != "" {
+        args = append(args, query.Company)
+        conditions = append(conditions, fmt.Sprintf("e.company = $%d", len(args)))
+    }
+    args = append(args, options.BucketWidth, percentileFractions(options))
+
+    // One grouping set per estate, one per histogram bucket and one for the
+    // whole portfolio. Estates without trees keep a row through the left join.
+    sqlQuery := fmt.Sprintf(`
+        WITH selected AS (
+            SELECT e.id, e.name, COALESCE(e.code, '') AS code, e.company, e.width, e.length
+            FROM estates e
+            WHERE %s
+        ), heights AS (
+            SELECT s.id AS estate_id, t.height, t.height / $%[2]d * $%[2]d AS bucket
+            FROM selected s LEFT JOIN trees t ON t.estate_id = s.id
+        ), grouped AS (
+            SELECT GROUPING(estate_id, bucket) AS grouping_set, estate_id, bucket,
+                %[3]s
+            FROM heights
+            GROUP BY GROUPING SETS ((estate_id), (bucket), ())
+        )
+        SELECT g.*, COALESCE(s.name, ''), COALESCE(s.code, ''), COALESCE(s.company, ''), COALESCE(s.width, 0), COALESCE(s.length, 0)
+        FROM grouped g LEFT JOIN selected s ON s.id = g.estate_id
+        ORDER BY g.grouping_set, s.name, s.code, g.estate_id, g.bucket
+    `, strings.Join(conditions, " AND "), len(args)-1, heightSummaryColumns(len(args)))
+
+    rows, err := r.db.Query(sqlQuery, args...)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve portfolio stats: %v", err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    portfolio := &models.PortfolioStats{Estates: []*models.PortfolioEstateStats{}}
+    var total *models.HeightStats
+    counts := map[int]int{}
+    plots := 0
+    for rows.Next() {
+        var groupingSet int
+        var estateID uuid.NullUUID
+        var bucket sql.NullInt64
+        var summary heightSummary
+        var estate models.PortfolioEstateStats
+        var width, length int
+        dest := append([]interface{}{&groupingSet, &estateID, &bucket}, summary.dest()...)
+        dest = append(dest, &estate.Name, &estate.Code, &estate.Company, &width, &length)
+        if err := rows.Scan(dest...); err != nil {
+            logrus.Errorf("Failed to scan portfolio stats row: %v", err)
+            return nil, err
+        }
+
+        switch groupingSet {
+        case portfolioEstateRow:
+            stats := summary.stats(options)
+            estate.ID = estateID.UUID
+            estate.Trees = stats.Count
+            estate.AreaHectares = round2(float64(width) * float64(length) * models.PlotAreaHectares)
+            estate.CoveragePercent = round2(100 * float64(stats.Count) / (float64(width) * float64(length)))
+            estate.Max, estate.Min = stats.Max, stats.Min
+            estate.Median, estate.Mean, estate.StdDev = stats.Median, stats.Mean, stats.StdDev
+            estate.Percentiles = stats.Percentiles
+            portfolio.Estates = append(portfolio.Estates, &estate)
+            plots += width * length
+        case portfolioBucketRow:
+            // Estates without trees fall in a NULL bucket
+            if bucket.Valid {
+                counts[int(bucket.Int64)] = summary.count
+            }
+        case portfolioTotalRow:
+            total = summary.stats(options)
+        }
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during portfolio stats rows iteration: %v", err)
+        return nil, err
+    }
+    if total == nil {
+        total = (&heightSummary{}).stats(options)
+    }
+
+    portfolio.HeightStats = *total
+    if total.Count > 0 {
+        portfolio.Histogram = histogramBuckets(total.Min, total.Max, options.BucketWidth, counts)
+    }
+    portfolio.EstateCount = len(portfolio.Estates)
+    portfolio.AreaHectares = round2(float64(plots) * models.PlotAreaHectares)
+    if plots > 0 {
+        portfolio.CoveragePercent = round2(100 * float64(total.Count) / float64(plots))
+    }
+    logrus.Infof("Portfolio stats retrieved successfully for %d estates", portfolio.EstateCount)
+    return portfolio, nil
+}
+
+// ListEstates retrieves one page of estates matching the query, using keyset
+// pagination on the sort key and the estate ID.
+func (r *estateRepository) ListEstates(query models.EstateQuery) (*models.EstatePage, error) {
+    logrus.Infof("Listing estates sorted by %s (desc=%t)", query.SortBy, query.Desc)
+
+    sortExpr := "e.created_at"
+    if query.SortBy == models.EstateSortArea {
+        sortExpr = "(e.width::BIGINT * e.length)"
+    }
+    direction, comparison := "ASC", ">"
+    if query.Desc {
+        direction, comparison = "DESC", "<"
+    }
+
+    var conditions []string
+    var args []interface{}
+    addCondition := func(format string, value interface{}) {
+        args = append(args, value)
+        conditions = append(conditions, fmt.Sprintf(format, len(args)))
+    }
+
+    // Archived estates are hidden unless explicitly requested
+    if query.Archived {
+        conditions = append(conditions, "e.archived_at IS NOT NULL")
+    } else {
+        conditions = append(conditions, "e.archived_at IS NULL")
+    }
+
+    treeCount := "(SELECT COUNT(*) FROM trees t WHERE t.estate_id = e.id)"
+    bounds := []struct {
+        expr  string
+        op    string
+        value *int
+    }{
+        {"e.width", ">=", query.MinWidth},
+        {"e.width", "<=", query.MaxWidth},
+        {"e.length", ">=", query.MinLength},
+        {"e.length", "<=", query.MaxLength},
+        {treeCount, ">=", query.MinTrees},
+        {treeCount, "<=", query.MaxTrees},
+    }
+    for _, b := range bounds {
+        if b.value != nil {
+            addCondition(b.expr+" "+b.op+" $%d", *b.value)
+        }
+    }
+    if query.Search != "" {
+        addCondition("(e.name ILIKE $%[1]d OR e.code ILIKE $%[1]d)", "%"+escapeLike(query.Search)+"%")
+    }
+    if query.Company != "" {
+        addCondition("e.company = $%d", query.Company)
+    }
+    if query.Region != "" {
+        addCondition("e.region = $%d", query.Region)
+    }
+    if query.Tag != "" {
+        addCondition("e.tags @> ARRAY[$%d]::TEXT[]", query.Tag)
+    }
+
+    if query.Cursor != "" {
+        sortValue, id, err := decodeEstateCursor(query.Cursor, query.SortBy)
+        if err != nil {
+            logrus.Warnf("Failed to decode estate cursor %q: %v", query.Cursor, err)
+            return nil, ErrInvalidCursor
+        }
+        args = append(args, sortValue, id)
+        conditions = append(conditions, fmt.Sprintf("(%s, e.id) %s ($%d, $%d)", sortExpr, comparison, len(args)-1, len(args)))
+    }
+
+    sqlQuery := "SELECT " + estateColumns + " FROM estates e WHERE " + strings.Join(conditions, " AND ")
+    // Fetch one extra row to find out whether there is a next page
+    args = append(args, query.Limit+1)
+    sqlQuery += fmt.Sprintf(" ORDER BY %s %s, e.id %s LIMIT $%d", sortExpr, direction, direction, len(args))
+
+    rows, err := r.db.Query(sqlQuery, args...)
+    if err != nil {
+        logrus.Errorf("Failed to list estates: %v", err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    page := &models.EstatePage{Estates: []*models.Estate{}}
+    for rows.Next() {
+        estate, err := scanEstate(rows)
+        if err != nil {
+            logrus.Errorf("Failed to scan estate row: %v", err)
+            return nil, err
+        }
+        page.Estates = append(page.Estates, estate)
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during estate rows iteration: %v", err)
+        return nil, err
+    }
+
+    if len(page.Estates) > query.Limit {
+        page.Estates = page.Estates[:query.Limit]
+        page.NextCursor = encodeEstateCursor(page.Estates[query.Limit-1], query.SortBy)
+    }
+    logrus.Infof("Listed %d estates", len(page.Estates))
+    return page, nil
+}
+
+// encodeEstateCursor builds an opaque cursor pointing just after the given estate.
+func encodeEstateCursor(estate *models.Estate, sortBy string) string {
+    var sortValue string
+    if sortBy == models.EstateSortArea {
+        sortValue = strconv.FormatInt(int64(estate.Width)*int64(estate.Length), 10)
+    } else {
+        sortValue = estate.CreatedAt.UTC().Format(time.RFC3339Nano)
+    }
+    return base64.RawURLEncoding.EncodeToString([]byte(sortValue + "|" + estate.ID.String()))
+}
+
+// decodeEstateCursor extracts the sort value and estate ID from a cursor.
+func decodeEstateCursor(cursor string, sortBy string) (interface{}, uuid.UUID, error) {
+    raw, err := base64.RawURLEncoding.DecodeString(cursor)
+    if err != nil {
+        return nil, uuid.Nil, err
+    }
+    parts := strings.SplitN(string(raw), "|", 2)
+    if len(parts) != 2 {
+        return nil, uuid.Nil, errors.New("malformed cursor")
+    }
+    id, err := uuid.Parse(parts[1])
+    if err != nil {
+        return nil, uuid.Nil, err
+    }
+    if sortBy == models.EstateSortArea {
+        area, err := strconv.ParseInt(parts[0], 10, 64)
+        return area, id, err
+    }
+    createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+    return createdAt, id, err
+}
+
+// escapeLike escapes the LIKE wildcards in a user supplied search term.
+func escapeLike(term string) string {
+    return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
+}
@@ -5,11 +5,17 @@ import (
     "errors"
     "testing"
     "sawitpro-recruitment/models"
+    "time"
+
     "github.com/DATA-DOG/go-sqlmock"
     "github.com/google/uuid"
+    "github.com/lib/pq"
     "github.com/stretchr/testify/assert"
 )
 
+// estateRowColumns are the columns returned by queries selecting estateColumns.
+var estateRowColumns = []string{"id", "width", "length", "name", "code", "company", "region", "notes", "created_at", "updated_at", "archived_at", "tags", "latitude", "longitude", "rotation"}
+
 func TestEstateRepository_CreateEstate(t *testing.T) {
     db, mock, err := sqlmock.New()
     assert.NoError(t, err)
@@ -17,14 +23,25 @@ func TestEstateRepository_CreateEstate(t *testing.T) {
 
     repo := NewEstateRepository(db)
 
+    now := time.Now().UTC()
     estate := &models.Estate{
-        ID:     uuid.New(),
-        Width:  100,
-        Length: 200,
+        ID:        uuid.New(),
+        Width:     100,
+        Length:    200,
+        Name:      "Riau Block A",
+        Code:      "RIAU-01",
+        Company:   "PT Sawit Makmur",
+        Region:    "Riau",
+        Location:  &models.Location{Latitude: 0.51, Longitude: 101.45, Rotation: 15},
+        CreatedAt: now,
+        UpdatedAt: now,
     }
 
-    mock.ExpectExec(`INSERT INTO estates \(id, width, length\) VALUES \(\$1, \$2, \$3\)`).
-        WithArgs(estate.ID, estate.Width, estate.Length).
+    mock.ExpectExec(`WITH estate AS \(\s+INSERT INTO estates \(id, width, length, name, code, company, region, notes, created_at, updated_at, tags, latitude, longitude, rotation\).+` +
+        `INSERT INTO estate_height_summaries \(estate_id\) SELECT id FROM estate`).
+        WithArgs(estate.ID, estate.Width, estate.Length, estate.Name, estate.Code,
+            estate.Company, estate.Region, estate.Notes, estate.CreatedAt, estate.UpdatedAt, pq.StringArray(estate.Tags),
+            estate.Location.Latitude, estate.Location.Longitude, estate.Location.Rotation).
         WillReturnResult(sqlmock.NewResult(1, 1))
 
     err = repo.CreateEstate(estate)
@@ -45,8 +62,7 @@ func TestEstateRepository_CreateEstate_Error(t *testing.T) {
         Length: 200,
     }
 
-    mock.ExpectExec(`INSERT INTO estates \(id, width, length\) VALUES \(\$1, \$2, \$3\)`).
-        WithArgs(estate.ID, estate.Width, estate.Length).
+    mock.ExpectExec(`INSERT INTO estates \(id, width, length, name, code, company, region, notes, created_at, updated_at, tags, latitude, longitude, rotation\)`).
         WillReturnError(errors.New("insert error"))
 
     err = repo.CreateEstate(estate)
@@ -54,6 +70,28 @@ func TestEstateRepository_CreateEstate_Error(t *testing.T) {
     assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestEstateRepository_CreateEstate_DuplicateCode(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estate := &models.Estate{
+        ID:     uuid.New(),
+        Width:  100,
+        Length: 200,
+        Code:   "RIAU-01",
+    }
+
+    mock.ExpectExec(`INSERT INTO estates`).
+        WillReturnError(&pq.Error{Code: "23505"})
+
+    err = repo.CreateEstate(estate)
+    assert.ErrorIs(t, err, ErrDuplicateEstateCode)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestEstateRepository_GetEstateByID(t *testing.T) {
     db, mock, err := sqlmock.New()
     assert.NoError(t, err)
@@ -62,16 +100,24 @@ func TestEstateRepository_GetEstateByID(t *testing.T) {
     repo := NewEstateRepository(db)
 
     estateID := uuid.New()
+    now := time.Now().UTC()
     expectedEstate := &models.Estate{
-        ID:     estateID,
-        Width:  100,
-        Length: 200,
+        ID:        estateID,
+        Width:     100,
+        Length:    200,
+        Name:      "Riau Block A",
+        Code:      "RIAU-01",
+        Tags:      []string{"organic", "north"},
+        Location:  &models.Location{Latitude: 1.25, Longitude: 101.5, Rotation: 30},
+        CreatedAt: now,
+        UpdatedAt: now,
     }
 
-    rows := sqlmock.NewRows([]string{"id", "width", "length"}).
-        AddRow(expectedEstate.ID, expectedEstate.Width, expectedEstate.Length)
+    rows := sqlmock.NewRows(estateRowColumns).
+        AddRow(expectedEstate.ID, expectedEstate.Width, expectedEstate.Length, expectedEstate.Name, expectedEstate.Code,
+            expectedEstate.Company, expectedEstate.Region, expectedEstate.Notes, expectedEstate.CreatedAt, expectedEstate.UpdatedAt, nil, "{organic,north}", 1.25, 101.5, 30.0)
 
-    mock.ExpectQuery(`SELECT id, width, length FROM estates WHERE id = \$1`).
+    mock.ExpectQuery(`SELECT id, width, length, name, COALESCE\(code, ''\), company, region, notes, created_at, updated_at, archived_at, tags, latitude, longitude, rotation FROM estates WHERE id = \$1`).
         WithArgs(estateID).
         WillReturnRows(rows)
 
@@ -90,7 +136,7 @@ func TestEstateRepository_GetEstateByID_NoRows(t *testing.T) {
 
     estateID := uuid.New()
 
-    mock.ExpectQuery(`SELECT id, width, length FROM estates WHERE id = \$1`).
+    mock.ExpectQuery(`SELECT id, width, length, name, COALESCE\(code, ''\), company, region, notes, created_at, updated_at, archived_at, tags, latitude, longitude, rotation FROM estates WHERE id = \$1`).
         WithArgs(estateID).
         WillReturnError(sql.ErrNoRows)
 
@@ -109,7 +155,7 @@ func TestEstateRepository_GetEstateByID_Error(t *testing.T) {
 
     estateID := uuid.New()
 
-    mock.ExpectQuery(`SELECT id, width, length FROM estates WHERE id = \$1`).
+    mock.ExpectQuery(`SELECT id, width, length, name, COALESCE\(code, ''\), company, region, notes, created_at, updated_at, archived_at, tags, latitude, longitude, rotation FROM estates WHERE id = \$1`).
         WithArgs(estateID).
         WillReturnError(errors.New("query error"))
 
@@ -119,6 +165,9 @@ func TestEstateRepository_GetEstateByID_Error(t *testing.T) {
     assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+// statsOptions are the options the stats tests ask for.
+var statsOptions = models.StatsOptions{Percentiles: []float64{10, 90}, BucketWidth: 5}
+
 func TestEstateRepository_GetEstateStats(t *testing.T) {
     db, mock, err := sqlmock.New()
     assert.NoError(t, err)
@@ -127,31 +176,58 @@ func TestEstateRepository_GetEstateStats(t *testing.T) {
     repo := NewEstateRepository(db)
 
     estateID := uuid.New()
-    expectedCount, expectedMax, expectedMin, expectedMedian := 10, 50, 5, 25
+    mock.ExpectQuery(`SELECT tree_count, height_sum, other_heights, height_counts FROM estate_height_summaries WHERE estate_id = \$1`).
+        WithArgs(estateID).
+        WillReturnRows(heightSummaryRow(map[int]int{3: 1, 8: 1, 15: 1, 16: 1}))
 
-    rows := sqlmock.NewRows([]string{"count", "max", "min", "median"}).
-        AddRow(expectedCount, expectedMax, expectedMin, expectedMedian)
+    stats, err := repo.GetEstateStats(estateID, nil, statsOptions)
+    assert.NoError(t, err)
+    assert.Equal(t, 4, stats.Count)
+    assert.Equal(t, 11.5, stats.Median)
+    assert.Equal(t, []models.Percentile{{P: 10, Height: 4.5}, {P: 90, Height: 15.7}}, stats.Percentiles)
+    assert.Len(t, stats.Histogram, 4)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-    query := `
-        SELECT 
-            COUNT\(\*\), 
-            MAX\(height\), 
-            MIN\(height\), 
-            PERCENTILE_CONT\(0.5\) WITHIN GROUP \(ORDER BY height\) 
-        FROM trees 
-        WHERE estate_id = \$1
-    `
+func TestEstateRepository_GetEstateStats_FromTrees(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
 
-    mock.ExpectQuery(query).
+    // A tree of a tall species is not counted by height in the summary
+    estateID := uuid.New()
+    mock.ExpectQuery(`FROM estate_height_summaries WHERE estate_id = \$1`).
         WithArgs(estateID).
-        WillReturnRows(rows)
+        WillReturnRows(heightSummaryRow(map[int]int{3: 1, 8: 1, 15: 1, 45: 1}))
+    summary := sqlmock.NewRows([]string{"count", "max", "min", "median", "avg", "stddev", "percentiles"}).
+        AddRow(4, 16, 3, 9.5, 9.5, 4.609772228646444, "{4.5,14.5}")
+    histogram := sqlmock.NewRows([]string{"bucket", "count"}).
+        AddRow(0, 1).
+        AddRow(5, 1).
+        AddRow(15, 2)
 
-    count, max, min, median, err := repo.GetEstateStats(estateID)
+    mock.ExpectQuery(`WITH heights AS \(SELECT height FROM trees WHERE estate_id = \$1\)\s+` +
+        `SELECT COUNT\(height\), MAX\(height\), MIN\(height\),\s+PERCENTILE_CONT\(0.5\) WITHIN GROUP \(ORDER BY height\),\s+` +
+        `AVG\(height\), STDDEV_POP\(height\),\s+PERCENTILE_CONT\(\$2::FLOAT8\[\]\) WITHIN GROUP \(ORDER BY height\)`).
+        WithArgs(estateID, pq.Float64Array{0.1, 0.9}).
+        WillReturnRows(summary)
+    mock.ExpectQuery(`SELECT height / \$2 \* \$2 AS bucket, COUNT\(\*\)\s+FROM heights\s+GROUP BY bucket`).
+        WithArgs(estateID, 5).
+        WillReturnRows(histogram)
+
+    stats, err := repo.GetEstateStats(estateID, nil, statsOptions)
     assert.NoError(t, err)
-    assert.Equal(t, expectedCount, count)
-    assert.Equal(t, expectedMax, max)
-    assert.Equal(t, expectedMin, min)
-    assert.Equal(t, expectedMedian, median)
+    assert.Equal(t, &models.HeightStats{
+        Count: 4, Max: 16, Min: 3, Median: 9.5, Mean: 9.5, StdDev: 4.61,
+        Percentiles: []models.Percentile{{P: 10, Height: 4.5}, {P: 90, Height: 14.5}},
+        BucketWidth: 5,
+        // The empty bucket between the trees is listed
+        Histogram: []models.HistogramBucket{
+            {From: 0, To: 4, Count: 1}, {From: 5, To: 9, Count: 1}, {From: 10, To: 14, Count: 0}, {From: 15, To: 19, Count: 2},
+        },
+    }, stats)
     assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -164,25 +240,835 @@ func TestEstateRepository_GetEstateStats_Error(t *testing.T) {
 
     estateID := uuid.New()
 
-    query := `
-        SELECT 
-            COUNT\(\*\), 
-            MAX\(height\), 
-            MIN\(height\), 
-            PERCENTILE_CONT\(0.5\) WITHIN GROUP \(ORDER BY height\) 
-        FROM trees 
-        WHERE estate_id = \$1
-    `
+    // Estates created before the summaries were introduced have none
+    mock.ExpectQuery(`FROM estate_height_summaries`).
+        WithArgs(estateID).
+        WillReturnError(sql.ErrNoRows)
+    mock.ExpectQuery(`WITH heights AS`).
+        WithArgs(estateID, pq.Float64Array{0.1, 0.9}).
+        WillReturnError(errors.New("query error"))
+
+    stats, err := repo.GetEstateStats(estateID, nil, statsOptions)
+    assert.Error(t, err)
+    assert.Nil(t, stats)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_RebuildHeightSummary(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+    mock.ExpectBegin()
+    mock.ExpectExec(`INSERT INTO estate_height_summaries \(estate_id\) SELECT id FROM estates WHERE id = \$1\s+ON CONFLICT \(estate_id\) DO NOTHING`).
+        WithArgs(estateID).
+        WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectQuery(heightSummaryQuery).
+        WithArgs(estateID).
+        WillReturnRows(heightSummaryRow(map[int]int{10: 2}))
+    mock.ExpectQuery(`SELECT height, COUNT\(\*\) FROM trees WHERE estate_id = \$1 GROUP BY height`).
+        WithArgs(estateID).
+        WillReturnRows(sqlmock.NewRows([]string{"height", "count"}).AddRow(10, 2).AddRow(12, 1))
+    expectHeightSummaryStored(mock, estateID, map[int]int{10: 2, 12: 1})
+    mock.ExpectCommit()
+
+    drifted, err := repo.RebuildHeightSummary(estateID)
+    assert.NoError(t, err)
+    assert.True(t, drifted)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_RebuildHeightSummary_NotFound(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+    mock.ExpectBegin()
+    mock.ExpectExec(`INSERT INTO estate_height_summaries`).
+        WithArgs(estateID).
+        WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectQuery(heightSummaryQuery).
+        WithArgs(estateID).
+        WillReturnError(sql.ErrNoRows)
+    mock.ExpectRollback()
+
+    _, err = repo.RebuildHeightSummary(estateID)
+    assert.ErrorIs(t, err, ErrEstateNotFound)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_ListEstateIDs(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    first, second := uuid.New(), uuid.New()
+    mock.ExpectQuery(`SELECT id FROM estates ORDER BY id`).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(first).AddRow(second))
+
+    ids, err := repo.ListEstateIDs()
+    assert.NoError(t, err)
+    assert.Equal(t, []uuid.UUID{first, second}, ids)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_ListEstates(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    first, second := uuid.New(), uuid.New()
+    rows := sqlmock.NewRows(estateRowColumns).
+        AddRow(first, 10, 20, "", "", "", "", "", createdAt, createdAt, nil, "{}", nil, nil, nil).
+        AddRow(second, 30, 40, "", "", "", "", "", createdAt.Add(time.Hour), createdAt.Add(time.Hour), nil, "{}", nil, nil, nil)
+
+    minWidth, minTrees := 5, 1
+    mock.ExpectQuery(`SELECT .+ FROM estates e WHERE e.archived_at IS NULL AND e.width >= \$1 AND \(SELECT COUNT\(\*\) FROM trees t WHERE t.estate_id = e.id\) >= \$2 ORDER BY e.created_at ASC, e.id ASC LIMIT \$3`).
+        WithArgs(minWidth, minTrees, 2).
+        WillReturnRows(rows)
+
+    page, err := repo.ListEstates(models.EstateQuery{
+        SortBy:   models.EstateSortCreatedAt,
+        Limit:    1,
+        MinWidth: &minWidth,
+        MinTrees: &minTrees,
+    })
+    assert.NoError(t, err)
+    assert.Len(t, page.Estates, 1)
+    assert.Equal(t, first, page.Estates[0].ID)
+    assert.NotEmpty(t, page.NextCursor)
+    assert.NoError(t, mock.ExpectationsWereMet())
+
+    // The cursor resumes after the last estate of the previous page
+    mock.ExpectQuery(`SELECT .+ FROM estates e WHERE e.archived_at IS NULL AND \(e.created_at, e.id\) > \(\$1, \$2\) ORDER BY e.created_at ASC, e.id ASC LIMIT \$3`).
+        WithArgs(createdAt, first, 2).
+        WillReturnRows(sqlmock.NewRows(estateRowColumns).
+            AddRow(second, 30, 40, "", "", "", "", "", createdAt.Add(time.Hour), createdAt.Add(time.Hour), nil, "{}", nil, nil, nil))
+
+    page, err = repo.ListEstates(models.EstateQuery{
+        SortBy: models.EstateSortCreatedAt,
+        Limit:  1,
+        Cursor: page.NextCursor,
+    })
+    assert.NoError(t, err)
+    assert.Len(t, page.Estates, 1)
+    assert.Equal(t, second, page.Estates[0].ID)
+    assert.Empty(t, page.NextCursor)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_ListEstates_SortByAreaDesc(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+    cursor := encodeEstateCursor(&models.Estate{ID: estateID, Width: 10, Length: 20}, models.EstateSortArea)
+
+    mock.ExpectQuery(`SELECT .+ FROM estates e WHERE e.archived_at IS NULL AND \(\(e.width::BIGINT \* e.length\), e.id\) < \(\$1, \$2\) ORDER BY \(e.width::BIGINT \* e.length\) DESC, e.id DESC LIMIT \$3`).
+        WithArgs(int64(200), estateID, 21).
+        WillReturnRows(sqlmock.NewRows(estateRowColumns))
+
+    page, err := repo.ListEstates(models.EstateQuery{
+        SortBy: models.EstateSortArea,
+        Desc:   true,
+        Limit:  20,
+        Cursor: cursor,
+    })
+    assert.NoError(t, err)
+    assert.Empty(t, page.Estates)
+    assert.Empty(t, page.NextCursor)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_ListEstates_InvalidCursor(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    page, err := repo.ListEstates(models.EstateQuery{
+        SortBy: models.EstateSortCreatedAt,
+        Limit:  20,
+        Cursor: "not-a-cursor",
+    })
+    assert.ErrorIs(t, err, ErrInvalidCursor)
+    assert.Nil(t, page)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_ListEstates_Search(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    mock.ExpectQuery(`SELECT .+ FROM estates e WHERE e.archived_at IS NULL AND \(e.name ILIKE \$1 OR e.code ILIKE \$1\) AND e.company = \$2 AND e.region = \$3 AND e.tags @> ARRAY\[\$4\]::TEXT\[\] ORDER BY`).
+        WithArgs(`%50\%%`, "PT Sawit Makmur", "Riau", "organic", 21).
+        WillReturnRows(sqlmock.NewRows(estateRowColumns))
+
+    page, err := repo.ListEstates(models.EstateQuery{
+        SortBy:  models.EstateSortCreatedAt,
+        Limit:   20,
+        Search:  "50%",
+        Company: "PT Sawit Makmur",
+        Region:  "Riau",
+        Tag:     "organic",
+    })
+    assert.NoError(t, err)
+    assert.Empty(t, page.Estates)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_UpdateEstate(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estate := &models.Estate{
+        ID:        uuid.New(),
+        Width:     30,
+        Length:    40,
+        Name:      "Extended",
+        UpdatedAt: time.Now().UTC(),
+    }
+
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT id FROM estates WHERE id = \$1 FOR UPDATE`).
+        WithArgs(estate.ID).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(estate.ID))
+    mock.ExpectExec(`UPDATE estates\s+SET width = \$2, length = \$3, .+ WHERE id = \$1 AND NOT EXISTS \(SELECT 1 FROM trees WHERE estate_id = \$1 AND \(x > \$2 OR y > \$3\)\)`).
+        WithArgs(estate.ID, estate.Width, estate.Length, estate.Name, estate.Code,
+            estate.Company, estate.Region, estate.Notes, estate.UpdatedAt, pq.StringArray(estate.Tags), nil, nil, nil).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectCommit()
+
+    err = repo.UpdateEstate(estate)
+    assert.NoError(t, err)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_UpdateEstate_TreesOutOfBounds(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estate := &models.Estate{ID: uuid.New(), Width: 5, Length: 5}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT id FROM estates WHERE id = \$1 FOR UPDATE`).
+        WithArgs(estate.ID).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(estate.ID))
+    mock.ExpectExec(`UPDATE estates`).
+        WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectRollback()
+
+    err = repo.UpdateEstate(estate)
+    assert.ErrorIs(t, err, ErrTreesOutOfBounds)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_UpdateEstate_DuplicateCode(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estate := &models.Estate{ID: uuid.New(), Width: 5, Length: 5, Code: "RIAU-01"}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT id FROM estates WHERE id = \$1 FOR UPDATE`).
+        WithArgs(estate.ID).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(estate.ID))
+    mock.ExpectExec(`UPDATE estates`).
+        WillReturnError(&pq.Error{Code: "23505"})
+    mock.ExpectRollback()
+
+    err = repo.UpdateEstate(estate)
+    assert.ErrorIs(t, err, ErrDuplicateEstateCode)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_UpdateEstate_NotFound(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estate := &models.Estate{ID: uuid.New(), Width: 5, Length: 5}
+
+    // A deleted estate is not mistaken for one with trees out of bounds
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT id FROM estates WHERE id = \$1 FOR UPDATE`).
+        WithArgs(estate.ID).
+        WillReturnError(sql.ErrNoRows)
+    mock.ExpectRollback()
+
+    err = repo.UpdateEstate(estate)
+    assert.ErrorIs(t, err, ErrEstateNotFound)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_GetTreesOutOfBounds(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+    treeID := uuid.New()
+    rows := sqlmock.NewRows([]string{"id", "estate_id", "x", "y", "height"}).
+        AddRow(treeID, estateID, 8, 3, 10)
+
+    mock.ExpectQuery(`SELECT id, estate_id, x, y, height FROM trees WHERE estate_id = \$1 AND \(x > \$2 OR y > \$3\) ORDER BY y, x LIMIT \$4`).
+        WithArgs(estateID, 5, 20, 101).
+        WillReturnRows(rows)
+
+    trees, err := repo.GetTreesOutOfBounds(estateID, 5, 20, 101)
+    assert.NoError(t, err)
+    assert.Equal(t, []*models.Tree{{ID: treeID, EstateID: estateID, X: 8, Y: 3, Height: 10}}, trees)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_GetEstateByID_Archived(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+    now := time.Now().UTC()
+    rows := sqlmock.NewRows(estateRowColumns).
+        AddRow(estateID, 10, 20, "", "", "", "", "", now, now, now, "{}", nil, nil, nil)
+
+    mock.ExpectQuery(`SELECT .+ FROM estates WHERE id = \$1`).
+        WithArgs(estateID).
+        WillReturnRows(rows)
+
+    estate, err := repo.GetEstateByID(estateID)
+    assert.NoError(t, err)
+    assert.True(t, estate.IsArchived())
+    assert.Equal(t, now, *estate.ArchivedAt)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_ListEstates_Archived(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    mock.ExpectQuery(`SELECT .+ FROM estates e WHERE e.archived_at IS NOT NULL ORDER BY`).
+        WithArgs(21).
+        WillReturnRows(sqlmock.NewRows(estateRowColumns))
+
+    page, err := repo.ListEstates(models.EstateQuery{
+        SortBy:   models.EstateSortCreatedAt,
+        Limit:    20,
+        Archived: true,
+    })
+    assert.NoError(t, err)
+    assert.Empty(t, page.Estates)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_ArchiveEstate(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+    archivedAt := time.Now().UTC()
+
+    mock.ExpectExec(`UPDATE estates SET archived_at = \$2 WHERE id = \$1`).
+        WithArgs(estateID, sql.NullTime{Time: archivedAt, Valid: true}).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+
+    err = repo.ArchiveEstate(estateID, archivedAt)
+    assert.NoError(t, err)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_RestoreEstate_NotFound(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+
+    mock.ExpectExec(`UPDATE estates SET archived_at = \$2 WHERE id = \$1`).
+        WithArgs(estateID, sql.NullTime{}).
+        WillReturnResult(sqlmock.NewResult(0, 0))
+
+    err = repo.RestoreEstate(estateID)
+    assert.ErrorIs(t, err, ErrEstateNotFound)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_DeleteEstate(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+
+    mock.ExpectBegin()
+    mock.ExpectExec(`DELETE FROM trees WHERE estate_id = \$1`).
+        WithArgs(estateID).
+        WillReturnResult(sqlmock.NewResult(0, 3))
+    mock.ExpectExec(`DELETE FROM estates WHERE id = \$1`).
+        WithArgs(estateID).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectCommit()
+
+    err = repo.DeleteEstate(estateID)
+    assert.NoError(t, err)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_DeleteEstate_NotFound(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+
+    mock.ExpectBegin()
+    mock.ExpectExec(`DELETE FROM trees WHERE estate_id = \$1`).
+        WithArgs(estateID).
+        WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectExec(`DELETE FROM estates WHERE id = \$1`).
+        WithArgs(estateID).
+        WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectRollback()
+
+    err = repo.DeleteEstate(estateID)
+    assert.ErrorIs(t, err, ErrEstateNotFound)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_DeleteEstate_Error(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+
+    mock.ExpectBegin()
+    mock.ExpectExec(`DELETE FROM trees WHERE estate_id = \$1`).
+        WithArgs(estateID).
+        WillReturnError(errors.New("delete error"))
+    mock.ExpectRollback()
+
+    err = repo.DeleteEstate(estateID)
+    assert.Error(t, err)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_GetEstateStats_Region(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+    region := &models.Region{
+        BBox:    &models.BoundingBox{X1: 1, Y1: 2, X2: 10, Y2: 20},
+        Rows:    &models.Range{From: 5, To: 8},
+        Polygon: []models.Plot{{X: 1, Y: 1}, {X: 9, Y: 1}, {X: 1, Y: 9}},
+    }
+
+    // The filters are pushed down into both queries, ahead of their own arguments
+    filter := `WHERE estate_id = \$1 AND x BETWEEN \$2 AND \$3 AND y BETWEEN \$4 AND \$5 ` +
+        `AND y BETWEEN \$6 AND \$7 AND location <@ \$8::polygon\)`
+    mock.ExpectQuery(filter + `[\s\S]*PERCENTILE_CONT\(\$9::FLOAT8\[\]\)`).
+        WithArgs(estateID, 1, 10, 2, 20, 5, 8, "((1,1),(9,1),(1,9))", pq.Float64Array{0.1, 0.9}).
+        WillReturnRows(sqlmock.NewRows([]string{"count", "max", "min", "median", "avg", "stddev", "percentiles"}).
+            AddRow(1, 7, 7, 7, 7, 0, "{7,7}"))
+    mock.ExpectQuery(filter + `[\s\S]*SELECT height / \$9 \* \$9 AS bucket`).
+        WithArgs(estateID, 1, 10, 2, 20, 5, 8, "((1,1),(9,1),(1,9))", 5).
+        WillReturnRows(sqlmock.NewRows([]string{"bucket", "count"}).AddRow(5, 1))
+
+    stats, err := repo.GetEstateStats(estateID, region, statsOptions)
+    assert.NoError(t, err)
+    assert.Equal(t, 1, stats.Count)
+    assert.Equal(t, []models.HistogramBucket{{From: 5, To: 9, Count: 1}}, stats.Histogram)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_GetEstateStatsAsOf(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+    before := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+    summary := sqlmock.NewRows([]string{"count", "max", "min", "median", "avg", "stddev", "percentiles"}).
+        AddRow(2, 11, 8, 9.5, 9.5, 1.5, "{8.3,10.7}")
+    histogram := sqlmock.NewRows([]string{"bucket", "count"}).
+        AddRow(5, 1).
+        AddRow(10, 1)
+
+    mock.ExpectQuery(`WITH heights AS \(\s+SELECT DISTINCT ON \(m.tree_id\) m.height\s+FROM tree_measurements m JOIN trees t ON t.id = m.tree_id\s+WHERE t.estate_id = \$1 AND m.measured_at < \$2`).
+        WithArgs(estateID, before, pq.Float64Array{0.1, 0.9}).
+        WillReturnRows(summary)
+    mock.ExpectQuery(`SELECT height / \$3 \* \$3 AS bucket`).
+        WithArgs(estateID, before, 5).
+        WillReturnRows(histogram)
+
+    stats, err := repo.GetEstateStatsAsOf(estateID, before, nil, statsOptions)
+    assert.NoError(t, err)
+    assert.Equal(t, []int{2, 11, 8}, []int{stats.Count, stats.Max, stats.Min})
+    // The median keeps its fraction
+    assert.Equal(t, 9.5, stats.Median)
+    assert.Equal(t, []models.HistogramBucket{{From: 5, To: 9, Count: 1}, {From: 10, To: 14, Count: 1}}, stats.Histogram)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_GetEstateStatsAsOf_NoMeasurements(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
 
-    mock.ExpectQuery(query).
+    estateID := uuid.New()
+    before := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+    rows := sqlmock.NewRows([]string{"count", "max", "min", "median", "avg", "stddev", "percentiles"}).
+        AddRow(0, nil, nil, nil, nil, nil, nil)
+
+    mock.ExpectQuery(`WITH heights AS`).
+        WithArgs(estateID, before, pq.Float64Array{0.1, 0.9}).
+        WillReturnRows(rows)
+
+    // Without trees the histogram is not queried
+    stats, err := repo.GetEstateStatsAsOf(estateID, before, nil, statsOptions)
+    assert.NoError(t, err)
+    assert.Equal(t, &models.HeightStats{BucketWidth: 5, Percentiles: []models.Percentile{}, Histogram: []models.HistogramBucket{}}, stats)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_GetEstateStatsBreakdown(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+    groupColumns := []string{"tree_group", "count", "max", "min", "median"}
+    mock.ExpectQuery(`SELECT COALESCE\(species, ''\) AS tree_group, COUNT\(\*\), MAX\(height\), MIN\(height\)`).
+        WithArgs(estateID).
+        WillReturnRows(sqlmock.NewRows(groupColumns).
+            AddRow("", 1, 10, 10, 10).
+            AddRow("tenera", 2, 17, 12, 14.5))
+    // Cohort names sort alphabetically in SQL and are put back in age order
+    mock.ExpectQuery(`SELECT CASE WHEN planted_on IS NULL THEN 'unknown' WHEN DATE_PART\('year', AGE\(planted_on\)\) <= 3 THEN 'immature'.* ELSE 'senile' END AS tree_group`).
+        WithArgs(estateID).
+        WillReturnRows(sqlmock.NewRows(groupColumns).
+            AddRow("immature", 1, 4, 4, 4).
+            AddRow("prime", 2, 20, 15, 18).
+            AddRow("unknown", 1, 10, 10, 10).
+            AddRow("young", 1, 12, 12, 12))
+
+    breakdown, err := repo.GetEstateStatsBreakdown(estateID)
+    assert.NoError(t, err)
+    assert.Equal(t, []models.TreeGroupStats{
+        {Group: "", Count: 1, Max: 10, Min: 10, Median: 10},
+        {Group: "tenera", Count: 2, Max: 17, Min: 12, Median: 14.5},
+    }, breakdown.Species)
+    cohorts := []string{}
+    for _, cohort := range breakdown.AgeCohorts {
+        cohorts = append(cohorts, cohort.Group)
+    }
+    assert.Equal(t, []string{"immature", "young", "prime", "unknown"}, cohorts)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_GetEstateStatsBreakdown_Error(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+    mock.ExpectQuery(`AS tree_group`).
         WithArgs(estateID).
         WillReturnError(errors.New("query error"))
 
-    count, max, min, median, err := repo.GetEstateStats(estateID)
+    breakdown, err := repo.GetEstateStatsBreakdown(estateID)
+    assert.Error(t, err)
+    assert.Nil(t, breakdown)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_GetEstateTimeseries(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+    jan := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    feb := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+    end := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+    mock.ExpectQuery(`LEAD\(m.measured_at\) OVER \(PARTITION BY m.tree_id ORDER BY m.measured_at, m.created_at\) AS superseded_at.*SELECT p.period_start, COUNT\(r.height\), PERCENTILE_CONT\(0.5\)`).
+        WithArgs(estateID,
+            pq.StringArray{"2024-01-01T00:00:00Z", "2024-02-01T00:00:00Z"},
+            pq.StringArray{"2024-02-01T00:00:00Z", "2024-03-01T00:00:00Z"},
+            end).
+        WillReturnRows(sqlmock.NewRows([]string{"period_start", "count", "value"}).
+            AddRow(jan, 0, nil).
+            AddRow(feb, 3, 12.333))
+
+    points, err := repo.GetEstateTimeseries(estateID, models.TimeseriesMetricMedianHeight, []time.Time{jan, feb}, end)
+    assert.NoError(t, err)
+    if assert.Len(t, points, 2) {
+        assert.Equal(t, jan, points[0].Period.Time)
+        assert.Equal(t, 0, points[0].Trees)
+        assert.Nil(t, points[0].Value)
+        assert.Equal(t, 3, points[1].Trees)
+        if assert.NotNil(t, points[1].Value) {
+            assert.Equal(t, 12.33, *points[1].Value)
+        }
+    }
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_GetEstateTimeseries_UnknownMetric(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    points, err := repo.GetEstateTimeseries(uuid.New(), "volume", []time.Time{time.Now()}, time.Now())
     assert.Error(t, err)
-    assert.Equal(t, 0, count)
-    assert.Equal(t, 0, max)
-    assert.Equal(t, 0, min)
-    assert.Equal(t, 0, median)
+    assert.Nil(t, points)
     assert.NoError(t, mock.ExpectationsWereMet())
-}
\ No newline at end of file
+}
+
+func TestEstateRepository_GetCohortGrowth(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+    from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+    to := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    mock.ExpectQuery(`HAVING MAX\(measured_at\) - MIN\(measured_at\) >= INTERVAL '30 days'.*SELECT CASE WHEN t.planted_on IS NULL THEN 'unknown' WHEN DATE_PART\('year', AGE\(\$3, t.planted_on\)\) <= 3 THEN 'immature'.* AS cohort, COUNT\(\*\), AVG\(g.rate\)`).
+        WithArgs(estateID, from, to).
+        WillReturnRows(sqlmock.NewRows([]string{"cohort", "count", "avg"}).
+            AddRow("prime", 4, 0.5549).
+            AddRow("unknown", 1, 0.2).
+            AddRow("immature", 2, 1.1))
+
+    cohorts, err := repo.GetCohortGrowth(estateID, from, to)
+    assert.NoError(t, err)
+    assert.Equal(t, []models.CohortGrowth{
+        {Cohort: "immature", Trees: 2, GrowthRate: 1.1},
+        {Cohort: "prime", Trees: 4, GrowthRate: 0.55},
+        {Cohort: "unknown", Trees: 1, GrowthRate: 0.2},
+    }, cohorts)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_GetCohortGrowth_Error(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+    mock.ExpectQuery(`AS cohort`).
+        WillReturnError(errors.New("query error"))
+
+    cohorts, err := repo.GetCohortGrowth(estateID, time.Now().AddDate(-1, 0, 0), time.Now())
+    assert.Error(t, err)
+    assert.Nil(t, cohorts)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_GetPortfolioStats(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    first, second := uuid.New(), uuid.New()
+    columns := []string{"grouping_set", "estate_id", "bucket", "count", "max", "min", "median", "avg", "stddev", "percentiles",
+        "name", "code", "company", "width", "length"}
+    rows := sqlmock.NewRows(columns).
+        AddRow(1, first, nil, 3, 16, 3, 9, 9.33, 5.31, "{4.2,14.6}", "Alpha", "A-1", "PT Sawit", 10, 10).
+        AddRow(1, second, nil, 0, nil, nil, nil, nil, nil, nil, "Beta", "", "PT Sawit", 20, 5).
+        AddRow(2, nil, 0, 1, 3, 3, 3, 3, 0, "{3,3}", "", "", "", 0, 0).
+        AddRow(2, nil, 5, 1, 9, 9, 9, 9, 0, "{9,9}", "", "", "", 0, 0).
+        AddRow(2, nil, 15, 1, 16, 16, 16, 16, 0, "{16,16}", "", "", "", 0, 0).
+        AddRow(2, nil, nil, 0, nil, nil, nil, nil, nil, nil, "", "", "", 0, 0).
+        AddRow(3, nil, nil, 3, 16, 3, 9, 9.33, 5.31, "{4.2,14.6}", "", "", "", 0, 0)
+
+    // Filters, bucket width and percentiles are all passed to one query
+    mock.ExpectQuery(`WHERE e.archived_at IS NULL AND e.id = ANY\(\$1::UUID\[\]\) AND e.tags @> ARRAY\[\$2\]::TEXT\[\] AND e.company = \$3\s+\)` +
+        `[\s\S]+t.height / \$4 \* \$4 AS bucket[\s\S]+PERCENTILE_CONT\(\$5::FLOAT8\[\]\)` +
+        `[\s\S]+GROUP BY GROUPING SETS \(\(estate_id\), \(bucket\), \(\)\)`).
+        WithArgs(pq.StringArray{first.String(), second.String()}, "organic", "PT Sawit", 5, pq.Float64Array{0.1, 0.9}).
+        WillReturnRows(rows)
+
+    portfolio, err := repo.GetPortfolioStats(models.PortfolioQuery{
+        IDs:     []uuid.UUID{first, second},
+        Tag:     "organic",
+        Company: "PT Sawit",
+    }, statsOptions)
+    assert.NoError(t, err)
+    assert.Equal(t, 2, portfolio.EstateCount)
+    assert.Equal(t, 2.0, portfolio.AreaHectares)
+    assert.Equal(t, 1.5, portfolio.CoveragePercent)
+    assert.Equal(t, 3, portfolio.Count)
+    assert.Equal(t, []models.Percentile{{P: 10, Height: 4.2}, {P: 90, Height: 14.6}}, portfolio.Percentiles)
+    assert.Equal(t, []models.HistogramBucket{
+        {From: 0, To: 4, Count: 1}, {From: 5, To: 9, Count: 1}, {From: 10, To: 14, Count: 0}, {From: 15, To: 19, Count: 1},
+    }, portfolio.Histogram)
+    if assert.Len(t, portfolio.Estates, 2) {
+        assert.Equal(t, &models.PortfolioEstateStats{
+            ID: first, Name: "Alpha", Code: "A-1", Company: "PT Sawit",
+            Trees: 3, AreaHectares: 1, CoveragePercent: 3,
+            Max: 16, Min: 3, Median: 9, Mean: 9.33, StdDev: 5.31,
+            Percentiles: []models.Percentile{{P: 10, Height: 4.2}, {P: 90, Height: 14.6}},
+        }, portfolio.Estates[0])
+        // An estate without trees is still listed
+        assert.Equal(t, second, portfolio.Estates[1].ID)
+        assert.Equal(t, 0, portfolio.Estates[1].Trees)
+        assert.Equal(t, []models.Percentile{}, portfolio.Estates[1].Percentiles)
+    }
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_GetPortfolioStats_NoEstates(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    columns := []string{"grouping_set", "estate_id", "bucket", "count", "max", "min", "median", "avg", "stddev", "percentiles",
+        "name", "code", "company", "width", "length"}
+    mock.ExpectQuery(`WHERE e.archived_at IS NULL AND e.tags @> ARRAY\[\$1\]::TEXT\[\]\s+\)`).
+        WithArgs("unused", 5, pq.Float64Array{0.1, 0.9}).
+        WillReturnRows(sqlmock.NewRows(columns).AddRow(3, nil, nil, 0, nil, nil, nil, nil, nil, nil, "", "", "", 0, 0))
+
+    portfolio, err := repo.GetPortfolioStats(models.PortfolioQuery{Tag: "unused"}, statsOptions)
+    assert.NoError(t, err)
+    assert.Equal(t, 0, portfolio.EstateCount)
+    assert.Equal(t, 0.0, portfolio.CoveragePercent)
+    assert.Equal(t, []models.HistogramBucket{}, portfolio.Histogram)
+    assert.Equal(t, []*models.PortfolioEstateStats{}, portfolio.Estates)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_GetHealthCounts(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+    rows := sqlmock.NewRows([]string{"health_status", "count"}).
+        AddRow("healthy", 8).
+        AddRow("ganoderma", 2)
+
+    mock.ExpectQuery(`SELECT health_status, COUNT\(\*\) FROM trees WHERE estate_id = \$1 GROUP BY health_status`).
+        WithArgs(estateID).
+        WillReturnRows(rows)
+
+    counts, err := repo.GetHealthCounts(estateID, nil)
+    assert.NoError(t, err)
+    assert.Equal(t, map[string]int{"healthy": 8, "ganoderma": 2}, counts)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_GetHealthCounts_Region(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+    region := &models.Region{Columns: &models.Range{From: 3, To: 4}}
+
+    mock.ExpectQuery(`SELECT health_status, COUNT\(\*\) FROM trees WHERE estate_id = \$1 AND x BETWEEN \$2 AND \$3 GROUP BY health_status`).
+        WithArgs(estateID, 3, 4).
+        WillReturnRows(sqlmock.NewRows([]string{"health_status", "count"}).AddRow("healthy", 2))
+
+    counts, err := repo.GetHealthCounts(estateID, region)
+    assert.NoError(t, err)
+    assert.Equal(t, map[string]int{"healthy": 2}, counts)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstateRepository_GetHealthCountsAsOf(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewEstateRepository(db)
+
+    estateID := uuid.New()
+    before := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+    rows := sqlmock.NewRows([]string{"status", "count"}).
+        AddRow("healthy", 3).
+        AddRow("dead", 1)
+
+    mock.ExpectQuery(`SELECT COALESCE\(o.status, \$3\), COUNT\(\*\)\s+FROM trees t\s+LEFT JOIN LATERAL \(\s+SELECT status FROM tree_observations\s+WHERE tree_id = t.id AND observed_at < \$2`).
+        WithArgs(estateID, before, "healthy").
+        WillReturnRows(rows)
+
+    counts, err := repo.GetHealthCountsAsOf(estateID, before, nil)
+    assert.NoError(t, err)
+    assert.Equal(t, map[string]int{"healthy": 3, "dead": 1}, counts)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,205 @@
+package repositories
+
+import (
+    "database/sql"
+    "sawitpro-recruitment/models"
+    "time"
+
+    "github.com/google/uuid"
+    "github.com/sirupsen/logrus"
+)
+
+// harvestColumns lists the harvest columns in the order read by scanHarvest.
+const harvestColumns = "id, estate_id, tree_id, x, y, harvested_on, bunches, weight_kg, harvester, created_at"
+
+// scanHarvest reads a harvest selected with harvestColumns.
+func scanHarvest(row rowScanner) (*models.Harvest, error) {
+    harvest := &models.Harvest{}
+    var treeID uuid.NullUUID
+    err := row.Scan(&harvest.ID, &harvest.EstateID, &treeID, &harvest.X, &harvest.Y, &harvest.HarvestedOn.Time,
+        &harvest.Bunches, &harvest.WeightKg, &harvest.Harvester, &harvest.CreatedAt)
+    if err != nil {
+        return nil, err
+    }
+    if treeID.Valid {
+        harvest.TreeID = &treeID.UUID
+    }
+    return harvest, nil
+}
+
+// HarvestRepository defines the methods for harvest-related database operations.
+type HarvestRepository interface {
+    AddHarvests(harvests []*models.Harvest) error
+    GetTreeHarvests(treeID uuid.UUID) ([]*models.Harvest, error)
+    GetYieldTotals(estateID uuid.UUID, region *models.Region, from, to time.Time) (*models.YieldTotals, error)
+    GetBlockYields(estateID uuid.UUID, from, to time.Time, blockSize int) ([]*models.BlockYield, error)
+    GetPlotYields(estateID uuid.UUID, from, to time.Time, periodDays int) ([]*models.PlotYield, error)
+}
+
+// harvestRepository is the concrete implementation of the HarvestRepository interface.
+type harvestRepository struct {
+    db *sql.DB
+}
+
+// NewHarvestRepository returns a new instance of harvestRepository.
+func NewHarvestRepository(db *sql.DB) HarvestRepository {
+    return &harvestRepository{
+        db: db,
+    }
+}
+
+// AddHarvests stores a batch of harvests in a single transaction. Harvests
+// recorded by plot are linked to the tree planted there, if any.
+func (r *harvestRepository) AddHarvests(harvests []*models.Harvest) error {
+    logrus.Infof("Adding %d harvests", len(harvests))
+    tx, err := r.db.Begin()
+    if err != nil {
+        logrus.Errorf("Failed to begin transaction for adding harvests: %v", err)
+        return err
+    }
+    defer tx.Rollback()
+
+    stmt, err := tx.Prepare(`INSERT INTO harvests (id, estate_id, tree_id, x, y, harvested_on, bunches, weight_kg, harvester, created_at)
+        VALUES ($1, $2, COALESCE($3, (SELECT id FROM trees WHERE estate_id = $2 AND x = $4 AND y = $5)), $4, $5, $6, $7, $8, $9, $10)
+        RETURNING tree_id`)
+    if err != nil {
+        logrus.Errorf("Failed to prepare harvest insert: %v", err)
+        return err
+    }
+    defer stmt.Close()
+
+    for _, harvest := range harvests {
+        var treeID uuid.NullUUID
+        if harvest.TreeID != nil {
+            treeID = uuid.NullUUID{UUID: *harvest.TreeID, Valid: true}
+        }
+        err := stmt.QueryRow(harvest.ID, harvest.EstateID, treeID, harvest.X, harvest.Y, harvest.HarvestedOn.Time,
+            harvest.Bunches, harvest.WeightKg, harvest.Harvester, harvest.CreatedAt).Scan(&treeID)
+        if err != nil {
+            logrus.Errorf("Failed to add harvest ID %v: %v", harvest.ID, err)
+            return err
+        }
+        if treeID.Valid {
+            harvest.TreeID = &treeID.UUID
+        }
+    }
+
+    if err := tx.Commit(); err != nil {
+        logrus.Errorf("Failed to commit harvests: %v", err)
+        return err
+    }
+    logrus.Infof("Added %d harvests successfully", len(harvests))
+    return nil
+}
+
+// GetTreeHarvests retrieves the harvests of a tree, oldest first.
+func (r *harvestRepository) GetTreeHarvests(treeID uuid.UUID) ([]*models.Harvest, error) {
+    logrus.Infof("Retrieving harvests for tree ID: %v", treeID)
+    rows, err := r.db.Query("SELECT "+harvestColumns+" FROM harvests WHERE tree_id = $1 ORDER BY harvested_on, created_at", treeID)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve harvests for tree ID %v: %v", treeID, err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    harvests := []*models.Harvest{}
+    for rows.Next() {
+        harvest, err := scanHarvest(rows)
+        if err != nil {
+            logrus.Errorf("Failed to scan harvest row for tree ID %v: %v", treeID, err)
+            return nil, err
+        }
+        harvests = append(harvests, harvest)
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during rows iteration for tree ID %v: %v", treeID, err)
+        return nil, err
+    }
+    logrus.Infof("Retrieved %d harvests for tree ID: %v", len(harvests), treeID)
+    return harvests, nil
+}
+
+// GetYieldTotals sums the harvests of an estate, or of a region of it, from
+// one day to another, both included.
+func (r *harvestRepository) GetYieldTotals(estateID uuid.UUID, region *models.Region, from, to time.Time) (*models.YieldTotals, error) {
+    logrus.Infof("Retrieving yield totals from %v to %v for estate ID: %v", from, to, estateID)
+    totals := &models.YieldTotals{}
+    filter, args := regionFilter(region, "x", "y", "point(x, y)", []interface{}{estateID, from, to})
+    err := r.db.QueryRow(`SELECT COUNT(*), COALESCE(SUM(bunches), 0), COALESCE(SUM(weight_kg), 0)
+        FROM harvests WHERE estate_id = $1 AND harvested_on BETWEEN $2 AND $3`+filter, args...).
+        Scan(&totals.Harvests, &totals.Bunches, &totals.WeightKg)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve yield totals for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    return totals, nil
+}
+
+// GetBlockYields sums the harvests of an estate from one day to another per
+// square block of blockSize plots, row by row. Blocks without harvests are
+// left out; the caller fills in the block bounds and area.
+func (r *harvestRepository) GetBlockYields(estateID uuid.UUID, from, to time.Time, blockSize int) ([]*models.BlockYield, error) {
+    logrus.Infof("Retrieving yield per block of %d plots from %v to %v for estate ID: %v", blockSize, from, to, estateID)
+    rows, err := r.db.Query(`
+        SELECT (x - 1) / $4 + 1 AS block_x, (y - 1) / $4 + 1 AS block_y,
+            COUNT(*), SUM(bunches), SUM(weight_kg)
+        FROM harvests
+        WHERE estate_id = $1 AND harvested_on BETWEEN $2 AND $3
+        GROUP BY block_x, block_y
+        ORDER BY block_y, block_x
+    `, estateID, from, to, blockSize)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve block yields for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    blocks := []*models.BlockYield{}
+    for rows.Next() {
+        block := &models.BlockYield{}
+        if err := rows.Scan(&block.BlockX, &block.BlockY, &block.Harvests, &block.Bunches, &block.WeightKg); err != nil {
+            logrus.Errorf("Failed to scan block yield row for estate ID %v: %v", estateID, err)
+            return nil, err
+        }
+        blocks = append(blocks, block)
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during rows iteration for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    return blocks, nil
+}
+
+// GetPlotYields sums the harvests of an estate from one day to another per plot
+// and per period of periodDays counted from the from day. Plots and periods
+// without harvests are left out.
+func (r *harvestRepository) GetPlotYields(estateID uuid.UUID, from, to time.Time, periodDays int) ([]*models.PlotYield, error) {
+    logrus.Infof("Retrieving yield per plot and period of %d days from %v to %v for estate ID: %v", periodDays, from, to, estateID)
+    rows, err := r.db.Query(`
+        SELECT x, y, (harvested_on - $2::date) / $4 AS period, COUNT(*), SUM(weight_kg)
+        FROM harvests
+        WHERE estate_id = $1 AND harvested_on BETWEEN $2 AND $3
+        GROUP BY x, y, period
+        ORDER BY y, x, period
+    `, estateID, from, to, periodDays)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve plot yields for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    yields := []*models.PlotYield{}
+    for rows.Next() {
+        yield := &models.PlotYield{}
+        if err := rows.Scan(&yield.X, &yield.Y, &yield.Period, &yield.Harvests, &yield.WeightKg); err != nil {
+            logrus.Errorf("Failed to scan plot yield row for estate ID %v: %v", estateID, err)
+            return nil, err
+        }
+        yields = append(yields, yield)
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during rows iteration for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    return yields, nil
+}
@@ -0,0 +1,190 @@
+package repositories
+
+import (
+    "database/sql"
+    "testing"
+    "sawitpro-recruitment/models"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "github.com/google/uuid"
+    "github.com/stretchr/testify/assert"
+)
+
+// harvestRowColumns are the columns returned by queries selecting harvestColumns.
+var harvestRowColumns = []string{"id", "estate_id", "tree_id", "x", "y", "harvested_on", "bunches", "weight_kg", "harvester", "created_at"}
+
+func TestHarvestRepository_AddHarvests(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewHarvestRepository(db)
+
+    estateID := uuid.New()
+    treeID := uuid.New()
+    plotTreeID := uuid.New()
+    now := time.Now().UTC()
+    harvestedOn := models.Date{Time: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}
+    byTree := &models.Harvest{ID: uuid.New(), EstateID: estateID, TreeID: &treeID, X: 2, Y: 3,
+        HarvestedOn: harvestedOn, Bunches: 3, WeightKg: 62.5, Harvester: "Team A", CreatedAt: now}
+    byPlot := &models.Harvest{ID: uuid.New(), EstateID: estateID, X: 4, Y: 2,
+        HarvestedOn: harvestedOn, Bunches: 2, WeightKg: 41, CreatedAt: now}
+
+    mock.ExpectBegin()
+    insert := mock.ExpectPrepare(`INSERT INTO harvests \(id, estate_id, tree_id, x, y, harvested_on, bunches, weight_kg, harvester, created_at\)\s+` +
+        `VALUES \(\$1, \$2, COALESCE\(\$3, \(SELECT id FROM trees WHERE estate_id = \$2 AND x = \$4 AND y = \$5\)\)`)
+    insert.ExpectQuery().
+        WithArgs(byTree.ID, estateID, uuid.NullUUID{UUID: treeID, Valid: true}, 2, 3, harvestedOn.Time, 3, 62.5, "Team A", now).
+        WillReturnRows(sqlmock.NewRows([]string{"tree_id"}).AddRow(treeID))
+    insert.ExpectQuery().
+        WithArgs(byPlot.ID, estateID, uuid.NullUUID{}, 4, 2, harvestedOn.Time, 2, 41.0, "", now).
+        WillReturnRows(sqlmock.NewRows([]string{"tree_id"}).AddRow(plotTreeID))
+    mock.ExpectCommit()
+
+    err = repo.AddHarvests([]*models.Harvest{byTree, byPlot})
+    assert.NoError(t, err)
+    // The harvest recorded by plot is linked to the tree planted there
+    assert.Equal(t, &plotTreeID, byPlot.TreeID)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHarvestRepository_AddHarvests_Error(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewHarvestRepository(db)
+
+    harvest := &models.Harvest{ID: uuid.New(), EstateID: uuid.New(), X: 1, Y: 1, Bunches: 1, WeightKg: 20}
+
+    mock.ExpectBegin()
+    insert := mock.ExpectPrepare(`INSERT INTO harvests`)
+    insert.ExpectQuery().WillReturnError(sql.ErrConnDone)
+    mock.ExpectRollback()
+
+    err = repo.AddHarvests([]*models.Harvest{harvest})
+    assert.ErrorIs(t, err, sql.ErrConnDone)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHarvestRepository_GetTreeHarvests(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewHarvestRepository(db)
+
+    estateID := uuid.New()
+    treeID := uuid.New()
+    harvestedOn := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+    rows := sqlmock.NewRows(harvestRowColumns).
+        AddRow(uuid.New(), estateID, treeID, 2, 3, harvestedOn, 3, 62.5, "Team A", harvestedOn)
+
+    mock.ExpectQuery(`SELECT id, estate_id, tree_id, x, y, harvested_on, bunches, weight_kg, harvester, created_at FROM harvests WHERE tree_id = \$1 ORDER BY harvested_on, created_at`).
+        WithArgs(treeID).
+        WillReturnRows(rows)
+
+    harvests, err := repo.GetTreeHarvests(treeID)
+    assert.NoError(t, err)
+    if assert.Len(t, harvests, 1) {
+        assert.Equal(t, &treeID, harvests[0].TreeID)
+        assert.Equal(t, "2024-06-01", harvests[0].HarvestedOn.String())
+        assert.Equal(t, 62.5, harvests[0].WeightKg)
+    }
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHarvestRepository_GetYieldTotals(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewHarvestRepository(db)
+
+    estateID := uuid.New()
+    from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    to := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+    mock.ExpectQuery(`SELECT COUNT\(\*\), COALESCE\(SUM\(bunches\), 0\), COALESCE\(SUM\(weight_kg\), 0\)\s+FROM harvests WHERE estate_id = \$1 AND harvested_on BETWEEN \$2 AND \$3`).
+        WithArgs(estateID, from, to).
+        WillReturnRows(sqlmock.NewRows([]string{"count", "bunches", "weight_kg"}).AddRow(12, 40, "812.50"))
+
+    totals, err := repo.GetYieldTotals(estateID, nil, from, to)
+    assert.NoError(t, err)
+    assert.Equal(t, &models.YieldTotals{Harvests: 12, Bunches: 40, WeightKg: 812.5}, totals)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHarvestRepository_GetYieldTotals_Region(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewHarvestRepository(db)
+
+    estateID := uuid.New()
+    from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    to := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+    region := &models.Region{Polygon: []models.Plot{{X: 1, Y: 1}, {X: 5, Y: 1}, {X: 5, Y: 5}}}
+    mock.ExpectQuery(`harvested_on BETWEEN \$2 AND \$3 AND point\(x, y\) <@ \$4::polygon`).
+        WithArgs(estateID, from, to, "((1,1),(5,1),(5,5))").
+        WillReturnRows(sqlmock.NewRows([]string{"count", "bunches", "weight_kg"}).AddRow(2, 5, "90.00"))
+
+    totals, err := repo.GetYieldTotals(estateID, region, from, to)
+    assert.NoError(t, err)
+    assert.Equal(t, &models.YieldTotals{Harvests: 2, Bunches: 5, WeightKg: 90}, totals)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHarvestRepository_GetBlockYields(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewHarvestRepository(db)
+
+    estateID := uuid.New()
+    from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    to := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+    rows := sqlmock.NewRows([]string{"block_x", "block_y", "count", "bunches", "weight_kg"}).
+        AddRow(1, 1, 10, 30, 600.0).
+        AddRow(2, 1, 2, 4, 90.0)
+
+    mock.ExpectQuery(`SELECT \(x - 1\) / \$4 \+ 1 AS block_x, \(y - 1\) / \$4 \+ 1 AS block_y,\s+COUNT\(\*\), SUM\(bunches\), SUM\(weight_kg\)\s+FROM harvests\s+WHERE estate_id = \$1 AND harvested_on BETWEEN \$2 AND \$3\s+GROUP BY block_x, block_y\s+ORDER BY block_y, block_x`).
+        WithArgs(estateID, from, to, 10).
+        WillReturnRows(rows)
+
+    blocks, err := repo.GetBlockYields(estateID, from, to, 10)
+    assert.NoError(t, err)
+    if assert.Len(t, blocks, 2) {
+        assert.Equal(t, 2, blocks[1].BlockX)
+        assert.Equal(t, models.YieldTotals{Harvests: 2, Bunches: 4, WeightKg: 90}, blocks[1].YieldTotals)
+    }
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHarvestRepository_GetPlotYields(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewHarvestRepository(db)
+
+    estateID := uuid.New()
+    from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    to := time.Date(2024, 12, 29, 0, 0, 0, 0, time.UTC)
+    rows := sqlmock.NewRows([]string{"x", "y", "period", "count", "weight_kg"}).
+        AddRow(1, 1, 0, 2, 40.0).
+        AddRow(1, 1, 3, 1, "22.50")
+
+    mock.ExpectQuery(`SELECT x, y, \(harvested_on - \$2::date\) / \$4 AS period, COUNT\(\*\), SUM\(weight_kg\)\s+FROM harvests\s+WHERE estate_id = \$1 AND harvested_on BETWEEN \$2 AND \$3\s+GROUP BY x, y, period`).
+        WithArgs(estateID, from, to, 91).
+        WillReturnRows(rows)
+
+    yields, err := repo.GetPlotYields(estateID, from, to, 91)
+    assert.NoError(t, err)
+    if assert.Len(t, yields, 2) {
+        assert.Equal(t, &models.PlotYield{X: 1, Y: 1, Period: 3, Harvests: 1, WeightKg: 22.5}, yields[1])
+    }
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,207 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+    "math"
+    "sawitpro-recruitment/models"
+
+    "github.com/google/uuid"
+    "github.com/lib/pq"
+)
+
+// summaryHeights is the number of heights, from 1 meter, counted one by one in
+// the height summary of an estate: every height a tree without a species may
+// have. Trees of a taller species are only counted as other heights.
+const summaryHeights = models.DefaultMaxTreeHeight
+
+// heightCounts is the height summary of an estate, kept in the
+// estate_height_summaries table, or a change to it. Every stat of the estate's
+// heights can be computed exactly from it as long as no tree has another height.
+type heightCounts struct {
+    trees  int
+    sum    int
+    other  int           // Trees with a height above summaryHeights
+    counts pq.Int64Array // counts[h-1] trees of height h
+}
+
+func newHeightCounts() *heightCounts {
+    return &heightCounts{counts: make(pq.Int64Array, summaryHeights)}
+}
+
+// add counts n more trees of the given height; n is negative for trees removed.
+func (c *heightCounts) add(height, n int) {
+    c.trees += n
+    c.sum += n * height
+    if height < 1 || height > summaryHeights {
+        c.other += n
+        return
+    }
+    c.counts[height-1] += int64(n)
+}
+
+// merge adds the counts of a change.
+func (c *heightCounts) merge(delta *heightCounts) {
+    c.trees += delta.trees
+    c.sum += delta.sum
+    c.other += delta.other
+    for i, n := range delta.counts {
+        c.counts[i] += n
+    }
+}
+
+// empty reports whether the counts are all zero, as for a change that leaves
+// every height as it was.
+func (c *heightCounts) empty() bool {
+    if c.trees != 0 || c.sum != 0 || c.other != 0 {
+        return false
+    }
+    for _, n := range c.counts {
+        if n != 0 {
+            return false
+        }
+    }
+    return true
+}
+
+// equal reports whether two summaries count the same trees.
+func (c *heightCounts) equal(other *heightCounts) bool {
+    if c.trees != other.trees || c.sum != other.sum || c.other != other.other {
+        return false
+    }
+    for i, n := range c.counts {
+        if n != other.counts[i] {
+            return false
+        }
+    }
+    return true
+}
+
+// exact reports whether every tree is counted by height.
+func (c *heightCounts) exact() bool {
+    return c.other == 0
+}
+
+// bounds returns the shortest and tallest heights, both NULL when there are no
+// trees or the tallest is not counted by height.
+func (c *heightCounts) bounds() (sql.NullInt64, sql.NullInt64) {
+    var min, max sql.NullInt64
+    if c.trees == 0 || !c.exact() {
+        return min, max
+    }
+    for i, n := range c.counts {
+        if n > 0 {
+            if !min.Valid {
+                min = sql.NullInt64{Int64: int64(i + 1), Valid: true}
+            }
+            max = sql.NullInt64{Int64: int64(i + 1), Valid: true}
+        }
+    }
+    return min, max
+}
+
+// nth returns the height of the tree at position n, counted from 0, in order
+// of height.
+func (c *heightCounts) nth(n int) float64 {
+    seen := 0
+    for i, count := range c.counts {
+        seen += int(count)
+        if n < seen {
+            return float64(i + 1)
+        }
+    }
+    return 0
+}
+
+// percentile interpolates the height below which a fraction of the trees
+// stand, as PERCENTILE_CONT does.
+func (c *heightCounts) percentile(fraction float64) float64 {
+    position := fraction * float64(c.trees-1)
+    lower := math.Floor(position)
+    low, high := c.nth(int(lower)), c.nth(int(math.Ceil(position)))
+    return low + (position-lower)*(high-low)
+}
+
+// stats computes the height stats of exact counts, rounded as heightSummary
+// rounds them.
+func (c *heightCounts) stats(options models.StatsOptions) *models.HeightStats {
+    stats := &models.HeightStats{Count: c.trees, BucketWidth: options.BucketWidth, Percentiles: []models.Percentile{}, Histogram: []models.HistogramBucket{}}
+    if c.trees == 0 {
+        return stats
+    }
+    min, max := c.bounds()
+    stats.Min, stats.Max = int(min.Int64), int(max.Int64)
+
+    mean := float64(c.sum) / float64(c.trees)
+    squares := 0.0
+    buckets := map[int]int{}
+    for i, n := range c.counts {
+        height := i + 1
+        squares += float64(n) * (float64(height) - mean) * (float64(height) - mean)
+        buckets[height/options.BucketWidth*options.BucketWidth] += int(n)
+    }
+    stats.Mean = round2(mean)
+    stats.StdDev = round2(math.Sqrt(squares / float64(c.trees)))
+    stats.Median = round2(c.percentile(0.5))
+    for _, p := range options.Percentiles {
+        stats.Percentiles = append(stats.Percentiles, models.Percentile{P: p, Height: round2(c.percentile(p / 100))})
+    }
+    stats.Histogram = histogramBuckets(stats.Min, stats.Max, options.BucketWidth, buckets)
+    return stats
+}
+
+// heightCountsColumns lists the summary columns in the order read by scanHeightCounts.
+const heightCountsColumns = "tree_count, height_sum, other_heights, height_counts"
+
+// scanHeightCounts reads a summary selected with heightCountsColumns.
+func scanHeightCounts(row rowScanner) (*heightCounts, error) {
+    counts := &heightCounts{}
+    if err := row.Scan(&counts.trees, &counts.sum, &counts.other, &counts.counts); err != nil {
+        return nil, err
+    }
+    if len(counts.counts) != summaryHeights {
+        return nil, fmt.Errorf("height summary counts %d heights, expected %d", len(counts.counts), summaryHeights)
+    }
+    return counts, nil
+}
+
+// storeHeightSummary writes the summary of an estate, creating its row if needed.
+func storeHeightSummary(tx *sql.Tx, estateID uuid.UUID, counts *heightCounts) error {
+    min, max := counts.bounds()
+    _, err := tx.Exec(`INSERT INTO estate_height_summaries (estate_id, tree_count, height_sum, min_height, max_height, other_heights, height_counts, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+        ON CONFLICT (estate_id) DO UPDATE SET tree_count = EXCLUDED.tree_count, height_sum = EXCLUDED.height_sum,
+            min_height = EXCLUDED.min_height, max_height = EXCLUDED.max_height, other_heights = EXCLUDED.other_heights,
+            height_counts = EXCLUDED.height_counts, updated_at = EXCLUDED.updated_at`,
+        estateID, counts.trees, counts.sum, min, max, counts.other, counts.counts)
+    return err
+}
+
+// updateHeightSummary applies a change of tree heights to the summary of an
+// estate, in the transaction changing the trees. The summary row stays locked
+// until the transaction ends, so concurrent changes to the estate's trees are
+// applied one after the other. An estate without a summary row is left to
+// the rebuild command; its stats are computed from its trees meanwhile.
+func updateHeightSummary(tx *sql.Tx, estateID uuid.UUID, delta *heightCounts) error {
+    if delta.empty() {
+        return nil
+    }
+    counts, err := scanHeightCounts(tx.QueryRow(`SELECT `+heightCountsColumns+` FROM estate_height_summaries WHERE estate_id = $1 FOR UPDATE`, estateID))
+    if err == sql.ErrNoRows {
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+    counts.merge(delta)
+    return storeHeightSummary(tx, estateID, counts)
+}
+
+// heightChange is the change to a summary made by a tree going from one
+// height to another.
+func heightChange(from, to int) *heightCounts {
+    delta := newHeightCounts()
+    delta.add(from, -1)
+    delta.add(to, 1)
+    return delta
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+    "database/sql"
+    "math"
+    "math/rand"
+    "sort"
+    "testing"
+    "sawitpro-recruitment/models"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "github.com/google/uuid"
+    "github.com/stretchr/testify/assert"
+)
+
+// heightSummaryQuery matches the query locking the height summary of an estate.
+const heightSummaryQuery = `SELECT tree_count, height_sum, other_heights, height_counts FROM estate_height_summaries WHERE estate_id = \$1 FOR UPDATE`
+
+// heightSummaryUpsert matches the statement storing the height summary of an estate.
+const heightSummaryUpsert = `INSERT INTO estate_height_summaries \(estate_id, tree_count, height_sum, min_height, max_height, other_heights, height_counts, updated_at\)`
+
+// heightCountsOf counts the given number of trees per height.
+func heightCountsOf(heights map[int]int) *heightCounts {
+    counts := newHeightCounts()
+    for height, n := range heights {
+        counts.add(height, n)
+    }
+    return counts
+}
+
+// heightSummaryRow returns the stored summary of an estate with the given
+// number of trees per height.
+func heightSummaryRow(heights map[int]int) *sqlmock.Rows {
+    counts := heightCountsOf(heights)
+    array, _ := counts.counts.Value()
+    return sqlmock.NewRows([]string{"tree_count", "height_sum", "other_heights", "height_counts"}).
+        AddRow(counts.trees, counts.sum, counts.other, array)
+}
+
+// expectHeightSummaryStored expects the summary of an estate to be stored with
+// the given number of trees per height.
+func expectHeightSummaryStored(mock sqlmock.Sqlmock, estateID uuid.UUID, heights map[int]int) {
+    counts := heightCountsOf(heights)
+    min, max := counts.bounds()
+    mock.ExpectExec(heightSummaryUpsert).
+        WithArgs(estateID, counts.trees, counts.sum, min, max, counts.other, counts.counts).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func TestHeightCounts_Stats(t *testing.T) {
+    counts := heightCountsOf(map[int]int{3: 1, 8: 1, 15: 1, 16: 1})
+
+    assert.Equal(t, &models.HeightStats{
+        Count: 4, Max: 16, Min: 3, Median: 11.5, Mean: 10.5, StdDev: 5.32,
+        Percentiles: []models.Percentile{{P: 10, Height: 4.5}, {P: 90, Height: 15.7}},
+        BucketWidth: 5,
+        Histogram: []models.HistogramBucket{
+            {From: 0, To: 4, Count: 1}, {From: 5, To: 9, Count: 1}, {From: 10, To: 14, Count: 0}, {From: 15, To: 19, Count: 2},
+        },
+    }, counts.stats(statsOptions))
+
+    empty := newHeightCounts().stats(statsOptions)
+    assert.Equal(t, 0, empty.Count)
+    assert.Empty(t, empty.Histogram)
+}
+
+func TestHeightCounts_MatchesHeights(t *testing.T) {
+    random := rand.New(rand.NewSource(3))
+    for i := 0; i < 200; i++ {
+        counts := newHeightCounts()
+        var heights []float64
+        for j := random.Intn(50); j >= 0; j-- {
+            height := 1 + random.Intn(summaryHeights)
+            counts.add(height, 1)
+            heights = append(heights, float64(height))
+        }
+        sort.Float64s(heights)
+
+        // PERCENTILE_CONT interpolates between the heights around the position
+        percentile := func(fraction float64) float64 {
+            position := fraction * float64(len(heights)-1)
+            lower := math.Floor(position)
+            return heights[int(lower)] + (position-lower)*(heights[int(math.Ceil(position))]-heights[int(lower)])
+        }
+        stats := counts.stats(models.StatsOptions{Percentiles: []float64{25, 75}, BucketWidth: 3})
+        assert.Equal(t, len(heights), stats.Count)
+        assert.Equal(t, int(heights[0]), stats.Min)
+        assert.Equal(t, int(heights[len(heights)-1]), stats.Max)
+        assert.Equal(t, round2(percentile(0.5)), stats.Median)
+        assert.Equal(t, round2(percentile(0.25)), stats.Percentiles[0].Height)
+        assert.Equal(t, round2(percentile(0.75)), stats.Percentiles[1].Height)
+    }
+}
+
+func TestHeightCounts_OtherHeights(t *testing.T) {
+    counts := heightCountsOf(map[int]int{12: 2, 40: 1})
+    assert.False(t, counts.exact())
+    min, max := counts.bounds()
+    assert.False(t, min.Valid)
+    assert.False(t, max.Valid)
+
+    counts.merge(heightChange(40, 20))
+    assert.True(t, counts.exact())
+    assert.Equal(t, heightCountsOf(map[int]int{12: 2, 20: 1}), counts)
+    assert.True(t, heightChange(7, 7).empty())
+}
+
+func TestUpdateHeightSummary_NoSummary(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    estateID := uuid.New()
+    mock.ExpectBegin()
+    mock.ExpectQuery(heightSummaryQuery).WithArgs(estateID).WillReturnError(sql.ErrNoRows)
+
+    tx, err := db.Begin()
+    assert.NoError(t, err)
+    // An estate without a summary is left to the rebuild command
+    assert.NoError(t, updateHeightSummary(tx, estateID, heightChange(3, 5)))
+    // An unchanged height does not lock the summary
+    assert.NoError(t, updateHeightSummary(tx, estateID, heightChange(5, 5)))
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
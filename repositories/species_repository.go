@@ -0,0 +1,100 @@
+package repositories
+
+import (
+    "database/sql"
+    "errors"
+    "sawitpro-recruitment/models"
+
+    "github.com/sirupsen/logrus"
+)
+
+// ErrDuplicateSpecies is returned when another species already uses the code.
+var ErrDuplicateSpecies = errors.New("species code already exists")
+
+// speciesColumns lists the species columns in the order read by scanSpecies.
+const speciesColumns = "code, name, min_height, max_height, created_at"
+
+// scanSpecies reads a species selected with speciesColumns.
+func scanSpecies(row rowScanner) (*models.Species, error) {
+    species := &models.Species{}
+    err := row.Scan(&species.Code, &species.Name, &species.MinHeight, &species.MaxHeight, &species.CreatedAt)
+    if err != nil {
+        return nil, err
+    }
+    return species, nil
+}
+
+// SpeciesRepository defines the methods for species-related database operations.
+type SpeciesRepository interface {
+    CreateSpecies(species *models.Species) error
+    GetSpecies(code string) (*models.Species, error)
+    ListSpecies() ([]*models.Species, error)
+}
+
+// speciesRepository is the concrete implementation of the SpeciesRepository interface.
+type speciesRepository struct {
+    db *sql.DB
+}
+
+// NewSpeciesRepository returns a new instance of speciesRepository.
+func NewSpeciesRepository(db *sql.DB) SpeciesRepository {
+    return &speciesRepository{
+        db: db,
+    }
+}
+
+// CreateSpecies inserts a new species into the database.
+func (r *speciesRepository) CreateSpecies(species *models.Species) error {
+    logrus.Infof("Creating species with code: %s", species.Code)
+    _, err := r.db.Exec("INSERT INTO species (code, name, min_height, max_height, created_at) VALUES ($1, $2, $3, $4, $5)",
+        species.Code, species.Name, species.MinHeight, species.MaxHeight, species.CreatedAt)
+    if err != nil {
+        logrus.Errorf("Failed to create species with code %s: %v", species.Code, err)
+        if isUniqueViolation(err) {
+            return ErrDuplicateSpecies
+        }
+    }
+    return err
+}
+
+// GetSpecies retrieves a species by its code.
+func (r *speciesRepository) GetSpecies(code string) (*models.Species, error) {
+    logrus.Infof("Retrieving species with code: %s", code)
+    species, err := scanSpecies(r.db.QueryRow("SELECT "+speciesColumns+" FROM species WHERE code = $1", code))
+    if err != nil {
+        if err == sql.ErrNoRows {
+            logrus.Warnf("No species found with code: %s", code)
+            return nil, nil
+        }
+        logrus.Errorf("Failed to retrieve species with code %s: %v", code, err)
+        return nil, err
+    }
+    return species, nil
+}
+
+// ListSpecies retrieves every species, ordered by code.
+func (r *speciesRepository) ListSpecies() ([]*models.Species, error) {
+    logrus.Info("Listing species")
+    rows, err := r.db.Query("SELECT " + speciesColumns + " FROM species ORDER BY code")
+    if err != nil {
+        logrus.Errorf("Failed to list species: %v", err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    species := []*models.Species{}
+    for rows.Next() {
+        s, err := scanSpecies(rows)
+        if err != nil {
+            logrus.Errorf("Failed to scan species row: %v", err)
+            return nil, err
+        }
+        species = append(species, s)
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during species rows iteration: %v", err)
+        return nil, err
+    }
+    logrus.Infof("Listed %d species", len(species))
+    return species, nil
+}
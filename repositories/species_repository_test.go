@@ -0,0 +1,104 @@
+package repositories
+
+import (
+    "database/sql"
+    "testing"
+    "sawitpro-recruitment/models"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "github.com/lib/pq"
+    "github.com/stretchr/testify/assert"
+)
+
+// speciesRowColumns are the columns returned by queries selecting speciesColumns.
+var speciesRowColumns = []string{"code", "name", "min_height", "max_height", "created_at"}
+
+func TestSpeciesRepository_CreateSpecies(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewSpeciesRepository(db)
+
+    species := &models.Species{Code: "tenera", Name: "Tenera", MinHeight: 1, MaxHeight: 25, CreatedAt: time.Now()}
+    mock.ExpectExec(`INSERT INTO species \(code, name, min_height, max_height, created_at\) VALUES \(\$1, \$2, \$3, \$4, \$5\)`).
+        WithArgs(species.Code, species.Name, species.MinHeight, species.MaxHeight, species.CreatedAt).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+
+    err = repo.CreateSpecies(species)
+    assert.NoError(t, err)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSpeciesRepository_CreateSpecies_Duplicate(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewSpeciesRepository(db)
+
+    mock.ExpectExec(`INSERT INTO species`).
+        WillReturnError(&pq.Error{Code: "23505"})
+
+    err = repo.CreateSpecies(&models.Species{Code: "tenera", Name: "Tenera", MinHeight: 1, MaxHeight: 25})
+    assert.ErrorIs(t, err, ErrDuplicateSpecies)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSpeciesRepository_GetSpecies(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewSpeciesRepository(db)
+
+    createdAt := time.Now()
+    mock.ExpectQuery(`SELECT code, name, min_height, max_height, created_at FROM species WHERE code = \$1`).
+        WithArgs("dura").
+        WillReturnRows(sqlmock.NewRows(speciesRowColumns).AddRow("dura", "Dura", 1, 30, createdAt))
+
+    species, err := repo.GetSpecies("dura")
+    assert.NoError(t, err)
+    assert.Equal(t, &models.Species{Code: "dura", Name: "Dura", MinHeight: 1, MaxHeight: 30, CreatedAt: createdAt}, species)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSpeciesRepository_GetSpecies_NotFound(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewSpeciesRepository(db)
+
+    mock.ExpectQuery(`FROM species WHERE code = \$1`).
+        WithArgs("oak").
+        WillReturnError(sql.ErrNoRows)
+
+    species, err := repo.GetSpecies("oak")
+    assert.NoError(t, err)
+    assert.Nil(t, species)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSpeciesRepository_ListSpecies(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewSpeciesRepository(db)
+
+    createdAt := time.Now()
+    mock.ExpectQuery(`SELECT code, name, min_height, max_height, created_at FROM species ORDER BY code`).
+        WillReturnRows(sqlmock.NewRows(speciesRowColumns).
+            AddRow("dura", "Dura", 1, 30, createdAt).
+            AddRow("tenera", "Tenera", 1, 25, createdAt))
+
+    species, err := repo.ListSpecies()
+    assert.NoError(t, err)
+    if assert.Len(t, species, 2) {
+        assert.Equal(t, "tenera", species[1].Code)
+        assert.Equal(t, 25, species[1].MaxHeight)
+    }
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
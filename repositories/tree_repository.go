@@ -2,17 +2,91 @@ package repositories
 
 import (
     "database/sql"
+    "encoding/base64"
+    "errors"
     "sawitpro-recruitment/models"
+    "strconv"
+    "strings"
+    "time"
     "github.com/google/uuid"
+    "github.com/lib/pq"
     "github.com/sirupsen/logrus"
     "fmt"
 )
 
+// ErrTreeNotFound is returned when a tree to modify does not exist in the estate.
+var ErrTreeNotFound = errors.New("tree not found")
+
+// ErrPlotOccupied is returned when another tree already stands on the target plot.
+var ErrPlotOccupied = errors.New("plot already has a tree")
+
+// ErrTreeOutOfBounds is returned when a tree would stand outside its estate.
+var ErrTreeOutOfBounds = errors.New("tree out of estate bounds")
+
+// latestObservationOrder sorts the health observations of a tree from the
+// latest to the earliest, like latestMeasurementOrder.
+const latestObservationOrder = "observed_at DESC, created_at DESC"
+
+// latestMeasurementOrder sorts the measurements of a tree from the latest to the
+// earliest; measurements taken at the same time are ordered by when they were recorded.
+const latestMeasurementOrder = "measured_at DESC, created_at DESC"
+
+// exportFetchSize is the number of trees fetched from the export cursor at a time.
+const exportFetchSize = 1000
+
+// treeColumns lists the tree columns in the order read by scanTree.
+const treeColumns = "id, estate_id, x, y, height, COALESCE(species, ''), planted_on, health_status"
+
+// scanTree reads a tree selected with treeColumns and derives its age.
+func scanTree(row rowScanner) (*models.Tree, error) {
+    tree := &models.Tree{}
+    var plantedOn sql.NullTime
+    if err := row.Scan(&tree.ID, &tree.EstateID, &tree.X, &tree.Y, &tree.Height, &tree.Species, &plantedOn, &tree.HealthStatus); err != nil {
+        return nil, err
+    }
+    if plantedOn.Valid {
+        tree.PlantedOn = &models.Date{Time: plantedOn.Time}
+    }
+    tree.SetAge(time.Now())
+    return tree, nil
+}
+
+// speciesValue returns the species of a tree to store, NULL when it has none.
+func speciesValue(tree *models.Tree) interface{} {
+    if tree.Species == "" {
+        return nil
+    }
+    return tree.Species
+}
+
+// plantedOnValue returns the planting date of a tree to store, NULL when it is unknown.
+func plantedOnValue(tree *models.Tree) interface{} {
+    if tree.PlantedOn == nil {
+        return nil
+    }
+    return tree.PlantedOn.Time
+}
+
 // TreeRepository defines the methods for tree-related database operations.
 type TreeRepository interface {
     AddTreeToEstate(tree *models.Tree) error
     GetTreeByCoordinates(estateID uuid.UUID, x, y int) (*models.Tree, error)
     GetTreesByEstateID(estateID uuid.UUID) (map[string]int, error)
+    GetTreeByID(estateID, treeID uuid.UUID) (*models.Tree, error)
+    UpdateTree(tree *models.Tree) error
+    DeleteTree(estateID, treeID uuid.UUID) error
+    AddMeasurement(measurement *models.TreeMeasurement) error
+    GetMeasurements(treeID uuid.UUID) ([]*models.TreeMeasurement, error)
+    GetTreeHeightsAsOf(estateID uuid.UUID, before time.Time) (map[string]int, error)
+    ImportTrees(trees []*models.Tree, measuredAt time.Time) error
+    ExportTrees(estateID uuid.UUID, fn func(tree *models.Tree) error) error
+    ListTrees(estateID uuid.UUID, query models.TreeQuery) (*models.TreePage, error)
+    AddObservation(observation *models.TreeObservation) error
+    GetObservations(treeID uuid.UUID) ([]*models.TreeObservation, error)
+    GetTreesToInspect(estateID uuid.UUID) ([]*models.Tree, error)
+    GetTreesWithin(estateID uuid.UUID, x, y, radius int) ([]*models.TreeNeighbour, error)
+    GetNearestTrees(estateID uuid.UUID, x, y, k int) ([]*models.TreeNeighbour, error)
+    ScanTreesInBox(estateID uuid.UUID, box models.BoundingBox, fn func(tree *models.Tree) error) error
 }
 
 // treeRepository is the concrete implementation of the TreeRepository interface.
@@ -27,21 +101,54 @@ func NewTreeRepository(db *sql.DB) TreeRepository {
     }
 }
 
-// AddTreeToEstate inserts a new tree into the specified estate.
+// AddTreeToEstate inserts a new tree into the specified estate together with
+// its first height measurement, and counts it in the estate's height summary.
+// Planting on a plot that already has a tree fails with ErrPlotOccupied.
 func (r *treeRepository) AddTreeToEstate(tree *models.Tree) error {
     logrus.Infof("Adding tree with ID: %v to estate ID: %v", tree.ID, tree.EstateID)
-    _, err := r.db.Exec("INSERT INTO trees (id, estate_id, x, y, height) VALUES ($1, $2, $3, $4, $5)", tree.ID, tree.EstateID, tree.X, tree.Y, tree.Height)
+    tx, err := r.db.Begin()
+    if err != nil {
+        logrus.Errorf("Failed to begin transaction for adding tree ID %v: %v", tree.ID, err)
+        return err
+    }
+    defer tx.Rollback()
+
+    if err := lockEstateBounds(tx, tree.EstateID, tree.X, tree.Y); err != nil {
+        logrus.Warnf("Tree ID %v not added to estate ID %v: %v", tree.ID, tree.EstateID, err)
+        return err
+    }
+    _, err = tx.Exec("INSERT INTO trees (id, estate_id, x, y, height, species, planted_on) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+        tree.ID, tree.EstateID, tree.X, tree.Y, tree.Height, speciesValue(tree), plantedOnValue(tree))
     if err != nil {
         logrus.Errorf("Failed to add tree with ID %v to estate ID %v: %v", tree.ID, tree.EstateID, err)
+        if isUniqueViolation(err) {
+            return ErrPlotOccupied
+        }
+        return err
     }
-    return err
+    _, err = tx.Exec(`INSERT INTO tree_measurements (id, tree_id, height, measured_at, source, surveyor)
+        VALUES ($1, $2, $3, NOW(), $4, '')`, uuid.New(), tree.ID, tree.Height, models.MeasurementSourceManual)
+    if err != nil {
+        logrus.Errorf("Failed to record initial measurement of tree ID %v: %v", tree.ID, err)
+        return err
+    }
+    delta := newHeightCounts()
+    delta.add(tree.Height, 1)
+    if err := updateHeightSummary(tx, tree.EstateID, delta); err != nil {
+        logrus.Errorf("Failed to update height summary of estate ID %v: %v", tree.EstateID, err)
+        return err
+    }
+    if err := tx.Commit(); err != nil {
+        logrus.Errorf("Failed to commit tree ID %v: %v", tree.ID, err)
+        return err
+    }
+    return nil
 }
 
 // GetTreeByCoordinates retrieves a tree by its coordinates in a specific estate.
 func (r *treeRepository) GetTreeByCoordinates(estateID uuid.UUID, x, y int) (*models.Tree, error) {
     logrus.Infof("Retrieving tree at coordinates (%d, %d) for estate ID: %v", x, y, estateID)
-    tree := &models.Tree{}
-    err := r.db.QueryRow("SELECT id, x, y, height FROM trees WHERE estate_id = $1 AND x = $2 AND y = $3", estateID, x, y).Scan(&tree.ID, &tree.X, &tree.Y, &tree.Height)
+    tree, err := scanTree(r.db.QueryRow("SELECT "+treeColumns+" FROM trees WHERE estate_id = $1 AND x = $2 AND y = $3", estateID, x, y))
     if err != nil {
         if err == sql.ErrNoRows {
             logrus.Warnf("No tree found at coordinates (%d, %d) for estate ID: %v", x, y, estateID)
@@ -80,4 +187,691 @@ func (r *treeRepository) GetTreesByEstateID(estateID uuid.UUID) (map[string]int,
     }
     logrus.Infof("All trees retrieved successfully for estate ID: %v", estateID)
     return treeHeights, nil
-}
\ No newline at end of file
+}
+
+// GetTreeByID retrieves a tree of a specific estate by its ID.
+func (r *treeRepository) GetTreeByID(estateID, treeID uuid.UUID) (*models.Tree, error) {
+    logrus.Infof("Retrieving tree ID: %v for estate ID: %v", treeID, estateID)
+    tree, err := scanTree(r.db.QueryRow("SELECT "+treeColumns+" FROM trees WHERE id = $1 AND estate_id = $2", treeID, estateID))
+    if err != nil {
+        if err == sql.ErrNoRows {
+            logrus.Warnf("No tree found with ID %v for estate ID: %v", treeID, estateID)
+            return nil, nil
+        }
+        logrus.Errorf("Failed to retrieve tree ID %v for estate ID %v: %v", treeID, estateID, err)
+        return nil, err
+    }
+    logrus.Infof("Tree retrieved successfully: %v", treeID)
+    return tree, nil
+}
+
+// UpdateTree stores the position, height, species and planting date of an
+// existing tree. A changed height is recorded as a new manual measurement
+// taken now, so the current height keeps matching the latest measurement while
+// the history stays append-only. Moving a tree onto a plot that already has one fails with
+// ErrPlotOccupied.
+func (r *treeRepository) UpdateTree(tree *models.Tree) error {
+    logrus.Infof("Updating tree ID: %v in estate ID: %v", tree.ID, tree.EstateID)
+    tx, err := r.db.Begin()
+    if err != nil {
+        logrus.Errorf("Failed to begin transaction for updating tree ID %v: %v", tree.ID, err)
+        return err
+    }
+    defer tx.Rollback()
+
+    if err := lockEstateBounds(tx, tree.EstateID, tree.X, tree.Y); err != nil {
+        logrus.Warnf("Tree ID %v not updated in estate ID %v: %v", tree.ID, tree.EstateID, err)
+        return err
+    }
+    // The height before the update is read from the locked row being updated
+    var previousHeight int
+    err = tx.QueryRow(`UPDATE trees t SET x = $3, y = $4, height = $5, species = $6, planted_on = $7
+        FROM (SELECT id, height FROM trees WHERE id = $1 AND estate_id = $2 FOR UPDATE) previous
+        WHERE t.id = previous.id
+        RETURNING previous.height`,
+        tree.ID, tree.EstateID, tree.X, tree.Y, tree.Height, speciesValue(tree), plantedOnValue(tree)).Scan(&previousHeight)
+    if err == sql.ErrNoRows {
+        logrus.Warnf("Tree not found: %v", tree.ID)
+        return ErrTreeNotFound
+    }
+    if err != nil {
+        logrus.Errorf("Failed to update tree ID %v in estate ID %v: %v", tree.ID, tree.EstateID, err)
+        if isUniqueViolation(err) {
+            return ErrPlotOccupied
+        }
+        return err
+    }
+    if tree.Height != previousHeight {
+        _, err = tx.Exec(`INSERT INTO tree_measurements (id, tree_id, height, measured_at, source, surveyor)
+            VALUES ($1, $2, $3, NOW(), $4, '')`, uuid.New(), tree.ID, tree.Height, models.MeasurementSourceManual)
+        if err != nil {
+            logrus.Errorf("Failed to record new height of tree ID %v: %v", tree.ID, err)
+            return err
+        }
+    }
+    if err := updateHeightSummary(tx, tree.EstateID, heightChange(previousHeight, tree.Height)); err != nil {
+        logrus.Errorf("Failed to update height summary of estate ID %v: %v", tree.EstateID, err)
+        return err
+    }
+    if err := tx.Commit(); err != nil {
+        logrus.Errorf("Failed to commit update of tree ID %v: %v", tree.ID, err)
+        return err
+    }
+    logrus.Infof("Tree updated successfully: %v", tree.ID)
+    return nil
+}
+
+// DeleteTree removes a tree from an estate and from its height summary.
+func (r *treeRepository) DeleteTree(estateID, treeID uuid.UUID) error {
+    logrus.Infof("Deleting tree ID: %v from estate ID: %v", treeID, estateID)
+    tx, err := r.db.Begin()
+    if err != nil {
+        logrus.Errorf("Failed to begin transaction for deleting tree ID %v: %v", treeID, err)
+        return err
+    }
+    defer tx.Rollback()
+
+    var height int
+    err = tx.QueryRow("DELETE FROM trees WHERE id = $1 AND estate_id = $2 RETURNING height", treeID, estateID).Scan(&height)
+    if err == sql.ErrNoRows {
+        logrus.Warnf("Tree not found: %v", treeID)
+        return ErrTreeNotFound
+    }
+    if err != nil {
+        logrus.Errorf("Failed to delete tree ID %v from estate ID %v: %v", treeID, estateID, err)
+        return err
+    }
+    delta := newHeightCounts()
+    delta.add(height, -1)
+    if err := updateHeightSummary(tx, estateID, delta); err != nil {
+        logrus.Errorf("Failed to update height summary of estate ID %v: %v", estateID, err)
+        return err
+    }
+    if err := tx.Commit(); err != nil {
+        logrus.Errorf("Failed to commit deletion of tree ID %v: %v", treeID, err)
+        return err
+    }
+    logrus.Infof("Tree deleted successfully: %v", treeID)
+    return nil
+}
+
+// AddMeasurement records a height measurement and sets the current height of
+// the tree to its latest measurement, updating the estate's height summary.
+func (r *treeRepository) AddMeasurement(measurement *models.TreeMeasurement) error {
+    logrus.Infof("Recording measurement ID: %v for tree ID: %v", measurement.ID, measurement.TreeID)
+    tx, err := r.db.Begin()
+    if err != nil {
+        logrus.Errorf("Failed to begin transaction for measurement of tree ID %v: %v", measurement.TreeID, err)
+        return err
+    }
+    defer tx.Rollback()
+
+    _, err = tx.Exec(`INSERT INTO tree_measurements (id, tree_id, height, measured_at, source, surveyor, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+        measurement.ID, measurement.TreeID, measurement.Height, measurement.MeasuredAt,
+        measurement.Source, measurement.Surveyor, measurement.CreatedAt)
+    if err != nil {
+        logrus.Errorf("Failed to record measurement for tree ID %v: %v", measurement.TreeID, err)
+        return err
+    }
+    var estateID uuid.UUID
+    var previousHeight, height int
+    err = tx.QueryRow(`UPDATE trees t SET height = (SELECT height FROM tree_measurements WHERE tree_id = $1 ORDER BY `+latestMeasurementOrder+` LIMIT 1)
+        FROM (SELECT id, height FROM trees WHERE id = $1 FOR UPDATE) previous
+        WHERE t.id = previous.id
+        RETURNING t.estate_id, previous.height, t.height`, measurement.TreeID).Scan(&estateID, &previousHeight, &height)
+    if err != nil {
+        logrus.Errorf("Failed to update current height of tree ID %v: %v", measurement.TreeID, err)
+        return err
+    }
+    if err := updateHeightSummary(tx, estateID, heightChange(previousHeight, height)); err != nil {
+        logrus.Errorf("Failed to update height summary of estate ID %v: %v", estateID, err)
+        return err
+    }
+    if err := tx.Commit(); err != nil {
+        logrus.Errorf("Failed to commit measurement for tree ID %v: %v", measurement.TreeID, err)
+        return err
+    }
+    logrus.Infof("Measurement recorded successfully for tree ID: %v", measurement.TreeID)
+    return nil
+}
+
+// GetMeasurements retrieves the measurement history of a tree, oldest first.
+func (r *treeRepository) GetMeasurements(treeID uuid.UUID) ([]*models.TreeMeasurement, error) {
+    logrus.Infof("Retrieving measurements for tree ID: %v", treeID)
+    rows, err := r.db.Query(`SELECT id, tree_id, height, measured_at, source, surveyor, created_at
+        FROM tree_measurements WHERE tree_id = $1 ORDER BY measured_at, created_at`, treeID)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve measurements for tree ID %v: %v", treeID, err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    measurements := []*models.TreeMeasurement{}
+    for rows.Next() {
+        m := &models.TreeMeasurement{}
+        if err := rows.Scan(&m.ID, &m.TreeID, &m.Height, &m.MeasuredAt, &m.Source, &m.Surveyor, &m.CreatedAt); err != nil {
+            logrus.Errorf("Failed to scan measurement row for tree ID %v: %v", treeID, err)
+            return nil, err
+        }
+        measurements = append(measurements, m)
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during rows iteration for tree ID %v: %v", treeID, err)
+        return nil, err
+    }
+    logrus.Infof("Retrieved %d measurements for tree ID: %v", len(measurements), treeID)
+    return measurements, nil
+}
+
+// AddObservation stores a health observation of a tree and updates the current
+// health status of the tree from its latest observation, in one transaction.
+func (r *treeRepository) AddObservation(observation *models.TreeObservation) error {
+    logrus.Infof("Recording observation ID: %v for tree ID: %v", observation.ID, observation.TreeID)
+    tx, err := r.db.Begin()
+    if err != nil {
+        logrus.Errorf("Failed to begin transaction for observation of tree ID %v: %v", observation.TreeID, err)
+        return err
+    }
+    defer tx.Rollback()
+
+    _, err = tx.Exec(`INSERT INTO tree_observations (id, tree_id, status, severity, notes, observer, observed_at, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+        observation.ID, observation.TreeID, observation.Status, observation.Severity, observation.Notes,
+        observation.Observer, observation.ObservedAt, observation.CreatedAt)
+    if err != nil {
+        logrus.Errorf("Failed to record observation for tree ID %v: %v", observation.TreeID, err)
+        return err
+    }
+    _, err = tx.Exec(`UPDATE trees SET health_status = (SELECT status FROM tree_observations WHERE tree_id = $1 ORDER BY `+latestObservationOrder+` LIMIT 1)
+        WHERE id = $1`, observation.TreeID)
+    if err != nil {
+        logrus.Errorf("Failed to update health status of tree ID %v: %v", observation.TreeID, err)
+        return err
+    }
+    if err := tx.Commit(); err != nil {
+        logrus.Errorf("Failed to commit observation for tree ID %v: %v", observation.TreeID, err)
+        return err
+    }
+    logrus.Infof("Observation recorded successfully for tree ID: %v", observation.TreeID)
+    return nil
+}
+
+// GetObservations retrieves the health observations of a tree, oldest first.
+func (r *treeRepository) GetObservations(treeID uuid.UUID) ([]*models.TreeObservation, error) {
+    logrus.Infof("Retrieving observations for tree ID: %v", treeID)
+    rows, err := r.db.Query(`SELECT id, tree_id, status, severity, notes, observer, observed_at, created_at
+        FROM tree_observations WHERE tree_id = $1 ORDER BY observed_at, created_at`, treeID)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve observations for tree ID %v: %v", treeID, err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    observations := []*models.TreeObservation{}
+    for rows.Next() {
+        o := &models.TreeObservation{}
+        if err := rows.Scan(&o.ID, &o.TreeID, &o.Status, &o.Severity, &o.Notes, &o.Observer, &o.ObservedAt, &o.CreatedAt); err != nil {
+            logrus.Errorf("Failed to scan observation row for tree ID %v: %v", treeID, err)
+            return nil, err
+        }
+        observations = append(observations, o)
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during rows iteration for tree ID %v: %v", treeID, err)
+        return nil, err
+    }
+    logrus.Infof("Retrieved %d observations for tree ID: %v", len(observations), treeID)
+    return observations, nil
+}
+
+// GetTreesToInspect retrieves the trees of an estate whose current health
+// status is neither healthy nor dead, ordered by row then column. Dead palms
+// are left out since there is nothing left to inspect.
+func (r *treeRepository) GetTreesToInspect(estateID uuid.UUID) ([]*models.Tree, error) {
+    logrus.Infof("Retrieving trees to inspect for estate ID: %v", estateID)
+    rows, err := r.db.Query("SELECT "+treeColumns+` FROM trees
+        WHERE estate_id = $1 AND health_status NOT IN ($2, $3) ORDER BY y, x`,
+        estateID, models.HealthStatusHealthy, models.HealthStatusDead)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve trees to inspect for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    trees := []*models.Tree{}
+    for rows.Next() {
+        tree, err := scanTree(rows)
+        if err != nil {
+            logrus.Errorf("Failed to scan tree row for estate ID %v: %v", estateID, err)
+            return nil, err
+        }
+        trees = append(trees, tree)
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during tree rows iteration for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    logrus.Infof("Retrieved %d trees to inspect for estate ID: %v", len(trees), estateID)
+    return trees, nil
+}
+
+// GetTreesWithin retrieves the trees of an estate at most radius plots from
+// (x, y) in a straight line, nearest first. The point lookup uses the GiST index
+// on the estate and location of the trees.
+func (r *treeRepository) GetTreesWithin(estateID uuid.UUID, x, y, radius int) ([]*models.TreeNeighbour, error) {
+    logrus.Infof("Retrieving trees within %d plots of (%d, %d) for estate ID: %v", radius, x, y, estateID)
+    return r.queryNeighbours(estateID, "SELECT "+treeColumns+`, location <-> point($2, $3) AS distance FROM trees
+        WHERE estate_id = $1 AND location <@ circle(point($2, $3), $4)
+        ORDER BY distance, y, x`, estateID, x, y, radius)
+}
+
+// GetNearestTrees retrieves the k trees of an estate nearest to (x, y), nearest
+// first. The GiST index on the estate and location of the trees returns them
+// in order of distance without sorting the estate.
+func (r *treeRepository) GetNearestTrees(estateID uuid.UUID, x, y, k int) ([]*models.TreeNeighbour, error) {
+    logrus.Infof("Retrieving the %d trees nearest to (%d, %d) for estate ID: %v", k, x, y, estateID)
+    return r.queryNeighbours(estateID, "SELECT "+treeColumns+`, location <-> point($2, $3) AS distance FROM trees
+        WHERE estate_id = $1
+        ORDER BY location <-> point($2, $3)
+        LIMIT $4`, estateID, x, y, k)
+}
+
+// ScanTreesInBox calls fn for every tree of an estate within a rectangle of
+// plots, bounds included. The rectangle lookup uses the GiST index on the
+// estate and location of the trees. An error returned by fn stops the scan and
+// is returned.
+func (r *treeRepository) ScanTreesInBox(estateID uuid.UUID, box models.BoundingBox, fn func(tree *models.Tree) error) error {
+    rows, err := r.db.Query("SELECT "+treeColumns+` FROM trees
+        WHERE estate_id = $1 AND location <@ box(point($2, $3), point($4, $5))`,
+        estateID, box.X1, box.Y1, box.X2, box.Y2)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve trees in (%d, %d)-(%d, %d) for estate ID %v: %v", box.X1, box.Y1, box.X2, box.Y2, estateID, err)
+        return err
+    }
+    defer rows.Close()
+
+    for rows.Next() {
+        tree, err := scanTree(rows)
+        if err != nil {
+            logrus.Errorf("Failed to scan tree row for estate ID %v: %v", estateID, err)
+            return err
+        }
+        if err := fn(tree); err != nil {
+            return err
+        }
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during tree rows iteration for estate ID %v: %v", estateID, err)
+        return err
+    }
+    return nil
+}
+
+// queryNeighbours runs a query selecting treeColumns followed by a distance in
+// plots, and reads the trees with their distance.
+func (r *treeRepository) queryNeighbours(estateID uuid.UUID, query string, args ...interface{}) ([]*models.TreeNeighbour, error) {
+    rows, err := r.db.Query(query, args...)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve neighbouring trees for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    neighbours := []*models.TreeNeighbour{}
+    for rows.Next() {
+        var distance float64
+        tree, err := scanTree(withColumns{rows, []interface{}{&distance}})
+        if err != nil {
+            logrus.Errorf("Failed to scan neighbouring tree row for estate ID %v: %v", estateID, err)
+            return nil, err
+        }
+        neighbours = append(neighbours, &models.TreeNeighbour{
+            Tree:           *tree,
+            DistancePlots:  round2(distance),
+            DistanceMeters: round2(distance * models.PlotSizeMeters),
+        })
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during neighbouring tree rows iteration for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    logrus.Infof("Retrieved %d neighbouring trees for estate ID: %v", len(neighbours), estateID)
+    return neighbours, nil
+}
+
+// withColumns reads extra columns selected after those a scan helper expects.
+type withColumns struct {
+    row   rowScanner
+    extra []interface{}
+}
+
+// Scan reads the row into dest followed by the extra columns.
+func (w withColumns) Scan(dest ...interface{}) error {
+    return w.row.Scan(append(dest, w.extra...)...)
+}
+
+// GetTreeHeightsAsOf retrieves the heights of the trees of an estate from their
+// latest measurement taken before the given time, keyed like GetTreesByEstateID.
+// Trees without a measurement by then are left out.
+func (r *treeRepository) GetTreeHeightsAsOf(estateID uuid.UUID, before time.Time) (map[string]int, error) {
+    logrus.Infof("Retrieving tree heights before %v for estate ID: %v", before, estateID)
+    rows, err := r.db.Query(`SELECT DISTINCT ON (t.id) t.x, t.y, m.height
+        FROM trees t JOIN tree_measurements m ON m.tree_id = t.id
+        WHERE t.estate_id = $1 AND m.measured_at < $2
+        ORDER BY t.id, m.measured_at DESC, m.created_at DESC`, estateID, before)
+    if err != nil {
+        logrus.Errorf("Failed to retrieve tree heights for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    treeHeights := make(map[string]int)
+    for rows.Next() {
+        var x, y, height int
+        if err := rows.Scan(&x, &y, &height); err != nil {
+            logrus.Errorf("Failed to scan tree height row for estate ID %v: %v", estateID, err)
+            return nil, err
+        }
+        treeHeights[fmt.Sprintf("%d,%d", x, y)] = height
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during rows iteration for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    logrus.Infof("Tree heights retrieved successfully for estate ID: %v", estateID)
+    return treeHeights, nil
+}
+
+// ImportTrees inserts many trees, with their first height measurement, using
+// COPY in a single transaction: either every tree is stored or none is. The
+// trees are counted in the height summary of their estate in one update.
+func (r *treeRepository) ImportTrees(trees []*models.Tree, measuredAt time.Time) error {
+    if len(trees) == 0 {
+        return nil
+    }
+    logrus.Infof("Importing %d trees into estate ID: %v", len(trees), trees[0].EstateID)
+    tx, err := r.db.Begin()
+    if err != nil {
+        logrus.Errorf("Failed to begin transaction for tree import: %v", err)
+        return err
+    }
+    defer tx.Rollback()
+
+    maxX, maxY := 0, 0
+    for _, tree := range trees {
+        maxX, maxY = max(maxX, tree.X), max(maxY, tree.Y)
+    }
+    if err := lockEstateBounds(tx, trees[0].EstateID, maxX, maxY); err != nil {
+        logrus.Warnf("Trees not imported into estate ID %v: %v", trees[0].EstateID, err)
+        return err
+    }
+    err = copyRows(tx, pq.CopyIn("trees", "id", "estate_id", "x", "y", "height", "species", "planted_on"), len(trees), func(i int) []interface{} {
+        tree := trees[i]
+        return []interface{}{tree.ID, tree.EstateID, tree.X, tree.Y, tree.Height, speciesValue(tree), plantedOnValue(tree)}
+    })
+    if err != nil {
+        logrus.Errorf("Failed to copy trees: %v", err)
+        if isUniqueViolation(err) {
+            return ErrPlotOccupied
+        }
+        return err
+    }
+    err = copyRows(tx, pq.CopyIn("tree_measurements", "id", "tree_id", "height", "measured_at", "source", "surveyor"), len(trees), func(i int) []interface{} {
+        tree := trees[i]
+        return []interface{}{uuid.New(), tree.ID, tree.Height, measuredAt, models.MeasurementSourceManual, ""}
+    })
+    if err != nil {
+        logrus.Errorf("Failed to copy initial measurements: %v", err)
+        return err
+    }
+    delta := newHeightCounts()
+    for _, tree := range trees {
+        delta.add(tree.Height, 1)
+    }
+    if err := updateHeightSummary(tx, trees[0].EstateID, delta); err != nil {
+        logrus.Errorf("Failed to update height summary of estate ID %v: %v", trees[0].EstateID, err)
+        return err
+    }
+    if err := tx.Commit(); err != nil {
+        logrus.Errorf("Failed to commit tree import: %v", err)
+        return err
+    }
+    logrus.Infof("Imported %d trees into estate ID: %v", len(trees), trees[0].EstateID)
+    return nil
+}
+
+// lockEstateBounds share locks the estate trees are planted in or moved
+// within, and checks that the plot x, y lies inside it. The lock is held until
+// the transaction ends, so the estate cannot be resized in the meantime:
+// UpdateEstate locks the estate row before checking its trees.
+func lockEstateBounds(tx *sql.Tx, estateID uuid.UUID, x, y int) error {
+    var width, length int
+    err := tx.QueryRow("SELECT width, length FROM estates WHERE id = $1 FOR SHARE", estateID).Scan(&width, &length)
+    if err == sql.ErrNoRows {
+        return ErrEstateNotFound
+    }
+    if err != nil {
+        return err
+    }
+    if x > width || y > length {
+        return ErrTreeOutOfBounds
+    }
+    return nil
+}
+
+// copyRows streams n rows produced by row into a COPY statement.
+func copyRows(tx *sql.Tx, copyStatement string, n int, row func(i int) []interface{}) error {
+    stmt, err := tx.Prepare(copyStatement)
+    if err != nil {
+        return err
+    }
+    defer stmt.Close()
+
+    for i := 0; i < n; i++ {
+        if _, err := stmt.Exec(row(i)...); err != nil {
+            return err
+        }
+    }
+    // An Exec without arguments flushes the buffered rows
+    _, err = stmt.Exec()
+    return err
+}
+
+// ExportTrees calls fn for every tree of an estate, ordered by row then column.
+// The trees are read through a server-side cursor, exportFetchSize at a time,
+// so an estate of any size can be exported without loading it into memory.
+// An error returned by fn stops the export and is returned.
+func (r *treeRepository) ExportTrees(estateID uuid.UUID, fn func(tree *models.Tree) error) error {
+    logrus.Infof("Exporting trees for estate ID: %v", estateID)
+    tx, err := r.db.Begin()
+    if err != nil {
+        logrus.Errorf("Failed to begin transaction for exporting estate ID %v: %v", estateID, err)
+        return err
+    }
+    defer tx.Rollback()
+
+    _, err = tx.Exec(`DECLARE tree_export NO SCROLL CURSOR FOR
+        SELECT `+treeColumns+` FROM trees WHERE estate_id = $1 ORDER BY y, x`, estateID)
+    if err != nil {
+        logrus.Errorf("Failed to open export cursor for estate ID %v: %v", estateID, err)
+        return err
+    }
+
+    exported := 0
+    for {
+        fetched, err := fetchTrees(tx, fn)
+        if err != nil {
+            logrus.Errorf("Failed to export trees for estate ID %v after %d trees: %v", estateID, exported, err)
+            return err
+        }
+        exported += fetched
+        if fetched < exportFetchSize {
+            break
+        }
+    }
+    if err := tx.Commit(); err != nil {
+        logrus.Errorf("Failed to close export of estate ID %v: %v", estateID, err)
+        return err
+    }
+    logrus.Infof("Exported %d trees for estate ID: %v", exported, estateID)
+    return nil
+}
+
+// fetchTrees reads the next batch of trees from the export cursor.
+func fetchTrees(tx *sql.Tx, fn func(tree *models.Tree) error) (int, error) {
+    rows, err := tx.Query(fmt.Sprintf("FETCH %d FROM tree_export", exportFetchSize))
+    if err != nil {
+        return 0, err
+    }
+    defer rows.Close()
+
+    fetched := 0
+    for rows.Next() {
+        tree, err := scanTree(rows)
+        if err != nil {
+            return fetched, err
+        }
+        fetched++
+        if err := fn(tree); err != nil {
+            return fetched, err
+        }
+    }
+    return fetched, rows.Err()
+}
+
+// ListTrees retrieves one page of the trees of an estate matching the query,
+// using keyset pagination on the sort key and the tree ID.
+func (r *treeRepository) ListTrees(estateID uuid.UUID, query models.TreeQuery) (*models.TreePage, error) {
+    logrus.Infof("Listing trees of estate ID %v sorted by %s (desc=%t)", estateID, query.SortBy, query.Desc)
+
+    sortColumns := []string{"t.y", "t.x"}
+    if query.SortBy == models.TreeSortHeight {
+        sortColumns = []string{"t.height"}
+    }
+    direction, comparison := "ASC", ">"
+    if query.Desc {
+        direction, comparison = "DESC", "<"
+    }
+
+    args := []interface{}{estateID}
+    conditions := []string{"t.estate_id = $1"}
+    addCondition := func(format string, value interface{}) {
+        args = append(args, value)
+        conditions = append(conditions, fmt.Sprintf(format, len(args)))
+    }
+
+    bounds := []struct {
+        expr  string
+        op    string
+        value *int
+    }{
+        {"t.x", ">=", query.MinX},
+        {"t.x", "<=", query.MaxX},
+        {"t.y", ">=", query.MinY},
+        {"t.y", "<=", query.MaxY},
+        {"t.height", ">=", query.MinHeight},
+        {"t.height", "<=", query.MaxHeight},
+    }
+    for _, b := range bounds {
+        if b.value != nil {
+            addCondition(b.expr+" "+b.op+" $%d", *b.value)
+        }
+    }
+    if query.Species != "" {
+        addCondition("t.species = $%d", query.Species)
+    }
+    if query.HealthStatus != "" {
+        addCondition("t.health_status = $%d", query.HealthStatus)
+    }
+
+    if query.Cursor != "" {
+        sortValues, id, err := decodeTreeCursor(query.Cursor, len(sortColumns))
+        if err != nil {
+            logrus.Warnf("Failed to decode tree cursor %q: %v", query.Cursor, err)
+            return nil, ErrInvalidCursor
+        }
+        placeholders := make([]string, 0, len(sortValues)+1)
+        for _, value := range append(sortValues, id) {
+            args = append(args, value)
+            placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+        }
+        conditions = append(conditions, fmt.Sprintf("(%s, t.id) %s (%s)",
+            strings.Join(sortColumns, ", "), comparison, strings.Join(placeholders, ", ")))
+    }
+
+    order := make([]string, 0, len(sortColumns)+1)
+    for _, column := range append(sortColumns, "t.id") {
+        order = append(order, column+" "+direction)
+    }
+    // Fetch one extra row to find out whether there is a next page
+    args = append(args, query.Limit+1)
+    sqlQuery := fmt.Sprintf("SELECT %s FROM trees t WHERE %s ORDER BY %s LIMIT $%d", treeColumns,
+        strings.Join(conditions, " AND "), strings.Join(order, ", "), len(args))
+
+    rows, err := r.db.Query(sqlQuery, args...)
+    if err != nil {
+        logrus.Errorf("Failed to list trees of estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    page := &models.TreePage{Trees: []*models.Tree{}}
+    for rows.Next() {
+        tree, err := scanTree(rows)
+        if err != nil {
+            logrus.Errorf("Failed to scan tree row for estate ID %v: %v", estateID, err)
+            return nil, err
+        }
+        page.Trees = append(page.Trees, tree)
+    }
+    if err := rows.Err(); err != nil {
+        logrus.Errorf("Error occurred during tree rows iteration for estate ID %v: %v", estateID, err)
+        return nil, err
+    }
+
+    if len(page.Trees) > query.Limit {
+        page.Trees = page.Trees[:query.Limit]
+        page.NextCursor = encodeTreeCursor(page.Trees[query.Limit-1], query.SortBy)
+    }
+    logrus.Infof("Listed %d trees of estate ID %v", len(page.Trees), estateID)
+    return page, nil
+}
+
+// encodeTreeCursor builds an opaque cursor pointing just after the given tree.
+func encodeTreeCursor(tree *models.Tree, sortBy string) string {
+    sortValues := fmt.Sprintf("%d,%d", tree.Y, tree.X)
+    if sortBy == models.TreeSortHeight {
+        sortValues = strconv.Itoa(tree.Height)
+    }
+    return base64.RawURLEncoding.EncodeToString([]byte(sortValues + "|" + tree.ID.String()))
+}
+
+// decodeTreeCursor extracts the sort values and tree ID from a cursor.
+func decodeTreeCursor(cursor string, sortValueCount int) ([]interface{}, uuid.UUID, error) {
+    raw, err := base64.RawURLEncoding.DecodeString(cursor)
+    if err != nil {
+        return nil, uuid.Nil, err
+    }
+    parts := strings.SplitN(string(raw), "|", 2)
+    if len(parts) != 2 {
+        return nil, uuid.Nil, errors.New("malformed cursor")
+    }
+    id, err := uuid.Parse(parts[1])
+    if err != nil {
+        return nil, uuid.Nil, err
+    }
+    fields := strings.Split(parts[0], ",")
+    if len(fields) != sortValueCount {
+        return nil, uuid.Nil, errors.New("cursor does not match the sort key")
+    }
+    sortValues := make([]interface{}, len(fields))
+    for i, field := range fields {
+        value, err := strconv.Atoi(field)
+        if err != nil {
+            return nil, uuid.Nil, err
+        }
+        sortValues[i] = value
+    }
+    return sortValues, id, nil
+}
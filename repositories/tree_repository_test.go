@@ -4,12 +4,25 @@ import (
     "database/sql"
     "errors"
     "testing"
+    "time"
     "sawitpro-recruitment/models"
     "github.com/DATA-DOG/go-sqlmock"
     "github.com/google/uuid"
+    "github.com/lib/pq"
     "github.com/stretchr/testify/assert"
 )
 
+// treeRowColumns are the columns of a tree row read by scanTree.
+var treeRowColumns = []string{"id", "estate_id", "x", "y", "height", "species", "planted_on", "health_status"}
+
+// expectEstateBoundsLocked expects the estate of a tree write to be share
+// locked, with the given dimensions.
+func expectEstateBoundsLocked(mock sqlmock.Sqlmock, estateID uuid.UUID, width, length int) {
+    mock.ExpectQuery(`SELECT width, length FROM estates WHERE id = \$1 FOR SHARE`).
+        WithArgs(estateID).
+        WillReturnRows(sqlmock.NewRows([]string{"width", "length"}).AddRow(width, length))
+}
+
 func TestTreeRepository_AddTreeToEstate(t *testing.T) {
     db, mock, err := sqlmock.New()
     assert.NoError(t, err)
@@ -25,9 +38,19 @@ func TestTreeRepository_AddTreeToEstate(t *testing.T) {
         Height:   30,
     }
 
+    mock.ExpectBegin()
+    expectEstateBoundsLocked(mock, tree.EstateID, 50, 50)
     mock.ExpectExec("INSERT INTO trees").
-        WithArgs(tree.ID, tree.EstateID, tree.X, tree.Y, tree.Height).
+        WithArgs(tree.ID, tree.EstateID, tree.X, tree.Y, tree.Height, nil, nil).
+        WillReturnResult(sqlmock.NewResult(1, 1))
+    mock.ExpectExec("INSERT INTO tree_measurements").
+        WithArgs(sqlmock.AnyArg(), tree.ID, tree.Height, models.MeasurementSourceManual).
         WillReturnResult(sqlmock.NewResult(1, 1))
+    mock.ExpectQuery(heightSummaryQuery).
+        WithArgs(tree.EstateID).
+        WillReturnRows(heightSummaryRow(map[int]int{12: 1, 30: 2}))
+    expectHeightSummaryStored(mock, tree.EstateID, map[int]int{12: 1, 30: 3})
+    mock.ExpectCommit()
 
     err = repo.AddTreeToEstate(tree)
     assert.NoError(t, err)
@@ -49,15 +72,78 @@ func TestTreeRepository_AddTreeToEstate_Error(t *testing.T) {
         Height:   30,
     }
 
+    mock.ExpectBegin()
+    expectEstateBoundsLocked(mock, tree.EstateID, 50, 50)
     mock.ExpectExec("INSERT INTO trees").
-        WithArgs(tree.ID, tree.EstateID, tree.X, tree.Y, tree.Height).
+        WithArgs(tree.ID, tree.EstateID, tree.X, tree.Y, tree.Height, nil, nil).
         WillReturnError(errors.New("insert error"))
+    mock.ExpectRollback()
 
     err = repo.AddTreeToEstate(tree)
     assert.Error(t, err)
     assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestTreeRepository_AddTreeToEstate_PlotOccupied(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    tree := &models.Tree{ID: uuid.New(), EstateID: uuid.New(), X: 10, Y: 20, Height: 30}
+
+    mock.ExpectBegin()
+    expectEstateBoundsLocked(mock, tree.EstateID, 50, 50)
+    mock.ExpectExec("INSERT INTO trees").
+        WithArgs(tree.ID, tree.EstateID, tree.X, tree.Y, tree.Height, nil, nil).
+        WillReturnError(&pq.Error{Code: "23505"})
+    mock.ExpectRollback()
+
+    err = repo.AddTreeToEstate(tree)
+    assert.ErrorIs(t, err, ErrPlotOccupied)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_AddTreeToEstate_OutOfBounds(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    tree := &models.Tree{ID: uuid.New(), EstateID: uuid.New(), X: 10, Y: 20, Height: 30}
+
+    // The estate was shrunk after the handler checked the plot
+    mock.ExpectBegin()
+    expectEstateBoundsLocked(mock, tree.EstateID, 10, 15)
+    mock.ExpectRollback()
+
+    err = repo.AddTreeToEstate(tree)
+    assert.ErrorIs(t, err, ErrTreeOutOfBounds)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_AddTreeToEstate_EstateNotFound(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    tree := &models.Tree{ID: uuid.New(), EstateID: uuid.New(), X: 10, Y: 20, Height: 30}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery(`SELECT width, length FROM estates WHERE id = \$1 FOR SHARE`).
+        WithArgs(tree.EstateID).
+        WillReturnError(sql.ErrNoRows)
+    mock.ExpectRollback()
+
+    err = repo.AddTreeToEstate(tree)
+    assert.ErrorIs(t, err, ErrEstateNotFound)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestTreeRepository_GetTreeByCoordinates(t *testing.T) {
     db, mock, err := sqlmock.New()
     assert.NoError(t, err)
@@ -67,16 +153,18 @@ func TestTreeRepository_GetTreeByCoordinates(t *testing.T) {
 
     estateID := uuid.New()
     expectedTree := &models.Tree{
-        ID:     uuid.New(),
-        X:      10,
-        Y:      20,
-        Height: 30,
+        ID:           uuid.New(),
+        EstateID:     estateID,
+        X:            10,
+        Y:            20,
+        Height:       30,
+        HealthStatus: models.HealthStatusHealthy,
     }
 
-    rows := sqlmock.NewRows([]string{"id", "x", "y", "height"}).
-        AddRow(expectedTree.ID, expectedTree.X, expectedTree.Y, expectedTree.Height)
+    rows := sqlmock.NewRows(treeRowColumns).
+        AddRow(expectedTree.ID, estateID, expectedTree.X, expectedTree.Y, expectedTree.Height, "", nil, "healthy")
 
-    mock.ExpectQuery("SELECT id, x, y, height FROM trees WHERE estate_id = \\$1 AND x = \\$2 AND y = \\$3").
+    mock.ExpectQuery("SELECT id, estate_id, x, y, height, COALESCE\\(species, ''\\), planted_on, health_status FROM trees WHERE estate_id = \\$1 AND x = \\$2 AND y = \\$3").
         WithArgs(estateID, 10, 20).
         WillReturnRows(rows)
 
@@ -95,7 +183,7 @@ func TestTreeRepository_GetTreeByCoordinates_NoRows(t *testing.T) {
 
     estateID := uuid.New()
 
-    mock.ExpectQuery("SELECT id, x, y, height FROM trees WHERE estate_id = \\$1 AND x = \\$2 AND y = \\$3").
+    mock.ExpectQuery("SELECT id, estate_id, x, y, height, COALESCE\\(species, ''\\), planted_on, health_status FROM trees WHERE estate_id = \\$1 AND x = \\$2 AND y = \\$3").
         WithArgs(estateID, 10, 20).
         WillReturnError(sql.ErrNoRows)
 
@@ -114,7 +202,7 @@ func TestTreeRepository_GetTreeByCoordinates_Error(t *testing.T) {
 
     estateID := uuid.New()
 
-    mock.ExpectQuery("SELECT id, x, y, height FROM trees WHERE estate_id = \\$1 AND x = \\$2 AND y = \\$3").
+    mock.ExpectQuery("SELECT id, estate_id, x, y, height, COALESCE\\(species, ''\\), planted_on, health_status FROM trees WHERE estate_id = \\$1 AND x = \\$2 AND y = \\$3").
         WithArgs(estateID, 10, 20).
         WillReturnError(errors.New("query error"))
 
@@ -168,4 +256,661 @@ func TestTreeRepository_GetTreesByEstateID_Error(t *testing.T) {
     assert.Error(t, err)
     assert.Nil(t, treeHeights)
     assert.NoError(t, mock.ExpectationsWereMet())
-}
\ No newline at end of file
+}
+
+func TestTreeRepository_GetTreeByID(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    estateID := uuid.New()
+    treeID := uuid.New()
+    // Planted ten years and a day ago
+    plantedOn := time.Now().UTC().AddDate(-10, 0, -1).Truncate(24 * time.Hour)
+    rows := sqlmock.NewRows(treeRowColumns).
+        AddRow(treeID, estateID, 3, 4, 12, "tenera", plantedOn, "ganoderma")
+
+    mock.ExpectQuery(`SELECT id, estate_id, x, y, height, COALESCE\(species, ''\), planted_on, health_status FROM trees WHERE id = \$1 AND estate_id = \$2`).
+        WithArgs(treeID, estateID).
+        WillReturnRows(rows)
+
+    tree, err := repo.GetTreeByID(estateID, treeID)
+    assert.NoError(t, err)
+    age := 10
+    assert.Equal(t, &models.Tree{ID: treeID, EstateID: estateID, X: 3, Y: 4, Height: 12,
+        Species: "tenera", PlantedOn: &models.Date{Time: plantedOn}, AgeYears: &age, HealthStatus: "ganoderma"}, tree)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_GetTreeByID_NotFound(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    estateID := uuid.New()
+    treeID := uuid.New()
+
+    mock.ExpectQuery(`SELECT id, estate_id, x, y, height, COALESCE\(species, ''\), planted_on, health_status FROM trees`).
+        WithArgs(treeID, estateID).
+        WillReturnError(sql.ErrNoRows)
+
+    tree, err := repo.GetTreeByID(estateID, treeID)
+    assert.NoError(t, err)
+    assert.Nil(t, tree)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_UpdateTree(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    plantedOn, _ := models.ParseDate("2012-07-15")
+    tree := &models.Tree{ID: uuid.New(), EstateID: uuid.New(), X: 2, Y: 5, Height: 20, Species: "tenera", PlantedOn: &plantedOn}
+
+    mock.ExpectBegin()
+    expectEstateBoundsLocked(mock, tree.EstateID, 50, 50)
+    mock.ExpectQuery(`UPDATE trees t SET x = \$3, y = \$4, height = \$5, species = \$6, planted_on = \$7\s+` +
+        `FROM \(SELECT id, height FROM trees WHERE id = \$1 AND estate_id = \$2 FOR UPDATE\) previous\s+` +
+        `WHERE t.id = previous.id\s+RETURNING previous.height`).
+        WithArgs(tree.ID, tree.EstateID, tree.X, tree.Y, tree.Height, "tenera", tree.PlantedOn.Time).
+        WillReturnRows(sqlmock.NewRows([]string{"height"}).AddRow(18))
+    // The new height is appended to the history
+    mock.ExpectExec(`INSERT INTO tree_measurements`).
+        WithArgs(sqlmock.AnyArg(), tree.ID, tree.Height, models.MeasurementSourceManual).
+        WillReturnResult(sqlmock.NewResult(1, 1))
+    // The tree moves from 18 to 20 meters in the summary
+    mock.ExpectQuery(heightSummaryQuery).
+        WithArgs(tree.EstateID).
+        WillReturnRows(heightSummaryRow(map[int]int{18: 2}))
+    expectHeightSummaryStored(mock, tree.EstateID, map[int]int{18: 1, 20: 1})
+    mock.ExpectCommit()
+
+    err = repo.UpdateTree(tree)
+    assert.NoError(t, err)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_UpdateTree_MoveOnly(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    tree := &models.Tree{ID: uuid.New(), EstateID: uuid.New(), X: 3, Y: 5, Height: 18}
+
+    // The height is unchanged, so no measurement is recorded
+    mock.ExpectBegin()
+    expectEstateBoundsLocked(mock, tree.EstateID, 50, 50)
+    mock.ExpectQuery(`UPDATE trees t SET`).
+        WithArgs(tree.ID, tree.EstateID, tree.X, tree.Y, tree.Height, nil, nil).
+        WillReturnRows(sqlmock.NewRows([]string{"height"}).AddRow(18))
+    mock.ExpectCommit()
+
+    err = repo.UpdateTree(tree)
+    assert.NoError(t, err)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_UpdateTree_PlotOccupied(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    tree := &models.Tree{ID: uuid.New(), EstateID: uuid.New(), X: 2, Y: 5, Height: 20}
+
+    mock.ExpectBegin()
+    expectEstateBoundsLocked(mock, tree.EstateID, 50, 50)
+    mock.ExpectQuery(`UPDATE trees`).
+        WithArgs(tree.ID, tree.EstateID, tree.X, tree.Y, tree.Height, nil, nil).
+        WillReturnError(&pq.Error{Code: "23505"})
+    mock.ExpectRollback()
+
+    err = repo.UpdateTree(tree)
+    assert.ErrorIs(t, err, ErrPlotOccupied)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_DeleteTree_NotFound(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    estateID := uuid.New()
+    treeID := uuid.New()
+
+    mock.ExpectBegin()
+    mock.ExpectQuery(`DELETE FROM trees WHERE id = \$1 AND estate_id = \$2 RETURNING height`).
+        WithArgs(treeID, estateID).
+        WillReturnError(sql.ErrNoRows)
+    mock.ExpectRollback()
+
+    err = repo.DeleteTree(estateID, treeID)
+    assert.ErrorIs(t, err, ErrTreeNotFound)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_AddMeasurement(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    now := time.Now().UTC()
+    measurement := &models.TreeMeasurement{
+        ID:         uuid.New(),
+        TreeID:     uuid.New(),
+        Height:     14,
+        MeasuredAt: now.Add(-time.Hour),
+        Source:     models.MeasurementSourceDrone,
+        Surveyor:   "Survey team A",
+        CreatedAt:  now,
+    }
+
+    mock.ExpectBegin()
+    mock.ExpectExec("INSERT INTO tree_measurements").
+        WithArgs(measurement.ID, measurement.TreeID, measurement.Height, measurement.MeasuredAt,
+            measurement.Source, measurement.Surveyor, measurement.CreatedAt).
+        WillReturnResult(sqlmock.NewResult(1, 1))
+    estateID := uuid.New()
+    mock.ExpectQuery(`UPDATE trees t SET height = \(SELECT height FROM tree_measurements WHERE tree_id = \$1 ORDER BY measured_at DESC, created_at DESC LIMIT 1\)\s+` +
+        `FROM \(SELECT id, height FROM trees WHERE id = \$1 FOR UPDATE\) previous\s+WHERE t.id = previous.id\s+` +
+        `RETURNING t.estate_id, previous.height, t.height`).
+        WithArgs(measurement.TreeID).
+        WillReturnRows(sqlmock.NewRows([]string{"estate_id", "height", "height"}).AddRow(estateID, 12, 14))
+    mock.ExpectQuery(heightSummaryQuery).
+        WithArgs(estateID).
+        WillReturnRows(heightSummaryRow(map[int]int{12: 3, 14: 1}))
+    expectHeightSummaryStored(mock, estateID, map[int]int{12: 2, 14: 2})
+    mock.ExpectCommit()
+
+    err = repo.AddMeasurement(measurement)
+    assert.NoError(t, err)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_AddMeasurement_Error(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    measurement := &models.TreeMeasurement{ID: uuid.New(), TreeID: uuid.New(), Height: 14, Source: models.MeasurementSourceManual}
+
+    mock.ExpectBegin()
+    mock.ExpectExec("INSERT INTO tree_measurements").
+        WillReturnError(errors.New("insert error"))
+    mock.ExpectRollback()
+
+    err = repo.AddMeasurement(measurement)
+    assert.Error(t, err)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_GetMeasurements(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    treeID := uuid.New()
+    first := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+    second := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+    rows := sqlmock.NewRows([]string{"id", "tree_id", "height", "measured_at", "source", "surveyor", "created_at"}).
+        AddRow(uuid.New(), treeID, 10, first, "manual", "", first).
+        AddRow(uuid.New(), treeID, 12, second, "lidar", "Team B", second)
+
+    mock.ExpectQuery(`SELECT id, tree_id, height, measured_at, source, surveyor, created_at\s+FROM tree_measurements WHERE tree_id = \$1 ORDER BY measured_at, created_at`).
+        WithArgs(treeID).
+        WillReturnRows(rows)
+
+    measurements, err := repo.GetMeasurements(treeID)
+    assert.NoError(t, err)
+    if assert.Len(t, measurements, 2) {
+        assert.Equal(t, 10, measurements[0].Height)
+        assert.Equal(t, "lidar", measurements[1].Source)
+        assert.Equal(t, "Team B", measurements[1].Surveyor)
+    }
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_GetTreeHeightsAsOf(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    estateID := uuid.New()
+    before := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+    rows := sqlmock.NewRows([]string{"x", "y", "height"}).
+        AddRow(1, 1, 8).
+        AddRow(2, 1, 11)
+
+    mock.ExpectQuery(`SELECT DISTINCT ON \(t.id\) t.x, t.y, m.height\s+FROM trees t JOIN tree_measurements m ON m.tree_id = t.id\s+WHERE t.estate_id = \$1 AND m.measured_at < \$2`).
+        WithArgs(estateID, before).
+        WillReturnRows(rows)
+
+    heights, err := repo.GetTreeHeightsAsOf(estateID, before)
+    assert.NoError(t, err)
+    assert.Equal(t, map[string]int{"1,1": 8, "2,1": 11}, heights)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_ImportTrees(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    estateID := uuid.New()
+    measuredAt := time.Now().UTC()
+    trees := []*models.Tree{
+        {ID: uuid.New(), EstateID: estateID, X: 1, Y: 1, Height: 10},
+        {ID: uuid.New(), EstateID: estateID, X: 2, Y: 1, Height: 12},
+    }
+
+    mock.ExpectBegin()
+    expectEstateBoundsLocked(mock, estateID, 50, 50)
+    copyTrees := mock.ExpectPrepare(`COPY "trees" \("id", "estate_id", "x", "y", "height", "species", "planted_on"\) FROM STDIN`)
+    for _, tree := range trees {
+        copyTrees.ExpectExec().WithArgs(tree.ID, tree.EstateID, tree.X, tree.Y, tree.Height, nil, nil).WillReturnResult(sqlmock.NewResult(0, 0))
+    }
+    copyTrees.ExpectExec().WithArgs().WillReturnResult(sqlmock.NewResult(0, 2))
+    copyMeasurements := mock.ExpectPrepare(`COPY "tree_measurements" \("id", "tree_id", "height", "measured_at", "source", "surveyor"\) FROM STDIN`)
+    for _, tree := range trees {
+        copyMeasurements.ExpectExec().WithArgs(sqlmock.AnyArg(), tree.ID, tree.Height, measuredAt, models.MeasurementSourceManual, "").WillReturnResult(sqlmock.NewResult(0, 0))
+    }
+    copyMeasurements.ExpectExec().WithArgs().WillReturnResult(sqlmock.NewResult(0, 2))
+    // Estates created before the summaries were introduced have none
+    mock.ExpectQuery(heightSummaryQuery).
+        WithArgs(estateID).
+        WillReturnError(sql.ErrNoRows)
+    mock.ExpectCommit()
+
+    err = repo.ImportTrees(trees, measuredAt)
+    assert.NoError(t, err)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_ImportTrees_PlotOccupied(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    tree := &models.Tree{ID: uuid.New(), EstateID: uuid.New(), X: 1, Y: 1, Height: 10}
+
+    mock.ExpectBegin()
+    expectEstateBoundsLocked(mock, tree.EstateID, 50, 50)
+    copyTrees := mock.ExpectPrepare(`COPY "trees"`)
+    copyTrees.ExpectExec().WithArgs(tree.ID, tree.EstateID, tree.X, tree.Y, tree.Height, nil, nil).WillReturnResult(sqlmock.NewResult(0, 0))
+    copyTrees.ExpectExec().WithArgs().WillReturnError(&pq.Error{Code: "23505"})
+    mock.ExpectRollback()
+
+    err = repo.ImportTrees([]*models.Tree{tree}, time.Now())
+    assert.ErrorIs(t, err, ErrPlotOccupied)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_ExportTrees(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    estateID := uuid.New()
+    rows := sqlmock.NewRows(treeRowColumns).
+        AddRow(uuid.New(), estateID, 1, 1, 10, "", nil, "healthy").
+        AddRow(uuid.New(), estateID, 2, 1, 12, "", nil, "healthy")
+
+    mock.ExpectBegin()
+    mock.ExpectExec(`DECLARE tree_export NO SCROLL CURSOR FOR\s+SELECT id, estate_id, x, y, height, COALESCE\(species, ''\), planted_on, health_status FROM trees WHERE estate_id = \$1 ORDER BY y, x`).
+        WithArgs(estateID).
+        WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectQuery(`FETCH 1000 FROM tree_export`).
+        WillReturnRows(rows)
+    mock.ExpectCommit()
+
+    var exported []*models.Tree
+    err = repo.ExportTrees(estateID, func(tree *models.Tree) error {
+        exported = append(exported, tree)
+        return nil
+    })
+    assert.NoError(t, err)
+    if assert.Len(t, exported, 2) {
+        assert.Equal(t, 2, exported[1].X)
+        assert.Equal(t, 12, exported[1].Height)
+    }
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_ExportTrees_CallbackError(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    estateID := uuid.New()
+    rows := sqlmock.NewRows(treeRowColumns).
+        AddRow(uuid.New(), estateID, 1, 1, 10, "", nil, "healthy")
+
+    mock.ExpectBegin()
+    mock.ExpectExec(`DECLARE tree_export`).
+        WithArgs(estateID).
+        WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectQuery(`FETCH 1000 FROM tree_export`).
+        WillReturnRows(rows)
+    mock.ExpectRollback()
+
+    writeErr := errors.New("client went away")
+    err = repo.ExportTrees(estateID, func(tree *models.Tree) error {
+        return writeErr
+    })
+    assert.ErrorIs(t, err, writeErr)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_ListTrees(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    estateID := uuid.New()
+    first, second, third := uuid.New(), uuid.New(), uuid.New()
+    rows := sqlmock.NewRows(treeRowColumns).
+        AddRow(first, estateID, 2, 1, 10, "", nil, "healthy").
+        AddRow(second, estateID, 3, 1, 12, "", nil, "healthy").
+        AddRow(third, estateID, 2, 2, 8, "", nil, "healthy")
+
+    mock.ExpectQuery(`SELECT id, estate_id, x, y, height, COALESCE\(species, ''\), planted_on, health_status FROM trees t WHERE t.estate_id = \$1 AND t.x >= \$2 AND t.x <= \$3 AND t.height >= \$4 ORDER BY t.y ASC, t.x ASC, t.id ASC LIMIT \$5`).
+        WithArgs(estateID, 2, 3, 5, 3).
+        WillReturnRows(rows)
+
+    minX, maxX, minHeight := 2, 3, 5
+    page, err := repo.ListTrees(estateID, models.TreeQuery{
+        SortBy:    models.TreeSortCoordinate,
+        Limit:     2,
+        MinX:      &minX,
+        MaxX:      &maxX,
+        MinHeight: &minHeight,
+    })
+    assert.NoError(t, err)
+    if assert.Len(t, page.Trees, 2) {
+        assert.Equal(t, second, page.Trees[1].ID)
+    }
+    assert.NotEmpty(t, page.NextCursor)
+    assert.NoError(t, mock.ExpectationsWereMet())
+
+    // The cursor continues after the last tree of the page
+    sortValues, id, err := decodeTreeCursor(page.NextCursor, 2)
+    assert.NoError(t, err)
+    assert.Equal(t, []interface{}{1, 3}, sortValues)
+    assert.Equal(t, second, id)
+}
+
+func TestTreeRepository_ListTrees_HeightCursor(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    estateID := uuid.New()
+    lastID := uuid.New()
+    cursor := encodeTreeCursor(&models.Tree{ID: lastID, Height: 12}, models.TreeSortHeight)
+
+    mock.ExpectQuery(`FROM trees t WHERE t.estate_id = \$1 AND t.species = \$2 AND \(t.height, t.id\) < \(\$3, \$4\) ORDER BY t.height DESC, t.id DESC LIMIT \$5`).
+        WithArgs(estateID, "dura", 12, lastID, 11).
+        WillReturnRows(sqlmock.NewRows(treeRowColumns))
+
+    page, err := repo.ListTrees(estateID, models.TreeQuery{
+        SortBy:  models.TreeSortHeight,
+        Desc:    true,
+        Cursor:  cursor,
+        Limit:   10,
+        Species: "dura",
+    })
+    assert.NoError(t, err)
+    assert.Empty(t, page.Trees)
+    assert.Empty(t, page.NextCursor)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_ListTrees_CursorOfOtherSort(t *testing.T) {
+    db, _, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    // A height cursor cannot continue a coordinate listing
+    cursor := encodeTreeCursor(&models.Tree{ID: uuid.New(), Height: 12}, models.TreeSortHeight)
+    _, err = repo.ListTrees(uuid.New(), models.TreeQuery{
+        SortBy: models.TreeSortCoordinate,
+        Cursor: cursor,
+        Limit:  10,
+    })
+    assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestTreeRepository_AddObservation(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    now := time.Now().UTC()
+    observation := &models.TreeObservation{
+        ID:         uuid.New(),
+        TreeID:     uuid.New(),
+        Status:     models.HealthStatusGanoderma,
+        Severity:   3,
+        Notes:      "Basal stem rot",
+        Observer:   "Scout B",
+        ObservedAt: now.Add(-time.Hour),
+        CreatedAt:  now,
+    }
+
+    mock.ExpectBegin()
+    mock.ExpectExec("INSERT INTO tree_observations").
+        WithArgs(observation.ID, observation.TreeID, observation.Status, observation.Severity, observation.Notes,
+            observation.Observer, observation.ObservedAt, observation.CreatedAt).
+        WillReturnResult(sqlmock.NewResult(1, 1))
+    mock.ExpectExec(`UPDATE trees SET health_status = \(SELECT status FROM tree_observations WHERE tree_id = \$1 ORDER BY observed_at DESC, created_at DESC LIMIT 1\)`).
+        WithArgs(observation.TreeID).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectCommit()
+
+    err = repo.AddObservation(observation)
+    assert.NoError(t, err)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_AddObservation_Error(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    observation := &models.TreeObservation{ID: uuid.New(), TreeID: uuid.New(), Status: models.HealthStatusDead, Severity: 5}
+
+    mock.ExpectBegin()
+    mock.ExpectExec("INSERT INTO tree_observations").WillReturnError(sql.ErrConnDone)
+    mock.ExpectRollback()
+
+    err = repo.AddObservation(observation)
+    assert.ErrorIs(t, err, sql.ErrConnDone)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_GetObservations(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    treeID := uuid.New()
+    observedAt := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+    rows := sqlmock.NewRows([]string{"id", "tree_id", "status", "severity", "notes", "observer", "observed_at", "created_at"}).
+        AddRow(uuid.New(), treeID, "oryctes", 2, "Frond damage", "Scout A", observedAt, observedAt).
+        AddRow(uuid.New(), treeID, "healthy", 0, "", "Scout A", observedAt.AddDate(0, 1, 0), observedAt.AddDate(0, 1, 0))
+
+    mock.ExpectQuery(`SELECT id, tree_id, status, severity, notes, observer, observed_at, created_at\s+FROM tree_observations WHERE tree_id = \$1 ORDER BY observed_at, created_at`).
+        WithArgs(treeID).
+        WillReturnRows(rows)
+
+    observations, err := repo.GetObservations(treeID)
+    assert.NoError(t, err)
+    if assert.Len(t, observations, 2) {
+        assert.Equal(t, models.HealthStatusOryctes, observations[0].Status)
+        assert.Equal(t, 2, observations[0].Severity)
+        assert.Equal(t, models.HealthStatusHealthy, observations[1].Status)
+    }
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_GetTreesToInspect(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    estateID := uuid.New()
+    rows := sqlmock.NewRows(treeRowColumns).
+        AddRow(uuid.New(), estateID, 4, 1, 10, "", nil, "ganoderma").
+        AddRow(uuid.New(), estateID, 2, 3, 12, "", nil, "nutrient_deficiency")
+
+    mock.ExpectQuery(`FROM trees\s+WHERE estate_id = \$1 AND health_status NOT IN \(\$2, \$3\) ORDER BY y, x`).
+        WithArgs(estateID, "healthy", "dead").
+        WillReturnRows(rows)
+
+    trees, err := repo.GetTreesToInspect(estateID)
+    assert.NoError(t, err)
+    if assert.Len(t, trees, 2) {
+        assert.Equal(t, 4, trees[0].X)
+        assert.Equal(t, models.HealthStatusGanoderma, trees[0].HealthStatus)
+        assert.Equal(t, models.HealthStatusNutrientDeficiency, trees[1].HealthStatus)
+    }
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_GetTreesWithin(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    estateID := uuid.New()
+    rows := sqlmock.NewRows(append(treeRowColumns, "distance")).
+        AddRow(uuid.New(), estateID, 5, 5, 10, "", nil, "healthy", 0.0).
+        AddRow(uuid.New(), estateID, 6, 6, 12, "", nil, "ganoderma", 1.4142135623730951)
+
+    mock.ExpectQuery(`, location <-> point\(\$2, \$3\) AS distance FROM trees\s+WHERE estate_id = \$1 AND location <@ circle\(point\(\$2, \$3\), \$4\)\s+ORDER BY distance, y, x`).
+        WithArgs(estateID, 5, 5, 2).
+        WillReturnRows(rows)
+
+    neighbours, err := repo.GetTreesWithin(estateID, 5, 5, 2)
+    assert.NoError(t, err)
+    if assert.Len(t, neighbours, 2) {
+        assert.Equal(t, 0.0, neighbours[0].DistanceMeters)
+        assert.Equal(t, 6, neighbours[1].X)
+        assert.Equal(t, models.HealthStatusGanoderma, neighbours[1].HealthStatus)
+        assert.Equal(t, 1.41, neighbours[1].DistancePlots)
+        assert.Equal(t, 14.14, neighbours[1].DistanceMeters)
+    }
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_GetNearestTrees(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    estateID := uuid.New()
+    rows := sqlmock.NewRows(append(treeRowColumns, "distance")).
+        AddRow(uuid.New(), estateID, 3, 1, 10, "", nil, "healthy", 2.0)
+
+    mock.ExpectQuery(`FROM trees\s+WHERE estate_id = \$1\s+ORDER BY location <-> point\(\$2, \$3\)\s+LIMIT \$4`).
+        WithArgs(estateID, 1, 1, 3).
+        WillReturnRows(rows)
+
+    neighbours, err := repo.GetNearestTrees(estateID, 1, 1, 3)
+    assert.NoError(t, err)
+    if assert.Len(t, neighbours, 1) {
+        assert.Equal(t, 3, neighbours[0].X)
+        assert.Equal(t, 20.0, neighbours[0].DistanceMeters)
+    }
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_GetNearestTrees_Error(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    mock.ExpectQuery(`ORDER BY location <-> point`).WillReturnError(sql.ErrConnDone)
+
+    neighbours, err := repo.GetNearestTrees(uuid.New(), 1, 1, 3)
+    assert.ErrorIs(t, err, sql.ErrConnDone)
+    assert.Nil(t, neighbours)
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTreeRepository_ScanTreesInBox(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    assert.NoError(t, err)
+    defer db.Close()
+
+    repo := NewTreeRepository(db)
+
+    estateID := uuid.New()
+    rows := sqlmock.NewRows(treeRowColumns).
+        AddRow(uuid.New(), estateID, 3, 4, 10, "", nil, "healthy").
+        AddRow(uuid.New(), estateID, 5, 6, 12, "tenera", nil, "ganoderma")
+
+    mock.ExpectQuery(`SELECT id, estate_id, x, y, height, COALESCE\(species, ''\), planted_on, health_status FROM trees\s+WHERE estate_id = \$1 AND location <@ box\(point\(\$2, \$3\), point\(\$4, \$5\)\)`).
+        WithArgs(estateID, 2, 3, 8, 9).
+        WillReturnRows(rows)
+
+    var scanned []*models.Tree
+    err = repo.ScanTreesInBox(estateID, models.BoundingBox{X1: 2, Y1: 3, X2: 8, Y2: 9}, func(tree *models.Tree) error {
+        scanned = append(scanned, tree)
+        return nil
+    })
+    assert.NoError(t, err)
+    if assert.Len(t, scanned, 2) {
+        assert.Equal(t, 5, scanned[1].X)
+        assert.Equal(t, "ganoderma", scanned[1].HealthStatus)
+    }
+    assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -6,9 +6,46 @@ import (
 )
 
 // InitRoutes initializes the API routes.
-func InitRoutes(e *echo.Echo, estateHandler *handlers.EstateHandler, treeHandler *handlers.TreeHandler, droneHandler *handlers.DroneHandler) {
+func InitRoutes(e *echo.Echo, estateHandler *handlers.EstateHandler, treeHandler *handlers.TreeHandler, droneHandler *handlers.DroneHandler, speciesHandler *handlers.SpeciesHandler) {
+	e.GET("/estate", estateHandler.ListEstates)
 	e.POST("/estate", estateHandler.CreateEstate)
+	e.GET("/estate/:id", estateHandler.GetEstate)
+	e.PATCH("/estate/:id", estateHandler.UpdateEstate)
+	e.DELETE("/estate/:id", estateHandler.DeleteEstate)
+	e.POST("/estate/:id/archive", estateHandler.ArchiveEstate)
+	e.POST("/estate/:id/restore", estateHandler.RestoreEstate)
 	e.POST("/estate/:id/tree", treeHandler.AddTreeToEstate)
+	e.POST("/estate/:id/tree/import", treeHandler.ImportTrees)
+	e.GET("/estate/:id/tree/:treeId", treeHandler.GetTree)
+	e.PATCH("/estate/:id/tree/:treeId", treeHandler.UpdateTree)
+	e.DELETE("/estate/:id/tree/:treeId", treeHandler.DeleteTree)
+	e.GET("/estate/:id/tree/:treeId/measurements", treeHandler.GetTreeMeasurements)
+	e.POST("/estate/:id/tree/:treeId/measurements", treeHandler.AddTreeMeasurement)
+	e.GET("/estate/:id/tree/:treeId/observations", treeHandler.GetTreeObservations)
+	e.POST("/estate/:id/tree/:treeId/observations", treeHandler.AddTreeObservation)
+	e.GET("/estate/:id/tree/:treeId/harvests", treeHandler.GetTreeHarvests)
+	e.POST("/estate/:id/harvests", treeHandler.AddHarvests)
+	e.GET("/estate/:id/trees", treeHandler.ListTrees)
+	e.GET("/estate/:id/trees/export", treeHandler.ExportTrees)
+	e.GET("/estate/:id/heatmap.png", treeHandler.HeatmapPNG)
+	e.GET("/estate/:id/heatmap.svg", treeHandler.HeatmapSVG)
+	e.GET("/estate/:id/tiles/:z/:x/:y", treeHandler.GetTile)
+	e.GET("/estate/:id/report", treeHandler.GetEstateReport)
+	e.GET("/estate/:id/trees/nearby", treeHandler.GetNearbyTrees)
+	e.GET("/estate/:id/plot/:x/:y", treeHandler.GetTreeAtPlot)
 	e.GET("/estate/:id/stats", estateHandler.GetEstateStats)
+	e.GET("/estate/:id/stats/breakdown", estateHandler.GetEstateStatsBreakdown)
+	e.GET("/estate/:id/stats/timeseries", estateHandler.GetEstateTimeseries)
+	e.POST("/estate/:id/stats/regions", estateHandler.GetEstateRegionStats)
+	e.GET("/portfolio/stats", estateHandler.GetPortfolioStats)
+	e.GET("/estate/:id/yield", estateHandler.GetEstateYield)
+	e.GET("/estate/:id/forecast", treeHandler.GetEstateForecast)
+	e.GET("/estate/:id/gaps", treeHandler.GetEstateGaps)
+	e.GET("/estate/:id/anomalies", treeHandler.ListAnomalies)
+	e.POST("/estate/:id/anomalies/detect", treeHandler.DetectAnomalies)
+	e.POST("/estate/:id/anomalies/:anomalyId/acknowledge", treeHandler.AcknowledgeAnomaly)
+	e.POST("/estate/:id/anomalies/:anomalyId/dismiss", treeHandler.DismissAnomaly)
 	e.GET("/estate/:id/drone-plan", droneHandler.CalculateDronePlanWithLimit)
+	e.GET("/species", speciesHandler.ListSpecies)
+	e.POST("/species", speciesHandler.CreateSpecies)
 }
@@ -0,0 +1,264 @@
+// Package spreadsheet writes Office Open XML workbooks (XLSX) without holding
+// their rows in memory: each sheet is streamed into the zip archive as its
+// rows are written.
+package spreadsheet
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of an XLSX file.
+const ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// MaxRows is the most rows a sheet holds in Excel, its header included.
+const MaxRows = 1048576
+
+// Other limits of Excel.
+const (
+	maxSheetName = 31
+	maxColumns   = 16384
+)
+
+// Cell styles, in the order of cellXfs in styles.xml.
+const (
+	styleDefault = iota
+	styleHeader
+	styleDate
+)
+
+// excelEpoch is day 0 of Excel dates, which count 1900 as a leap year.
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// Workbook writes the sheets of an XLSX file one after the other. Rows can
+// only be written to the last sheet added.
+type Workbook struct {
+	zw     *zip.Writer
+	sheets []string
+	sheet  io.Writer // Data of the current sheet
+	rows   int       // Rows written to the current sheet, its header included
+	width  int       // Columns of the current sheet
+}
+
+// NewWorkbook starts a workbook written to w. It must be closed to be valid.
+func NewWorkbook(w io.Writer) *Workbook {
+	return &Workbook{zw: zip.NewWriter(w)}
+}
+
+// AddSheet ends the current sheet and starts a new one with a bold header
+// row that stays in view when scrolling. Columns are as wide as their header.
+func (wb *Workbook) AddSheet(name string, header ...string) error {
+	if name == "" || utf8.RuneCountInString(name) > maxSheetName || strings.ContainsAny(name, `[]:*?/\`) {
+		return fmt.Errorf("spreadsheet: invalid sheet name %q", name)
+	}
+	for _, sheet := range wb.sheets {
+		if strings.EqualFold(sheet, name) {
+			return fmt.Errorf("spreadsheet: duplicate sheet name %q", name)
+		}
+	}
+	if len(header) == 0 || len(header) > maxColumns {
+		return fmt.Errorf("spreadsheet: sheet %q must have between 1 and %d columns", name, maxColumns)
+	}
+	if err := wb.endSheet(); err != nil {
+		return err
+	}
+
+	w, err := wb.zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(wb.sheets)+1))
+	if err != nil {
+		return err
+	}
+	wb.sheets = append(wb.sheets, name)
+	wb.sheet, wb.rows, wb.width = w, 0, len(header)
+
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0">`)
+	b.WriteString(`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>`)
+	b.WriteString(`<selection pane="bottomLeft"/></sheetView></sheetViews><cols>`)
+	for i, title := range header {
+		fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, max(len(title)+4, 12))
+	}
+	b.WriteString(`</cols><sheetData>`)
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return err
+	}
+
+	cells := make([]interface{}, len(header))
+	for i, title := range header {
+		cells[i] = title
+	}
+	return wb.writeRow(styleHeader, cells)
+}
+
+// WriteRow appends a row to the current sheet. Cells may be strings, ints,
+// floats, bools, times, written as dates, or nil for an empty cell.
+func (wb *Workbook) WriteRow(cells ...interface{}) error {
+	if wb.sheet == nil {
+		return fmt.Errorf("spreadsheet: no sheet to write to")
+	}
+	if len(cells) > wb.width {
+		return fmt.Errorf("spreadsheet: row of %d cells in a sheet of %d columns", len(cells), wb.width)
+	}
+	if wb.rows == MaxRows {
+		return fmt.Errorf("spreadsheet: sheet %q is full", wb.sheets[len(wb.sheets)-1])
+	}
+	return wb.writeRow(styleDefault, cells)
+}
+
+func (wb *Workbook) writeRow(style int, cells []interface{}) error {
+	row := wb.rows + 1
+	var b strings.Builder
+	fmt.Fprintf(&b, `<row r="%d">`, row)
+	for i, cell := range cells {
+		ref := columnName(i) + strconv.Itoa(row)
+		if err := writeCell(&b, ref, style, cell); err != nil {
+			return err
+		}
+	}
+	b.WriteString(`</row>`)
+	wb.rows = row
+	_, err := io.WriteString(wb.sheet, b.String())
+	return err
+}
+
+// writeCell writes a cell with the type of its value.
+func writeCell(b *strings.Builder, ref string, style int, value interface{}) error {
+	number := func(v string) {
+		fmt.Fprintf(b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, v)
+	}
+	switch v := value.(type) {
+	case nil:
+	case string:
+		fmt.Fprintf(b, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">`, ref, style)
+		if err := xml.EscapeText(b, []byte(v)); err != nil {
+			return err
+		}
+		b.WriteString(`</t></is></c>`)
+	case int:
+		number(strconv.Itoa(v))
+	case int64:
+		number(strconv.FormatInt(v, 10))
+	case float64:
+		// Excel has no NaN or infinity, the cell is left empty
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			number(strconv.FormatFloat(v, 'g', -1, 64))
+		}
+	case bool:
+		flag := "0"
+		if v {
+			flag = "1"
+		}
+		fmt.Fprintf(b, `<c r="%s" s="%d" t="b"><v>%s</v></c>`, ref, style, flag)
+	case time.Time:
+		days := v.UTC().Sub(excelEpoch).Hours() / 24
+		fmt.Fprintf(b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, styleDate, strconv.FormatFloat(days, 'f', -1, 64))
+	default:
+		return fmt.Errorf("spreadsheet: unsupported cell type %T", value)
+	}
+	return nil
+}
+
+// columnName returns the letters naming the column at index i: A to Z, then
+// AA and so on.
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+func (wb *Workbook) endSheet() error {
+	if wb.sheet == nil {
+		return nil
+	}
+	_, err := io.WriteString(wb.sheet, `</sheetData></worksheet>`)
+	wb.sheet = nil
+	return err
+}
+
+// Close ends the last sheet and writes the parts describing the workbook. It
+// does not close the underlying writer.
+func (wb *Workbook) Close() error {
+	if len(wb.sheets) == 0 {
+		return fmt.Errorf("spreadsheet: a workbook needs at least one sheet")
+	}
+	if err := wb.endSheet(); err != nil {
+		return err
+	}
+
+	var types, workbook, rels strings.Builder
+	types.WriteString(xml.Header)
+	types.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	types.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	types.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	types.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	types.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+
+	workbook.WriteString(xml.Header)
+	workbook.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" `)
+	workbook.WriteString(`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+
+	rels.WriteString(xml.Header)
+	rels.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	rels.WriteString(`<Relationship Id="rIdStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`)
+
+	for i, name := range wb.sheets {
+		fmt.Fprintf(&types, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		workbook.WriteString(`<sheet name="`)
+		if err := xml.EscapeText(&workbook, []byte(name)); err != nil {
+			return err
+		}
+		fmt.Fprintf(&workbook, `" sheetId="%d" r:id="rId%d"/>`, i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	types.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	rels.WriteString(`</Relationships>`)
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", types.String()},
+		{"_rels/.rels", packageRels},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", rels.String()},
+		{"xl/styles.xml", styles},
+	}
+	for _, part := range parts {
+		w, err := wb.zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, part.content); err != nil {
+			return err
+		}
+	}
+	return wb.zw.Close()
+}
+
+// packageRels points the package at the workbook.
+const packageRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// styles defines the default, header and date cell styles.
+const styles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
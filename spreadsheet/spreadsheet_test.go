@@ -0,0 +1,89 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readParts unzips a workbook and checks that every part is well-formed XML.
+func readParts(t *testing.T, data []byte) map[string]string {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+
+		d := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			_, err := d.Token()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err, f.Name)
+		}
+		parts[f.Name] = string(content)
+	}
+	return parts
+}
+
+func TestWorkbook(t *testing.T) {
+	var buf bytes.Buffer
+	wb := NewWorkbook(&buf)
+	require.NoError(t, wb.AddSheet("Summary", "Metric", "Value"))
+	require.NoError(t, wb.WriteRow("Trees", 12))
+	require.NoError(t, wb.WriteRow("Mean height (m)", 12.25))
+	require.NoError(t, wb.WriteRow(" <padded> & ", nil))
+	require.NoError(t, wb.AddSheet("Trees", "x", "planted_on", "healthy", "score"))
+	require.NoError(t, wb.WriteRow(int64(3), time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC), true, math.NaN()))
+	require.NoError(t, wb.Close())
+
+	parts := readParts(t, buf.Bytes())
+	assert.Contains(t, parts["[Content_Types].xml"], `PartName="/xl/worksheets/sheet2.xml"`)
+	assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="Summary" sheetId="1" r:id="rId1"/><sheet name="Trees" sheetId="2" r:id="rId2"/>`)
+	assert.Contains(t, parts["xl/_rels/workbook.xml.rels"], `Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet2.xml"`)
+	assert.Contains(t, parts, "_rels/.rels")
+	assert.Contains(t, parts, "xl/styles.xml")
+
+	summary := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, summary, `<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>`)
+	assert.Contains(t, summary, `<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">Metric</t></is></c>`)
+	assert.Contains(t, summary, `<c r="B2" s="0"><v>12</v></c>`)
+	assert.Contains(t, summary, `<c r="B3" s="0"><v>12.25</v></c>`)
+	assert.Contains(t, summary, `<row r="4"><c r="A4" s="0" t="inlineStr"><is><t xml:space="preserve"> &lt;padded&gt; &amp; </t></is></c></row>`)
+
+	trees := parts["xl/worksheets/sheet2.xml"]
+	// 2015-03-01 is day 42064 of Excel
+	assert.Contains(t, trees, `<row r="2"><c r="A2" s="0"><v>3</v></c><c r="B2" s="2"><v>42064</v></c><c r="C2" s="0" t="b"><v>1</v></c></row>`)
+}
+
+func TestWorkbook_Errors(t *testing.T) {
+	wb := NewWorkbook(io.Discard)
+	assert.Error(t, wb.WriteRow("no sheet"))
+	assert.Error(t, wb.AddSheet("Bad/name", "a"))
+	assert.Error(t, wb.AddSheet("A name much longer than Excel allows", "a"))
+	// Names are limited in characters, not bytes
+	assert.NoError(t, wb.AddSheet(strings.Repeat("é", maxSheetName), "a"))
+	assert.Error(t, wb.AddSheet("Empty"))
+	require.NoError(t, wb.AddSheet("Trees", "a"))
+	assert.Error(t, wb.AddSheet("trees", "a"))
+	assert.Error(t, wb.WriteRow(1, 2))
+	assert.Error(t, wb.WriteRow(struct{}{}))
+	assert.Error(t, NewWorkbook(io.Discard).Close())
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, []string{"A", "Z", "AA", "AZ", "BA", "ZZ", "AAA", "XFD"},
+		[]string{columnName(0), columnName(25), columnName(26), columnName(51), columnName(52), columnName(701), columnName(702), columnName(maxColumns - 1)})
+}
@@ -21,7 +21,9 @@ func TestGetEstateStats(t *testing.T) {
     defer ctrl.Finish()
 
     mockEstateRepo := mocks.NewMockEstateRepository(ctrl)
+    mockHarvestRepo := mocks.NewMockHarvestRepository(ctrl)
     handler := handlers.NewEstateHandler(mockEstateRepo)
+    handler.HarvestRepo = mockHarvestRepo
 
     t.Run("successful retrieval", func(t *testing.T) {
         estateID := uuid.New()
@@ -30,10 +32,22 @@ func TestGetEstateStats(t *testing.T) {
             Width:  10,
             Length: 10,
         }
-        count, max, min, median := 100, 20, 5, 10
+        heights := &models.HeightStats{
+            Count:       100,
+            Max:         20,
+            Min:         5,
+            Median:      10.5,
+            Mean:        11.2,
+            StdDev:      3.4,
+            Percentiles: []models.Percentile{{P: 10, Height: 6.5}, {P: 90, Height: 16}},
+            BucketWidth: 5,
+            Histogram:   []models.HistogramBucket{{From: 5, To: 9, Count: 40}, {From: 10, To: 14, Count: 45}, {From: 15, To: 19, Count: 14}, {From: 20, To: 24, Count: 1}},
+        }
 
         mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(estate, nil)
-        mockEstateRepo.EXPECT().GetEstateStats(estateID).Return(count, max, min, median, nil)
+        mockEstateRepo.EXPECT().GetEstateStats(estateID, gomock.Nil(), gomock.Any()).Return(heights, nil)
+        mockEstateRepo.EXPECT().GetHealthCounts(estateID, gomock.Nil()).Return(map[string]int{"healthy": 100}, nil)
+        mockHarvestRepo.EXPECT().GetYieldTotals(estateID, gomock.Nil(), gomock.Any(), gomock.Any()).Return(&models.YieldTotals{WeightKg: 2000}, nil)
 
         req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/stats", nil)
         rec := httptest.NewRecorder()
@@ -44,12 +58,11 @@ func TestGetEstateStats(t *testing.T) {
 
         if assert.NoError(t, handler.GetEstateStats(c)) {
             assert.Equal(t, http.StatusOK, rec.Code)
-            var response map[string]int
+            var response models.EstateStats
             assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
-            assert.Equal(t, count, response["count"])
-            assert.Equal(t, max, response["max"])
-            assert.Equal(t, min, response["min"])
-            assert.Equal(t, median, response["median"])
+            assert.Equal(t, *heights, response.HeightStats)
+            assert.Equal(t, 100, response.Health["healthy"])
+            assert.Equal(t, 2.0, response.TonnesPerHectare)
         }
     })
 
@@ -118,7 +131,7 @@ func TestGetEstateStats(t *testing.T) {
         }
 
         mockEstateRepo.EXPECT().GetEstateByID(estateID).Return(estate, nil)
-        mockEstateRepo.EXPECT().GetEstateStats(estateID).Return(0, 0, 0, 0, assert.AnError)
+        mockEstateRepo.EXPECT().GetEstateStats(estateID, gomock.Nil(), gomock.Any()).Return(nil, assert.AnError)
 
         req := httptest.NewRequest(http.MethodGet, "/estate/"+estateID.String()+"/stats", nil)
         rec := httptest.NewRecorder()
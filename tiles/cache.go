@@ -0,0 +1,155 @@
+package tiles
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sawitpro-recruitment/models"
+	"strconv"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// DiskCache keeps rendered tiles in a directory, one file per tile under
+// <estate>/<z>/<x>/<y>.<variant>. A nil *DiskCache is valid and caches nothing.
+//
+// Each estate has a generation, bumped whenever its tiles are invalidated. A
+// tile rendered before an invalidation is not stored, so a tile never outlives
+// a change to its trees made while it was rendered.
+type DiskCache struct {
+	dir         string
+	mu          sync.Mutex // Held while tiles are stored or removed
+	generations map[uuid.UUID]uint64
+}
+
+// NewDiskCache creates a cache storing tiles under dir.
+func NewDiskCache(dir string) *DiskCache {
+	return &DiskCache{dir: dir, generations: make(map[uuid.UUID]uint64)}
+}
+
+// path returns the file of a cached tile.
+func (c *DiskCache) path(estateID uuid.UUID, t Tile, variant string) string {
+	return filepath.Join(c.dir, estateID.String(), strconv.Itoa(t.Z), strconv.Itoa(t.X), fmt.Sprintf("%d.%s", t.Y, variant))
+}
+
+// Generation returns the generation of an estate's tiles, to be passed to Put
+// for a tile rendered from now on.
+func (c *DiskCache) Generation(estateID uuid.UUID) uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generations[estateID]
+}
+
+// Get returns a cached tile.
+func (c *DiskCache) Get(estateID uuid.UUID, t Tile, variant string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(c.path(estateID, t, variant))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Put stores a tile rendered at the given generation, unless the estate's
+// tiles have been invalidated since. The file is written aside and renamed, so
+// readers never see part of a tile.
+func (c *DiskCache) Put(estateID uuid.UUID, t Tile, variant string, generation uint64, data []byte) error {
+	if c == nil {
+		return nil
+	}
+	path := c.path(estateID, t, variant)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), ".tile-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generations[estateID] != generation {
+		return nil
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// InvalidateEstate removes every cached tile of an estate, as when it moves,
+// is resized or is deleted.
+func (c *DiskCache) InvalidateEstate(estateID uuid.UUID) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generations[estateID]++
+	return os.RemoveAll(filepath.Join(c.dir, estateID.String()))
+}
+
+// InvalidatePlots removes the cached tiles a tree on any of the plots is drawn
+// on, at every zoom with cached tiles. It suits changes to a few trees; bulk
+// changes are cheaper to handle with InvalidateEstate.
+func (c *DiskCache) InvalidatePlots(estate *models.Estate, plots []models.Plot) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generations[estate.ID]++
+
+	projection := NewProjection(estate)
+	if projection == nil {
+		return nil
+	}
+	zooms, err := os.ReadDir(filepath.Join(c.dir, estate.ID.String()))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, zoom := range zooms {
+		z, err := strconv.Atoi(zoom.Name())
+		if err != nil || z < MinZoom || z > MaxZoom {
+			continue
+		}
+		// A tree is drawn up to the margin around its center
+		pad := Margin() / TileSize * Tile{Z: z}.size()
+		seen := map[Tile]bool{}
+		for _, plot := range plots {
+			mx, my := projection.center(plot.X, plot.Y)
+			x1, y1 := tileOf(mx-pad, my+pad, z)
+			x2, y2 := tileOf(mx+pad, my-pad, z)
+			for x := x1; x <= x2; x++ {
+				for y := y1; y <= y2; y++ {
+					t := Tile{Z: z, X: x, Y: y}
+					if seen[t] {
+						continue
+					}
+					seen[t] = true
+					for _, variant := range variants {
+						if err := os.Remove(c.path(estate.ID, t, variant)); err != nil && !os.IsNotExist(err) {
+							return err
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package tiles
+
+import (
+	"io"
+	"math"
+	"sawitpro-recruitment/models"
+)
+
+// Vector tile layout: the single layer of trees, its extent and the buffer
+// around it in which trees are kept, so that symbols crossing the tile edge
+// are drawn whole.
+const (
+	mvtLayer   = "trees"
+	mvtExtent  = 4096
+	mvtBuffer  = 64
+	mvtVersion = 2
+)
+
+// Protobuf wire types and the fields of the vector tile messages used.
+const (
+	wireVarint = 0
+	wireBytes  = 2
+
+	tileLayers = 3
+
+	layerVersion  = 15
+	layerName     = 1
+	layerFeatures = 2
+	layerKeys     = 3
+	layerValues   = 4
+	layerExtent   = 5
+
+	featureTags     = 2
+	featureType     = 3
+	featureGeometry = 4
+
+	valueString = 1
+	valueInt    = 4
+
+	geometryPoint = 1
+	commandMoveTo = 1
+)
+
+// protobuf appends protocol buffer fields to a message.
+type protobuf []byte
+
+func (b *protobuf) varint(v uint64) {
+	for v >= 0x80 {
+		*b = append(*b, byte(v)|0x80)
+		v >>= 7
+	}
+	*b = append(*b, byte(v))
+}
+
+func (b *protobuf) key(field, wire int) {
+	b.varint(uint64(field<<3 | wire))
+}
+
+func (b *protobuf) uint(field int, v uint64) {
+	b.key(field, wireVarint)
+	b.varint(v)
+}
+
+func (b *protobuf) bytes(field int, data []byte) {
+	b.key(field, wireBytes)
+	b.varint(uint64(len(data)))
+	*b = append(*b, data...)
+}
+
+// packed appends a packed repeated field of unsigned integers.
+func (b *protobuf) packed(field int, values ...uint64) {
+	var packed protobuf
+	for _, v := range values {
+		packed.varint(v)
+	}
+	b.bytes(field, packed)
+}
+
+// zigzag encodes a signed integer as vector tile geometries do.
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+// mvtValue is a property value, a string or an integer.
+type mvtValue struct {
+	s     string
+	i     int64
+	isInt bool
+}
+
+// mvtTile encodes a layer with a point per tree, with the tree's ID, height,
+// species, planting date and health status as properties.
+type mvtTile struct {
+	tile       Tile
+	projection *Projection
+	features   protobuf
+	keys       []string
+	keyIndex   map[string]uint64
+	values     []mvtValue
+	valueIndex map[mvtValue]uint64
+}
+
+func newMVTTile(t Tile, projection *Projection) *mvtTile {
+	return &mvtTile{
+		tile:       t,
+		projection: projection,
+		keyIndex:   map[string]uint64{},
+		valueIndex: map[mvtValue]uint64{},
+	}
+}
+
+// tag returns the key and value indexes of a property, adding them to the
+// layer when first seen.
+func (m *mvtTile) tag(key string, value mvtValue) []uint64 {
+	k, ok := m.keyIndex[key]
+	if !ok {
+		k = uint64(len(m.keys))
+		m.keyIndex[key] = k
+		m.keys = append(m.keys, key)
+	}
+	v, ok := m.valueIndex[value]
+	if !ok {
+		v = uint64(len(m.values))
+		m.valueIndex[value] = v
+		m.values = append(m.values, value)
+	}
+	return []uint64{k, v}
+}
+
+// Add encodes a tree as a point feature, unless it lies beyond the buffer.
+func (m *mvtTile) Add(tree *models.Tree) {
+	mx, my := m.projection.center(tree.X, tree.Y)
+	px, py := m.tile.pixel(mx, my, mvtExtent)
+	x, y := int64(math.Round(px)), int64(math.Round(py))
+	if x < -mvtBuffer || y < -mvtBuffer || x > mvtExtent+mvtBuffer || y > mvtExtent+mvtBuffer {
+		return
+	}
+
+	var tags []uint64
+	tags = append(tags, m.tag("id", mvtValue{s: tree.ID.String()})...)
+	tags = append(tags, m.tag("height", mvtValue{i: int64(tree.Height), isInt: true})...)
+	if tree.Species != "" {
+		tags = append(tags, m.tag("species", mvtValue{s: tree.Species})...)
+	}
+	if tree.PlantedOn != nil {
+		tags = append(tags, m.tag("planted_on", mvtValue{s: tree.PlantedOn.String()})...)
+	}
+	if tree.HealthStatus != "" {
+		tags = append(tags, m.tag("health_status", mvtValue{s: tree.HealthStatus})...)
+	}
+
+	var feature protobuf
+	feature.packed(featureTags, tags...)
+	feature.uint(featureType, geometryPoint)
+	feature.packed(featureGeometry, commandMoveTo|1<<3, zigzag(x), zigzag(y))
+	m.features.bytes(layerFeatures, feature)
+}
+
+// Encode writes the tile; a tile without trees has no layer.
+func (m *mvtTile) Encode(w io.Writer) error {
+	if len(m.features) == 0 {
+		return nil
+	}
+	var layer protobuf
+	layer.uint(layerVersion, mvtVersion)
+	layer.bytes(layerName, []byte(mvtLayer))
+	layer = append(layer, m.features...)
+	for _, key := range m.keys {
+		layer.bytes(layerKeys, []byte(key))
+	}
+	for _, value := range m.values {
+		var v protobuf
+		if value.isInt {
+			v.uint(valueInt, uint64(value.i))
+		} else {
+			v.bytes(valueString, []byte(value.s))
+		}
+		layer.bytes(layerValues, v)
+	}
+	layer.uint(layerExtent, mvtExtent)
+
+	var tile protobuf
+	tile.bytes(tileLayers, layer)
+	_, err := w.Write(tile)
+	return err
+}
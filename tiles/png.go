@@ -0,0 +1,84 @@
+package tiles
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"sawitpro-recruitment/heatmap"
+	"sawitpro-recruitment/models"
+)
+
+// Dot sizes, in pixels. A dot covers most of its plot, within these bounds.
+const (
+	minDotRadius = 1.0
+	maxDotRadius = 12.0
+)
+
+// scaleHeight is the height at the top of the colour scale. Every tile uses
+// the same scale, so a tree only changes the tiles it is drawn on.
+const scaleHeight = models.DefaultMaxTreeHeight
+
+// healthColors are the colours of trees by health status.
+var healthColors = map[string]color.NRGBA{
+	models.HealthStatusHealthy:            {0x2e, 0x7d, 0x32, 0xff},
+	models.HealthStatusGanoderma:          {0xc6, 0x28, 0x28, 0xff},
+	models.HealthStatusOryctes:            {0xef, 0x6c, 0x00, 0xff},
+	models.HealthStatusNutrientDeficiency: {0xf9, 0xa8, 0x25, 0xff},
+	models.HealthStatusOther:              {0x6d, 0x4c, 0x41, 0xff},
+	models.HealthStatusDead:               {0x21, 0x21, 0x21, 0xff},
+}
+
+// pngTile draws a dot per tree, on a transparent background.
+type pngTile struct {
+	tile       Tile
+	colorBy    string
+	projection *Projection
+	radius     float64
+	img        *image.NRGBA
+}
+
+func newPNGTile(t Tile, colorBy string, projection *Projection) *pngTile {
+	return &pngTile{
+		tile:       t,
+		colorBy:    colorBy,
+		projection: projection,
+		radius:     math.Max(minDotRadius, math.Min(maxDotRadius, 0.4*projection.plotPixels(t.Z))),
+		img:        image.NewNRGBA(image.Rect(0, 0, TileSize, TileSize)),
+	}
+}
+
+// color returns the colour of a tree's dot.
+func (r *pngTile) color(tree *models.Tree) color.NRGBA {
+	if r.colorBy == ColorByHealth {
+		if c, ok := healthColors[tree.HealthStatus]; ok {
+			return c
+		}
+		return healthColors[models.HealthStatusHealthy]
+	}
+	return heatmap.ScaleColor(float64(tree.Height) / scaleHeight)
+}
+
+// Add draws a tree. The pixel holding its center is always drawn, so that
+// trees stay visible however far out the map is.
+func (r *pngTile) Add(tree *models.Tree) {
+	mx, my := r.projection.center(tree.X, tree.Y)
+	cx, cy := r.tile.pixel(mx, my, TileSize)
+	c := r.color(tree)
+	if image.Pt(int(math.Floor(cx)), int(math.Floor(cy))).In(r.img.Rect) {
+		r.img.SetNRGBA(int(math.Floor(cx)), int(math.Floor(cy)), c)
+	}
+	for y := int(math.Floor(cy - r.radius)); y <= int(math.Ceil(cy+r.radius)); y++ {
+		for x := int(math.Floor(cx - r.radius)); x <= int(math.Ceil(cx+r.radius)); x++ {
+			dx, dy := float64(x)+0.5-cx, float64(y)+0.5-cy
+			if dx*dx+dy*dy <= r.radius*r.radius && image.Pt(x, y).In(r.img.Rect) {
+				r.img.SetNRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+func (r *pngTile) Encode(w io.Writer) error {
+	return png.Encode(w, r.img)
+}
@@ -0,0 +1,210 @@
+// Package tiles renders the trees of an estate as slippy map tiles, in the
+// XYZ scheme of web maps: PNG images with a dot per tree, or Mapbox Vector
+// Tiles with a point per tree.
+//
+// Estates are placed on Web Mercator from the location of their corner. Plots
+// are taken as squares in the projection, scaled at the corner's latitude,
+// which holds for estates much smaller than the Earth.
+package tiles
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sawitpro-recruitment/models"
+	"strconv"
+	"strings"
+)
+
+// Zoom levels served. Below MinZoom a tile covers too many plots to be drawn
+// tree by tree.
+const (
+	MinZoom = 13
+	MaxZoom = 22
+)
+
+// TileSize is the side of a PNG tile, in pixels.
+const TileSize = 256
+
+// Tile formats.
+const (
+	FormatPNG = "png"
+	FormatMVT = "mvt"
+)
+
+// Colour schemes of PNG tiles.
+const (
+	ColorByHeight = "height"
+	ColorByHealth = "health"
+)
+
+// earthRadius is the radius of the Web Mercator sphere, in meters.
+const earthRadius = 6378137.0
+
+// worldSize is the side of the Web Mercator square, in meters.
+const worldSize = 2 * math.Pi * earthRadius
+
+// Tile is a tile of the XYZ scheme: column X and row Y, from the north-west,
+// at zoom Z.
+type Tile struct {
+	Z, X, Y int
+}
+
+// ParseTile reads a tile from its zoom, column and last path segment, such as
+// "12.png", returning the tile and its format.
+func ParseTile(z, x, name string) (Tile, string, error) {
+	y, format, _ := strings.Cut(name, ".")
+	if format != FormatPNG && format != FormatMVT {
+		return Tile{}, "", fmt.Errorf("tile format must be png or mvt")
+	}
+	var t Tile
+	var errZ, errX, errY error
+	t.Z, errZ = strconv.Atoi(z)
+	t.X, errX = strconv.Atoi(x)
+	t.Y, errY = strconv.Atoi(y)
+	if errZ != nil || errX != nil || errY != nil {
+		return Tile{}, "", fmt.Errorf("invalid tile coordinates")
+	}
+	if t.Z < MinZoom || t.Z > MaxZoom {
+		return Tile{}, "", fmt.Errorf("zoom must be between %d and %d", MinZoom, MaxZoom)
+	}
+	if t.X < 0 || t.Y < 0 || t.X >= 1<<t.Z || t.Y >= 1<<t.Z {
+		return Tile{}, "", fmt.Errorf("tile %d/%d/%d does not exist", t.Z, t.X, t.Y)
+	}
+	return t, format, nil
+}
+
+func (t Tile) String() string {
+	return fmt.Sprintf("%d/%d/%d", t.Z, t.X, t.Y)
+}
+
+// size returns the side of the tile, in Mercator meters.
+func (t Tile) size() float64 {
+	return worldSize / float64(int(1)<<t.Z)
+}
+
+// pixel returns the position of a Mercator point on the tile, scaled to extent
+// units a side.
+func (t Tile) pixel(mx, my, extent float64) (float64, float64) {
+	size := t.size()
+	left := -worldSize/2 + float64(t.X)*size
+	top := worldSize/2 - float64(t.Y)*size
+	return (mx - left) / size * extent, (top - my) / size * extent
+}
+
+// tileOf returns the column and row, at zoom z, of the tile holding a Mercator point.
+func tileOf(mx, my float64, z int) (int, int) {
+	n := float64(int(1) << z)
+	return int(math.Floor((mx + worldSize/2) / worldSize * n)), int(math.Floor((worldSize/2 - my) / worldSize * n))
+}
+
+// Projection places the plots of an estate on Web Mercator.
+type Projection struct {
+	originX, originY float64 // Outer corner of plot (1,1), in Mercator meters
+	scale            float64 // Mercator meters per plot
+	sin, cos         float64 // Of the estate's rotation
+	width, length    int
+}
+
+// NewProjection returns the projection of an estate, or nil when the estate
+// has no location.
+func NewProjection(estate *models.Estate) *Projection {
+	location := estate.Location
+	if location == nil {
+		return nil
+	}
+	latitude := location.Latitude * math.Pi / 180
+	rotation := location.Rotation * math.Pi / 180
+	return &Projection{
+		originX: earthRadius * location.Longitude * math.Pi / 180,
+		originY: earthRadius * math.Log(math.Tan(math.Pi/4+latitude/2)),
+		scale:   models.PlotSizeMeters / math.Cos(latitude),
+		sin:     math.Sin(rotation),
+		cos:     math.Cos(rotation),
+		width:   estate.Width,
+		length:  estate.Length,
+	}
+}
+
+// mercator returns the Mercator position of a point given in plots from the
+// outer corner of plot (1,1), along the rows (u) and the columns (v).
+func (p *Projection) mercator(u, v float64) (float64, float64) {
+	east := u*p.cos - v*p.sin
+	north := -u*p.sin - v*p.cos
+	return p.originX + east*p.scale, p.originY + north*p.scale
+}
+
+// plots is the inverse of mercator; the rotation is its own inverse.
+func (p *Projection) plots(mx, my float64) (float64, float64) {
+	east := (mx - p.originX) / p.scale
+	north := (my - p.originY) / p.scale
+	return east*p.cos - north*p.sin, -east*p.sin - north*p.cos
+}
+
+// center returns the Mercator position of the center of a plot.
+func (p *Projection) center(x, y int) (float64, float64) {
+	return p.mercator(float64(x)-0.5, float64(y)-0.5)
+}
+
+// PlotBox returns the plots of the estate whose center lies on a tile or
+// within margin pixels of it, and false when there is none.
+func (p *Projection) PlotBox(t Tile, margin float64) (models.BoundingBox, bool) {
+	size := t.size()
+	pad := margin / TileSize * size
+	left := -worldSize/2 + float64(t.X)*size - pad
+	top := worldSize/2 - float64(t.Y)*size + pad
+	right, bottom := left+size+2*pad, top-size-2*pad
+
+	minU, minV := math.Inf(1), math.Inf(1)
+	maxU, maxV := math.Inf(-1), math.Inf(-1)
+	for _, corner := range [][2]float64{{left, top}, {right, top}, {left, bottom}, {right, bottom}} {
+		u, v := p.plots(corner[0], corner[1])
+		minU, maxU = math.Min(minU, u), math.Max(maxU, u)
+		minV, maxV = math.Min(minV, v), math.Max(maxV, v)
+	}
+	// The center of plot (x,y) is at (x-0.5, y-0.5)
+	box := models.BoundingBox{
+		X1: int(math.Max(1, math.Ceil(minU+0.5))),
+		Y1: int(math.Max(1, math.Ceil(minV+0.5))),
+		X2: int(math.Min(float64(p.width), math.Floor(maxU+0.5))),
+		Y2: int(math.Min(float64(p.length), math.Floor(maxV+0.5))),
+	}
+	return box, box.X1 <= box.X2 && box.Y1 <= box.Y2
+}
+
+// plotPixels returns the side of a plot on a PNG tile at zoom z, in pixels.
+func (p *Projection) plotPixels(z int) float64 {
+	return p.scale / Tile{Z: z}.size() * TileSize
+}
+
+// Renderer draws the trees of a tile.
+type Renderer interface {
+	Add(tree *models.Tree)
+	Encode(w io.Writer) error
+}
+
+// NewRenderer returns a renderer for a tile in one of the tile formats. The
+// colour scheme only applies to PNG tiles.
+func NewRenderer(t Tile, format, colorBy string, projection *Projection) Renderer {
+	if format == FormatMVT {
+		return newMVTTile(t, projection)
+	}
+	return newPNGTile(t, colorBy, projection)
+}
+
+// Margin returns how far, in pixels of a PNG tile, a tree can be drawn from
+// its tile: the largest dot or the vector tile buffer.
+func Margin() float64 {
+	return math.Max(maxDotRadius, mvtBuffer*TileSize/mvtExtent) + 1
+}
+
+// Variant names the cached file of a tile in a format and colour scheme.
+func Variant(format, colorBy string) string {
+	if format == FormatMVT {
+		return FormatMVT
+	}
+	return colorBy + "." + FormatPNG
+}
+
+// variants lists every cached file of a tile.
+var variants = []string{Variant(FormatPNG, ColorByHeight), Variant(FormatPNG, ColorByHealth), Variant(FormatMVT, "")}
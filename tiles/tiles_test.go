@@ -0,0 +1,272 @@
+package tiles
+
+import (
+	"bytes"
+	"image/png"
+	"math"
+	"sawitpro-recruitment/heatmap"
+	"sawitpro-recruitment/models"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// equatorEstate lies at the origin of Web Mercator, where plot (1,1) is on
+// tile (1<<21, 1<<21) at zoom 22.
+func equatorEstate(width, length int) *models.Estate {
+	return &models.Estate{ID: uuid.New(), Width: width, Length: length, Location: &models.Location{}}
+}
+
+// tileOfPlot returns the tile at zoom z holding the center of a plot.
+func tileOfPlot(p *Projection, x, y, z int) Tile {
+	mx, my := p.center(x, y)
+	tx, ty := tileOf(mx, my, z)
+	return Tile{Z: z, X: tx, Y: ty}
+}
+
+func TestParseTile(t *testing.T) {
+	tile, format, err := ParseTile("15", "25000", "16000.mvt")
+	assert.NoError(t, err)
+	assert.Equal(t, Tile{Z: 15, X: 25000, Y: 16000}, tile)
+	assert.Equal(t, FormatMVT, format)
+
+	for _, tc := range []struct {
+		z, x, name string
+		message    string
+	}{
+		{"15", "1", "2.jpg", "tile format must be png or mvt"},
+		{"15", "1", "2", "tile format must be png or mvt"},
+		{"15", "a", "2.png", "invalid tile coordinates"},
+		{"12", "1", "2.png", "zoom must be between 13 and 22"},
+		{"13", "8192", "0.png", "tile 13/8192/0 does not exist"},
+		{"15", "1", "-2.png", "tile 15/1/-2 does not exist"},
+	} {
+		_, _, err := ParseTile(tc.z, tc.x, tc.name)
+		assert.EqualError(t, err, tc.message, tc.name)
+	}
+}
+
+func TestProjection_RoundTrip(t *testing.T) {
+	estate := equatorEstate(10, 10)
+	estate.Location = &models.Location{Latitude: 1.25, Longitude: 101.5, Rotation: 30}
+	p := NewProjection(estate)
+
+	u, v := p.plots(p.mercator(3.5, 7.25))
+	assert.InDelta(t, 3.5, u, 1e-6)
+	assert.InDelta(t, 7.25, v, 1e-6)
+
+	// Rows run east at rotation 0, turned clockwise by the rotation
+	x1, y1 := p.mercator(0, 0)
+	x2, y2 := p.mercator(1, 0)
+	assert.InDelta(t, -30, math.Atan2(y2-y1, x2-x1)*180/math.Pi, 1e-6)
+
+	assert.Nil(t, NewProjection(&models.Estate{Width: 1, Length: 1}))
+}
+
+func TestProjection_PlotBox(t *testing.T) {
+	p := NewProjection(equatorEstate(10, 10))
+
+	// Tiles at zoom 22 are about 9.55 m a side, a plot 10 m
+	box, ok := p.PlotBox(Tile{Z: 22, X: 1 << 21, Y: 1 << 21}, Margin())
+	assert.True(t, ok)
+	assert.Equal(t, models.BoundingBox{X1: 1, Y1: 1, X2: 1, Y2: 1}, box)
+
+	box, ok = p.PlotBox(Tile{Z: 18, X: 1 << 17, Y: 1 << 17}, 0)
+	assert.True(t, ok)
+	assert.Equal(t, models.BoundingBox{X1: 1, Y1: 1, X2: 10, Y2: 10}, box)
+
+	// North of the equator is outside the estate
+	_, ok = p.PlotBox(Tile{Z: 22, X: 1 << 21, Y: 1<<21 - 1}, Margin())
+	assert.False(t, ok)
+}
+
+func TestPNGTile(t *testing.T) {
+	estate := equatorEstate(10, 10)
+	p := NewProjection(estate)
+	tile := tileOfPlot(p, 1, 1, 22)
+
+	for colorBy, expected := range map[string]interface{}{
+		ColorByHeight: heatmap.ScaleColor(1),
+		ColorByHealth: healthColors[models.HealthStatusGanoderma],
+	} {
+		r := NewRenderer(tile, FormatPNG, colorBy, p)
+		r.Add(&models.Tree{X: 1, Y: 1, Height: 30, HealthStatus: models.HealthStatusGanoderma})
+		var buf bytes.Buffer
+		assert.NoError(t, r.Encode(&buf))
+
+		img, err := png.Decode(&buf)
+		if assert.NoError(t, err) {
+			assert.Equal(t, TileSize, img.Bounds().Dx())
+			mx, my := p.center(1, 1)
+			cx, cy := tile.pixel(mx, my, TileSize)
+			assert.Equal(t, expected, img.At(int(cx), int(cy)), colorBy)
+			_, _, _, alpha := img.At(0, 0).RGBA()
+			assert.Zero(t, alpha, colorBy)
+		}
+	}
+}
+
+// protoField is a field read from a protocol buffer message.
+type protoField struct {
+	number int
+	value  uint64 // Of a varint field
+	data   []byte // Of a length-delimited field
+}
+
+// readVarint reads a varint from the start of data, returning it and the rest.
+func readVarint(t *testing.T, data []byte) (uint64, []byte) {
+	var v uint64
+	for shift := 0; ; shift += 7 {
+		if len(data) == 0 {
+			t.Fatal("truncated varint")
+		}
+		b := data[0]
+		data = data[1:]
+		v |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			return v, data
+		}
+	}
+}
+
+// readProto reads the fields of a message of varint and length-delimited fields.
+func readProto(t *testing.T, data []byte) []protoField {
+	var fields []protoField
+	for len(data) > 0 {
+		var key, n uint64
+		key, data = readVarint(t, data)
+		field := protoField{number: int(key >> 3)}
+		switch key & 7 {
+		case wireVarint:
+			field.value, data = readVarint(t, data)
+		case wireBytes:
+			n, data = readVarint(t, data)
+			field.data, data = data[:n], data[n:]
+		default:
+			t.Fatalf("unexpected wire type %d", key&7)
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// readPacked reads a packed repeated field of unsigned integers.
+func readPacked(t *testing.T, data []byte) []uint64 {
+	var values []uint64
+	for len(data) > 0 {
+		var v uint64
+		v, data = readVarint(t, data)
+		values = append(values, v)
+	}
+	return values
+}
+
+func TestMVTTile(t *testing.T) {
+	estate := equatorEstate(10, 10)
+	p := NewProjection(estate)
+	tile := tileOfPlot(p, 1, 1, 22)
+
+	var empty bytes.Buffer
+	assert.NoError(t, NewRenderer(tile, FormatMVT, "", p).Encode(&empty))
+	assert.Zero(t, empty.Len())
+
+	treeID := uuid.New()
+	r := NewRenderer(tile, FormatMVT, "", p)
+	r.Add(&models.Tree{ID: treeID, X: 1, Y: 1, Height: 12, Species: "tenera", HealthStatus: models.HealthStatusHealthy})
+	r.Add(&models.Tree{ID: uuid.New(), X: 9, Y: 9, Height: 12}) // Beyond the buffer
+	var buf bytes.Buffer
+	assert.NoError(t, r.Encode(&buf))
+
+	tileFields := readProto(t, buf.Bytes())
+	if !assert.Len(t, tileFields, 1) || !assert.Equal(t, tileLayers, tileFields[0].number) {
+		return
+	}
+	var name string
+	var extent uint64
+	var features [][]byte
+	var keys, values []string
+	for _, f := range readProto(t, tileFields[0].data) {
+		switch f.number {
+		case layerName:
+			name = string(f.data)
+		case layerExtent:
+			extent = f.value
+		case layerFeatures:
+			features = append(features, f.data)
+		case layerKeys:
+			keys = append(keys, string(f.data))
+		case layerValues:
+			v := readProto(t, f.data)[0]
+			if v.number == valueString {
+				values = append(values, string(v.data))
+			} else {
+				values = append(values, "int")
+			}
+		}
+	}
+	assert.Equal(t, "trees", name)
+	assert.Equal(t, uint64(mvtExtent), extent)
+	assert.Equal(t, []string{"id", "height", "species", "health_status"}, keys)
+	assert.Equal(t, []string{treeID.String(), "int", "tenera", "healthy"}, values)
+	if !assert.Len(t, features, 1) {
+		return
+	}
+
+	mx, my := p.center(1, 1)
+	px, py := tile.pixel(mx, my, mvtExtent)
+	for _, f := range readProto(t, features[0]) {
+		switch f.number {
+		case featureType:
+			assert.Equal(t, uint64(geometryPoint), f.value)
+		case featureGeometry:
+			assert.Equal(t, []uint64{9, zigzag(int64(math.Round(px))), zigzag(int64(math.Round(py)))}, readPacked(t, f.data))
+		case featureTags:
+			assert.Equal(t, []uint64{0, 0, 1, 1, 2, 2, 3, 3}, readPacked(t, f.data))
+		}
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	estate := equatorEstate(100, 100)
+	p := NewProjection(estate)
+	near := tileOfPlot(p, 10, 10, 20)
+	far := tileOfPlot(p, 90, 90, 20)
+	variant := Variant(FormatPNG, ColorByHeight)
+
+	c := NewDiskCache(t.TempDir())
+	generation := c.Generation(estate.ID)
+	assert.NoError(t, c.Put(estate.ID, near, variant, generation, []byte("near")))
+	assert.NoError(t, c.Put(estate.ID, near, FormatMVT, generation, []byte("vector")))
+	assert.NoError(t, c.Put(estate.ID, far, variant, generation, []byte("far")))
+
+	data, ok := c.Get(estate.ID, near, variant)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("near"), data)
+	_, ok = c.Get(estate.ID, near, Variant(FormatPNG, ColorByHealth))
+	assert.False(t, ok)
+
+	// Every variant of the tiles a changed tree is drawn on is dropped
+	assert.NoError(t, c.InvalidatePlots(estate, []models.Plot{{X: 10, Y: 10}}))
+	_, ok = c.Get(estate.ID, near, variant)
+	assert.False(t, ok)
+	_, ok = c.Get(estate.ID, near, FormatMVT)
+	assert.False(t, ok)
+	_, ok = c.Get(estate.ID, far, variant)
+	assert.True(t, ok)
+
+	// A tile rendered before the invalidation is not stored
+	assert.NoError(t, c.Put(estate.ID, near, variant, generation, []byte("stale")))
+	_, ok = c.Get(estate.ID, near, variant)
+	assert.False(t, ok)
+
+	assert.NoError(t, c.InvalidateEstate(estate.ID))
+	_, ok = c.Get(estate.ID, far, variant)
+	assert.False(t, ok)
+	assert.Equal(t, generation+2, c.Generation(estate.ID))
+
+	var disabled *DiskCache
+	assert.NoError(t, disabled.Put(estate.ID, near, variant, 0, []byte("near")))
+	_, ok = disabled.Get(estate.ID, near, variant)
+	assert.False(t, ok)
+}